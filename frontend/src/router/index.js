@@ -1,5 +1,6 @@
 import { createRouter, createWebHistory, createWebHashHistory } from 'vue-router'
-import { getToken } from '../utils/auth'
+import { getToken, clearAuth } from '../utils/auth'
+import { Authenticate } from '../../wailsjs/go/service/AuthService'
 import HomeView from '../views/EquipmentView.vue'
 import AuthView from '../views/AuthView.vue'
 
@@ -40,9 +41,23 @@ const router = createRouter({
     routes
 })
 
-router.beforeEach((to, from, next) => {
+// Сессия на стороне Go живет только пока запущено приложение,
+// поэтому после перезапуска восстанавливаем её по сохраненному токену
+let sessionRestored = false
+
+router.beforeEach(async (to, from, next) => {
     const requiresAuth = to.matched.some(record => record.meta.requiresAuth)
-    const hasToken = getToken()
+    let hasToken = getToken()
+
+    if (hasToken && !sessionRestored) {
+        try {
+            await Authenticate(hasToken)
+            sessionRestored = true
+        } catch {
+            clearAuth()
+            hasToken = null
+        }
+    }
 
     if (requiresAuth && !hasToken) {
         next('/auth')
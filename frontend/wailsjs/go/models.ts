@@ -38,10 +38,48 @@ export namespace gorm {
 
 export namespace model {
 	
+	export class CategoryDefaults {
+	    maintenance_interval_days: number;
+	    okof: string;
+	    depreciation_group: number;
+	    depreciation_method: string;
+	    useful_life_months: number;
+	    depreciation_factor: number;
+	    unit: string;
+	    serial_prefix: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CategoryDefaults(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maintenance_interval_days = source["maintenance_interval_days"];
+	        this.okof = source["okof"];
+	        this.depreciation_group = source["depreciation_group"];
+	        this.depreciation_method = source["depreciation_method"];
+	        this.useful_life_months = source["useful_life_months"];
+	        this.depreciation_factor = source["depreciation_factor"];
+	        this.unit = source["unit"];
+	        this.serial_prefix = source["serial_prefix"];
+	    }
+	}
 	export class Category {
 	    id: number;
 	    name: string;
 	    description: string;
+	    parent_id: number;
+	    path: string;
+	    maintenance_interval_days: number;
+	    okof: string;
+	    depreciation_group: number;
+	    depreciation_method: string;
+	    useful_life_months: number;
+	    depreciation_factor: number;
+	    unit: string;
+	    serial_prefix: string;
+	    full_name: string;
+	    effective: CategoryDefaults;
 	
 	    static createFrom(source: any = {}) {
 	        return new Category(source);
@@ -52,7 +90,37 @@ export namespace model {
 	        this.id = source["id"];
 	        this.name = source["name"];
 	        this.description = source["description"];
+	        this.parent_id = source["parent_id"];
+	        this.path = source["path"];
+	        this.maintenance_interval_days = source["maintenance_interval_days"];
+	        this.okof = source["okof"];
+	        this.depreciation_group = source["depreciation_group"];
+	        this.depreciation_method = source["depreciation_method"];
+	        this.useful_life_months = source["useful_life_months"];
+	        this.depreciation_factor = source["depreciation_factor"];
+	        this.unit = source["unit"];
+	        this.serial_prefix = source["serial_prefix"];
+	        this.full_name = source["full_name"];
+	        this.effective = this.convertValues(source["effective"], CategoryDefaults);
 	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class CategoryListResponse {
 	    model: Category[];
@@ -127,7 +195,14 @@ export namespace model {
 	    actual_quantity: number;
 	    price: number;
 	    total_price: number;
+	    vat_rate: string;
+	    vat_amount: number;
+	    currency: string;
+	    currency_price: number;
+	    exchange_rate: number;
+	    exchange_nominal: number;
 	    comment: string;
+	    counted: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new DocumentItem(source);
@@ -143,7 +218,14 @@ export namespace model {
 	        this.actual_quantity = source["actual_quantity"];
 	        this.price = source["price"];
 	        this.total_price = source["total_price"];
+	        this.vat_rate = source["vat_rate"];
+	        this.vat_amount = source["vat_amount"];
+	        this.currency = source["currency"];
+	        this.currency_price = source["currency_price"];
+	        this.exchange_rate = source["exchange_rate"];
+	        this.exchange_nominal = source["exchange_nominal"];
 	        this.comment = source["comment"];
+	        this.counted = source["counted"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -264,15 +346,27 @@ export namespace model {
 	    name: string;
 	    description: string;
 	    serial_number: string;
+	    inventory_number: string;
 	    status: string;
 	    quantity: number;
 	    price: number;
+	    currency: string;
+	    currency_price: number;
+	    exchange_rate: number;
+	    exchange_nominal: number;
 	    category_id: number;
 	    category?: Category;
 	    location_id: number;
 	    location?: Location;
 	    supplier_id: number;
 	    supplier?: Supplier;
+	    // Go type: time
+	    commissioned_at?: any;
+	    depreciation_method: string;
+	    useful_life_months: number;
+	    residual_value: number;
+	    accumulated_depreciation: number;
+	    book_value: number;
 	    movements: Movement[];
 	    documents: DocumentItem[];
 	    // Go type: time
@@ -290,15 +384,26 @@ export namespace model {
 	        this.name = source["name"];
 	        this.description = source["description"];
 	        this.serial_number = source["serial_number"];
+	        this.inventory_number = source["inventory_number"];
 	        this.status = source["status"];
 	        this.quantity = source["quantity"];
 	        this.price = source["price"];
+	        this.currency = source["currency"];
+	        this.currency_price = source["currency_price"];
+	        this.exchange_rate = source["exchange_rate"];
+	        this.exchange_nominal = source["exchange_nominal"];
 	        this.category_id = source["category_id"];
 	        this.category = this.convertValues(source["category"], Category);
 	        this.location_id = source["location_id"];
 	        this.location = this.convertValues(source["location"], Location);
 	        this.supplier_id = source["supplier_id"];
 	        this.supplier = this.convertValues(source["supplier"], Supplier);
+	        this.commissioned_at = this.convertValues(source["commissioned_at"], null);
+	        this.depreciation_method = source["depreciation_method"];
+	        this.useful_life_months = source["useful_life_months"];
+	        this.residual_value = source["residual_value"];
+	        this.accumulated_depreciation = source["accumulated_depreciation"];
+	        this.book_value = source["book_value"];
 	        this.movements = this.convertValues(source["movements"], Movement);
 	        this.documents = this.convertValues(source["documents"], DocumentItem);
 	        this.created_at = this.convertValues(source["created_at"], null);
@@ -328,6 +433,10 @@ export namespace model {
 	    name: string;
 	    description: string;
 	    address: string;
+	    kind: string;
+	    parent_id: number;
+	    path: string;
+	    full_name: string;
 	    equipment: Equipment[];
 	    from_movements: Movement[];
 	    to_movements: Movement[];
@@ -342,6 +451,10 @@ export namespace model {
 	        this.name = source["name"];
 	        this.description = source["description"];
 	        this.address = source["address"];
+	        this.kind = source["kind"];
+	        this.parent_id = source["parent_id"];
+	        this.path = source["path"];
+	        this.full_name = source["full_name"];
 	        this.equipment = this.convertValues(source["equipment"], Equipment);
 	        this.from_movements = this.convertValues(source["from_movements"], Movement);
 	        this.to_movements = this.convertValues(source["to_movements"], Movement);
@@ -422,6 +535,15 @@ export namespace model {
 	    location?: Location;
 	    items: DocumentItem[];
 	    comment: string;
+	    basis: string;
+	    basis_number: string;
+	    // Go type: time
+	    basis_date?: any;
+	    reversal_of_id: number;
+	    reversal_of?: Document;
+	    reversal?: Document;
+	    inventory_id: number;
+	    corrections: Document[];
 	    // Go type: time
 	    created_at: any;
 	    // Go type: time
@@ -446,6 +568,14 @@ export namespace model {
 	        this.location = this.convertValues(source["location"], Location);
 	        this.items = this.convertValues(source["items"], DocumentItem);
 	        this.comment = source["comment"];
+	        this.basis = source["basis"];
+	        this.basis_number = source["basis_number"];
+	        this.basis_date = this.convertValues(source["basis_date"], null);
+	        this.reversal_of_id = source["reversal_of_id"];
+	        this.reversal_of = this.convertValues(source["reversal_of"], Document);
+	        this.reversal = this.convertValues(source["reversal"], Document);
+	        this.inventory_id = source["inventory_id"];
+	        this.corrections = this.convertValues(source["corrections"], Document);
 	        this.created_at = this.convertValues(source["created_at"], null);
 	        this.updated_at = this.convertValues(source["updated_at"], null);
 	    }
@@ -468,6 +598,160 @@ export namespace model {
 		    return a;
 		}
 	}
+	export class DepreciationPosting {
+	    id: number;
+	    run_id: number;
+	    equipment_id: number;
+	    equipment?: Equipment;
+	    method: string;
+	    quantity: number;
+	    book_value: number;
+	    unit_amount: number;
+	    amount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DepreciationPosting(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.run_id = source["run_id"];
+	        this.equipment_id = source["equipment_id"];
+	        this.equipment = this.convertValues(source["equipment"], Equipment);
+	        this.method = source["method"];
+	        this.quantity = source["quantity"];
+	        this.book_value = source["book_value"];
+	        this.unit_amount = source["unit_amount"];
+	        this.amount = source["amount"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DepreciationRun {
+	    id: number;
+	    // Go type: time
+	    period: any;
+	    total: number;
+	    created_by_id: number;
+	    created_by?: User;
+	    postings: DepreciationPosting[];
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new DepreciationRun(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.period = this.convertValues(source["period"], null);
+	        this.total = source["total"];
+	        this.created_by_id = source["created_by_id"];
+	        this.created_by = this.convertValues(source["created_by"], User);
+	        this.postings = this.convertValues(source["postings"], DepreciationPosting);
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DepreciationRunListResponse {
+	    model: DepreciationRun[];
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DepreciationRunListResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], DepreciationRun);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DepreciationRunResponse {
+	    model?: DepreciationRun;
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DepreciationRunResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], DepreciationRun);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class DocumentExportResponse {
 	    content: string;
 	    message: string;
@@ -548,18 +832,38 @@ export namespace model {
 		}
 	}
 	
-	export class EquipmentListResponse {
-	    model: Equipment[];
-	    msg: string;
+	export class Employee {
+	    id: number;
+	    name: string;
+	    position: string;
+	    department: string;
+	    personnel_number: string;
+	    contact: string;
+	    user_id: number;
+	    user?: User;
+	    equipment: Equipment[];
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new EquipmentListResponse(source);
+	        return new Employee(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.model = this.convertValues(source["model"], Equipment);
-	        this.msg = source["msg"];
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.position = source["position"];
+	        this.department = source["department"];
+	        this.personnel_number = source["personnel_number"];
+	        this.contact = source["contact"];
+	        this.user_id = source["user_id"];
+	        this.user = this.convertValues(source["user"], User);
+	        this.equipment = this.convertValues(source["equipment"], Equipment);
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -580,17 +884,17 @@ export namespace model {
 		    return a;
 		}
 	}
-	export class EquipmentResponse {
-	    model?: Equipment;
+	export class EmployeeListResponse {
+	    model: Employee[];
 	    msg: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new EquipmentResponse(source);
+	        return new EmployeeListResponse(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.model = this.convertValues(source["model"], Equipment);
+	        this.model = this.convertValues(source["model"], Employee);
 	        this.msg = source["msg"];
 	    }
 	
@@ -612,18 +916,17 @@ export namespace model {
 		    return a;
 		}
 	}
-	
-	export class LocationListResponse {
-	    model: Location[];
+	export class EmployeeResponse {
+	    model?: Employee;
 	    msg: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new LocationListResponse(source);
+	        return new EmployeeResponse(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.model = this.convertValues(source["model"], Location);
+	        this.model = this.convertValues(source["model"], Employee);
 	        this.msg = source["msg"];
 	    }
 	
@@ -645,17 +948,17 @@ export namespace model {
 		    return a;
 		}
 	}
-	export class LocationResponse {
-	    model?: Location;
+	export class EquipmentListResponse {
+	    model: Equipment[];
 	    msg: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new LocationResponse(source);
+	        return new EquipmentListResponse(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.model = this.convertValues(source["model"], Location);
+	        this.model = this.convertValues(source["model"], Equipment);
 	        this.msg = source["msg"];
 	    }
 	
@@ -677,18 +980,18 @@ export namespace model {
 		    return a;
 		}
 	}
-	export class LoginResponse {
-	    user?: User;
-	    token: string;
+	export class EquipmentResponse {
+	    model?: Equipment;
+	    msg: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new LoginResponse(source);
+	        return new EquipmentResponse(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.user = this.convertValues(source["user"], User);
-	        this.token = source["token"];
+	        this.model = this.convertValues(source["model"], Equipment);
+	        this.msg = source["msg"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -709,19 +1012,33 @@ export namespace model {
 		    return a;
 		}
 	}
-	
-	export class MovementListResponse {
-	    model: Movement[];
-	    msg: string;
+	export class EquipmentStatusChange {
+	    id: number;
+	    equipment_id: number;
+	    from_status: string;
+	    to_status: string;
+	    reason: string;
+	    user_id: number;
+	    user?: User;
+	    document_id: number;
+	    // Go type: time
+	    created_at: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new MovementListResponse(source);
+	        return new EquipmentStatusChange(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.model = this.convertValues(source["model"], Movement);
-	        this.msg = source["msg"];
+	        this.id = source["id"];
+	        this.equipment_id = source["equipment_id"];
+	        this.from_status = source["from_status"];
+	        this.to_status = source["to_status"];
+	        this.reason = source["reason"];
+	        this.user_id = source["user_id"];
+	        this.user = this.convertValues(source["user"], User);
+	        this.document_id = source["document_id"];
+	        this.created_at = this.convertValues(source["created_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -742,17 +1059,17 @@ export namespace model {
 		    return a;
 		}
 	}
-	export class MovementResponse {
-	    model?: Movement;
+	export class EquipmentStatusHistoryResponse {
+	    model: EquipmentStatusChange[];
 	    msg: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new MovementResponse(source);
+	        return new EquipmentStatusHistoryResponse(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.model = this.convertValues(source["model"], Movement);
+	        this.model = this.convertValues(source["model"], EquipmentStatusChange);
 	        this.msg = source["msg"];
 	    }
 	
@@ -774,18 +1091,34 @@ export namespace model {
 		    return a;
 		}
 	}
-	export class Response__tohaboy_internal_model_User_ {
-	    model?: User;
-	    msg: string;
+	
+	export class ExchangeRate {
+	    id: number;
+	    currency: string;
+	    // Go type: time
+	    date: any;
+	    nominal: number;
+	    rate: number;
+	    source: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new Response__tohaboy_internal_model_User_(source);
+	        return new ExchangeRate(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.model = this.convertValues(source["model"], User);
-	        this.msg = source["msg"];
+	        this.id = source["id"];
+	        this.currency = source["currency"];
+	        this.date = this.convertValues(source["date"], null);
+	        this.nominal = source["nominal"];
+	        this.rate = source["rate"];
+	        this.source = source["source"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -806,7 +1139,1059 @@ export namespace model {
 		    return a;
 		}
 	}
+	export class ExchangeRateListResponse {
+	    model: ExchangeRate[];
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExchangeRateListResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], ExchangeRate);
+	        this.msg = source["msg"];
+	    }
 	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExchangeRateResponse {
+	    model?: ExchangeRate;
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExchangeRateResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], ExchangeRate);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportRequest {
+	    file_name: string;
+	    content: string;
+	    sheet: string;
+	    columns: Record<string, string>;
+	    location_id: number;
+	    create_document: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file_name = source["file_name"];
+	        this.content = source["content"];
+	        this.sheet = source["sheet"];
+	        this.columns = source["columns"];
+	        this.location_id = source["location_id"];
+	        this.create_document = source["create_document"];
+	    }
+	}
+	
+	export class ImportRow {
+	    row: number;
+	    equipment: Equipment;
+	    errors: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.equipment = this.convertValues(source["equipment"], Equipment);
+	        this.errors = source["errors"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportPreview {
+	    columns: Record<string, string>;
+	    rows: ImportRow[];
+	    valid: number;
+	    invalid: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.columns = source["columns"];
+	        this.rows = this.convertValues(source["rows"], ImportRow);
+	        this.valid = source["valid"];
+	        this.invalid = source["invalid"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportPreviewResponse {
+	    model?: ImportPreview;
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportPreviewResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], ImportPreview);
+	        this.msg = source["msg"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportResult {
+	    equipment: Equipment[];
+	    documents: Document[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.equipment = this.convertValues(source["equipment"], Equipment);
+	        this.documents = this.convertValues(source["documents"], Document);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportResultResponse {
+	    model?: ImportResult;
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResultResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], ImportResult);
+	        this.msg = source["msg"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class InventoryLine {
+	    equipment_id: number;
+	    name: string;
+	    serial_number: string;
+	    expected: number;
+	    actual: number;
+	    difference: number;
+	    price: number;
+	    amount: number;
+	    counted: boolean;
+	    unexpected: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new InventoryLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.equipment_id = source["equipment_id"];
+	        this.name = source["name"];
+	        this.serial_number = source["serial_number"];
+	        this.expected = source["expected"];
+	        this.actual = source["actual"];
+	        this.difference = source["difference"];
+	        this.price = source["price"];
+	        this.amount = source["amount"];
+	        this.counted = source["counted"];
+	        this.unexpected = source["unexpected"];
+	    }
+	}
+	export class InventoryDiscrepancies {
+	    document_id: number;
+	    number: string;
+	    status: string;
+	    lines: InventoryLine[];
+	    counted: number;
+	    uncounted: number;
+	    surpluses: number;
+	    shortages: number;
+	    surplus_quantity: number;
+	    shortage_quantity: number;
+	    surplus_amount: number;
+	    shortage_amount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new InventoryDiscrepancies(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.document_id = source["document_id"];
+	        this.number = source["number"];
+	        this.status = source["status"];
+	        this.lines = this.convertValues(source["lines"], InventoryLine);
+	        this.counted = source["counted"];
+	        this.uncounted = source["uncounted"];
+	        this.surpluses = source["surpluses"];
+	        this.shortages = source["shortages"];
+	        this.surplus_quantity = source["surplus_quantity"];
+	        this.shortage_quantity = source["shortage_quantity"];
+	        this.surplus_amount = source["surplus_amount"];
+	        this.shortage_amount = source["shortage_amount"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class InventoryDiscrepanciesResponse {
+	    model?: InventoryDiscrepancies;
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new InventoryDiscrepanciesResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], InventoryDiscrepancies);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LabelRequest {
+	    equipment_ids: number[];
+	    copies: number;
+	    skip: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LabelRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.equipment_ids = source["equipment_ids"];
+	        this.copies = source["copies"];
+	        this.skip = source["skip"];
+	    }
+	}
+	export class LabelSheetResponse {
+	    content: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LabelSheetResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.content = source["content"];
+	        this.message = source["message"];
+	    }
+	}
+	export class LocationListResponse {
+	    model: Location[];
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LocationListResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], Location);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LocationNode {
+	    id: number;
+	    name: string;
+	    description: string;
+	    address: string;
+	    kind: string;
+	    parent_id: number;
+	    path: string;
+	    full_name: string;
+	    equipment: Equipment[];
+	    from_movements: Movement[];
+	    to_movements: Movement[];
+	    depth: number;
+	    own_quantity: number;
+	    own_amount: number;
+	    quantity: number;
+	    amount: number;
+	    children: LocationNode[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LocationNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.address = source["address"];
+	        this.kind = source["kind"];
+	        this.parent_id = source["parent_id"];
+	        this.path = source["path"];
+	        this.full_name = source["full_name"];
+	        this.equipment = this.convertValues(source["equipment"], Equipment);
+	        this.from_movements = this.convertValues(source["from_movements"], Movement);
+	        this.to_movements = this.convertValues(source["to_movements"], Movement);
+	        this.depth = source["depth"];
+	        this.own_quantity = source["own_quantity"];
+	        this.own_amount = source["own_amount"];
+	        this.quantity = source["quantity"];
+	        this.amount = source["amount"];
+	        this.children = this.convertValues(source["children"], LocationNode);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LocationResponse {
+	    model?: Location;
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LocationResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], Location);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LocationTreeResponse {
+	    model: LocationNode[];
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LocationTreeResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], LocationNode);
+	        this.msg = source["msg"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LoginResponse {
+	    user?: User;
+	    token: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoginResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.user = this.convertValues(source["user"], User);
+	        this.token = source["token"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class MaintenanceDue {
+	    equipment: Equipment;
+	    interval_days: number;
+	    // Go type: time
+	    last_date?: any;
+	    // Go type: time
+	    due_date: any;
+	    overdue_days: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaintenanceDue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.equipment = this.convertValues(source["equipment"], Equipment);
+	        this.interval_days = source["interval_days"];
+	        this.last_date = this.convertValues(source["last_date"], null);
+	        this.due_date = this.convertValues(source["due_date"], null);
+	        this.overdue_days = source["overdue_days"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MaintenanceDueResponse {
+	    model: MaintenanceDue[];
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaintenanceDueResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], MaintenanceDue);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MaintenanceRecord {
+	    id: number;
+	    equipment_id: number;
+	    equipment?: Equipment;
+	    type: string;
+	    supplier_id: number;
+	    supplier?: Supplier;
+	    // Go type: time
+	    sent_at: any;
+	    // Go type: time
+	    returned_at?: any;
+	    cost: number;
+	    description: string;
+	    outcome: string;
+	    created_by_id: number;
+	    created_by?: User;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaintenanceRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.equipment_id = source["equipment_id"];
+	        this.equipment = this.convertValues(source["equipment"], Equipment);
+	        this.type = source["type"];
+	        this.supplier_id = source["supplier_id"];
+	        this.supplier = this.convertValues(source["supplier"], Supplier);
+	        this.sent_at = this.convertValues(source["sent_at"], null);
+	        this.returned_at = this.convertValues(source["returned_at"], null);
+	        this.cost = source["cost"];
+	        this.description = source["description"];
+	        this.outcome = source["outcome"];
+	        this.created_by_id = source["created_by_id"];
+	        this.created_by = this.convertValues(source["created_by"], User);
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MaintenanceListResponse {
+	    model: MaintenanceRecord[];
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaintenanceListResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], MaintenanceRecord);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MaintenanceResponse {
+	    model?: MaintenanceRecord;
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaintenanceResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], MaintenanceRecord);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MovementListResponse {
+	    model: Movement[];
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MovementListResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], Movement);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MovementResponse {
+	    model?: Movement;
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MovementResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], Movement);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReportFilter {
+	    date_from: string;
+	    date_to: string;
+	    category_id: number;
+	    location_id: number;
+	    supplier_id: number;
+	    status: string;
+	    format: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date_from = source["date_from"];
+	        this.date_to = source["date_to"];
+	        this.category_id = source["category_id"];
+	        this.location_id = source["location_id"];
+	        this.supplier_id = source["supplier_id"];
+	        this.status = source["status"];
+	        this.format = source["format"];
+	    }
+	}
+	export class ReportResponse {
+	    content: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.content = source["content"];
+	        this.message = source["message"];
+	    }
+	}
+	export class Response__tohaboy_internal_model_User_ {
+	    model?: User;
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Response__tohaboy_internal_model_User_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], User);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ScanBatch {
+	    document?: Document;
+	    added: number;
+	    unknown: string[];
+	    errors: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanBatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.document = this.convertValues(source["document"], Document);
+	        this.added = source["added"];
+	        this.unknown = source["unknown"];
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScanBatchResponse {
+	    model?: ScanBatch;
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanBatchResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], ScanBatch);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScanResult {
+	    code: string;
+	    equipment?: Equipment;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.equipment = this.convertValues(source["equipment"], Equipment);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScanResponse {
+	    model?: ScanResult;
+	    msg: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = this.convertValues(source["model"], ScanResult);
+	        this.msg = source["msg"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class SupplierListResponse {
 	    model: Supplier[];
 	    msg: string;
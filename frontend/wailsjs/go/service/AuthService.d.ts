@@ -2,6 +2,12 @@
 // This file is automatically generated. DO NOT EDIT
 import {model} from '../models';
 
+export function Authenticate(arg1:string):Promise<model.User>;
+
 export function Login(arg1:Record<string, string>):Promise<model.LoginResponse>;
 
+export function Logout():Promise<void>;
+
 export function Register(arg1:Record<string, string>):Promise<model.User>;
+
+export function SignupAllowed():Promise<boolean>;
@@ -2,10 +2,22 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function Authenticate(arg1) {
+  return window['go']['service']['AuthService']['Authenticate'](arg1);
+}
+
 export function Login(arg1) {
   return window['go']['service']['AuthService']['Login'](arg1);
 }
 
+export function Logout() {
+  return window['go']['service']['AuthService']['Logout']();
+}
+
 export function Register(arg1) {
   return window['go']['service']['AuthService']['Register'](arg1);
 }
+
+export function SignupAllowed() {
+  return window['go']['service']['AuthService']['SignupAllowed']();
+}
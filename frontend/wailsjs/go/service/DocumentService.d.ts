@@ -12,6 +12,8 @@ export function ExportDocument(arg1:number):Promise<model.DocumentExportResponse
 
 export function ExportDocumentGOST(arg1:number):Promise<model.DocumentExportResponse>;
 
+export function ExportDocumentPDF(arg1:number):Promise<model.DocumentExportResponse>;
+
 export function GetAllDocuments():Promise<model.DocumentListResponse>;
 
 export function GetDocument(arg1:number):Promise<model.DocumentResponse>;
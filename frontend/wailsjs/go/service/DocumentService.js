@@ -22,6 +22,10 @@ export function ExportDocumentGOST(arg1) {
   return window['go']['service']['DocumentService']['ExportDocumentGOST'](arg1);
 }
 
+export function ExportDocumentPDF(arg1) {
+  return window['go']['service']['DocumentService']['ExportDocumentPDF'](arg1);
+}
+
 export function GetAllDocuments() {
   return window['go']['service']['DocumentService']['GetAllDocuments']();
 }
@@ -14,4 +14,12 @@ export function GetEquipmentByLocation(arg1:number):Promise<model.EquipmentListR
 
 export function GetEquipmentBySupplier(arg1:number):Promise<model.EquipmentListResponse>;
 
+export function GetStatusHistory(arg1:number):Promise<model.EquipmentStatusHistoryResponse>;
+
+export function IssueEquipment(arg1:number,arg2:string):Promise<model.EquipmentResponse>;
+
+export function ReturnEquipment(arg1:number,arg2:string):Promise<model.EquipmentResponse>;
+
+export function SendToRepair(arg1:number,arg2:string):Promise<model.EquipmentResponse>;
+
 export function UpdateEquipment(arg1:model.Equipment):Promise<model.EquipmentResponse>;
@@ -26,6 +26,22 @@ export function GetEquipmentBySupplier(arg1) {
   return window['go']['service']['EquipmentService']['GetEquipmentBySupplier'](arg1);
 }
 
+export function GetStatusHistory(arg1) {
+  return window['go']['service']['EquipmentService']['GetStatusHistory'](arg1);
+}
+
+export function IssueEquipment(arg1, arg2) {
+  return window['go']['service']['EquipmentService']['IssueEquipment'](arg1, arg2);
+}
+
+export function ReturnEquipment(arg1, arg2) {
+  return window['go']['service']['EquipmentService']['ReturnEquipment'](arg1, arg2);
+}
+
+export function SendToRepair(arg1, arg2) {
+  return window['go']['service']['EquipmentService']['SendToRepair'](arg1, arg2);
+}
+
 export function UpdateEquipment(arg1) {
   return window['go']['service']['EquipmentService']['UpdateEquipment'](arg1);
 }
@@ -12,4 +12,10 @@ export function GetLocation(arg1:number):Promise<model.LocationResponse>;
 
 export function GetLocationByEquipment(arg1:number):Promise<model.LocationListResponse>;
 
+export function GetLocationPath(arg1:number):Promise<model.LocationListResponse>;
+
+export function GetLocationTree():Promise<model.LocationTreeResponse>;
+
+export function MoveLocation(arg1:number,arg2:number):Promise<model.LocationResponse>;
+
 export function UpdateLocation(arg1:model.Location):Promise<model.LocationResponse>;
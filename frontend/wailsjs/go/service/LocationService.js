@@ -22,6 +22,18 @@ export function GetLocationByEquipment(arg1) {
   return window['go']['service']['LocationService']['GetLocationByEquipment'](arg1);
 }
 
+export function GetLocationPath(arg1) {
+  return window['go']['service']['LocationService']['GetLocationPath'](arg1);
+}
+
+export function GetLocationTree() {
+  return window['go']['service']['LocationService']['GetLocationTree']();
+}
+
+export function MoveLocation(arg1, arg2) {
+  return window['go']['service']['LocationService']['MoveLocation'](arg1, arg2);
+}
+
 export function UpdateLocation(arg1) {
   return window['go']['service']['LocationService']['UpdateLocation'](arg1);
 }
@@ -0,0 +1,124 @@
+// Package barcode кодирует строки в штрихкод Code128 (ГОСТ 30743, ISO/IEC 15417).
+// Результат - последовательность модулей, которую вызывающий код рисует
+// в нужном масштабе: прямоугольниками в PDF или пикселями в изображении.
+package barcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotEncodable символ строки не входит в наборы Code128 (кириллица, управляющие символы)
+var ErrNotEncodable = errors.New("не кодируется Code128")
+
+// code128Patterns ширины штрихов и пробелов (в модулях) символов Code128 по значениям 0..106.
+// Каждый символ начинается со штриха; стоп-символ 106 состоит из семи элементов.
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// Служебные символы Code128
+const (
+	codeC  = 99
+	codeB  = 100
+	startB = 104
+	startC = 105
+	stop   = 106
+)
+
+// QuietZone ширина свободного поля слева и справа от штрихкода в модулях
+const QuietZone = 10
+
+// Code128 кодирует text и возвращает модули штрихкода без свободных полей:
+// true - штрих, false - пробел. Печатные символы ASCII кодируются набором B,
+// серии цифр - вдвое компактнее набором C. Кириллица и управляющие символы
+// в Code128 не кодируются.
+func Code128(text string) ([]bool, error) {
+	if text == "" {
+		return nil, fmt.Errorf("пустая строка")
+	}
+	for _, r := range text {
+		if r < 32 || r > 126 {
+			return nil, fmt.Errorf("символ %q %w", r, ErrNotEncodable)
+		}
+	}
+
+	var values []int
+	set := 0
+	for i := 0; i < len(text); {
+		// Набор C выгоден для серии из 4 цифр в начале или конце строки и из 6 цифр в середине
+		digits := digitRun(text[i:])
+		useC := digits >= 6 || (digits >= 4 && (i == 0 || i+digits == len(text))) || (digits == 2 && len(text) == 2)
+		if useC && digits%2 == 1 {
+			// нечетная первая цифра кодируется набором B, остальные - парами в наборе C
+			values, set = appendB(values, set, text[i])
+			i++
+			digits--
+		}
+		if useC {
+			switch set {
+			case 0:
+				values = append(values, startC)
+			case codeB:
+				values = append(values, codeC)
+			}
+			set = codeC
+			for n := digits; n > 0; n -= 2 {
+				values = append(values, int(text[i]-'0')*10+int(text[i+1]-'0'))
+				i += 2
+			}
+			continue
+		}
+
+		values, set = appendB(values, set, text[i])
+		i++
+	}
+
+	checksum := values[0]
+	for i := 1; i < len(values); i++ {
+		checksum += values[i] * i
+	}
+	values = append(values, checksum%103, stop)
+
+	var modules []bool
+	for _, value := range values {
+		for i, width := range code128Patterns[value] {
+			bar := i%2 == 0
+			for n := 0; n < int(width-'0'); n++ {
+				modules = append(modules, bar)
+			}
+		}
+	}
+	return modules, nil
+}
+
+// appendB добавляет символ набора B, при необходимости начиная штрихкод
+// или переключаясь на набор B
+func appendB(values []int, set int, c byte) ([]int, int) {
+	switch set {
+	case 0:
+		values = append(values, startB)
+	case codeC:
+		values = append(values, codeB)
+	}
+	return append(values, int(c)-32), codeB
+}
+
+// digitRun возвращает количество цифр в начале строки
+func digitRun(text string) int {
+	n := 0
+	for n < len(text) && text[n] >= '0' && text[n] <= '9' {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,107 @@
+package barcode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// decode переводит модули штрихкода обратно в значения символов Code128
+func decode(t *testing.T, modules []bool) []int {
+	t.Helper()
+	values := make(map[string]int, len(code128Patterns))
+	for value, pattern := range code128Patterns {
+		values[pattern] = value
+	}
+
+	var result []int
+	for i := 0; i < len(modules); {
+		var pattern strings.Builder
+		elements := 6
+		if len(modules)-i == 13 {
+			elements = 7 // стоп-символ
+		}
+		for e := 0; e < elements; e++ {
+			if i >= len(modules) || modules[i] != (e%2 == 0) {
+				t.Fatalf("символ %d: элемент %d не на своем месте", len(result), e)
+			}
+			width := 0
+			for i < len(modules) && modules[i] == (e%2 == 0) {
+				width++
+				i++
+			}
+			fmt.Fprint(&pattern, width)
+		}
+		value, ok := values[pattern.String()]
+		if !ok {
+			t.Fatalf("символ %d: неизвестный рисунок %s", len(result), pattern.String())
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
+func TestCode128(t *testing.T) {
+	tests := []struct {
+		text string
+		// Значения символов без контрольного и стоп-символа
+		values   []int
+		checksum int
+	}{
+		{"A", []int{startB, 33}, 34},
+		{"a~ ", []int{startB, 65, 94, 0}, 48},
+		// Две цифры целиком - набор C
+		{"12", []int{startC, 12}, 14},
+		{"1234", []int{startC, 12, 34}, 82},
+		// Нечетная серия: первая цифра набором B, остальные парами
+		{"12345", []int{startB, 17, codeC, 23, 45}, 53},
+		// Серия из 4 цифр в конце и в начале строки
+		{"A1234", []int{startB, 33, codeC, 12, 34}, 95},
+		{"1234A", []int{startC, 12, 34, codeB, 33}, 102},
+		// В середине набор C выгоден только с 6 цифр
+		{"A1234B", []int{startB, 33, 17, 18, 19, 20, 34}, 90},
+		{"AB123456", []int{startB, 33, 34, codeC, 12, 34, 56}, 26},
+		{"A1234567B", []int{startB, 33, 17, codeC, 23, 45, 67, codeB, 34}, 99},
+		// Короткие серии цифр остаются в наборе B
+		{"A12", []int{startB, 33, 17, 18}, 19},
+		{"INV-000123", []int{startB, 41, 46, 54, 13, codeC, 0, 1, 23}, 4},
+	}
+	for _, tt := range tests {
+		modules, err := Code128(tt.text)
+		if err != nil {
+			t.Errorf("Code128(%q): %v", tt.text, err)
+			continue
+		}
+
+		want := append(append([]int{}, tt.values...), tt.checksum, stop)
+		if n := 11*(len(want)-1) + 13; len(modules) != n {
+			t.Errorf("Code128(%q): модулей %d, ожидалось %d", tt.text, len(modules), n)
+			continue
+		}
+		got := decode(t, modules)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("Code128(%q) = %v, ожидалось %v", tt.text, got, want)
+		}
+
+		// Контрольный символ: стартовый плюс сумма значений, умноженных на позицию, по модулю 103
+		sum := got[0]
+		for i := 1; i < len(got)-2; i++ {
+			sum += got[i] * i
+		}
+		if sum%103 != got[len(got)-2] {
+			t.Errorf("Code128(%q): контрольный символ %d, по сумме %d", tt.text, got[len(got)-2], sum%103)
+		}
+	}
+}
+
+func TestCode128Errors(t *testing.T) {
+	if _, err := Code128(""); err == nil || errors.Is(err, ErrNotEncodable) {
+		t.Errorf("Code128(\"\"): ошибка %v, ожидалась ошибка пустой строки", err)
+	}
+	for _, text := range []string{"Инв-1", "A\tB", "\x7f", "№1"} {
+		if _, err := Code128(text); !errors.Is(err, ErrNotEncodable) {
+			t.Errorf("Code128(%q): ошибка %v, ожидалась ErrNotEncodable", text, err)
+		}
+	}
+}
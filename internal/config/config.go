@@ -0,0 +1,407 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	appDir     = "tohaboy"
+	fileName   = "config.yaml"
+	envPrefix  = "INVENT"
+	secretSize = 32
+)
+
+// Режимы заполнения пустой базы
+const (
+	SeedNone  = "none"  // база остается пустой
+	SeedAdmin = "admin" // создается только администратор
+	SeedDemo  = "demo"  // администратор и демонстрационные данные
+)
+
+// Форматы экспорта документов
+const (
+	ExportStandard = "standard"
+	ExportGOST     = "gost"
+)
+
+// Правила сброса нумерации документов
+const (
+	ResetNever   = "never"   // сквозная нумерация
+	ResetYearly  = "yearly"  // нумерация начинается заново каждый год
+	ResetMonthly = "monthly" // нумерация начинается заново каждый месяц
+)
+
+// DefaultNumberFormat шаблон номера документа по умолчанию, например "ИНВ-2024-001"
+const DefaultNumberFormat = "{prefix}-{yyyy}-{seq:000}"
+
+// EquipmentSequence ключ правила нумерации в numbering.sequences, по которому
+// оборудованию выдаются инвентарные номера
+const EquipmentSequence = "equipment"
+
+// DefaultInventoryNumberFormat шаблон инвентарного номера по умолчанию, например "00000042".
+// Номер из одних цифр компактно кодируется штрихкодом Code128.
+const DefaultInventoryNumberFormat = "{seq:00000000}"
+
+// documentPrefixes префиксы номеров документов по умолчанию
+var documentPrefixes = map[string]string{
+	"inventory":  "ИНВ",
+	"transfer":   "ПЕР",
+	"write_off":  "СПС",
+	"acceptance": "ПРМ",
+}
+
+// Config настройки приложения. Значения читаются из config.yaml в каталоге
+// настроек пользователя и переопределяются переменными окружения вида
+// INVENT_<РАЗДЕЛ>_<КЛЮЧ>, например INVENT_DATABASE_PATH.
+type Config struct {
+	Database     DatabaseConfig     `mapstructure:"database"`
+	Auth         AuthConfig         `mapstructure:"auth"`
+	Window       WindowConfig       `mapstructure:"window"`
+	Export       ExportConfig       `mapstructure:"export"`
+	Organization OrganizationConfig `mapstructure:"organization"`
+	Numbering    NumberingConfig    `mapstructure:"numbering"`
+
+	// File путь к файлу, из которого загружены настройки
+	File string `mapstructure:"-"`
+}
+
+type DatabaseConfig struct {
+	// Path путь к файлу SQLite; относительный путь считается от каталога настроек
+	Path string `mapstructure:"path"`
+	// Reset удаляет все данные и создает схему заново при запуске
+	Reset bool `mapstructure:"reset"`
+	// Seed режим заполнения пустой базы: none, admin или demo
+	Seed string `mapstructure:"seed"`
+}
+
+type AuthConfig struct {
+	// SecretKey ключ подписи токенов, генерируется при первом запуске
+	SecretKey string `mapstructure:"secret_key"`
+	// TokenTTL срок действия токена сессии
+	TokenTTL time.Duration `mapstructure:"token_ttl"`
+	// AdminPassword пароль администратора, создаваемого в пустой базе
+	AdminPassword string `mapstructure:"admin_password"`
+	// AllowSignup разрешает регистрацию без входа администратора (только с ролью аудитора)
+	AllowSignup bool `mapstructure:"allow_signup"`
+}
+
+type WindowConfig struct {
+	Width  int `mapstructure:"width"`
+	Height int `mapstructure:"height"`
+}
+
+type ExportConfig struct {
+	// Format формат, в котором ExportDocument выгружает документы: standard или gost
+	Format string `mapstructure:"format"`
+	// Unit единица измерения в печатных формах для оборудования, в категории которого она не задана
+	Unit string `mapstructure:"unit"`
+}
+
+// OrganizationConfig реквизиты организации для печатных форм
+type OrganizationConfig struct {
+	Name       string `mapstructure:"name"`
+	INN        string `mapstructure:"inn"`
+	KPP        string `mapstructure:"kpp"`
+	OKPO       string `mapstructure:"okpo"`
+	Address    string `mapstructure:"address"`
+	Head       string `mapstructure:"head"`
+	Accountant string `mapstructure:"accountant"`
+}
+
+// NumberingConfig правила нумерации документов по их типам и инвентарных
+// номеров оборудования (ключ EquipmentSequence)
+type NumberingConfig struct {
+	Sequences map[string]SequenceConfig `mapstructure:"sequences"`
+}
+
+// SequenceConfig правило нумерации документов одного типа.
+// Format - шаблон номера, в котором подставляются:
+//
+//	{prefix} - префикс
+//	{yyyy}, {yy}, {mm}, {dd} - год, год двумя цифрами, месяц и день даты документа
+//	{location} - ID местоположения документа
+//	{seq} - порядковый номер; {seq:000} дополняет его нулями до трех цифр
+type SequenceConfig struct {
+	Prefix string `mapstructure:"prefix"`
+	Format string `mapstructure:"format"`
+	// Reset правило сброса счетчика: never, yearly или monthly
+	Reset string `mapstructure:"reset"`
+	// PerLocation ведет отдельный счетчик для каждого местоположения
+	PerLocation bool `mapstructure:"per_location"`
+}
+
+var numberToken = regexp.MustCompile(`\{(\w+)(?::(0+))?\}`)
+
+// Period возвращает период нумерации, к которому относится дата
+func (c SequenceConfig) Period(date time.Time) string {
+	switch c.Reset {
+	case ResetYearly:
+		return date.Format("2006")
+	case ResetMonthly:
+		return date.Format("2006-01")
+	}
+	return ""
+}
+
+// Render собирает номер документа по шаблону
+func (c SequenceConfig) Render(seq uint, date time.Time, locationID uint) string {
+	return numberToken.ReplaceAllStringFunc(c.Format, func(token string) string {
+		parts := numberToken.FindStringSubmatch(token)
+		switch parts[1] {
+		case "prefix":
+			return c.Prefix
+		case "yyyy":
+			return date.Format("2006")
+		case "yy":
+			return date.Format("06")
+		case "mm":
+			return date.Format("01")
+		case "dd":
+			return date.Format("02")
+		case "location":
+			return fmt.Sprint(locationID)
+		case "seq":
+			return fmt.Sprintf("%0*d", len(parts[2]), seq)
+		}
+		return token
+	})
+}
+
+// Validate проверяет, что шаблон номера не дает повторов при выбранном правиле сброса
+func (c SequenceConfig) Validate() error {
+	tokens := make(map[string]bool)
+	for _, parts := range numberToken.FindAllStringSubmatch(c.Format, -1) {
+		switch parts[1] {
+		case "prefix", "yyyy", "yy", "mm", "dd", "location", "seq":
+			tokens[parts[1]] = true
+		default:
+			return fmt.Errorf("неизвестная подстановка %s в шаблоне %q", parts[0], c.Format)
+		}
+	}
+
+	if !tokens["seq"] {
+		return fmt.Errorf("шаблон %q не содержит порядковый номер {seq}", c.Format)
+	}
+	hasYear := tokens["yyyy"] || tokens["yy"]
+	switch c.Reset {
+	case ResetNever:
+	case ResetYearly:
+		if !hasYear {
+			return fmt.Errorf("при ежегодном сбросе шаблон %q должен содержать год {yyyy} или {yy}", c.Format)
+		}
+	case ResetMonthly:
+		if !hasYear || !tokens["mm"] {
+			return fmt.Errorf("при ежемесячном сбросе шаблон %q должен содержать год и месяц {mm}", c.Format)
+		}
+	default:
+		return fmt.Errorf("неизвестное правило сброса %q (допустимо: never, yearly, monthly)", c.Reset)
+	}
+	if c.PerLocation && !tokens["location"] {
+		return fmt.Errorf("при раздельной нумерации шаблон %q должен содержать {location}", c.Format)
+	}
+	return nil
+}
+
+// Load читает настройки из файла path. Если path пуст, используется
+// переменная INVENT_CONFIG или config.yaml в каталоге настроек пользователя.
+// При первом запуске файл создается со значениями по умолчанию и новым
+// ключом подписи токенов.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		var err error
+		if path, err = defaultPath(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := writeDefaults(path); err != nil {
+			return nil, fmt.Errorf("создание файла настроек: %v", err)
+		}
+	}
+
+	v := viper.New()
+	setDefaults(v)
+	v.SetConfigFile(path)
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("чтение файла настроек %s: %v", path, err)
+	}
+
+	cfg := &Config{File: path}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("разбор файла настроек %s: %v", path, err)
+	}
+
+	// Файл мог быть создан вручную без ключа - генерируем и сохраняем его
+	if cfg.Auth.SecretKey == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		if err := saveSecret(path, secret); err != nil {
+			return nil, fmt.Errorf("сохранение ключа подписи: %v", err)
+		}
+		cfg.Auth.SecretKey = secret
+	}
+
+	if cfg.Database.Path != ":memory:" && !filepath.IsAbs(cfg.Database.Path) {
+		cfg.Database.Path = filepath.Join(filepath.Dir(path), cfg.Database.Path)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("неверные настройки в %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate проверяет значения настроек
+func (c *Config) Validate() error {
+	if c.Database.Path == "" {
+		return fmt.Errorf("database.path: путь к базе данных не указан")
+	}
+	switch c.Database.Seed {
+	case SeedNone, SeedAdmin, SeedDemo:
+	default:
+		return fmt.Errorf("database.seed: неизвестный режим %q (допустимо: none, admin, demo)", c.Database.Seed)
+	}
+
+	if len(c.Auth.SecretKey) < secretSize {
+		return fmt.Errorf("auth.secret_key: ключ должен быть не короче %d символов", secretSize)
+	}
+	if c.Auth.TokenTTL < time.Minute {
+		return fmt.Errorf("auth.token_ttl: срок действия токена должен быть не меньше минуты")
+	}
+	if c.Database.Seed != SeedNone && c.Auth.AdminPassword == "" {
+		return fmt.Errorf("auth.admin_password: пароль администратора не указан")
+	}
+
+	if c.Window.Width < 800 || c.Window.Height < 600 {
+		return fmt.Errorf("window: размер окна должен быть не меньше 800x600")
+	}
+
+	switch c.Export.Format {
+	case ExportStandard, ExportGOST:
+	default:
+		return fmt.Errorf("export.format: неизвестный формат %q (допустимо: standard, gost)", c.Export.Format)
+	}
+	if c.Export.Unit == "" {
+		return fmt.Errorf("export.unit: единица измерения не указана")
+	}
+
+	for docType := range documentPrefixes {
+		if _, ok := c.Numbering.Sequences[docType]; !ok {
+			return fmt.Errorf("numbering.sequences.%s: нумерация не настроена", docType)
+		}
+	}
+	if _, ok := c.Numbering.Sequences[EquipmentSequence]; !ok {
+		return fmt.Errorf("numbering.sequences.%s: нумерация не настроена", EquipmentSequence)
+	}
+	for docType, sequence := range c.Numbering.Sequences {
+		if err := sequence.Validate(); err != nil {
+			return fmt.Errorf("numbering.sequences.%s: %v", docType, err)
+		}
+	}
+
+	return nil
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("database.path", "invent.db")
+	v.SetDefault("database.reset", false)
+	v.SetDefault("database.seed", SeedDemo)
+	v.SetDefault("auth.secret_key", "")
+	v.SetDefault("auth.token_ttl", "24h")
+	v.SetDefault("auth.admin_password", "admin")
+	v.SetDefault("auth.allow_signup", false)
+	v.SetDefault("window.width", 1024)
+	v.SetDefault("window.height", 768)
+	v.SetDefault("export.format", ExportStandard)
+	v.SetDefault("export.unit", "шт.")
+	v.SetDefault("organization.name", "")
+	v.SetDefault("organization.inn", "")
+	v.SetDefault("organization.kpp", "")
+	v.SetDefault("organization.okpo", "")
+	v.SetDefault("organization.address", "")
+	v.SetDefault("organization.head", "")
+	v.SetDefault("organization.accountant", "")
+	for docType, prefix := range documentPrefixes {
+		key := "numbering.sequences." + docType
+		v.SetDefault(key+".prefix", prefix)
+		v.SetDefault(key+".format", DefaultNumberFormat)
+		v.SetDefault(key+".reset", ResetYearly)
+		v.SetDefault(key+".per_location", false)
+	}
+	key := "numbering.sequences." + EquipmentSequence
+	v.SetDefault(key+".prefix", "")
+	v.SetDefault(key+".format", DefaultInventoryNumberFormat)
+	v.SetDefault(key+".reset", ResetNever)
+	v.SetDefault(key+".per_location", false)
+}
+
+func defaultPath() (string, error) {
+	if path := os.Getenv(envPrefix + "_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("каталог настроек пользователя не найден: %v", err)
+	}
+	return filepath.Join(dir, appDir, fileName), nil
+}
+
+// writeDefaults создает файл настроек со значениями по умолчанию.
+// Переменные окружения в файл не попадают.
+func writeDefaults(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return err
+	}
+
+	v := viper.New()
+	setDefaults(v)
+	v.Set("auth.secret_key", secret)
+	if err := v.WriteConfigAs(path); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
+
+// saveSecret дописывает ключ подписи в существующий файл, не меняя остальных значений
+func saveSecret(path, secret string) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return err
+	}
+	v.Set("auth.secret_key", secret)
+	if err := v.WriteConfigAs(path); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, secretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("генерация ключа подписи: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSequenceRender(t *testing.T) {
+	date := time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		format   string
+		seq      uint
+		location uint
+		want     string
+	}{
+		{DefaultNumberFormat, 1, 0, "ПРМ-2024-001"},
+		{DefaultNumberFormat, 1234, 0, "ПРМ-2024-1234"},
+		{"{prefix}/{yy}{mm}{dd}/{seq}", 42, 0, "ПРМ/240307/42"},
+		{"{prefix}-{location}-{seq:00000}", 7, 12, "ПРМ-12-00007"},
+		{"№ {seq:0}", 5, 0, "№ 5"},
+		{"{seq}-{seq:00}", 3, 0, "3-03"},
+	}
+	for _, tt := range tests {
+		rule := SequenceConfig{Prefix: "ПРМ", Format: tt.format}
+		if got := rule.Render(tt.seq, date, tt.location); got != tt.want {
+			t.Errorf("Render(%q, %d) = %q, want %q", tt.format, tt.seq, got, tt.want)
+		}
+	}
+}
+
+func TestSequencePeriod(t *testing.T) {
+	december := time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC)
+	january := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		reset          string
+		before, after  string
+		sharesSequence bool
+	}{
+		{ResetNever, "", "", true},
+		{ResetYearly, "2024", "2025", false},
+		{ResetMonthly, "2024-12", "2025-01", false},
+	}
+	for _, tt := range tests {
+		rule := SequenceConfig{Reset: tt.reset}
+		before, after := rule.Period(december), rule.Period(january)
+		if before != tt.before || after != tt.after {
+			t.Errorf("%s: периоды %q и %q, want %q и %q", tt.reset, before, after, tt.before, tt.after)
+		}
+		if (before == after) != tt.sharesSequence {
+			t.Errorf("%s: счетчик на рубеже года общий = %v", tt.reset, before == after)
+		}
+	}
+
+	// Ежемесячный сброс разделяет месяцы одного года
+	rule := SequenceConfig{Reset: ResetMonthly}
+	if rule.Period(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)) == rule.Period(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("monthly: январь и февраль в одном периоде")
+	}
+}
+
+func TestSequenceValidate(t *testing.T) {
+	tests := []struct {
+		rule SequenceConfig
+		err  string // пусто - правило допустимо
+	}{
+		{SequenceConfig{Format: DefaultNumberFormat, Reset: ResetYearly}, ""},
+		{SequenceConfig{Format: "{prefix}-{seq}", Reset: ResetNever}, ""},
+		{SequenceConfig{Format: "{prefix}-{yy}{mm}-{seq:000}", Reset: ResetMonthly}, ""},
+		{SequenceConfig{Format: "{prefix}-{location}-{seq}", Reset: ResetNever, PerLocation: true}, ""},
+		{SequenceConfig{Format: "{prefix}-{yyyy}", Reset: ResetYearly}, "не содержит порядковый номер {seq}"},
+		{SequenceConfig{Format: "{prefix}-{seq}", Reset: ResetYearly}, "при ежегодном сбросе"},
+		{SequenceConfig{Format: "{prefix}-{yyyy}-{seq}", Reset: ResetMonthly}, "при ежемесячном сбросе"},
+		{SequenceConfig{Format: "{prefix}-{mm}-{seq}", Reset: ResetMonthly}, "при ежемесячном сбросе"},
+		{SequenceConfig{Format: "{prefix}-{seq}", Reset: ResetNever, PerLocation: true}, "при раздельной нумерации"},
+		{SequenceConfig{Format: "{prefix}-{seq}", Reset: "weekly"}, "неизвестное правило сброса"},
+		{SequenceConfig{Format: "{prefix}-{week}-{seq}", Reset: ResetNever}, "неизвестная подстановка {week}"},
+	}
+	for _, tt := range tests {
+		err := tt.rule.Validate()
+		if tt.err == "" {
+			if err != nil {
+				t.Errorf("%q (%s): %v", tt.rule.Format, tt.rule.Reset, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%q (%s): ошибка %v, want %q", tt.rule.Format, tt.rule.Reset, err, tt.err)
+		}
+	}
+}
+
+func TestLoadNumbering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, docType := range []string{"acceptance", "write_off", "transfer", "inventory", EquipmentSequence} {
+		rule, ok := cfg.Numbering.Sequences[docType]
+		if !ok {
+			t.Errorf("нет правила нумерации %s по умолчанию", docType)
+			continue
+		}
+		if err := rule.Validate(); err != nil {
+			t.Errorf("правило %s по умолчанию: %v", docType, err)
+		}
+	}
+
+	// Правило без {seq} в файле настроек не принимается
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken := strings.Replace(string(data), `format: '{prefix}-{yyyy}-{seq:000}'`, `format: '{prefix}-{yyyy}'`, 1)
+	if broken == string(data) {
+		t.Fatalf("шаблон номера не найден в файле настроек:\n%s", data)
+	}
+	if err := os.WriteFile(path, []byte(broken), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "не содержит порядковый номер") {
+		t.Errorf("Load с шаблоном без {seq}: %v", err)
+	}
+}
@@ -7,28 +7,32 @@ import (
 	"gorm.io/gorm"
 )
 
-// GetCategories возвращает список категорий оборудования
+// GetCategories возвращает список категорий оборудования: ноутбуки и планшеты
+// вложены в компьютерную технику и наследуют ее амортизационную группу
 func GetCategories() []model.Category {
 	return []model.Category{
-		{ID: 1, Name: "Компьютерная техника", Description: "Компьютеры, ноутбуки, серверы и комплектующие"},
-		{ID: 2, Name: "Сетевое оборудование", Description: "Маршрутизаторы, коммутаторы, точки доступа"},
-		{ID: 3, Name: "Офисная техника", Description: "Принтеры, сканеры, МФУ"},
-		{ID: 4, Name: "Мебель", Description: "Столы, стулья, шкафы"},
-		{ID: 5, Name: "Инструменты", Description: "Ручной и электрический инструмент"},
-		{ID: 6, Name: "Программное обеспечение", Description: "Лицензии на ПО"},
-		{ID: 7, Name: "Телекоммуникационное оборудование", Description: "Телефоны, видеоконференцсвязь"},
-		{ID: 8, Name: "Системы безопасности", Description: "Камеры, СКУД, сигнализация"},
+		{ID: 1, Name: "Компьютерная техника", Description: "Компьютеры, ноутбуки, серверы и комплектующие", Path: "/1/",
+			CategoryDefaults: model.CategoryDefaults{DepreciationGroup: 2, Unit: "шт."}},
+		{ID: 2, Name: "Сетевое оборудование", Description: "Маршрутизаторы, коммутаторы, точки доступа", Path: "/2/"},
+		{ID: 3, Name: "Офисная техника", Description: "Принтеры, сканеры, МФУ", Path: "/3/"},
+		{ID: 4, Name: "Мебель", Description: "Столы, стулья, шкафы", Path: "/4/"},
+		{ID: 5, Name: "Инструменты", Description: "Ручной и электрический инструмент", Path: "/5/"},
+		{ID: 6, Name: "Программное обеспечение", Description: "Лицензии на ПО", Path: "/6/"},
+		{ID: 7, Name: "Телекоммуникационное оборудование", Description: "Телефоны, видеоконференцсвязь", Path: "/7/"},
+		{ID: 8, Name: "Системы безопасности", Description: "Камеры, СКУД, сигнализация", Path: "/8/"},
+		{ID: 9, Name: "Ноутбуки и планшеты", Description: "Переносные компьютеры", ParentID: 1, Path: "/1/9/"},
 	}
 }
 
-// GetLocations возвращает список местоположений
+// GetLocations возвращает список местоположений: помещения главного офиса
+// вложены в здание
 func GetLocations() []model.Location {
 	return []model.Location{
-		{ID: 1, Name: "Главный офис", Description: "Основное здание компании", Address: "ул. Ленина, 1"},
-		{ID: 2, Name: "Серверная", Description: "Серверное помещение", Address: "ул. Ленина, 1"},
-		{ID: 3, Name: "Склад", Description: "Основной склад", Address: "ул. Складская, 5"},
-		{ID: 4, Name: "Офис разработки", Description: "Отдел разработки", Address: "ул. Ленина, 1"},
-		{ID: 5, Name: "Бухгалтерия", Description: "Бухгалтерия и финансовый отдел", Address: "ул. Ленина, 1"},
+		{ID: 1, Name: "Главный офис", Description: "Основное здание компании", Address: "ул. Ленина, 1", Kind: model.LocationBuilding, Path: "/1/"},
+		{ID: 2, Name: "Серверная", Description: "Серверное помещение", Address: "ул. Ленина, 1", Kind: model.LocationRoom, ParentID: 1, Path: "/1/2/"},
+		{ID: 3, Name: "Склад", Description: "Основной склад", Address: "ул. Складская, 5", Kind: model.LocationBuilding, Path: "/3/"},
+		{ID: 4, Name: "Офис разработки", Description: "Отдел разработки", Address: "ул. Ленина, 1", Kind: model.LocationRoom, ParentID: 1, Path: "/1/4/"},
+		{ID: 5, Name: "Бухгалтерия", Description: "Бухгалтерия и финансовый отдел", Address: "ул. Ленина, 1", Kind: model.LocationRoom, ParentID: 1, Path: "/1/5/"},
 	}
 }
 
@@ -43,6 +47,15 @@ func GetSuppliers() []model.Supplier {
 	}
 }
 
+// GetEmployees возвращает список сотрудников
+func GetEmployees() []model.Employee {
+	return []model.Employee{
+		{ID: 1, Name: "Иванов Иван Иванович", Position: "Системный администратор", Department: "ИТ-отдел", PersonnelNumber: "0001", Contact: "+7 (900) 100-00-01"},
+		{ID: 2, Name: "Петрова Анна Сергеевна", Position: "Заведующая складом", Department: "Склад", PersonnelNumber: "0002", Contact: "+7 (900) 100-00-02"},
+		{ID: 3, Name: "Сидоров Павел Андреевич", Position: "Инженер", Department: "Серверная", PersonnelNumber: "0003", Contact: "+7 (900) 100-00-03"},
+	}
+}
+
 // GetEquipment возвращает список оборудования
 func GetEquipment() []model.Equipment {
 	return []model.Equipment{
@@ -53,8 +66,8 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "DELL-2023-001",
 			Status:       "available",
 			Quantity:     5,
-			Price:        89999.99,
-			CategoryID:   1,
+			Price:        model.NewMoney(89999.99),
+			CategoryID:   9,
 			LocationID:   1,
 			SupplierID:   1,
 		},
@@ -64,7 +77,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "HP-2023-001",
 			Status:       "in_use",
 			Quantity:     10,
-			Price:        79999.99,
+			Price:        model.NewMoney(79999.99),
 			CategoryID:   1,
 			LocationID:   4,
 			SupplierID:   1,
@@ -75,7 +88,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "MON-2023-001",
 			Status:       "available",
 			Quantity:     15,
-			Price:        19999.99,
+			Price:        model.NewMoney(19999.99),
 			CategoryID:   1,
 			LocationID:   4,
 			SupplierID:   1,
@@ -87,7 +100,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "CISCO-2023-001",
 			Status:       "in_use",
 			Quantity:     3,
-			Price:        149999.99,
+			Price:        model.NewMoney(149999.99),
 			CategoryID:   2,
 			LocationID:   2,
 			SupplierID:   5,
@@ -98,7 +111,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "UBNT-2023-001",
 			Status:       "available",
 			Quantity:     8,
-			Price:        15999.99,
+			Price:        model.NewMoney(15999.99),
 			CategoryID:   2,
 			LocationID:   1,
 			SupplierID:   5,
@@ -110,7 +123,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "HPPR-2023-001",
 			Status:       "in_use",
 			Quantity:     4,
-			Price:        39999.99,
+			Price:        model.NewMoney(39999.99),
 			CategoryID:   3,
 			LocationID:   1,
 			SupplierID:   1,
@@ -121,7 +134,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "EPSN-2023-001",
 			Status:       "available",
 			Quantity:     2,
-			Price:        89999.99,
+			Price:        model.NewMoney(89999.99),
 			CategoryID:   3,
 			LocationID:   5,
 			SupplierID:   1,
@@ -133,7 +146,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "CHAIR-2023-001",
 			Status:       "in_use",
 			Quantity:     20,
-			Price:        29999.99,
+			Price:        model.NewMoney(29999.99),
 			CategoryID:   4,
 			LocationID:   4,
 			SupplierID:   2,
@@ -144,7 +157,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "DESK-2023-001",
 			Status:       "available",
 			Quantity:     15,
-			Price:        49999.99,
+			Price:        model.NewMoney(49999.99),
 			CategoryID:   4,
 			LocationID:   4,
 			SupplierID:   2,
@@ -156,7 +169,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "TOOL-2023-001",
 			Status:       "in_use",
 			Quantity:     3,
-			Price:        19999.99,
+			Price:        model.NewMoney(19999.99),
 			CategoryID:   5,
 			LocationID:   2,
 			SupplierID:   1,
@@ -168,7 +181,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "MS365-2023-001",
 			Status:       "in_use",
 			Quantity:     50,
-			Price:        5999.99,
+			Price:        model.NewMoney(5999.99),
 			CategoryID:   6,
 			LocationID:   1,
 			SupplierID:   1,
@@ -179,7 +192,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "ADOBE-2023-001",
 			Status:       "in_use",
 			Quantity:     10,
-			Price:        39999.99,
+			Price:        model.NewMoney(39999.99),
 			CategoryID:   6,
 			LocationID:   4,
 			SupplierID:   1,
@@ -191,7 +204,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "POLY-2023-001",
 			Status:       "available",
 			Quantity:     3,
-			Price:        99999.99,
+			Price:        model.NewMoney(99999.99),
 			CategoryID:   7,
 			LocationID:   1,
 			SupplierID:   1,
@@ -202,7 +215,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "YEAL-2023-001",
 			Status:       "in_use",
 			Quantity:     25,
-			Price:        19999.99,
+			Price:        model.NewMoney(19999.99),
 			CategoryID:   7,
 			LocationID:   1,
 			SupplierID:   5,
@@ -214,7 +227,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "HIK-2023-001",
 			Status:       "in_use",
 			Quantity:     10,
-			Price:        15999.99,
+			Price:        model.NewMoney(15999.99),
 			CategoryID:   8,
 			LocationID:   1,
 			SupplierID:   4,
@@ -225,7 +238,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "PERCO-2023-001",
 			Status:       "in_use",
 			Quantity:     1,
-			Price:        299999.99,
+			Price:        model.NewMoney(299999.99),
 			CategoryID:   8,
 			LocationID:   1,
 			SupplierID:   4,
@@ -237,7 +250,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "APC-2023-001",
 			Status:       "in_use",
 			Quantity:     4,
-			Price:        89999.99,
+			Price:        model.NewMoney(89999.99),
 			CategoryID:   1,
 			LocationID:   2,
 			SupplierID:   1,
@@ -248,7 +261,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "PROJ-2023-001",
 			Status:       "available",
 			Quantity:     2,
-			Price:        129999.99,
+			Price:        model.NewMoney(129999.99),
 			CategoryID:   1,
 			LocationID:   1,
 			SupplierID:   1,
@@ -259,7 +272,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "SYN-2023-001",
 			Status:       "in_use",
 			Quantity:     1,
-			Price:        159999.99,
+			Price:        model.NewMoney(159999.99),
 			CategoryID:   1,
 			LocationID:   2,
 			SupplierID:   1,
@@ -270,7 +283,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "RACK-2023-001",
 			Status:       "in_use",
 			Quantity:     2,
-			Price:        49999.99,
+			Price:        model.NewMoney(49999.99),
 			CategoryID:   4,
 			LocationID:   2,
 			SupplierID:   2,
@@ -281,7 +294,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "COOL-2023-001",
 			Status:       "in_use",
 			Quantity:     2,
-			Price:        399999.99,
+			Price:        model.NewMoney(399999.99),
 			CategoryID:   1,
 			LocationID:   2,
 			SupplierID:   1,
@@ -292,7 +305,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "SHRED-2023-001",
 			Status:       "in_use",
 			Quantity:     3,
-			Price:        59999.99,
+			Price:        model.NewMoney(59999.99),
 			CategoryID:   3,
 			LocationID:   5,
 			SupplierID:   1,
@@ -303,7 +316,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "VCST-2023-001",
 			Status:       "available",
 			Quantity:     2,
-			Price:        29999.99,
+			Price:        model.NewMoney(29999.99),
 			CategoryID:   4,
 			LocationID:   1,
 			SupplierID:   2,
@@ -314,8 +327,8 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "TAB-2023-001",
 			Status:       "in_use",
 			Quantity:     5,
-			Price:        49999.99,
-			CategoryID:   1,
+			Price:        model.NewMoney(49999.99),
+			CategoryID:   9,
 			LocationID:   1,
 			SupplierID:   1,
 		},
@@ -325,7 +338,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "TOOLS-2023-001",
 			Status:       "in_use",
 			Quantity:     2,
-			Price:        39999.99,
+			Price:        model.NewMoney(39999.99),
 			CategoryID:   4,
 			LocationID:   2,
 			SupplierID:   2,
@@ -336,7 +349,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "TEST-2023-001",
 			Status:       "available",
 			Quantity:     1,
-			Price:        199999.99,
+			Price:        model.NewMoney(199999.99),
 			CategoryID:   5,
 			LocationID:   2,
 			SupplierID:   5,
@@ -347,7 +360,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "CCTV-2023-001",
 			Status:       "in_use",
 			Quantity:     1,
-			Price:        299999.99,
+			Price:        model.NewMoney(299999.99),
 			CategoryID:   8,
 			LocationID:   1,
 			SupplierID:   4,
@@ -358,7 +371,7 @@ func GetEquipment() []model.Equipment {
 			SerialNumber: "CONF-2023-001",
 			Status:       "in_use",
 			Quantity:     1,
-			Price:        499999.99,
+			Price:        model.NewMoney(499999.99),
 			CategoryID:   7,
 			LocationID:   1,
 			SupplierID:   1,
@@ -368,16 +381,21 @@ func GetEquipment() []model.Equipment {
 
 // SeedDatabase заполняет базу данных тестовыми данными
 func SeedDatabase(db *gorm.DB) error {
-	// Создаем категории
+	// Создаем категории. По одному, как и местоположения: у вложенных задан parent_id
 	categories := GetCategories()
-	if err := db.Create(&categories).Error; err != nil {
-		return err
+	for i := range categories {
+		if err := db.Create(&categories[i]).Error; err != nil {
+			return err
+		}
 	}
 
-	// Создаем местоположения
+	// Создаем местоположения. По одному: пакетная вставка мест верхнего уровня
+	// вместе с вложенными требует DEFAULT для parent_id, который SQLite не принимает
 	locations := GetLocations()
-	if err := db.Create(&locations).Error; err != nil {
-		return err
+	for i := range locations {
+		if err := db.Create(&locations[i]).Error; err != nil {
+			return err
+		}
 	}
 
 	// Создаем поставщиков
@@ -386,6 +404,12 @@ func SeedDatabase(db *gorm.DB) error {
 		return err
 	}
 
+	// Создаем сотрудников
+	employees := GetEmployees()
+	if err := db.Create(&employees).Error; err != nil {
+		return err
+	}
+
 	// Создаем оборудование
 	equipment := GetEquipment()
 	for i := range equipment {
@@ -396,5 +420,22 @@ func SeedDatabase(db *gorm.DB) error {
 		return err
 	}
 
+	// Размещаем остатки оборудования по его местоположениям
+	balances := make([]model.StockBalance, 0, len(equipment))
+	for _, item := range equipment {
+		if item.Quantity > 0 && item.LocationID != 0 {
+			balances = append(balances, model.StockBalance{
+				EquipmentID: item.ID,
+				LocationID:  item.LocationID,
+				Quantity:    item.Quantity,
+			})
+		}
+	}
+	if len(balances) > 0 {
+		if err := db.Create(&balances).Error; err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
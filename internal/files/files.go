@@ -0,0 +1,11 @@
+// Package files содержит шаблоны печатных форм, встроенные в приложение
+package files
+
+import _ "embed"
+
+// Law книга Excel 97-2003 с унифицированной формой № ИНВ-19
+// (сличительная ведомость результатов инвентаризации): лист "стр1" - титульный,
+// лист "стр2" - таблица позиций (2-я и 3-я страницы формы)
+//
+//go:embed law.XLS
+var Law []byte
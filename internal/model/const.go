@@ -20,3 +20,10 @@ const (
 	UP      STATUS = "Up"
 	DOWN    STATUS = "Down"
 )
+
+// Роли пользователей системы
+const (
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleAuditor = "auditor"
+)
@@ -2,6 +2,8 @@ package model
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -94,36 +96,232 @@ type User struct {
 //	ID - уникальный идентификатор
 //	Name - название оборудования
 //	SerialNumber - серийный номер (уникальный)
+//	InventoryNumber - инвентарный номер, выдается счетчиком при создании и не меняется
 //	Category - категория оборудования
 //	Description - описание/характеристики
-//	Price - стоимость единицы (в валюте)
-//	Status - текущий статус: "available", "in_use", "maintenance", "written_off"
-//	Quantity - общее количество на складе
-//	LocationID - ссылка на местоположение
+//	Price - стоимость единицы в рублях
+//	Currency, CurrencyPrice - валюта закупки и цена в ней (пустая валюта - рубли),
+//	Price пересчитывается по курсу на дату ввода; ExchangeRate, ExchangeNominal - примененный
+//	курс и количество единиц валюты, за которое он указан
+//	Status - текущий статус (см. EquipmentStatus)
+//	Quantity - общее количество по всем местоположениям (сумма Balances)
+//	LocationID - основное местоположение
 //	Location - связанное местоположение (gorm relation)
 //	SupplierID - ссылка на поставщика
 //	Supplier - связанный поставщик (gorm relation)
+//	ResponsibleID - материально ответственный сотрудник (может быть null)
+//	Responsible - связанный сотрудник
+//	CommissionedAt - дата ввода в эксплуатацию, амортизация начисляется со следующего месяца
+//	DepreciationMethod, UsefulLifeMonths - способ амортизации и срок полезного использования
+//	  (пустые значения берутся из категории)
+//	ResidualValue - ликвидационная стоимость единицы
+//	AccumulatedDepreciation - накопленная амортизация единицы
+//	BookValue - остаточная стоимость единицы (Price - AccumulatedDepreciation), не хранится
+//	Balances - остатки по местоположениям
 //	Movements - история перемещений
 //	Documents - связанные документы
 //	CreatedAt/UpdatedAt - метки времени
 type Equipment struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	Name         string         `json:"name"`
-	Description  string         `json:"description"`
-	SerialNumber string         `gorm:"unique" json:"serial_number"`
-	Status       string         `json:"status"`
-	Quantity     int            `json:"quantity"`
-	Price        float64        `json:"price"`
-	CategoryID   uint           `json:"category_id"`
-	Category     *Category      `gorm:"foreignKey:CategoryID;references:ID" json:"category"`
-	LocationID   uint           `json:"location_id"`
-	Location     *Location      `gorm:"foreignKey:LocationID;references:ID" json:"location"`
-	SupplierID   uint           `json:"supplier_id"`
-	Supplier     *Supplier      `gorm:"foreignKey:SupplierID;references:ID" json:"supplier"`
-	Movements    []Movement     `gorm:"foreignKey:EquipmentID" json:"movements"`
-	Documents    []DocumentItem `gorm:"foreignKey:EquipmentID" json:"documents"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
+	ID                      uint            `gorm:"primaryKey" json:"id"`
+	Name                    string          `json:"name"`
+	Description             string          `json:"description"`
+	SerialNumber            string          `gorm:"unique" json:"serial_number"`
+	InventoryNumber         string          `gorm:"default:null;uniqueIndex" json:"inventory_number"`
+	Status                  EquipmentStatus `json:"status"`
+	Quantity                int             `json:"quantity"`
+	Price                   Money           `json:"price"`
+	Currency                string          `json:"currency"`
+	CurrencyPrice           Money           `gorm:"default:0" json:"currency_price"`
+	ExchangeRate            FixedRate       `gorm:"default:0" json:"exchange_rate"`
+	ExchangeNominal         int             `gorm:"default:0" json:"exchange_nominal"`
+	CategoryID              uint            `json:"category_id"`
+	Category                *Category       `gorm:"foreignKey:CategoryID;references:ID" json:"category"`
+	LocationID              uint            `json:"location_id"`
+	Location                *Location       `gorm:"foreignKey:LocationID;references:ID" json:"location"`
+	SupplierID              uint            `json:"supplier_id"`
+	Supplier                *Supplier       `gorm:"foreignKey:SupplierID;references:ID" json:"supplier"`
+	ResponsibleID           uint            `gorm:"default:null;index" json:"responsible_id"`
+	Responsible             *Employee       `gorm:"foreignKey:ResponsibleID;references:ID" json:"responsible"`
+	CommissionedAt          *time.Time      `gorm:"type:date" json:"commissioned_at"`
+	DepreciationMethod      string          `json:"depreciation_method"`
+	UsefulLifeMonths        int             `gorm:"default:0" json:"useful_life_months"`
+	ResidualValue           Money           `gorm:"default:0" json:"residual_value"`
+	AccumulatedDepreciation Money           `gorm:"default:0" json:"accumulated_depreciation"`
+	BookValue               Money           `gorm:"-" json:"book_value"`
+	Balances                []StockBalance  `gorm:"foreignKey:EquipmentID" json:"balances"`
+	Movements               []Movement      `gorm:"foreignKey:EquipmentID" json:"movements"`
+	Documents               []DocumentItem  `gorm:"foreignKey:EquipmentID" json:"documents"`
+	CreatedAt               time.Time       `json:"created_at"`
+	UpdatedAt               time.Time       `json:"updated_at"`
+}
+
+// AfterFind вычисляет остаточную стоимость загруженного оборудования
+func (e *Equipment) AfterFind(tx *gorm.DB) error {
+	e.BookValue = e.Price - e.AccumulatedDepreciation
+	return nil
+}
+
+// EquipmentStatus статус жизненного цикла оборудования
+type EquipmentStatus string
+
+const (
+	EquipmentAvailable   EquipmentStatus = "available"   // на складе, доступно для выдачи
+	EquipmentInUse       EquipmentStatus = "in_use"      // выдано в эксплуатацию
+	EquipmentMaintenance EquipmentStatus = "maintenance" // в ремонте или на обслуживании
+	EquipmentWrittenOff  EquipmentStatus = "written_off" // списано, конечный статус
+)
+
+// equipmentTransitions допустимые переходы между статусами. Списание выполняется
+// только документом списания, сторно которого возвращает оборудование на учет
+// в обход графа.
+var equipmentTransitions = map[EquipmentStatus][]EquipmentStatus{
+	EquipmentAvailable:   {EquipmentInUse, EquipmentMaintenance, EquipmentWrittenOff},
+	EquipmentInUse:       {EquipmentAvailable, EquipmentMaintenance, EquipmentWrittenOff},
+	EquipmentMaintenance: {EquipmentAvailable, EquipmentWrittenOff},
+	EquipmentWrittenOff:  {},
+}
+
+// Valid сообщает, известен ли статус
+func (s EquipmentStatus) Valid() bool {
+	_, ok := equipmentTransitions[s]
+	return ok
+}
+
+// CanChangeTo сообщает, допустим ли переход из статуса s в статус to
+func (s EquipmentStatus) CanChangeTo(to EquipmentStatus) bool {
+	for _, allowed := range equipmentTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// EquipmentStatusChange запись истории статусов оборудования
+// Поля:
+//
+//	EquipmentID - оборудование
+//	FromStatus, ToStatus - статус до и после перехода
+//	Reason - причина перехода
+//	UserID - пользователь, выполнивший операцию
+//	DocumentID - документ, проведение или сторно которого изменило статус (может быть null)
+//	CreatedAt - время перехода
+type EquipmentStatusChange struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	EquipmentID uint            `gorm:"not null;index" json:"equipment_id"`
+	FromStatus  EquipmentStatus `json:"from_status"`
+	ToStatus    EquipmentStatus `gorm:"not null" json:"to_status"`
+	Reason      string          `json:"reason"`
+	UserID      uint            `gorm:"default:null" json:"user_id"`
+	User        *User           `gorm:"foreignKey:UserID;references:ID" json:"user"`
+	DocumentID  uint            `gorm:"default:null" json:"document_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Виды обслуживания оборудования
+const (
+	MaintenancePlanned   = "planned"   // плановое (профилактическое) обслуживание
+	MaintenanceUnplanned = "unplanned" // внеплановый ремонт
+)
+
+// Результаты обслуживания
+const (
+	MaintenanceRepaired     = "repaired"     // исправно, возвращается на склад
+	MaintenanceUnrepairable = "unrepairable" // ремонту не подлежит, остается в ремонте до списания
+)
+
+// MaintenanceRecord запись о ремонте или обслуживании оборудования
+// Поля:
+//
+//	EquipmentID - обслуживаемое оборудование
+//	Type - вид: "planned", "unplanned"
+//	SupplierID - подрядчик, выполняющий работы (может быть null - своими силами)
+//	SentAt - дата передачи в обслуживание
+//	ReturnedAt - дата возврата (null, пока обслуживание не завершено)
+//	Cost - стоимость работ
+//	Description - описание неисправности или состава работ
+//	Outcome - результат: "repaired", "unrepairable" (пусто, пока обслуживание не завершено)
+//	CreatedByID - пользователь, передавший оборудование в обслуживание
+//	CreatedAt/UpdatedAt - метки времени
+type MaintenanceRecord struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	EquipmentID uint       `gorm:"not null;index" json:"equipment_id"`
+	Equipment   *Equipment `gorm:"foreignKey:EquipmentID;references:ID" json:"equipment"`
+	Type        string     `gorm:"not null" json:"type"`
+	SupplierID  uint       `gorm:"default:null;index" json:"supplier_id"`
+	Supplier    *Supplier  `gorm:"foreignKey:SupplierID;references:ID" json:"supplier"`
+	SentAt      time.Time  `gorm:"not null" json:"sent_at"`
+	ReturnedAt  *time.Time `json:"returned_at"`
+	Cost        Money      `json:"cost"`
+	Description string     `json:"description"`
+	Outcome     string     `json:"outcome"`
+	CreatedByID uint       `gorm:"default:null" json:"created_by_id"`
+	CreatedBy   *User      `gorm:"foreignKey:CreatedByID;references:ID" json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Способы начисления амортизации
+const (
+	DepreciationStraightLine    = "straight_line"    // линейный
+	DepreciationReducingBalance = "reducing_balance" // уменьшаемого остатка
+)
+
+// DepreciationRun начисление амортизации за месяц
+// Поля:
+//
+//	Period - первое число месяца начисления (уникально)
+//	Total - сумма начисленной амортизации
+//	CreatedByID - пользователь, выполнивший начисление
+//	Postings - проводки начисления по оборудованию
+type DepreciationRun struct {
+	ID          uint                  `gorm:"primaryKey" json:"id"`
+	Period      time.Time             `gorm:"type:date;not null;uniqueIndex" json:"period"`
+	Total       Money                 `json:"total"`
+	CreatedByID uint                  `gorm:"default:null" json:"created_by_id"`
+	CreatedBy   *User                 `gorm:"foreignKey:CreatedByID;references:ID" json:"created_by"`
+	Postings    []DepreciationPosting `gorm:"foreignKey:RunID" json:"postings"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// DepreciationPosting проводка начисления амортизации по оборудованию
+// Поля:
+//
+//	RunID - начисление, в которое входит проводка
+//	EquipmentID - оборудование
+//	Method - способ амортизации, по которому начислено
+//	Quantity - количество единиц на момент начисления
+//	BookValue - остаточная стоимость единицы до начисления
+//	UnitAmount - амортизация единицы за месяц
+//	Amount - амортизация всего количества
+type DepreciationPosting struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	RunID       uint       `gorm:"not null;index" json:"run_id"`
+	EquipmentID uint       `gorm:"not null;index" json:"equipment_id"`
+	Equipment   *Equipment `gorm:"foreignKey:EquipmentID;references:ID" json:"equipment"`
+	Method      string     `json:"method"`
+	Quantity    int        `json:"quantity"`
+	BookValue   Money      `json:"book_value"`
+	UnitAmount  Money      `json:"unit_amount"`
+	Amount      Money      `json:"amount"`
+}
+
+// StockBalance хранит остаток оборудования в конкретном местоположении
+// Поля:
+//
+//	ID - уникальный идентификатор
+//	EquipmentID - ссылка на оборудование
+//	LocationID - ссылка на местоположение
+//	Location - связанное местоположение
+//	Quantity - количество единиц в местоположении (всегда больше нуля)
+//	UpdatedAt - время последнего изменения остатка
+type StockBalance struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	EquipmentID uint      `gorm:"not null;uniqueIndex:idx_balance_equipment_location,priority:1" json:"equipment_id"`
+	LocationID  uint      `gorm:"not null;uniqueIndex:idx_balance_equipment_location,priority:2;index" json:"location_id"`
+	Location    *Location `gorm:"foreignKey:LocationID;references:ID" json:"location"`
+	Quantity    int       `gorm:"not null" json:"quantity"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 // Supplier содержит информацию о поставщике оборудования
@@ -144,13 +342,27 @@ type Supplier struct {
 	Equipment   []Equipment `gorm:"foreignKey:SupplierID" json:"equipment"`
 }
 
-// Location описывает место хранения оборудования
+// Виды местоположений в иерархии: здание, этаж, помещение, стеллаж (полка)
+const (
+	LocationBuilding = "building"
+	LocationFloor    = "floor"
+	LocationRoom     = "room"
+	LocationShelf    = "shelf"
+)
+
+// Location описывает место хранения оборудования. Места образуют дерево:
+// здание - этаж - помещение - стеллаж.
 // Поля:
 //
 //	ID - уникальный идентификатор
 //	Name - название места (склад/кабинет)
 //	Description - описание места
 //	Address - физический адрес
+//	Kind - вид места (building, floor, room, shelf), пустой - не указан
+//	ParentID - родительское место (0 - место верхнего уровня)
+//	Path - идентификаторы мест от корня дерева до этого места: "/1/4/".
+//	       Вложенные места ищутся по префиксу пути.
+//	FullName - названия мест от корня через " / " (не хранится в базе)
 //	Equipment - список оборудования на этом месте
 //	FromMovements - история перемещений из этого места
 //	ToMovements - история перемещений в это место
@@ -159,38 +371,124 @@ type Location struct {
 	Name          string      `json:"name"`
 	Description   string      `json:"description"`
 	Address       string      `json:"address"`
+	Kind          string      `json:"kind"`
+	ParentID      uint        `gorm:"default:null;index" json:"parent_id"`
+	Path          string      `gorm:"index" json:"path"`
+	FullName      string      `gorm:"-" json:"full_name"`
 	Equipment     []Equipment `gorm:"foreignKey:LocationID" json:"equipment"`
 	FromMovements []Movement  `gorm:"foreignKey:FromLocationID" json:"from_movements"`
 	ToMovements   []Movement  `gorm:"foreignKey:ToLocationID" json:"to_movements"`
 }
 
+// ValidLocationKind проверяет вид местоположения
+func ValidLocationKind(kind string) bool {
+	switch kind {
+	case "", LocationBuilding, LocationFloor, LocationRoom, LocationShelf:
+		return true
+	}
+	return false
+}
+
+// AncestorIDs идентификаторы мест из пути от корня до самого места включительно
+func (l *Location) AncestorIDs() []uint {
+	return pathIDs(l.Path)
+}
+
+// pathIDs разбирает путь узла дерева "/1/4/7/" в список идентификаторов
+func pathIDs(path string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if id, err := strconv.ParseUint(part, 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+// Contains сообщает, входит ли место other в поддерево места l (включая само l)
+func (l *Location) Contains(other *Location) bool {
+	return l.Path != "" && strings.HasPrefix(other.Path, l.Path)
+}
+
+// LocationNode узел дерева местоположений с остатками оборудования.
+// Поля:
+//
+//	Location - местоположение
+//	Depth - уровень вложенности (0 - место верхнего уровня)
+//	OwnQuantity, OwnAmount - остаток и его стоимость в самом месте
+//	Quantity, Amount - остаток и стоимость в месте и во всех вложенных местах
+//	Children - вложенные места
+type LocationNode struct {
+	Location
+	Depth       int            `json:"depth"`
+	OwnQuantity int            `json:"own_quantity"`
+	OwnAmount   Money          `json:"own_amount"`
+	Quantity    int            `json:"quantity"`
+	Amount      Money          `json:"amount"`
+	Children    []LocationNode `json:"children"`
+}
+
+// Employee сотрудник, за которым может быть закреплено оборудование
+// Поля:
+//
+//	ID - уникальный идентификатор
+//	Name - ФИО сотрудника
+//	Position - должность
+//	Department - подразделение
+//	PersonnelNumber - табельный номер (уникальный, если указан)
+//	Contact - телефон или e-mail
+//	UserID - учетная запись сотрудника в системе (может быть null)
+//	User - связанный пользователь
+//	Equipment - оборудование, за которое сотрудник материально ответственен
+//	CreatedAt/UpdatedAt - метки времени
+type Employee struct {
+	ID              uint        `gorm:"primaryKey" json:"id"`
+	Name            string      `gorm:"not null" json:"name"`
+	Position        string      `json:"position"`
+	Department      string      `json:"department"`
+	PersonnelNumber string      `gorm:"index" json:"personnel_number"`
+	Contact         string      `json:"contact"`
+	UserID          uint        `gorm:"default:null" json:"user_id"`
+	User            *User       `gorm:"foreignKey:UserID" json:"user"`
+	Equipment       []Equipment `gorm:"foreignKey:ResponsibleID" json:"equipment"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
 // Movement фиксирует факт перемещения оборудования
 // Поля:
 //
 //	ID - уникальный идентификатор
 //	EquipmentID - ссылка на оборудование
 //	FromLocationID - откуда перемещается (0 если приемка)
-//	ToLocationID - куда перемещается
+//	ToLocationID - куда перемещается (0 если списание)
 //	Quantity - количество перемещаемых единиц
-//	Reason - причина: "transfer", "inventory", "repair"
+//	Reason - причина: "transfer", "inventory", "repair", "acceptance", "write_off", "assignment"
+//	  (при закреплении за сотрудником местоположение не меняется)
+//	FromResponsibleID - материально ответственный до перемещения (0 - не назначен)
+//	ToResponsibleID - материально ответственный после перемещения (0 - не назначен)
 //	CreatedByID - кто создал перемещение
 //	CreatedBy - связанный пользователь (создатель)
 //	DocumentID - ссылка на документ-основание
 //	Date - дата перемещения
 type Movement struct {
-	ID             uint       `gorm:"primaryKey" json:"id"`
-	EquipmentID    uint       `json:"equipment_id"`
-	Equipment      *Equipment `gorm:"foreignKey:EquipmentID;references:ID" json:"equipment"`
-	FromLocationID uint       `json:"from_location_id"`
-	FromLocation   *Location  `gorm:"foreignKey:FromLocationID;references:ID" json:"from_location"`
-	ToLocationID   uint       `json:"to_location_id"`
-	ToLocation     *Location  `gorm:"foreignKey:ToLocationID;references:ID" json:"to_location"`
-	Quantity       int        `json:"quantity"`
-	Reason         string     `json:"reason"`
-	CreatedByID    uint       `json:"created_by_id"`
-	CreatedBy      *User      `gorm:"foreignKey:CreatedByID;references:ID" json:"created_by"`
-	DocumentID     uint       `json:"document_id"`
-	Date           time.Time  `json:"date" gorm:"type:date"`
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	EquipmentID       uint       `json:"equipment_id"`
+	Equipment         *Equipment `gorm:"foreignKey:EquipmentID;references:ID" json:"equipment"`
+	FromLocationID    uint       `json:"from_location_id"`
+	FromLocation      *Location  `gorm:"foreignKey:FromLocationID;references:ID" json:"from_location"`
+	ToLocationID      uint       `json:"to_location_id"`
+	ToLocation        *Location  `gorm:"foreignKey:ToLocationID;references:ID" json:"to_location"`
+	Quantity          int        `json:"quantity"`
+	Reason            string     `json:"reason"`
+	FromResponsibleID uint       `gorm:"default:null" json:"from_responsible_id"`
+	FromResponsible   *Employee  `gorm:"foreignKey:FromResponsibleID;references:ID" json:"from_responsible"`
+	ToResponsibleID   uint       `gorm:"default:null" json:"to_responsible_id"`
+	ToResponsible     *Employee  `gorm:"foreignKey:ToResponsibleID;references:ID" json:"to_responsible"`
+	CreatedByID       uint       `json:"created_by_id"`
+	CreatedBy         *User      `gorm:"foreignKey:CreatedByID;references:ID" json:"created_by"`
+	DocumentID        uint       `json:"document_id"`
+	Date              time.Time  `json:"date" gorm:"type:date"`
 }
 
 // MarshalJSON реализует интерфейс json.Marshaler для Movement
@@ -232,34 +530,68 @@ func (m *Movement) UnmarshalJSON(data []byte) error {
 //
 //	ID - уникальный идентификатор
 //	Type - тип документа: "inventory", "transfer", "write_off", "acceptance"
-//	Number - уникальный номер документа (формат "ИНВ-2023-001")
+//	Number - уникальный номер документа, выдается счетчиком NumberSequence (по умолчанию "ИНВ-2023-001")
 //	Status - статус: "draft", "completed", "canceled"
+//	  (проведенный документ не изменяется, его отменяет сторнирующий документ)
 //	Date - дата документа
 //	CreatedByID - кто создал документ
 //	CreatedBy - связанный пользователь (создатель)
 //	ApprovedByID - кто утвердил (может быть null)
 //	ApprovedBy - связанный пользователь (утвердивший)
-//	LocationID - место проведения (для инвентаризации)
+//	LocationID - место проведения (для перемещения - место назначения)
 //	Location - связанное местоположение
+//	FromLocationID - откуда перемещается (только для перемещения, 0 - основное местоположение оборудования)
+//	FromLocation - связанное исходное местоположение
+//	ResponsibleID - сотрудник, за которым закрепляется оборудование при приемке
+//	  и перемещении (может быть null - ответственный не меняется)
+//	Responsible - связанный сотрудник
 //	Items - позиции документа
 //	Comment - комментарий к документу
+//	Basis - основание для проведения: приказ, постановление или распоряжение
+//	BasisNumber, BasisDate - номер и дата документа-основания
+//	CancelReason - причина отмены черновика
+//	CanceledByID - кто отменил черновик (может быть null)
+//	CanceledBy - связанный пользователь (отменивший)
+//	CanceledAt - время отмены
+//	ReversalOfID - для сторнирующего документа: ссылка на сторнируемый (может быть null)
+//	ReversalOf - сторнируемый документ
+//	Reversal - сторнирующий документ, если этот документ сторнирован (не хранится в БД)
+//	InventoryID - для корректирующей приемки или списания: инвентаризация, при проведении
+//	  которой создан документ (может быть null)
+//	Corrections - корректирующие документы проведенной инвентаризации (не хранится в БД)
 //	CreatedAt/UpdatedAt - метки времени
 type Document struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	Type         string         `json:"type"`
-	Number       string         `gorm:"unique" json:"number"`
-	Status       string         `json:"status"`
-	Date         time.Time      `json:"date" gorm:"type:date"`
-	CreatedByID  uint           `json:"created_by_id"`
-	CreatedBy    *User          `gorm:"foreignKey:CreatedByID" json:"created_by"`
-	ApprovedByID uint           `gorm:"default:null" json:"approved_by_id"`
-	ApprovedBy   *User          `gorm:"foreignKey:ApprovedByID" json:"approved_by"`
-	LocationID   uint           `json:"location_id"`
-	Location     *Location      `gorm:"foreignKey:LocationID" json:"location"`
-	Items        []DocumentItem `gorm:"foreignKey:DocumentID" json:"items"`
-	Comment      string         `json:"comment"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	Type           string         `json:"type"`
+	Number         string         `gorm:"unique" json:"number"`
+	Status         string         `json:"status"`
+	Date           time.Time      `json:"date" gorm:"type:date"`
+	CreatedByID    uint           `json:"created_by_id"`
+	CreatedBy      *User          `gorm:"foreignKey:CreatedByID" json:"created_by"`
+	ApprovedByID   uint           `gorm:"default:null" json:"approved_by_id"`
+	ApprovedBy     *User          `gorm:"foreignKey:ApprovedByID" json:"approved_by"`
+	LocationID     uint           `json:"location_id"`
+	Location       *Location      `gorm:"foreignKey:LocationID" json:"location"`
+	FromLocationID uint           `json:"from_location_id"`
+	FromLocation   *Location      `gorm:"foreignKey:FromLocationID" json:"from_location"`
+	ResponsibleID  uint           `gorm:"default:null" json:"responsible_id"`
+	Responsible    *Employee      `gorm:"foreignKey:ResponsibleID" json:"responsible"`
+	Items          []DocumentItem `gorm:"foreignKey:DocumentID" json:"items"`
+	Comment        string         `json:"comment"`
+	Basis          string         `json:"basis"`
+	BasisNumber    string         `json:"basis_number"`
+	BasisDate      *time.Time     `json:"basis_date" gorm:"type:date"`
+	CancelReason   string         `json:"cancel_reason"`
+	CanceledByID   uint           `gorm:"default:null" json:"canceled_by_id"`
+	CanceledBy     *User          `gorm:"foreignKey:CanceledByID" json:"canceled_by"`
+	CanceledAt     *time.Time     `json:"canceled_at"`
+	ReversalOfID   uint           `gorm:"default:null;index" json:"reversal_of_id"`
+	ReversalOf     *Document      `gorm:"foreignKey:ReversalOfID" json:"reversal_of"`
+	Reversal       *Document      `gorm:"-" json:"reversal"`
+	InventoryID    uint           `gorm:"default:null;index" json:"inventory_id"`
+	Corrections    []Document     `gorm:"-" json:"corrections"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
 }
 
 // MarshalJSON реализует интерфейс json.Marshaler для Document
@@ -305,26 +637,208 @@ func (d *Document) UnmarshalJSON(data []byte) error {
 //	Equipment - связанное оборудование
 //	Quantity - количество
 //	ActualQuantity - фактическое количество
-//	Price - цена единицы
-//	TotalPrice - общая стоимость
+//	Price - цена единицы в рублях
+//	TotalPrice - стоимость без НДС (Price * Quantity)
+//	VATRate - ставка НДС, пустая - без НДС
+//	VATAmount - сумма НДС сверх стоимости, округленная до копейки
+//	Currency - валюта цены поставщика, пустая - рубли
+//	CurrencyPrice - цена единицы в валюте; Price пересчитывается из нее по курсу на дату документа
+//	ExchangeRate, ExchangeNominal - примененный курс: рублей за ExchangeNominal единиц валюты
 //	Comment - комментарий
+//	Counted - для инвентаризации: позиция пересчитана (отсканирована или введена вручную)
 type DocumentItem struct {
-	ID             uint      `gorm:"primaryKey;autoIncrement" json:"id"`
-	DocumentID     uint      `gorm:"not null;index:idx_doc_equipment,priority:1" json:"document_id"`
-	EquipmentID    uint      `gorm:"not null;index:idx_doc_equipment,priority:2" json:"equipment_id"`
-	Equipment      Equipment `gorm:"foreignKey:EquipmentID;references:ID" json:"equipment"`
-	Quantity       int       `gorm:"not null" json:"quantity"`
-	ActualQuantity int       `json:"actual_quantity"`
-	Price          float64   `gorm:"not null" json:"price"`
-	TotalPrice     float64   `gorm:"not null" json:"total_price"`
-	Comment        string    `json:"comment"`
-}
-
-// Category represents an equipment category
+	ID              uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	DocumentID      uint      `gorm:"not null;index:idx_doc_equipment,priority:1" json:"document_id"`
+	EquipmentID     uint      `gorm:"not null;index:idx_doc_equipment,priority:2" json:"equipment_id"`
+	Equipment       Equipment `gorm:"foreignKey:EquipmentID;references:ID" json:"equipment"`
+	Quantity        int       `gorm:"not null" json:"quantity"`
+	ActualQuantity  int       `json:"actual_quantity"`
+	Price           Money     `gorm:"not null" json:"price"`
+	TotalPrice      Money     `gorm:"not null" json:"total_price"`
+	VATRate         VATRate   `gorm:"column:vat_rate" json:"vat_rate"`
+	VATAmount       Money     `gorm:"column:vat_amount;default:0" json:"vat_amount"`
+	Currency        string    `json:"currency"`
+	CurrencyPrice   Money     `gorm:"default:0" json:"currency_price"`
+	ExchangeRate    FixedRate `gorm:"default:0" json:"exchange_rate"`
+	ExchangeNominal int       `gorm:"default:0" json:"exchange_nominal"`
+	Comment         string    `json:"comment"`
+	Counted         bool      `gorm:"default:false" json:"counted"`
+}
+
+// Calculate пересчитывает стоимость позиции и сумму НДС по цене и количеству
+func (i *DocumentItem) Calculate() {
+	i.TotalPrice = i.Price.Mul(i.Quantity)
+	i.VATAmount = i.VATRate.Of(i.TotalPrice)
+}
+
+// TotalWithVAT стоимость позиции с НДС
+func (i *DocumentItem) TotalWithVAT() Money {
+	return i.TotalPrice + i.VATAmount
+}
+
+// ExchangeRate курс валюты к рублю на дату
+// Поля:
+//
+//	Currency - буквенный код валюты (USD, EUR, ...)
+//	Date - дата, с которой действует курс (уникальна для валюты)
+//	Nominal - количество единиц валюты, за которое указан курс
+//	Rate - стоимость Nominal единиц валюты в рублях с четырьмя знаками после запятой
+//	Source - откуда получен курс: "manual" или "cbr"
+type ExchangeRate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Currency  string    `gorm:"not null;uniqueIndex:idx_exchange_rates_key,priority:1" json:"currency"`
+	Date      time.Time `gorm:"type:date;not null;uniqueIndex:idx_exchange_rates_key,priority:2" json:"date"`
+	Nominal   int       `gorm:"not null;default:1" json:"nominal"`
+	Rate      FixedRate `gorm:"not null" json:"rate"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Источники курсов валют
+const (
+	RateSourceManual = "manual"
+	RateSourceCBR    = "cbr"
+)
+
+// UnitRate точный курс за одну единицу валюты
+func (r *ExchangeRate) UnitRate() Rate {
+	return r.Rate.Per(r.Nominal)
+}
+
+// Convert пересчитывает сумму в валюте в рубли с округлением результата до копейки
+func (r *ExchangeRate) Convert(amount Money) Money {
+	return amount.MulRate(r.UnitRate())
+}
+
+// Category represents an equipment category. Категории образуют дерево
+// (Компьютерная техника - Ноутбуки), незаданные значения по умолчанию
+// подкатегория наследует от ближайшего предка, у которого они заданы.
+// ParentID - родительская категория (0 - категория верхнего уровня)
+// Path - идентификаторы категорий от корня дерева: "/1/9/"
+// FullName - названия категорий от корня через " / " (не хранится в базе)
+// Effective - значения по умолчанию с учетом унаследованных (не хранятся в базе)
 type Category struct {
 	ID          uint   `gorm:"primaryKey" json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	ParentID    uint   `gorm:"default:null;index" json:"parent_id"`
+	Path        string `gorm:"index" json:"path"`
+	CategoryDefaults
+	FullName  string           `gorm:"-" json:"full_name"`
+	Effective CategoryDefaults `gorm:"-" json:"effective"`
+}
+
+// CategoryDefaults значения категории по умолчанию для ее оборудования.
+// Нулевое значение - не задано, наследуется от родительской категории.
+// MaintenanceIntervalDays - периодичность планового обслуживания в днях (0 - не обслуживается)
+// OKOF - код группы ОКОФ; DepreciationGroup - амортизационная группа (1-10)
+// DepreciationMethod, UsefulLifeMonths, DepreciationFactor - условия
+// амортизации оборудования категории по умолчанию (коэффициент - для уменьшаемого остатка)
+// Unit - единица измерения для печатных форм
+// SerialPrefix - префикс серийных номеров, выдаваемых оборудованию без серийного номера
+type CategoryDefaults struct {
+	MaintenanceIntervalDays int     `gorm:"default:0" json:"maintenance_interval_days"`
+	OKOF                    string  `gorm:"column:okof" json:"okof"`
+	DepreciationGroup       int     `gorm:"default:0" json:"depreciation_group"`
+	DepreciationMethod      string  `json:"depreciation_method"`
+	UsefulLifeMonths        int     `gorm:"default:0" json:"useful_life_months"`
+	DepreciationFactor      float64 `gorm:"default:0" json:"depreciation_factor"`
+	Unit                    string  `json:"unit"`
+	SerialPrefix            string  `json:"serial_prefix"`
+}
+
+// inherit заполняет незаданные значения значениями родительской категории
+func (d *CategoryDefaults) inherit(parent CategoryDefaults) {
+	if d.MaintenanceIntervalDays == 0 {
+		d.MaintenanceIntervalDays = parent.MaintenanceIntervalDays
+	}
+	if d.OKOF == "" {
+		d.OKOF = parent.OKOF
+	}
+	if d.DepreciationGroup == 0 {
+		d.DepreciationGroup = parent.DepreciationGroup
+	}
+	if d.DepreciationMethod == "" {
+		d.DepreciationMethod = parent.DepreciationMethod
+	}
+	if d.UsefulLifeMonths == 0 {
+		d.UsefulLifeMonths = parent.UsefulLifeMonths
+	}
+	if d.DepreciationFactor == 0 {
+		d.DepreciationFactor = parent.DepreciationFactor
+	}
+	if d.Unit == "" {
+		d.Unit = parent.Unit
+	}
+	if d.SerialPrefix == "" {
+		d.SerialPrefix = parent.SerialPrefix
+	}
+}
+
+// AncestorIDs идентификаторы категорий из пути от корня до самой категории включительно
+func (c *Category) AncestorIDs() []uint {
+	return pathIDs(c.Path)
+}
+
+// Contains сообщает, входит ли категория other в поддерево категории c (включая саму c)
+func (c *Category) Contains(other *Category) bool {
+	return c.Path != "" && strings.HasPrefix(other.Path, c.Path)
+}
+
+// AfterFind заполняет полное название и действующие значения по умолчанию
+// загруженной категории по ее предкам
+func (c *Category) AfterFind(tx *gorm.DB) error {
+	c.Effective = c.CategoryDefaults
+	c.FullName = c.Name
+
+	ids := c.AncestorIDs()
+	if len(ids) <= 1 {
+		return nil
+	}
+	var ancestors []Category
+	if err := tx.Session(&gorm.Session{NewDB: true, SkipHooks: true}).
+		Where("id IN ?", ids[:len(ids)-1]).Find(&ancestors).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]Category, len(ancestors))
+	for _, ancestor := range ancestors {
+		byID[ancestor.ID] = ancestor
+	}
+
+	names := make([]string, 0, len(ids))
+	for i := len(ids) - 2; i >= 0; i-- {
+		if ancestor, ok := byID[ids[i]]; ok {
+			c.Effective.inherit(ancestor.CategoryDefaults)
+			names = append([]string{ancestor.Name}, names...)
+		}
+	}
+	c.FullName = strings.Join(append(names, c.Name), " / ")
+	return nil
+}
+
+// depreciationGroupLives сроки полезного использования амортизационных групп
+// в месяцах (постановление Правительства РФ от 01.01.2002 N 1): от from
+// до to включительно, у десятой группы верхней границы нет
+var depreciationGroupLives = [...]struct{ from, to int }{
+	{12, 24}, {25, 36}, {37, 60}, {61, 84}, {85, 120},
+	{121, 180}, {181, 240}, {241, 300}, {301, 360}, {361, 0},
+}
+
+// DepreciationGroupLife границы срока полезного использования амортизационной
+// группы в месяцах: от from до to включительно (to = 0 - без ограничения)
+func DepreciationGroupLife(group int) (from, to int, ok bool) {
+	if group < 1 || group > len(depreciationGroupLives) {
+		return 0, 0, false
+	}
+	life := depreciationGroupLives[group-1]
+	return life.from, life.to, true
+}
+
+// FitsDepreciationGroup сообщает, соответствует ли срок в месяцах амортизационной группе
+func FitsDepreciationGroup(group, months int) bool {
+	from, to, ok := DepreciationGroupLife(group)
+	return ok && months >= from && (to == 0 || months <= to)
 }
 
 // CategoryResponse represents a response containing a single category
@@ -338,3 +852,364 @@ type CategoryListResponse struct {
 	Model   []Category `json:"model"`
 	Message string     `json:"msg"`
 }
+
+// NumberSequence счетчик нумерации документов. Для каждого типа документа
+// (и, если настроено, местоположения) заводится отдельный счетчик на период,
+// по окончании которого нумерация начинается заново.
+// Поля:
+//
+//	ID - уникальный идентификатор
+//	DocumentType - тип документа ("inventory", "transfer", ...) или "equipment" для инвентарных номеров
+//	Period - период нумерации: "2024" (по годам), "2024-03" (по месяцам) или "" (без сброса)
+//	LocationID - местоположение при раздельной нумерации (0 - общая нумерация)
+//	Value - последний выданный номер
+//	UpdatedAt - время выдачи последнего номера
+type NumberSequence struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	DocumentType string    `gorm:"not null;uniqueIndex:idx_number_sequences_key,priority:1" json:"document_type"`
+	Period       string    `gorm:"not null;uniqueIndex:idx_number_sequences_key,priority:2" json:"period"`
+	LocationID   uint      `gorm:"not null;uniqueIndex:idx_number_sequences_key,priority:3" json:"location_id"`
+	Value        uint      `gorm:"not null" json:"value"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// AuditEntry запись журнала аудита (только добавление, изменять и удалять нельзя)
+// Поля:
+//
+//	ID - уникальный идентификатор
+//	Entity - таблица измененной сущности ("equipment", "suppliers", ...)
+//	EntityID - идентификатор измененной записи
+//	Action - действие: "create", "update", "delete"
+//	UserID - кто выполнил изменение (0 - система)
+//	User - связанный пользователь
+//	Before - состояние записи до изменения (JSON)
+//	After - состояние записи после изменения (JSON)
+//	Changes - измененные поля в виде {"поле": [старое, новое]} (JSON)
+//	CreatedAt - время изменения
+type AuditEntry struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Entity    string    `gorm:"not null;index:idx_audit_entity,priority:1" json:"entity"`
+	EntityID  uint      `gorm:"index:idx_audit_entity,priority:2" json:"entity_id"`
+	Action    string    `gorm:"not null" json:"action"`
+	UserID    uint      `gorm:"index" json:"user_id"`
+	User      *User     `gorm:"foreignKey:UserID" json:"user"`
+	Before    string    `gorm:"type:text" json:"before"`
+	After     string    `gorm:"type:text" json:"after"`
+	Changes   string    `gorm:"type:text" json:"changes"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// AuditFilter условия выборки журнала аудита, пустые поля не учитываются
+type AuditFilter struct {
+	Entity   string `json:"entity"`
+	EntityID uint   `json:"entity_id"`
+	UserID   uint   `json:"user_id"`
+	DateFrom string `json:"date_from"` // "2006-01-02"
+	DateTo   string `json:"date_to"`   // "2006-01-02", включительно
+}
+
+// ReportFilter условия отчетов, пустые поля не учитываются
+// Поля:
+//
+//	DateFrom, DateTo - период "2006-01-02", включительно; для остатков DateTo - дата,
+//	  на конец которой считаются остатки (по умолчанию сегодня)
+//	CategoryID, LocationID, SupplierID - фильтры по справочникам
+//	Status - статус оборудования
+//	Format - формат файла: "xlsx" (по умолчанию) или "pdf"
+type ReportFilter struct {
+	DateFrom   string `json:"date_from"`
+	DateTo     string `json:"date_to"`
+	CategoryID uint   `json:"category_id"`
+	LocationID uint   `json:"location_id"`
+	SupplierID uint   `json:"supplier_id"`
+	Status     string `json:"status"`
+	Format     string `json:"format"`
+}
+
+// Форматы файлов отчетов
+const (
+	ReportFormatXLSX = "xlsx"
+	ReportFormatPDF  = "pdf"
+)
+
+// StockRow строка отчета по оборудованию в одном местоположении
+// Поля:
+//
+//	Opening - остаток на начало периода
+//	Incoming, Outgoing - поступление и выбытие за период
+//	Closing - остаток на конец периода (для реестра - текущий остаток)
+type StockRow struct {
+	EquipmentID  uint   `json:"equipment_id"`
+	Name         string `json:"name"`
+	SerialNumber string `json:"serial_number"`
+	Status       string `json:"status"`
+	Category     string `json:"category"`
+	Location     string `json:"location"`
+	Supplier     string `json:"supplier"`
+	Responsible  string `json:"responsible"`
+	Price        Money  `json:"price"`
+	Opening      int    `json:"opening"`
+	Incoming     int    `json:"incoming"`
+	Outgoing     int    `json:"outgoing"`
+	Closing      int    `json:"closing"`
+}
+
+// WriteOffRow списание оборудования по проведенному и не сторнированному документу
+type WriteOffRow struct {
+	Date           time.Time `json:"date"`
+	DocumentNumber string    `json:"document_number"`
+	Name           string    `json:"name"`
+	SerialNumber   string    `json:"serial_number"`
+	Category       string    `json:"category"`
+	Location       string    `json:"location"`
+	Quantity       int       `json:"quantity"`
+	Price          Money     `json:"price"`
+	Reason         string    `json:"reason"`
+}
+
+// ListQuery параметры постраничной выборки списков, пустые поля не учитываются
+// Поля:
+//
+//	Page - номер страницы, начиная с 1
+//	PageSize - количество записей на странице (по умолчанию 50, не больше 500)
+//	SortBy - поле сортировки в формате JSON модели ("name", "date", ...)
+//	SortOrder - направление сортировки: "asc" или "desc"
+//	Search - текст для поиска
+//	Status - статус оборудования или документа
+//	Type - тип документа или причина перемещения
+//	CategoryID, LocationID, SupplierID - фильтры по связанным справочникам
+//	DateFrom, DateTo - диапазон дат "2006-01-02", включительно
+type ListQuery struct {
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+	SortBy     string `json:"sort_by"`
+	SortOrder  string `json:"sort_order"`
+	Search     string `json:"search"`
+	Status     string `json:"status"`
+	Type       string `json:"type"`
+	CategoryID uint   `json:"category_id"`
+	LocationID uint   `json:"location_id"`
+	SupplierID uint   `json:"supplier_id"`
+	DateFrom   string `json:"date_from"`
+	DateTo     string `json:"date_to"`
+}
+
+// Page страница списка с общим количеством записей, подходящих под фильтры
+type Page[T any] struct {
+	Items    []T   `json:"items"`
+	Total    int64 `json:"total"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+}
+
+// SearchQuery параметры полнотекстового поиска
+// Поля:
+//
+//	Text - строка поиска, каждое слово ищется по началу (префиксу)
+//	Entities - типы сущностей: "equipment", "document", "supplier", "location" (пусто - все)
+//	Limit - максимальное количество результатов (по умолчанию 20, не больше 100)
+type SearchQuery struct {
+	Text     string   `json:"text"`
+	Entities []string `json:"entities"`
+	Limit    int      `json:"limit"`
+}
+
+// SearchHit найденная запись
+// Поля:
+//
+//	Entity - тип сущности
+//	ID - идентификатор записи
+//	Title - заголовок (название или номер), совпадения выделены [скобками]
+//	Snippet - фрагмент текста с совпадениями
+//	Rank - релевантность, чем больше, тем выше в результатах
+type SearchHit struct {
+	Entity  string  `json:"entity"`
+	ID      uint    `json:"id"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
+
+// ImportRequest файл с оборудованием для импорта
+// Поля:
+//
+//	FileName - имя файла, по расширению определяется формат: .xlsx или .csv
+//	Content - содержимое файла в base64
+//	Sheet - лист книги Excel (пусто - первый лист)
+//	Columns - сопоставление полей заголовкам столбцов, например {"serial_number": "Зав. №"};
+//	  поля без сопоставления определяются по заголовкам автоматически
+//	LocationID - местоположение для строк, где оно не указано
+//	CreateDocument - оформить поступление документами приемки (по одному на местоположение)
+type ImportRequest struct {
+	FileName       string            `json:"file_name"`
+	Content        string            `json:"content"`
+	Sheet          string            `json:"sheet"`
+	Columns        map[string]string `json:"columns"`
+	LocationID     uint              `json:"location_id"`
+	CreateDocument bool              `json:"create_document"`
+}
+
+// ImportRow строка файла импорта после разбора
+// Поля:
+//
+//	Row - номер строки в файле, начиная с 1
+//	Equipment - оборудование, заполненное из строки (справочники найдены по названию)
+//	Errors - ошибки проверки, строка с ошибками не импортируется
+type ImportRow struct {
+	Row       int       `json:"row"`
+	Equipment Equipment `json:"equipment"`
+	Errors    []string  `json:"errors"`
+}
+
+// ImportPreview результат проверки файла импорта без записи в базу
+// Поля:
+//
+//	Columns - сопоставление полей заголовкам столбцов, использованное при разборе
+//	Rows - строки файла
+//	Valid, Invalid - количество строк без ошибок и с ошибками
+type ImportPreview struct {
+	Columns map[string]string `json:"columns"`
+	Rows    []ImportRow       `json:"rows"`
+	Valid   int               `json:"valid"`
+	Invalid int               `json:"invalid"`
+}
+
+// ImportResult результат импорта: созданное оборудование и документы приемки
+type ImportResult struct {
+	Equipment []Equipment `json:"equipment"`
+	Documents []Document  `json:"documents"`
+}
+
+// InventoryLine позиция инвентаризации с расхождением фактического количества и учетного
+// Поля:
+//
+//	Expected - количество по учету на начало инвентаризации
+//	Actual - фактическое количество
+//	Difference - разница: больше нуля - излишек, меньше нуля - недостача
+//	Amount - стоимость расхождения по цене позиции
+//	Counted - позиция пересчитана
+//	Unexpected - оборудование найдено в месте, где по учету его нет
+type InventoryLine struct {
+	EquipmentID  uint   `json:"equipment_id"`
+	Name         string `json:"name"`
+	SerialNumber string `json:"serial_number"`
+	Expected     int    `json:"expected"`
+	Actual       int    `json:"actual"`
+	Difference   int    `json:"difference"`
+	Price        Money  `json:"price"`
+	Amount       Money  `json:"amount"`
+	Counted      bool   `json:"counted"`
+	Unexpected   bool   `json:"unexpected"`
+}
+
+// InventoryDiscrepancies результаты инвентаризации
+// Поля:
+//
+//	Lines - все позиции инвентаризации
+//	Counted, Uncounted - количество пересчитанных и еще не пересчитанных позиций
+//	Surpluses, Shortages - количество позиций с излишком и с недостачей
+//	SurplusQuantity, ShortageQuantity - количество излишков и недостачи
+//	SurplusAmount, ShortageAmount - стоимость излишков и недостачи
+type InventoryDiscrepancies struct {
+	DocumentID       uint            `json:"document_id"`
+	Number           string          `json:"number"`
+	Status           string          `json:"status"`
+	Lines            []InventoryLine `json:"lines"`
+	Counted          int             `json:"counted"`
+	Uncounted        int             `json:"uncounted"`
+	Surpluses        int             `json:"surpluses"`
+	Shortages        int             `json:"shortages"`
+	SurplusQuantity  int             `json:"surplus_quantity"`
+	ShortageQuantity int             `json:"shortage_quantity"`
+	SurplusAmount    Money           `json:"surplus_amount"`
+	ShortageAmount   Money           `json:"shortage_amount"`
+}
+
+// LabelRequest печать этикеток оборудования
+// Поля:
+//
+//	EquipmentIDs - оборудование в порядке печати
+//	Copies - количество этикеток на каждую единицу (по умолчанию одна)
+//	Skip - сколько этикеток пропустить в начале первого листа, чтобы допечатать начатый лист
+type LabelRequest struct {
+	EquipmentIDs []uint `json:"equipment_ids"`
+	Copies       int    `json:"copies"`
+	Skip         int    `json:"skip"`
+}
+
+// ScanResult оборудование, найденное по отсканированному коду
+// Поля:
+//
+//	Code - код после очистки от служебных символов сканера
+//	Equipment - оборудование с категорией, ответственным и остатками по местоположениям
+type ScanResult struct {
+	Code      string     `json:"code"`
+	Equipment *Equipment `json:"equipment"`
+}
+
+// ScanBatch результат добавления отсканированного оборудования в документ
+// Поля:
+//
+//	Document - документ после добавления
+//	Added - количество принятых сканирований
+//	Unknown - коды, по которым оборудование не найдено
+//	Errors - сканирования, которые не удалось добавить, с причиной
+type ScanBatch struct {
+	Document *Document `json:"document"`
+	Added    int       `json:"added"`
+	Unknown  []string  `json:"unknown"`
+	Errors   []string  `json:"errors"`
+}
+
+// MaintenanceDue оборудование, плановое обслуживание которого просрочено
+// Поля:
+//
+//	Equipment - оборудование с категорией и местоположением
+//	IntervalDays - периодичность обслуживания по категории
+//	LastDate - дата последнего планового обслуживания (null, если не обслуживалось)
+//	DueDate - дата, к которой обслуживание должно было быть выполнено
+//	OverdueDays - на сколько дней просрочено
+type MaintenanceDue struct {
+	Equipment    Equipment  `json:"equipment"`
+	IntervalDays int        `json:"interval_days"`
+	LastDate     *time.Time `json:"last_date"`
+	DueDate      time.Time  `json:"due_date"`
+	OverdueDays  int        `json:"overdue_days"`
+}
+
+// DepreciationSchedule график амортизации за период по месяцам
+// Поля:
+//
+//	Months - первые числа месяцев периода
+//	Forecast - для каждого месяца: суммы рассчитаны по прогнозу, начисление еще не проводилось
+//	Rows - оборудование с суммами амортизации по месяцам
+type DepreciationSchedule struct {
+	Months   []time.Time               `json:"months"`
+	Forecast []bool                    `json:"forecast"`
+	Rows     []DepreciationScheduleRow `json:"rows"`
+}
+
+// DepreciationScheduleRow строка графика амортизации. Стоимости указаны на все количество.
+// Поля:
+//
+//	Category, OKOF, Group - категория (полное название), код ОКОФ и амортизационная группа
+//	Cost - первоначальная стоимость
+//	Opening, Closing - остаточная стоимость на начало и конец периода
+//	Amounts - амортизация по месяцам графика
+type DepreciationScheduleRow struct {
+	EquipmentID      uint       `json:"equipment_id"`
+	InventoryNumber  string     `json:"inventory_number"`
+	Name             string     `json:"name"`
+	Category         string     `json:"category"`
+	OKOF             string     `json:"okof"`
+	Group            int        `json:"depreciation_group"`
+	CommissionedAt   *time.Time `json:"commissioned_at"`
+	Method           string     `json:"method"`
+	UsefulLifeMonths int        `json:"useful_life_months"`
+	Quantity         int        `json:"quantity"`
+	Cost             Money      `json:"cost"`
+	ResidualValue    Money      `json:"residual_value"`
+	Opening          Money      `json:"opening"`
+	Amounts          []Money    `json:"amounts"`
+	Closing          Money      `json:"closing"`
+}
@@ -0,0 +1,437 @@
+package model
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money денежная сумма в копейках. Суммы складываются и умножаются на количество
+// в целых копейках, поэтому итоги документов и отчетов не накапливают ошибку
+// округления. В базе данных хранится целым числом копеек, в JSON передается
+// числом в рублях (1234.5 - 1234 рубля 50 копеек).
+type Money int64
+
+// NewMoney переводит сумму в рублях в копейки с округлением до копейки
+// (половина копейки округляется от нуля)
+func NewMoney(rubles float64) Money {
+	return Money(math.Round(rubles * 100))
+}
+
+// ParseMoney разбирает сумму в рублях: "1234.56", "1 234,56", "-0.5".
+// Знаки после второго округляются до копейки.
+func ParseMoney(value string) (Money, error) {
+	kopecks, err := parseDecimal(value, 2)
+	switch {
+	case errors.Is(err, errEmptyDecimal):
+		return 0, fmt.Errorf("пустая сумма")
+	case errors.Is(err, errDecimalRange):
+		return 0, fmt.Errorf("слишком большая сумма: %s", value)
+	case err != nil:
+		return 0, fmt.Errorf("неверная сумма: %s", value)
+	}
+	return Money(kopecks), nil
+}
+
+var (
+	errEmptyDecimal   = errors.New("пустое число")
+	errDecimalRange   = errors.New("слишком большое число")
+	errInvalidDecimal = errors.New("неверное число")
+)
+
+// parseDecimal разбирает десятичное число с пробелами между разрядами и запятой
+// или точкой в целое число единиц places-го знака после запятой. Лишние знаки
+// округляются по первому отброшенному (половина округляется от нуля).
+func parseDecimal(value string, places int) (int64, error) {
+	value = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f':
+			return -1
+		case ',':
+			return '.'
+		}
+		return r
+	}, value)
+	if value == "" {
+		return 0, errEmptyDecimal
+	}
+
+	scale := int64(1)
+	for i := 0; i < places; i++ {
+		scale *= 10
+	}
+
+	negative := false
+	digits := value
+	if digits[0] == '-' || digits[0] == '+' {
+		negative = digits[0] == '-'
+		digits = digits[1:]
+	}
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		// Экспоненциальная запись и прочие формы, допустимые для чисел JSON
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsInf(number, 0) || math.IsNaN(number) {
+			return 0, errInvalidDecimal
+		}
+		scaled := math.Round(number * float64(scale))
+		if math.Abs(scaled) >= math.MaxInt64 {
+			return 0, errDecimalRange
+		}
+		return int64(scaled), nil
+	}
+
+	if whole == "" {
+		whole = "0"
+	}
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/scale-1 {
+		return 0, errDecimalRange
+	}
+	parts := int64(0)
+	for i := 0; i < places; i++ {
+		parts *= 10
+		if i < len(fraction) {
+			parts += int64(fraction[i] - '0')
+		}
+	}
+	if len(fraction) > places && fraction[places] >= '5' {
+		parts++
+	}
+
+	result := units*scale + parts
+	if negative {
+		result = -result
+	}
+	return result, nil
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Float64 сумма в рублях
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// String сумма в рублях с двумя знаками после точки: "1234.50", "-0.05"
+func (m Money) String() string {
+	sign := ""
+	kopecks := int64(m)
+	if kopecks < 0 {
+		sign = "-"
+		kopecks = -kopecks
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, kopecks/100, kopecks%100)
+}
+
+// Mul стоимость quantity единиц по цене m
+func (m Money) Mul(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// MulRate умножает сумму на коэффициент rate. Произведение вычисляется точно
+// и округляется до копейки один раз (половина копейки округляется от нуля).
+func (m Money) MulRate(rate Rate) Money {
+	if rate.Den == 0 {
+		return 0
+	}
+	numerator := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(rate.Num))
+	denominator := big.NewInt(rate.Den)
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+
+	// QuoRem отбрасывает дробную часть, половина и больше добавляет копейку от нуля
+	negative := (numerator.Sign() < 0) != (rate.Den < 0)
+	remainder.Abs(remainder).Lsh(remainder, 1)
+	if remainder.Cmp(denominator.Abs(denominator)) >= 0 {
+		if negative {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return Money(quotient.Int64())
+}
+
+// Percent доля percent процентов от суммы, округленная до копейки
+// (половина копейки округляется от нуля)
+func (m Money) Percent(percent int) Money {
+	value := int64(m) * int64(percent)
+	if value < 0 {
+		return Money((value - 50) / 100)
+	}
+	return Money((value + 50) / 100)
+}
+
+// Abs модуль суммы
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// Scan читает сумму в копейках из базы данных. Дробное значение (результат
+// вычислений в запросе) округляется до копейки.
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+	case int64:
+		*m = Money(v)
+	case float64:
+		*m = Money(math.Round(v))
+	case []byte:
+		return m.Scan(string(v))
+	case string:
+		kopecks, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return fmt.Errorf("неверная сумма в копейках: %s", v)
+		}
+		*m = Money(math.Round(kopecks))
+	default:
+		return fmt.Errorf("неподдерживаемый тип суммы: %T", value)
+	}
+	return nil
+}
+
+// Value сохраняет сумму целым числом копеек
+func (m Money) Value() (driver.Value, error) {
+	return int64(m), nil
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON принимает сумму числом или строкой, null - нулевая сумма
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		*m = 0
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(string(data)); err == nil {
+		if strings.TrimSpace(unquoted) == "" {
+			*m = 0
+			return nil
+		}
+		data = []byte(unquoted)
+	}
+	parsed, err := ParseMoney(string(data))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Rate точный коэффициент Num/Den, на который умножаются суммы: курс валюты
+// за единицу, месячная норма амортизации. Дробь не теряет точности, как float64,
+// поэтому пересчет одной и той же суммы всегда дает одинаковые копейки.
+type Rate struct {
+	Num int64
+	Den int64
+}
+
+// NewRate коэффициент num/den
+func NewRate(num, den int64) Rate {
+	return Rate{Num: num, Den: den}
+}
+
+// DecimalRate коэффициент value, явно округленный до places знаков после запятой
+// (половина округляется от нуля): DecimalRate(1.5, 2) = 150/100
+func DecimalRate(value float64, places int) Rate {
+	den := int64(1)
+	for i := 0; i < places; i++ {
+		den *= 10
+	}
+	return Rate{Num: int64(math.Round(value * float64(den))), Den: den}
+}
+
+// Div коэффициент, уменьшенный в n раз
+func (r Rate) Div(n int64) Rate {
+	return Rate{Num: r.Num, Den: r.Den * n}
+}
+
+// Float64 приближенное значение коэффициента для отображения
+func (r Rate) Float64() float64 {
+	if r.Den == 0 {
+		return 0
+	}
+	return float64(r.Num) / float64(r.Den)
+}
+
+// RateScale знаменатель курса валюты: курс хранится с четырьмя знаками
+// после запятой, как его публикует ЦБ
+const RateScale = 10000
+
+// FixedRate курс валюты в десятитысячных долях рубля (92.5058 - 925058).
+// В базе данных хранится целым числом, в JSON передается числом в рублях.
+type FixedRate int64
+
+// ParseFixedRate разбирает курс в рублях: "92.5058", "92,5058".
+// Знаки после четвертого округляются.
+func ParseFixedRate(value string) (FixedRate, error) {
+	units, err := parseDecimal(value, 4)
+	if err != nil {
+		return 0, fmt.Errorf("неверный курс: %s", value)
+	}
+	return FixedRate(units), nil
+}
+
+// Per коэффициент пересчета одной единицы валюты, когда курс указан за nominal
+// единиц. Номинал меньше единицы считается единицей.
+func (r FixedRate) Per(nominal int) Rate {
+	if nominal < 1 {
+		nominal = 1
+	}
+	return NewRate(int64(r), RateScale*int64(nominal))
+}
+
+// Float64 курс в рублях
+func (r FixedRate) Float64() float64 {
+	return float64(r) / RateScale
+}
+
+// String курс в рублях с четырьмя знаками после точки: "92.5058"
+func (r FixedRate) String() string {
+	sign := ""
+	units := int64(r)
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	return fmt.Sprintf("%s%d.%04d", sign, units/RateScale, units%RateScale)
+}
+
+// Scan читает курс в десятитысячных долях рубля из базы данных
+func (r *FixedRate) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*r = 0
+	case int64:
+		*r = FixedRate(v)
+	case float64:
+		*r = FixedRate(math.Round(v))
+	case []byte:
+		return r.Scan(string(v))
+	case string:
+		units, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return fmt.Errorf("неверный курс: %s", v)
+		}
+		*r = FixedRate(math.Round(units))
+	default:
+		return fmt.Errorf("неподдерживаемый тип курса: %T", value)
+	}
+	return nil
+}
+
+// Value сохраняет курс целым числом десятитысячных долей рубля
+func (r FixedRate) Value() (driver.Value, error) {
+	return int64(r), nil
+}
+
+func (r FixedRate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON принимает курс числом или строкой, null - нулевой курс
+func (r *FixedRate) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		*r = 0
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(string(data)); err == nil {
+		if strings.TrimSpace(unquoted) == "" {
+			*r = 0
+			return nil
+		}
+		data = []byte(unquoted)
+	}
+	parsed, err := ParseFixedRate(string(data))
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// VATRate ставка НДС позиции документа. Пустая ставка - без НДС.
+type VATRate string
+
+const (
+	VATNone VATRate = ""
+	VAT0    VATRate = "0%"
+	VAT5    VATRate = "5%"
+	VAT7    VATRate = "7%"
+	VAT10   VATRate = "10%"
+	VAT20   VATRate = "20%"
+)
+
+var vatPercents = map[VATRate]int{
+	VATNone: 0,
+	VAT0:    0,
+	VAT5:    5,
+	VAT7:    7,
+	VAT10:   10,
+	VAT20:   20,
+}
+
+// Valid сообщает, поддерживается ли ставка
+func (r VATRate) Valid() bool {
+	_, ok := vatPercents[r]
+	return ok
+}
+
+// Of сумма НДС по ставке r, начисляемая сверх суммы amount без налога
+func (r VATRate) Of(amount Money) Money {
+	return amount.Percent(vatPercents[r])
+}
+
+// Label ставка для печатных форм: "20%" или "без НДС"
+func (r VATRate) Label() string {
+	if r == VATNone {
+		return "без НДС"
+	}
+	return string(r)
+}
+
+// CurrencyRUB код рубля. Цены в рублях хранятся с пустым кодом валюты.
+const CurrencyRUB = "RUB"
+
+// NormalizeCurrency приводит код валюты к верхнему регистру. Рубль (RUB, RUR)
+// и пустой код дают пустую строку - цена в рублях, пересчет не нужен.
+func NormalizeCurrency(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == CurrencyRUB || code == "RUR" {
+		return ""
+	}
+	return code
+}
+
+// ValidCurrency проверяет буквенный код валюты ISO 4217 (три латинские буквы)
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package model
+
+import "testing"
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Money
+	}{
+		{"0", 0},
+		{"1234.56", 123456},
+		{"1 234,56", 123456},
+		{"1 234.5", 123450},
+		{"+7", 700},
+		{".5", 50},
+		{"5.", 500},
+		{"-0.5", -50},
+		// Третий знак округляет до копейки, половина - от нуля
+		{"0.004", 0},
+		{"0.005", 1},
+		{"0.0049999", 0},
+		{"2.675", 268},
+		{"-0.005", -1},
+		{"-2.674", -267},
+		{"1e3", 100000},
+		{"1.5E-2", 2},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.value)
+		if err != nil {
+			t.Errorf("ParseMoney(%q): %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseMoneyErrors(t *testing.T) {
+	for _, value := range []string{"", " ", "abc", "1.2.3", "12a", "-", "NaN", "Inf", "99999999999999999999"} {
+		if got, err := ParseMoney(value); err == nil {
+			t.Errorf("ParseMoney(%q) = %d, want error", value, got)
+		}
+	}
+}
+
+func TestMoneyPercent(t *testing.T) {
+	tests := []struct {
+		amount  Money
+		percent int
+		want    Money
+	}{
+		{10000, 20, 2000},
+		{0, 20, 0},
+		{1, 20, 0},
+		// 0.20 * 0.025 = 0.005 - половина копейки округляется от нуля
+		{25, 20, 5},
+		{2, 25, 1},
+		{-2, 25, -1},
+		{3, 10, 0},
+		{15, 10, 2},
+		{-15, 10, -2},
+		{12345, 0, 0},
+		{99999, 7, 7000},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Percent(tt.percent); got != tt.want {
+			t.Errorf("Money(%d).Percent(%d) = %d, want %d", tt.amount, tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMulRate(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Money
+		rate   Rate
+		want   Money
+	}{
+		{"единица", 12345, NewRate(1, 1), 12345},
+		{"ноль в знаменателе", 12345, NewRate(1, 0), 0},
+		{"треть", 100, NewRate(1, 3), 33},
+		{"две трети", 100, NewRate(2, 3), 67},
+		{"половина вверх", 1, NewRate(1, 2), 1},
+		{"половина от нуля", -1, NewRate(1, 2), -1},
+		{"отрицательный знаменатель", 100, NewRate(1, -3), -33},
+		{"курс за 100 единиц", 1500000, DecimalRate(61.2345, 4).Div(100), 918518},
+		{"курс доллара", 120000, DecimalRate(92.5058, 4), 11100696},
+		{"норма 1/36", 10000000, NewRate(1, 36), 277778},
+		// Произведение больше int64 считается точно
+		{"большое произведение", 900000000000000, NewRate(900000, 1000000), 810000000000000},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.MulRate(tt.rate); got != tt.want {
+			t.Errorf("%s: Money(%d).MulRate(%d/%d) = %d, want %d", tt.name, tt.amount, tt.rate.Num, tt.rate.Den, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalRate(t *testing.T) {
+	tests := []struct {
+		value  float64
+		places int
+		want   Rate
+	}{
+		{1.5, 2, Rate{150, 100}},
+		{92.5058, 4, Rate{925058, 10000}},
+		{0.1 + 0.2, 2, Rate{30, 100}},
+		{2.005, 0, Rate{2, 1}},
+		{-1.25, 1, Rate{-13, 10}},
+	}
+	for _, tt := range tests {
+		if got := DecimalRate(tt.value, tt.places); got != tt.want {
+			t.Errorf("DecimalRate(%v, %d) = %v, want %v", tt.value, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		amount Money
+		want   string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{-5, "-0.05"},
+		{123450, "1234.50"},
+		{-123456, "-1234.56"},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
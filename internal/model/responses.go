@@ -10,6 +10,12 @@ type LoginResponse struct {
 	Token string `json:"token"`
 }
 
+// Сообщения об ошибках авторизации, одинаковые для всех сервисов
+const (
+	MessageUnauthorized = "Требуется авторизация"
+	MessageForbidden    = "Доступ запрещен"
+)
+
 // Response базовый тип ответа
 type Response[T any] struct {
 	Model   T      `json:"model"`
@@ -27,6 +33,11 @@ type EquipmentListResponse struct {
 	Message string      `json:"msg"`
 }
 
+type EquipmentPageResponse struct {
+	Model   *Page[Equipment] `json:"model"`
+	Message string           `json:"msg"`
+}
+
 type DocumentResponse struct {
 	Model   *Document `json:"model"`
 	Message string    `json:"msg"`
@@ -37,6 +48,11 @@ type DocumentListResponse struct {
 	Message string     `json:"msg"`
 }
 
+type DocumentPageResponse struct {
+	Model   *Page[Document] `json:"model"`
+	Message string          `json:"msg"`
+}
+
 type MovementResponse struct {
 	Model   *Movement `json:"model"`
 	Message string    `json:"msg"`
@@ -47,6 +63,11 @@ type MovementListResponse struct {
 	Message string     `json:"msg"`
 }
 
+type MovementPageResponse struct {
+	Model   *Page[Movement] `json:"model"`
+	Message string          `json:"msg"`
+}
+
 type SupplierResponse struct {
 	Model   *Supplier `json:"model"`
 	Message string    `json:"msg"`
@@ -57,6 +78,11 @@ type SupplierListResponse struct {
 	Message string     `json:"msg"`
 }
 
+type SupplierPageResponse struct {
+	Model   *Page[Supplier] `json:"model"`
+	Message string          `json:"msg"`
+}
+
 type LocationResponse struct {
 	Model   *Location `json:"model"`
 	Message string    `json:"msg"`
@@ -67,7 +93,109 @@ type LocationListResponse struct {
 	Message string     `json:"msg"`
 }
 
+type LocationTreeResponse struct {
+	Model   []LocationNode `json:"model"`
+	Message string         `json:"msg"`
+}
+
 type DocumentExportResponse struct {
 	Content string `json:"content"`
 	Message string `json:"message"`
 }
+
+// ReportResponse файл отчета XLSX или PDF в base64
+type ReportResponse struct {
+	Content string `json:"content"`
+	Message string `json:"message"`
+}
+
+// LabelSheetResponse лист этикеток PDF в base64
+type LabelSheetResponse struct {
+	Content string `json:"content"`
+	Message string `json:"message"`
+}
+
+type AuditListResponse struct {
+	Model   []AuditEntry `json:"model"`
+	Message string       `json:"msg"`
+}
+
+type SearchResponse struct {
+	Model   []SearchHit `json:"model"`
+	Message string      `json:"msg"`
+}
+
+type EmployeeResponse struct {
+	Model   *Employee `json:"model"`
+	Message string    `json:"msg"`
+}
+
+type EmployeeListResponse struct {
+	Model   []Employee `json:"model"`
+	Message string     `json:"msg"`
+}
+
+type ImportPreviewResponse struct {
+	Model   *ImportPreview `json:"model"`
+	Message string         `json:"msg"`
+}
+
+type ImportResultResponse struct {
+	Model   *ImportResult `json:"model"`
+	Message string        `json:"msg"`
+}
+
+type InventoryDiscrepanciesResponse struct {
+	Model   *InventoryDiscrepancies `json:"model"`
+	Message string                  `json:"msg"`
+}
+
+type ScanResponse struct {
+	Model   *ScanResult `json:"model"`
+	Message string      `json:"msg"`
+}
+
+type ScanBatchResponse struct {
+	Model   *ScanBatch `json:"model"`
+	Message string     `json:"msg"`
+}
+
+type EquipmentStatusHistoryResponse struct {
+	Model   []EquipmentStatusChange `json:"model"`
+	Message string                  `json:"msg"`
+}
+
+type MaintenanceResponse struct {
+	Model   *MaintenanceRecord `json:"model"`
+	Message string             `json:"msg"`
+}
+
+type MaintenanceListResponse struct {
+	Model   []MaintenanceRecord `json:"model"`
+	Message string              `json:"msg"`
+}
+
+type MaintenanceDueResponse struct {
+	Model   []MaintenanceDue `json:"model"`
+	Message string           `json:"msg"`
+}
+
+type DepreciationRunResponse struct {
+	Model   *DepreciationRun `json:"model"`
+	Message string           `json:"msg"`
+}
+
+type DepreciationRunListResponse struct {
+	Model   []DepreciationRun `json:"model"`
+	Message string            `json:"msg"`
+}
+
+type ExchangeRateResponse struct {
+	Model   *ExchangeRate `json:"model"`
+	Message string        `json:"msg"`
+}
+
+type ExchangeRateListResponse struct {
+	Model   []ExchangeRate `json:"model"`
+	Message string         `json:"msg"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import "testing"
+
+func TestEquipmentStatusCanChangeTo(t *testing.T) {
+	statuses := []EquipmentStatus{EquipmentAvailable, EquipmentInUse, EquipmentMaintenance, EquipmentWrittenOff}
+	allowed := map[EquipmentStatus]map[EquipmentStatus]bool{
+		EquipmentAvailable:   {EquipmentInUse: true, EquipmentMaintenance: true, EquipmentWrittenOff: true},
+		EquipmentInUse:       {EquipmentAvailable: true, EquipmentMaintenance: true, EquipmentWrittenOff: true},
+		EquipmentMaintenance: {EquipmentAvailable: true, EquipmentWrittenOff: true},
+		// Списание - конечный статус
+		EquipmentWrittenOff: {},
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if got := from.CanChangeTo(to); got != allowed[from][to] {
+				t.Errorf("%s -> %s: CanChangeTo = %v, want %v", from, to, got, allowed[from][to])
+			}
+		}
+		if from.CanChangeTo("lost") {
+			t.Errorf("%s -> lost: переход в неизвестный статус разрешен", from)
+		}
+	}
+
+	for _, status := range statuses {
+		if !status.Valid() {
+			t.Errorf("%s: Valid = false", status)
+		}
+	}
+	for _, status := range []EquipmentStatus{"", "lost", "Available"} {
+		if status.Valid() {
+			t.Errorf("%q: Valid = true", status)
+		}
+		if status.CanChangeTo(EquipmentAvailable) {
+			t.Errorf("%q -> available: переход из неизвестного статуса разрешен", status)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"time"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// AuditRepository только читает журнал аудита, записи создаются
+// callbacks, зарегистрированными storage.EnableAudit
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) GetAuditEntries(filter model.AuditFilter) model.Response[[]model.AuditEntry] {
+	query := r.db.Preload("User")
+
+	if filter.Entity != "" {
+		query = query.Where("entity = ?", filter.Entity)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.DateFrom != "" {
+		from, err := time.ParseInLocation("2006-01-02", filter.DateFrom, time.Local)
+		if err != nil {
+			return model.Response[[]model.AuditEntry]{
+				Message: "Неверная начальная дата: " + err.Error(),
+			}
+		}
+		query = query.Where("created_at >= ?", from)
+	}
+	if filter.DateTo != "" {
+		to, err := time.ParseInLocation("2006-01-02", filter.DateTo, time.Local)
+		if err != nil {
+			return model.Response[[]model.AuditEntry]{
+				Message: "Неверная конечная дата: " + err.Error(),
+			}
+		}
+		query = query.Where("created_at < ?", to.AddDate(0, 0, 1))
+	}
+
+	var entries []model.AuditEntry
+	if err := query.Order("created_at DESC, id DESC").Find(&entries).Error; err != nil {
+		return model.Response[[]model.AuditEntry]{
+			Message: err.Error(),
+		}
+	}
+
+	for i := range entries {
+		if entries[i].User != nil {
+			entries[i].User.Password = ""
+		}
+	}
+
+	return model.Response[[]model.AuditEntry]{
+		Model: entries,
+	}
+}
@@ -1,11 +1,20 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"sort"
 	"tohaboy/internal/model"
 
 	"gorm.io/gorm"
 )
 
+// subtreeCategoryIDs подзапрос идентификаторов категории ? и всех ее подкатегорий
+const subtreeCategoryIDs = "SELECT id FROM categories WHERE path LIKE (SELECT path FROM categories WHERE id = ?) || '%'"
+
+// subtreeCategoryValue тот же подзапрос для фильтров списков с параметром @value
+const subtreeCategoryValue = "SELECT id FROM categories WHERE path LIKE (SELECT path FROM categories WHERE id = @value) || '%'"
+
 type CategoryRepository struct {
 	db *gorm.DB
 }
@@ -14,11 +23,28 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepositoryInterface {
 	return &CategoryRepository{db: db}
 }
 
+// CreateCategory создает категорию и вычисляет ее путь в дереве
 func (r *CategoryRepository) CreateCategory(category *model.Category) model.Response[*model.Category] {
-	if err := r.db.Create(category).Error; err != nil {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		parentPath := ""
+		if category.ParentID != 0 {
+			parent, err := findCategory(tx, category.ParentID)
+			if err != nil {
+				return fmt.Errorf("родительская категория: %w", err)
+			}
+			parentPath = parent.Path
+		}
+
+		category.Path = ""
+		if err := tx.Create(category).Error; err != nil {
+			return err
+		}
+		category.Path = treePath(parentPath, category.ID)
+		return tx.Model(category).Update("path", category.Path).Error
+	}); err != nil {
 		return model.Response[*model.Category]{Message: err.Error()}
 	}
-	return model.Response[*model.Category]{Model: category}
+	return r.GetCategory(int(category.ID))
 }
 
 func (r *CategoryRepository) GetCategory(id int) model.Response[*model.Category] {
@@ -29,28 +55,101 @@ func (r *CategoryRepository) GetCategory(id int) model.Response[*model.Category]
 	return model.Response[*model.Category]{Model: &category}
 }
 
+// GetAllCategories возвращает все категории в порядке обхода дерева
 func (r *CategoryRepository) GetAllCategories() model.Response[[]model.Category] {
 	var categories []model.Category
 	if err := r.db.Find(&categories).Error; err != nil {
 		return model.Response[[]model.Category]{Message: err.Error()}
 	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].FullName < categories[j].FullName
+	})
 	return model.Response[[]model.Category]{Model: categories}
 }
 
+// UpdateCategory обновляет категорию. При смене родителя категория
+// переносится вместе со всеми подкатегориями.
 func (r *CategoryRepository) UpdateCategory(category *model.Category) model.Response[*model.Category] {
-	if err := r.db.Save(category).Error; err != nil {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		existing, err := findCategory(tx, category.ID)
+		if err != nil {
+			return err
+		}
+		if existing.ParentID != category.ParentID {
+			if err := moveCategory(tx, existing, category.ParentID); err != nil {
+				return err
+			}
+		}
+		category.ParentID, category.Path = existing.ParentID, existing.Path
+		return tx.Omit("parent_id", "path").Save(category).Error
+	}); err != nil {
 		return model.Response[*model.Category]{Message: err.Error()}
 	}
-	return model.Response[*model.Category]{Model: category}
+	return r.GetCategory(int(category.ID))
 }
 
+// DeleteCategory удаляет категорию без подкатегорий, к которой не отнесено
+// оборудование. Проверки и удаление выполняются в одной транзакции.
 func (r *CategoryRepository) DeleteCategory(id int) model.Response[*model.Category] {
-	var category model.Category
-	if err := r.db.First(&category, id).Error; err != nil {
+	var category *model.Category
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if category, err = findCategory(tx, uint(id)); err != nil {
+			return err
+		}
+
+		var children int64
+		if err := tx.Model(&model.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return fmt.Errorf("Нельзя удалить категорию: у нее есть подкатегории (%d)", children)
+		}
+
+		var equipment int64
+		if err := tx.Model(&model.Equipment{}).Where("category_id = ?", id).Count(&equipment).Error; err != nil {
+			return err
+		}
+		if equipment > 0 {
+			return fmt.Errorf("Нельзя удалить категорию: к ней отнесено оборудование (%d)", equipment)
+		}
+
+		return tx.Delete(category).Error
+	}); err != nil {
 		return model.Response[*model.Category]{Message: err.Error()}
 	}
-	if err := r.db.Delete(&category).Error; err != nil {
-		return model.Response[*model.Category]{Message: err.Error()}
+	return model.Response[*model.Category]{Model: category}
+}
+
+func findCategory(db *gorm.DB, id uint) (*model.Category, error) {
+	var category model.Category
+	if err := db.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("категория %d не найдена", id)
+		}
+		return nil, fmt.Errorf("ошибка при получении категории: %w", err)
 	}
-	return model.Response[*model.Category]{Model: &category}
+	return &category, nil
+}
+
+// moveCategory переносит категорию под parentID вместе со всеми подкатегориями
+func moveCategory(tx *gorm.DB, category *model.Category, parentID uint) error {
+	newPath := treePath("", category.ID)
+	if parentID != 0 {
+		parent, err := findCategory(tx, parentID)
+		if err != nil {
+			return fmt.Errorf("родительская категория: %w", err)
+		}
+		if category.Contains(parent) {
+			return errors.New("нельзя перенести категорию внутрь самой себя")
+		}
+		newPath = treePath(parent.Path, category.ID)
+	}
+
+	if err := moveTreeNode(tx, category, category.Path, newPath, parentID); err != nil {
+		return fmt.Errorf("ошибка при переносе категории: %w", err)
+	}
+	category.ParentID = parentID
+	category.Path = newPath
+	return nil
 }
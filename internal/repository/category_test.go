@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"tohaboy/internal/model"
+)
+
+func TestDeleteCategory(t *testing.T) {
+	f := newPostingFixture(t)
+	categories := NewCategoryRepository(f.db)
+
+	newCategory := func(t *testing.T, name string, parentID uint) uint {
+		t.Helper()
+		created := categories.CreateCategory(&model.Category{Name: name, ParentID: parentID})
+		if created.Model == nil {
+			t.Fatalf("CreateCategory: %s", created.Message)
+		}
+		return created.Model.ID
+	}
+
+	empty := newCategory(t, "Мебель", 0)
+	parent := newCategory(t, "Техника", 0)
+	newCategory(t, "Ноутбуки", parent)
+	used := newCategory(t, "Принтеры", 0)
+	if err := f.db.Model(&model.Equipment{}).Where("id = ?", f.equipment).Update("category_id", used).Error; err != nil {
+		t.Fatalf("назначение категории: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      uint
+		message string // пусто - удаление разрешено
+	}{
+		{"пустая", empty, ""},
+		{"с подкатегориями", parent, "подкатегории"},
+		{"с оборудованием", used, "оборудование"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := categories.DeleteCategory(int(tt.id))
+
+			var count int64
+			f.db.Model(&model.Category{}).Where("id = ?", tt.id).Count(&count)
+			if tt.message == "" {
+				if deleted.Model == nil || count != 0 {
+					t.Fatalf("категория не удалена: %s", deleted.Message)
+				}
+				return
+			}
+			if deleted.Model != nil || count != 1 {
+				t.Fatalf("удалена категория (%s)", tt.name)
+			}
+			if !strings.Contains(deleted.Message, tt.message) {
+				t.Errorf("сообщение %q, ожидалось упоминание %q", deleted.Message, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,456 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// defaultDepreciationFactor коэффициент ускорения для способа уменьшаемого остатка,
+// если в категории он не задан
+const defaultDepreciationFactor = 2
+
+// maxScheduleMonths наибольшая длина графика амортизации
+const maxScheduleMonths = 36
+
+type DepreciationRepository struct {
+	db *gorm.DB
+}
+
+func NewDepreciationRepository(db *gorm.DB) *DepreciationRepository {
+	return &DepreciationRepository{db: db}
+}
+
+// RunDepreciation начисляет амортизацию за месяц period по всему оборудованию,
+// введенному в эксплуатацию до этого месяца. Месяцы начисляются по порядку:
+// после первого начисления следующее возможно только за следующий месяц.
+func (r *DepreciationRepository) RunDepreciation(period time.Time, userID uint) model.Response[*model.DepreciationRun] {
+	period = monthStart(period)
+	run := &model.DepreciationRun{Period: period, CreatedByID: userID}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if period.After(monthStart(time.Now())) {
+			return fmt.Errorf("амортизация за будущий месяц не начисляется")
+		}
+
+		last, err := lastDepreciationRun(tx)
+		if err != nil {
+			return err
+		}
+		if last != nil {
+			next := monthStart(last.Period).AddDate(0, 1, 0)
+			if period.Before(next) {
+				return fmt.Errorf("амортизация за %s уже начислена", period.Format("01.2006"))
+			}
+			if period.After(next) {
+				return fmt.Errorf("сначала начислите амортизацию за %s", next.Format("01.2006"))
+			}
+		}
+
+		var equipment []model.Equipment
+		if err := tx.Preload("Category").
+			Where("status <> ? AND quantity > 0 AND commissioned_at IS NOT NULL", model.EquipmentWrittenOff).
+			Order("id").
+			Find(&equipment).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Postings", "CreatedBy").Create(run).Error; err != nil {
+			return err
+		}
+		for i := range equipment {
+			item := &equipment[i]
+			terms, ok := depreciationTermsOf(item)
+			if !ok {
+				continue
+			}
+			unit := terms.amount(item, item.AccumulatedDepreciation, period)
+			if unit == 0 {
+				continue
+			}
+
+			posting := model.DepreciationPosting{
+				RunID:       run.ID,
+				EquipmentID: item.ID,
+				Method:      terms.method,
+				Quantity:    item.Quantity,
+				BookValue:   item.Price - item.AccumulatedDepreciation,
+				UnitAmount:  unit,
+				Amount:      unit.Mul(item.Quantity),
+			}
+			if err := tx.Omit("Equipment").Create(&posting).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(item).
+				Update("accumulated_depreciation", item.AccumulatedDepreciation+unit).Error; err != nil {
+				return err
+			}
+			run.Total += posting.Amount
+		}
+
+		return tx.Model(run).Update("total", run.Total).Error
+	})
+	if err != nil {
+		return model.Response[*model.DepreciationRun]{
+			Message: err.Error(),
+		}
+	}
+
+	response := r.GetDepreciationRun(run.ID)
+	response.Message = fmt.Sprintf("Амортизация за %s начислена", period.Format("01.2006"))
+	return response
+}
+
+// CancelDepreciation отменяет последнее начисление: накопленная амортизация
+// оборудования уменьшается на суммы его проводок
+func (r *DepreciationRepository) CancelDepreciation(id uint) model.Response[*model.DepreciationRun] {
+	var run model.DepreciationRun
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Postings").First(&run, id).Error; err != nil {
+			return fmt.Errorf("начисление не найдено")
+		}
+		last, err := lastDepreciationRun(tx)
+		if err != nil {
+			return err
+		}
+		if last.ID != run.ID {
+			return fmt.Errorf("отменить можно только последнее начисление")
+		}
+
+		for _, posting := range run.Postings {
+			if err := tx.Model(&model.Equipment{}).Where("id = ?", posting.EquipmentID).
+				Update("accumulated_depreciation", gorm.Expr("accumulated_depreciation - ?", posting.UnitAmount)).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("run_id = ?", run.ID).Delete(&model.DepreciationPosting{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&run).Error
+	})
+	if err != nil {
+		return model.Response[*model.DepreciationRun]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[*model.DepreciationRun]{
+		Model:   &run,
+		Message: fmt.Sprintf("Начисление амортизации за %s отменено", run.Period.Format("01.2006")),
+	}
+}
+
+func (r *DepreciationRepository) GetDepreciationRun(id uint) model.Response[*model.DepreciationRun] {
+	var run model.DepreciationRun
+
+	if err := r.db.Preload("CreatedBy").
+		Preload("Postings", func(db *gorm.DB) *gorm.DB { return db.Order("equipment_id") }).
+		Preload("Postings.Equipment").
+		First(&run, id).Error; err != nil {
+		return model.Response[*model.DepreciationRun]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[*model.DepreciationRun]{
+		Model: &run,
+	}
+}
+
+// GetDepreciationRuns возвращает начисления без проводок, последние первыми
+func (r *DepreciationRepository) GetDepreciationRuns() model.Response[[]model.DepreciationRun] {
+	var runs []model.DepreciationRun
+
+	if err := r.db.Preload("CreatedBy").Order("period DESC").Find(&runs).Error; err != nil {
+		return model.Response[[]model.DepreciationRun]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[[]model.DepreciationRun]{
+		Model: runs,
+	}
+}
+
+// GetDepreciationSchedule возвращает график амортизации по месяцам периода filter.
+// Месяцы, за которые начисление уже проводилось, берутся из проводок, остальные
+// рассчитываются по прогнозу от текущей накопленной амортизации. По умолчанию
+// график строится на текущий год.
+func (r *DepreciationRepository) GetDepreciationSchedule(filter model.ReportFilter) model.Response[*model.DepreciationSchedule] {
+	months, err := scheduleMonths(filter)
+	if err != nil {
+		return model.Response[*model.DepreciationSchedule]{
+			Message: err.Error(),
+		}
+	}
+	from := months[0]
+
+	query := r.db.Preload("Category").Where("commissioned_at IS NOT NULL")
+	if filter.CategoryID != 0 {
+		query = query.Where("category_id IN ("+subtreeCategoryIDs+")", filter.CategoryID)
+	}
+	if filter.LocationID != 0 {
+		query = query.Where("location_id IN ("+subtreeLocationIDs+")", filter.LocationID)
+	}
+	if filter.SupplierID != 0 {
+		query = query.Where("supplier_id = ?", filter.SupplierID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	var equipment []model.Equipment
+	if err := query.Find(&equipment).Error; err != nil {
+		return model.Response[*model.DepreciationSchedule]{
+			Message: err.Error(),
+		}
+	}
+
+	last, err := lastDepreciationRun(r.db)
+	if err != nil {
+		return model.Response[*model.DepreciationSchedule]{
+			Message: err.Error(),
+		}
+	}
+
+	var postings []struct {
+		EquipmentID uint
+		UnitAmount  model.Money
+		Amount      model.Money
+		Period      time.Time
+	}
+	if err := r.db.Table("depreciation_postings AS p").
+		Select("p.equipment_id, p.unit_amount, p.amount, r.period").
+		Joins("JOIN depreciation_runs r ON r.id = p.run_id").
+		Where("r.period >= ?", from).
+		Scan(&postings).Error; err != nil {
+		return model.Response[*model.DepreciationSchedule]{
+			Message: err.Error(),
+		}
+	}
+	type posted struct{ unit, amount model.Money }
+	byEquipment := make(map[uint]map[int]posted)
+	later := make(map[uint]model.Money)
+	for _, p := range postings {
+		later[p.EquipmentID] += p.UnitAmount
+		if byEquipment[p.EquipmentID] == nil {
+			byEquipment[p.EquipmentID] = make(map[int]posted)
+		}
+		byEquipment[p.EquipmentID][monthsBetween(from, p.Period)] = posted{p.UnitAmount, p.Amount}
+	}
+
+	schedule := &model.DepreciationSchedule{
+		Months:   months,
+		Forecast: make([]bool, len(months)),
+		Rows:     []model.DepreciationScheduleRow{},
+	}
+	for i, month := range months {
+		schedule.Forecast[i] = last == nil || month.After(monthStart(last.Period))
+	}
+
+	for i := range equipment {
+		item := &equipment[i]
+		terms, ok := depreciationTermsOf(item)
+		if !ok && byEquipment[item.ID] == nil {
+			continue
+		}
+
+		accumulated := item.AccumulatedDepreciation - later[item.ID]
+		row := model.DepreciationScheduleRow{
+			EquipmentID:      item.ID,
+			InventoryNumber:  item.InventoryNumber,
+			Name:             item.Name,
+			CommissionedAt:   item.CommissionedAt,
+			Method:           terms.method,
+			UsefulLifeMonths: terms.life,
+			Quantity:         item.Quantity,
+			Cost:             item.Price.Mul(item.Quantity),
+			ResidualValue:    item.ResidualValue.Mul(item.Quantity),
+			Opening:          (item.Price - accumulated).Mul(item.Quantity),
+			Amounts:          make([]model.Money, len(months)),
+		}
+		if item.Category != nil {
+			row.Category = item.Category.FullName
+			row.OKOF = item.Category.Effective.OKOF
+			row.Group = item.Category.Effective.DepreciationGroup
+		}
+
+		forecast := ok && item.Status != model.EquipmentWrittenOff && item.Quantity > 0
+		for m, month := range months {
+			if !schedule.Forecast[m] {
+				if p, found := byEquipment[item.ID][m]; found {
+					row.Amounts[m] = p.amount
+					accumulated += p.unit
+				}
+				continue
+			}
+			if forecast {
+				unit := terms.amount(item, accumulated, month)
+				row.Amounts[m] = unit.Mul(item.Quantity)
+				accumulated += unit
+			}
+		}
+		row.Closing = (item.Price - accumulated).Mul(item.Quantity)
+		schedule.Rows = append(schedule.Rows, row)
+	}
+
+	sort.Slice(schedule.Rows, func(i, j int) bool {
+		a, b := schedule.Rows[i], schedule.Rows[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.InventoryNumber != b.InventoryNumber {
+			return a.InventoryNumber < b.InventoryNumber
+		}
+		return a.EquipmentID < b.EquipmentID
+	})
+
+	return model.Response[*model.DepreciationSchedule]{
+		Model: schedule,
+	}
+}
+
+// depreciationTerms условия амортизации оборудования
+type depreciationTerms struct {
+	method string
+	life   int
+	factor float64
+	start  time.Time // первый месяц начисления
+}
+
+// depreciationTermsOf условия амортизации оборудования: собственные, а не заданные -
+// из категории (с учетом унаследованных от родительских категорий). Срок, не заданный
+// и в категории, - наименьший срок ее амортизационной группы.
+// Без способа, срока, даты ввода или стоимости амортизация не начисляется.
+func depreciationTermsOf(equipment *model.Equipment) (depreciationTerms, bool) {
+	terms := depreciationTerms{
+		method: equipment.DepreciationMethod,
+		life:   equipment.UsefulLifeMonths,
+		factor: defaultDepreciationFactor,
+	}
+	if category := equipment.Category; category != nil {
+		defaults := category.Effective
+		if terms.method == "" {
+			terms.method = defaults.DepreciationMethod
+		}
+		if terms.life == 0 {
+			terms.life = defaults.UsefulLifeMonths
+		}
+		if terms.life == 0 {
+			terms.life, _, _ = model.DepreciationGroupLife(defaults.DepreciationGroup)
+		}
+		if defaults.DepreciationFactor > 0 {
+			terms.factor = defaults.DepreciationFactor
+		}
+	}
+	if terms.method == "" || terms.life <= 0 || equipment.CommissionedAt == nil || equipment.Price <= 0 {
+		return terms, false
+	}
+	terms.start = monthStart(*equipment.CommissionedAt).AddDate(0, 1, 0)
+	return terms, true
+}
+
+// amount амортизация единицы оборудования за месяц period при накопленной
+// амортизации accumulated. Остаточная стоимость не опускается ниже ликвидационной,
+// в последнем месяце срока полезного использования списывается весь остаток.
+func (t depreciationTerms) amount(equipment *model.Equipment, accumulated model.Money, period time.Time) model.Money {
+	if period.Before(t.start) {
+		return 0
+	}
+	remaining := equipment.Price - equipment.ResidualValue - accumulated
+	if remaining <= 0 {
+		return 0
+	}
+
+	var amount model.Money
+	switch t.method {
+	case model.DepreciationStraightLine:
+		amount = (equipment.Price - equipment.ResidualValue).MulRate(model.NewRate(1, int64(t.life)))
+	case model.DepreciationReducingBalance:
+		// коэффициент ускорения задается с точностью до сотых
+		amount = (equipment.Price - accumulated).MulRate(model.DecimalRate(t.factor, 2).Div(int64(t.life)))
+	default:
+		return 0
+	}
+
+	if monthsBetween(t.start, period) >= t.life-1 || amount > remaining {
+		amount = remaining
+	}
+	return amount
+}
+
+// validateDepreciation проверяет условия амортизации оборудования
+func validateDepreciation(equipment *model.Equipment) error {
+	switch equipment.DepreciationMethod {
+	case "", model.DepreciationStraightLine, model.DepreciationReducingBalance:
+	default:
+		return fmt.Errorf("неизвестный способ амортизации: %s", equipment.DepreciationMethod)
+	}
+	if equipment.UsefulLifeMonths < 0 {
+		return fmt.Errorf("срок полезного использования не может быть отрицательным")
+	}
+	if equipment.ResidualValue < 0 || equipment.ResidualValue > equipment.Price {
+		return fmt.Errorf("ликвидационная стоимость должна быть от нуля до стоимости оборудования")
+	}
+	if equipment.AccumulatedDepreciation < 0 || equipment.AccumulatedDepreciation > equipment.Price-equipment.ResidualValue {
+		return fmt.Errorf("накопленная амортизация превышает амортизируемую стоимость")
+	}
+	return nil
+}
+
+// scheduleMonths месяцы графика амортизации: с месяца DateFrom по месяц DateTo
+func scheduleMonths(filter model.ReportFilter) ([]time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.Local)
+	if filter.DateFrom != "" {
+		date, err := time.ParseInLocation("2006-01-02", filter.DateFrom, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("неверная начальная дата: %v", err)
+		}
+		from = monthStart(date)
+	}
+	to := from.AddDate(0, 11, 0)
+	if filter.DateTo != "" {
+		date, err := time.ParseInLocation("2006-01-02", filter.DateTo, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("неверная конечная дата: %v", err)
+		}
+		to = monthStart(date)
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("начало периода позже его окончания")
+	}
+	if monthsBetween(from, to) >= maxScheduleMonths {
+		return nil, fmt.Errorf("график амортизации строится не более чем на %d месяцев", maxScheduleMonths)
+	}
+
+	var months []time.Time
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		months = append(months, month)
+	}
+	return months, nil
+}
+
+// lastDepreciationRun последнее начисление амортизации или nil
+func lastDepreciationRun(tx *gorm.DB) (*model.DepreciationRun, error) {
+	var run model.DepreciationRun
+	err := tx.Order("period DESC").First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func monthStart(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"testing"
+	"time"
+	"tohaboy/internal/model"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.Local)
+}
+
+func TestDepreciationTermsOf(t *testing.T) {
+	commissioned := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	category := func(defaults model.CategoryDefaults) *model.Category {
+		return &model.Category{Effective: defaults}
+	}
+
+	tests := []struct {
+		name      string
+		equipment model.Equipment
+		want      depreciationTerms
+		ok        bool
+	}{
+		{
+			name: "собственные условия",
+			equipment: model.Equipment{Price: 100000, CommissionedAt: &commissioned,
+				DepreciationMethod: model.DepreciationStraightLine, UsefulLifeMonths: 36,
+				Category: category(model.CategoryDefaults{DepreciationMethod: model.DepreciationReducingBalance, UsefulLifeMonths: 60})},
+			want: depreciationTerms{method: model.DepreciationStraightLine, life: 36, factor: defaultDepreciationFactor, start: month(2024, time.February)},
+			ok:   true,
+		},
+		{
+			name: "условия категории",
+			equipment: model.Equipment{Price: 100000, CommissionedAt: &commissioned,
+				Category: category(model.CategoryDefaults{DepreciationMethod: model.DepreciationReducingBalance, UsefulLifeMonths: 60, DepreciationFactor: 1.5})},
+			want: depreciationTerms{method: model.DepreciationReducingBalance, life: 60, factor: 1.5, start: month(2024, time.February)},
+			ok:   true,
+		},
+		{
+			name: "наименьший срок амортизационной группы",
+			equipment: model.Equipment{Price: 100000, CommissionedAt: &commissioned,
+				Category: category(model.CategoryDefaults{DepreciationMethod: model.DepreciationStraightLine, DepreciationGroup: 3})},
+			want: depreciationTerms{method: model.DepreciationStraightLine, life: 37, factor: defaultDepreciationFactor, start: month(2024, time.February)},
+			ok:   true,
+		},
+		{
+			name:      "без способа",
+			equipment: model.Equipment{Price: 100000, CommissionedAt: &commissioned, UsefulLifeMonths: 36},
+		},
+		{
+			name:      "не введено в эксплуатацию",
+			equipment: model.Equipment{Price: 100000, DepreciationMethod: model.DepreciationStraightLine, UsefulLifeMonths: 36},
+		},
+		{
+			name:      "без стоимости",
+			equipment: model.Equipment{CommissionedAt: &commissioned, DepreciationMethod: model.DepreciationStraightLine, UsefulLifeMonths: 36},
+		},
+	}
+
+	for _, tt := range tests {
+		terms, ok := depreciationTermsOf(&tt.equipment)
+		if ok != tt.ok {
+			t.Errorf("%s: ok = %v, ожидалось %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if ok && terms != tt.want {
+			t.Errorf("%s: %+v, ожидалось %+v", tt.name, terms, tt.want)
+		}
+	}
+}
+
+func TestDepreciationAmount(t *testing.T) {
+	start := month(2024, time.February)
+	straight := depreciationTerms{method: model.DepreciationStraightLine, life: 3, factor: defaultDepreciationFactor, start: start}
+	reducing := depreciationTerms{method: model.DepreciationReducingBalance, life: 12, factor: defaultDepreciationFactor, start: start}
+
+	tests := []struct {
+		name        string
+		terms       depreciationTerms
+		price       model.Money
+		residual    model.Money
+		accumulated model.Money
+		period      time.Time
+		want        model.Money
+	}{
+		// Линейный способ: 1000,00 на 3 месяца по 333,33, остаток копеек - в последнем месяце
+		{"линейный до начала", straight, 100000, 0, 0, month(2024, time.January), 0},
+		{"линейный первый месяц", straight, 100000, 0, 0, start, 33333},
+		{"линейный второй месяц", straight, 100000, 0, 33333, month(2024, time.March), 33333},
+		{"линейный последний месяц", straight, 100000, 0, 66666, month(2024, time.April), 33334},
+		{"линейный после срока", straight, 100000, 0, 100000, month(2024, time.May), 0},
+		{"линейный с ликвидационной стоимостью", straight, 100000, 10000, 0, start, 30000},
+
+		// Уменьшаемый остаток: 2/12 от остаточной стоимости
+		{"уменьшаемый остаток первый месяц", reducing, 120000, 0, 0, start, 20000},
+		{"уменьшаемый остаток второй месяц", reducing, 120000, 0, 20000, month(2024, time.March), 16667},
+		{"уменьшаемый остаток до ликвидационной", reducing, 120000, 110000, 0, start, 10000},
+		{"уменьшаемый остаток последний месяц", reducing, 120000, 0, 90000, month(2025, time.January), 30000},
+
+		{"неизвестный способ", depreciationTerms{method: "sum_of_years", life: 12, start: start}, 120000, 0, 0, start, 0},
+	}
+
+	for _, tt := range tests {
+		equipment := &model.Equipment{Price: tt.price, ResidualValue: tt.residual}
+		if got := tt.terms.amount(equipment, tt.accumulated, tt.period); got != tt.want {
+			t.Errorf("%s: %d, ожидалось %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunDepreciation(t *testing.T) {
+	f := newPostingFixture(t)
+	depreciation := NewDepreciationRepository(f.db)
+
+	// Оборудование фикстуры: 5 единиц по 100 000,00 на 10 месяцев - 10 000,00 в месяц
+	commissioned := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	if err := f.db.Model(&model.Equipment{}).Where("id = ?", f.equipment).Updates(map[string]interface{}{
+		"commissioned_at":     commissioned,
+		"depreciation_method": model.DepreciationStraightLine,
+		"useful_life_months":  10,
+	}).Error; err != nil {
+		t.Fatalf("условия амортизации: %v", err)
+	}
+	// Списанное оборудование не амортизируется
+	if err := f.db.Create(&model.Equipment{Name: "Старый ноутбук", SerialNumber: "SN-0", Status: model.EquipmentWrittenOff,
+		Quantity: 1, Price: 5000000, CommissionedAt: &commissioned,
+		DepreciationMethod: model.DepreciationStraightLine, UsefulLifeMonths: 10}).Error; err != nil {
+		t.Fatalf("создание оборудования: %v", err)
+	}
+
+	accumulated := func() model.Money {
+		return f.state(t).AccumulatedDepreciation
+	}
+	run := func(period time.Time) model.Response[*model.DepreciationRun] {
+		return depreciation.RunDepreciation(period, 1)
+	}
+
+	february := run(month(2024, time.February))
+	if february.Model == nil {
+		t.Fatalf("RunDepreciation: %s", february.Message)
+	}
+	if len(february.Model.Postings) != 1 || february.Model.Total != 5000000 {
+		t.Fatalf("проводок %d на %d, ожидалась одна на 5000000", len(february.Model.Postings), february.Model.Total)
+	}
+	posting := february.Model.Postings[0]
+	if posting.EquipmentID != f.equipment || posting.UnitAmount != 1000000 || posting.Quantity != 5 || posting.BookValue != 10000000 {
+		t.Errorf("проводка %+v", posting)
+	}
+	if got := accumulated(); got != 1000000 {
+		t.Errorf("накоплено %d, ожидалось 1000000", got)
+	}
+
+	for _, tt := range []struct {
+		period time.Time
+		err    string
+	}{
+		{month(2024, time.February), "амортизация за 02.2024 уже начислена"},
+		{month(2024, time.January), "амортизация за 01.2024 уже начислена"},
+		{month(2024, time.April), "сначала начислите амортизацию за 03.2024"},
+		{monthStart(time.Now()).AddDate(0, 1, 0), "амортизация за будущий месяц не начисляется"},
+	} {
+		if rejected := run(tt.period); rejected.Model != nil || rejected.Message != tt.err {
+			t.Errorf("%s: ответ %q, ожидалась ошибка %q", tt.period.Format("01.2006"), rejected.Message, tt.err)
+		}
+	}
+
+	march := run(month(2024, time.March))
+	if march.Model == nil {
+		t.Fatalf("RunDepreciation: %s", march.Message)
+	}
+	if got := accumulated(); got != 2000000 {
+		t.Errorf("накоплено %d, ожидалось 2000000", got)
+	}
+
+	// Отменить можно только последнее начисление
+	if cancelled := depreciation.CancelDepreciation(february.Model.ID); cancelled.Model != nil ||
+		cancelled.Message != "отменить можно только последнее начисление" {
+		t.Errorf("отмена февраля: ответ %q", cancelled.Message)
+	}
+	if cancelled := depreciation.CancelDepreciation(999); cancelled.Model != nil || cancelled.Message != "начисление не найдено" {
+		t.Errorf("отмена несуществующего: ответ %q", cancelled.Message)
+	}
+
+	if cancelled := depreciation.CancelDepreciation(march.Model.ID); cancelled.Model == nil {
+		t.Fatalf("CancelDepreciation: %s", cancelled.Message)
+	}
+	if got := accumulated(); got != 1000000 {
+		t.Errorf("после отмены накоплено %d, ожидалось 1000000", got)
+	}
+	var postings int64
+	f.db.Model(&model.DepreciationPosting{}).Where("run_id = ?", march.Model.ID).Count(&postings)
+	if postings != 0 {
+		t.Errorf("после отмены осталось проводок: %d", postings)
+	}
+
+	// После отмены месяц начисляется заново
+	if again := run(month(2024, time.March)); again.Model == nil || again.Model.Total != 5000000 {
+		t.Errorf("повторное начисление за март: %s", again.Message)
+	}
+}
@@ -1,49 +1,38 @@
 package repository
 
 import (
+	"time"
+	"tohaboy/internal/config"
 	"tohaboy/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DocumentRepository struct {
-	db *gorm.DB
+	db        *gorm.DB
+	numbering config.NumberingConfig
 }
 
 func (r *DocumentRepository) GetDB() *gorm.DB {
 	return r.db
 }
 
-func NewDocumentRepository(db *gorm.DB) *DocumentRepository {
-	return &DocumentRepository{db: db}
+func NewDocumentRepository(db *gorm.DB, numbering config.NumberingConfig) *DocumentRepository {
+	return &DocumentRepository{db: db, numbering: numbering}
 }
 
 func (r *DocumentRepository) CreateDocument(doc *model.Document) model.Response[*model.Document] {
 	// Начинаем транзакцию
 	tx := r.db.Begin()
 
-	// Создаем документ без items
-	items := doc.Items
-	doc.Items = nil
-	if err := tx.Create(doc).Error; err != nil {
+	if err := insertDocument(tx, r.numbering, doc); err != nil {
 		tx.Rollback()
 		return model.Response[*model.Document]{
 			Message: err.Error(),
 		}
 	}
 
-	// Создаем позиции документа
-	for i := range items {
-		items[i].DocumentID = doc.ID
-		items[i].ID = 0 // Ensure ID is zero to let GORM auto-increment
-		if err := tx.Create(&items[i]).Error; err != nil {
-			tx.Rollback()
-			return model.Response[*model.Document]{
-				Message: err.Error(),
-			}
-		}
-	}
-
 	// Подтверждаем транзакцию
 	if err := tx.Commit().Error; err != nil {
 		return model.Response[*model.Document]{
@@ -52,8 +41,10 @@ func (r *DocumentRepository) CreateDocument(doc *model.Document) model.Response[
 	}
 
 	// Загружаем созданный документ со всеми связями
-	if err := r.db.Preload("Items.Equipment").
+	if err := r.db.Preload("Items.Equipment.Category").
 		Preload("Location").
+		Preload("FromLocation").
+		Preload("Responsible").
 		Preload("CreatedBy").
 		Preload("ApprovedBy").
 		First(doc, doc.ID).Error; err != nil {
@@ -70,16 +61,39 @@ func (r *DocumentRepository) CreateDocument(doc *model.Document) model.Response[
 func (r *DocumentRepository) GetDocument(id uint) model.Response[*model.Document] {
 	var doc model.Document
 
-	if err := r.db.Preload("Items.Equipment").
+	if err := r.db.Preload("Items.Equipment.Category").
 		Preload("Location").
+		Preload("FromLocation").
+		Preload("Responsible").
 		Preload("CreatedBy").
 		Preload("ApprovedBy").
+		Preload("CanceledBy").
+		Preload("ReversalOf").
 		First(&doc, id).Error; err != nil {
 		return model.Response[*model.Document]{
 			Message: err.Error(),
 		}
 	}
 
+	// Сторнирующий документ ссылается на исходный, обратная ссылка ищется запросом
+	var reversals []model.Document
+	if err := r.db.Where("reversal_of_id = ?", doc.ID).Limit(1).Find(&reversals).Error; err != nil {
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+	if len(reversals) > 0 {
+		doc.Reversal = &reversals[0]
+	}
+
+	if doc.Type == "inventory" {
+		if err := r.db.Where("inventory_id = ?", doc.ID).Order("id").Find(&doc.Corrections).Error; err != nil {
+			return model.Response[*model.Document]{
+				Message: err.Error(),
+			}
+		}
+	}
+
 	return model.Response[*model.Document]{
 		Model: &doc,
 	}
@@ -106,6 +120,27 @@ func (r *DocumentRepository) UpdateDocument(doc *model.Document) model.Response[
 		}
 	}
 
+	// Номер выдан счетчиком при создании и не меняется. Статус, автор,
+	// утверждение, отмена и ссылки на сторнируемый документ и инвентаризацию
+	// меняются только проведением, отменой и сторно, а не редактированием.
+	doc.Number = existingDoc.Number
+	doc.Status = existingDoc.Status
+	doc.CreatedByID = existingDoc.CreatedByID
+	doc.CreatedAt = existingDoc.CreatedAt
+	doc.ApprovedByID = existingDoc.ApprovedByID
+	doc.CancelReason = existingDoc.CancelReason
+	doc.CanceledByID = existingDoc.CanceledByID
+	doc.CanceledAt = existingDoc.CanceledAt
+	doc.ReversalOfID = existingDoc.ReversalOfID
+	doc.InventoryID = existingDoc.InventoryID
+
+	if err := applyExchangeRates(tx, doc); err != nil {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+
 	// Удаляем старые позиции
 	if err := tx.Where("document_id = ?", doc.ID).Delete(&model.DocumentItem{}).Error; err != nil {
 		tx.Rollback()
@@ -114,8 +149,8 @@ func (r *DocumentRepository) UpdateDocument(doc *model.Document) model.Response[
 		}
 	}
 
-	// Обновляем документ
-	if err := tx.Save(doc).Error; err != nil {
+	// Обновляем документ; позиции создаются ниже, связанные записи не трогаем
+	if err := tx.Omit(clause.Associations).Save(doc).Error; err != nil {
 		tx.Rollback()
 		return model.Response[*model.Document]{
 			Message: err.Error(),
@@ -142,8 +177,10 @@ func (r *DocumentRepository) UpdateDocument(doc *model.Document) model.Response[
 	}
 
 	// Загружаем обновленный документ со всеми связями
-	if err := r.db.Preload("Items.Equipment").
+	if err := r.db.Preload("Items.Equipment.Category").
 		Preload("Location").
+		Preload("FromLocation").
+		Preload("Responsible").
 		Preload("CreatedBy").
 		Preload("ApprovedBy").
 		First(doc, doc.ID).Error; err != nil {
@@ -163,8 +200,10 @@ func (r *DocumentRepository) DeleteDocument(id uint) model.Response[*model.Docum
 
 	// Получаем документ для проверки статуса и возврата данных
 	var doc model.Document
-	if err := tx.Preload("Items.Equipment").
+	if err := tx.Preload("Items.Equipment.Category").
 		Preload("Location").
+		Preload("FromLocation").
+		Preload("Responsible").
 		Preload("CreatedBy").
 		Preload("ApprovedBy").
 		First(&doc, id).Error; err != nil {
@@ -213,10 +252,13 @@ func (r *DocumentRepository) DeleteDocument(id uint) model.Response[*model.Docum
 func (r *DocumentRepository) GetAllDocuments() model.Response[[]model.Document] {
 	var docs []model.Document
 
-	if err := r.db.Preload("Items.Equipment").
+	if err := r.db.Preload("Items.Equipment.Category").
 		Preload("Location").
+		Preload("FromLocation").
+		Preload("Responsible").
 		Preload("CreatedBy").
 		Preload("ApprovedBy").
+		Preload("ReversalOf").
 		Find(&docs).Error; err != nil {
 		return model.Response[[]model.Document]{
 			Message: err.Error(),
@@ -228,6 +270,43 @@ func (r *DocumentRepository) GetAllDocuments() model.Response[[]model.Document]
 	}
 }
 
+// documentListSpec поля сортировки и фильтры списка документов.
+// Категория и поставщик относятся к оборудованию в позициях документа.
+var documentListSpec = listSpec{
+	sortFields: map[string]string{
+		"id":         "id",
+		"number":     "number",
+		"type":       "type",
+		"status":     "status",
+		"date":       "date",
+		"created_at": "created_at",
+	},
+	defaultSort: "date DESC",
+	search:      "number LIKE @value ESCAPE '\\' OR comment LIKE @value ESCAPE '\\'",
+	status:      "status = @value",
+	kind:        "type = @value",
+	category: `id IN (SELECT di.document_id FROM document_items di
+		JOIN equipment e ON e.id = di.equipment_id WHERE e.category_id IN (` + subtreeCategoryValue + `))`,
+	location: "location_id IN (" + subtreeLocationValue + ") OR from_location_id IN (" + subtreeLocationValue + ")",
+	supplier: `id IN (SELECT di.document_id FROM document_items di
+		JOIN equipment e ON e.id = di.equipment_id WHERE e.supplier_id = @value)`,
+	date: "date",
+}
+
+func (r *DocumentRepository) ListDocuments(query model.ListQuery) model.Response[*model.Page[model.Document]] {
+	page, err := paginate[model.Document](r.db, query, documentListSpec,
+		"Items.Equipment", "Location", "FromLocation", "Responsible", "CreatedBy", "ApprovedBy", "ReversalOf")
+	if err != nil {
+		return model.Response[*model.Page[model.Document]]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[*model.Page[model.Document]]{
+		Model: page,
+	}
+}
+
 func (r *DocumentRepository) ApproveDocument(id uint, approvedByID uint) model.Response[*model.Document] {
 	// Начинаем транзакцию
 	tx := r.db.Begin()
@@ -249,6 +328,21 @@ func (r *DocumentRepository) ApproveDocument(id uint, approvedByID uint) model.R
 		}
 	}
 
+	// Проводим документ по остаткам в той же транзакции. Инвентаризация
+	// проводится корректирующими документами.
+	post := postDocument
+	if doc.Type == "inventory" {
+		post = func(tx *gorm.DB, doc *model.Document, userID uint) error {
+			return postInventory(tx, r.numbering, doc, userID)
+		}
+	}
+	if err := post(tx, &doc, approvedByID); err != nil {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+
 	// Обновляем статус и добавляем утверждающего
 	doc.Status = "completed"
 	doc.ApprovedByID = approvedByID
@@ -268,8 +362,10 @@ func (r *DocumentRepository) ApproveDocument(id uint, approvedByID uint) model.R
 	}
 
 	// Загружаем обновленный документ со всеми связями
-	if err := r.db.Preload("Items.Equipment").
+	if err := r.db.Preload("Items.Equipment.Category").
 		Preload("Location").
+		Preload("FromLocation").
+		Preload("Responsible").
 		Preload("CreatedBy").
 		Preload("ApprovedBy").
 		First(&doc, id).Error; err != nil {
@@ -282,3 +378,195 @@ func (r *DocumentRepository) ApproveDocument(id uint, approvedByID uint) model.R
 		Model: &doc,
 	}
 }
+
+func (r *DocumentRepository) CancelDocument(id uint, canceledByID uint, reason string) model.Response[*model.Document] {
+	// Начинаем транзакцию
+	tx := r.db.Begin()
+
+	var doc model.Document
+	if err := tx.First(&doc, id).Error; err != nil {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+
+	// Проведенные документы не отменяются, а сторнируются
+	if doc.Status != "draft" {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: "Можно отменить только черновик",
+		}
+	}
+
+	now := time.Now()
+	doc.Status = "canceled"
+	doc.CanceledByID = canceledByID
+	doc.CanceledAt = &now
+	doc.CancelReason = reason
+
+	if err := tx.Save(&doc).Error; err != nil {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+
+	// Подтверждаем транзакцию
+	if err := tx.Commit().Error; err != nil {
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+
+	return r.GetDocument(id)
+}
+
+func (r *DocumentRepository) ReverseDocument(id uint, reversal *model.Document) model.Response[*model.Document] {
+	// Начинаем транзакцию
+	tx := r.db.Begin()
+
+	var original model.Document
+	if err := tx.Preload("Items").First(&original, id).Error; err != nil {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+
+	if original.Status != "completed" {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: "Сторнировать можно только проведенный документ",
+		}
+	}
+
+	if original.ReversalOfID != 0 {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: "Сторнирующий документ нельзя сторнировать",
+		}
+	}
+
+	if original.InventoryID != 0 {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: "Корректирующий документ сторнируется вместе с инвентаризацией",
+		}
+	}
+
+	var reversed int64
+	if err := tx.Model(&model.Document{}).Where("reversal_of_id = ?", id).Count(&reversed).Error; err != nil {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+	if reversed > 0 {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: "Документ уже сторнирован",
+		}
+	}
+
+	// Сторнировать можно только то, что изменило остатки
+	var posted int64
+	if err := postedMovements(tx, id).Count(&posted).Error; err != nil {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+	if posted == 0 {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: "У документа нет проводок, сторнировать нечего",
+		}
+	}
+
+	// Сторнирующий документ повторяет исходный и сразу считается проведенным
+	reversal.Type = original.Type
+	reversal.Status = "completed"
+	reversal.ApprovedByID = reversal.CreatedByID
+	reversal.LocationID = original.LocationID
+	reversal.FromLocationID = original.FromLocationID
+	reversal.ReversalOfID = original.ID
+	reversal.Items = nil
+
+	number, err := nextDocumentNumber(tx, r.numbering, reversal)
+	if err != nil {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+	reversal.Number = number
+
+	if err := tx.Create(reversal).Error; err != nil {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+
+	for _, item := range original.Items {
+		item.ID = 0
+		item.DocumentID = reversal.ID
+		if err := tx.Create(&item).Error; err != nil {
+			tx.Rollback()
+			return model.Response[*model.Document]{
+				Message: err.Error(),
+			}
+		}
+	}
+
+	// Отменяем проводки исходного документа, сам он остается без изменений
+	if err := reverseDocument(tx, &original, reversal, reversal.CreatedByID); err != nil {
+		tx.Rollback()
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+
+	// Подтверждаем транзакцию
+	if err := tx.Commit().Error; err != nil {
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+
+	return r.GetDocument(reversal.ID)
+}
+
+// insertDocument создает документ с позициями в транзакции tx.
+// Номер выдается счетчиком в той же транзакции, цены в валюте
+// пересчитываются в рубли по курсу на дату документа.
+func insertDocument(tx *gorm.DB, numbering config.NumberingConfig, doc *model.Document) error {
+	if err := applyExchangeRates(tx, doc); err != nil {
+		return err
+	}
+
+	number, err := nextDocumentNumber(tx, numbering, doc)
+	if err != nil {
+		return err
+	}
+	doc.Number = number
+
+	// Создаем документ без items
+	items := doc.Items
+	doc.Items = nil
+	if err := tx.Create(doc).Error; err != nil {
+		return err
+	}
+
+	// Создаем позиции документа
+	for i := range items {
+		items[i].DocumentID = doc.ID
+		items[i].ID = 0 // Ensure ID is zero to let GORM auto-increment
+		if err := tx.Create(&items[i]).Error; err != nil {
+			return err
+		}
+	}
+	doc.Items = items
+	return nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type EmployeeRepository struct {
+	db *gorm.DB
+}
+
+func NewEmployeeRepository(db *gorm.DB) EmployeeRepositoryInterface {
+	return &EmployeeRepository{db: db}
+}
+
+func (r *EmployeeRepository) CreateEmployee(employee *model.Employee) model.Response[*model.Employee] {
+	if err := r.checkEmployee(employee); err != nil {
+		return model.Response[*model.Employee]{Message: err.Error()}
+	}
+	if err := r.db.Omit("User", "Equipment").Create(employee).Error; err != nil {
+		return model.Response[*model.Employee]{Message: err.Error()}
+	}
+	return model.Response[*model.Employee]{Model: employee, Message: "Сотрудник добавлен"}
+}
+
+func (r *EmployeeRepository) GetEmployee(id uint) model.Response[*model.Employee] {
+	var employee model.Employee
+	if err := r.db.Preload("User").
+		Preload("Equipment.Category").
+		Preload("Equipment.Location").
+		First(&employee, id).Error; err != nil {
+		return model.Response[*model.Employee]{Message: "Сотрудник не найден: " + err.Error()}
+	}
+	hideUserPassword(&employee)
+	return model.Response[*model.Employee]{Model: &employee}
+}
+
+func (r *EmployeeRepository) GetAllEmployees() model.Response[[]model.Employee] {
+	var employees []model.Employee
+	if err := r.db.Preload("User").
+		Preload("Equipment.Category").
+		Order("name").
+		Find(&employees).Error; err != nil {
+		return model.Response[[]model.Employee]{Message: err.Error()}
+	}
+	for i := range employees {
+		hideUserPassword(&employees[i])
+	}
+	return model.Response[[]model.Employee]{Model: employees}
+}
+
+func (r *EmployeeRepository) UpdateEmployee(employee *model.Employee) model.Response[*model.Employee] {
+	var existing model.Employee
+	if err := r.db.First(&existing, employee.ID).Error; err != nil {
+		return model.Response[*model.Employee]{Message: "Сотрудник не найден: " + err.Error()}
+	}
+	if err := r.checkEmployee(employee); err != nil {
+		return model.Response[*model.Employee]{Message: err.Error()}
+	}
+
+	// Закрепленное оборудование меняется только через AssignEquipment и документы
+	employee.CreatedAt = existing.CreatedAt
+	if err := r.db.Omit("User", "Equipment").Save(employee).Error; err != nil {
+		return model.Response[*model.Employee]{Message: err.Error()}
+	}
+	return model.Response[*model.Employee]{Model: employee, Message: "Данные сотрудника обновлены"}
+}
+
+func (r *EmployeeRepository) DeleteEmployee(id uint) model.Response[*model.Employee] {
+	var employee model.Employee
+	if err := r.db.First(&employee, id).Error; err != nil {
+		return model.Response[*model.Employee]{Message: "Сотрудник не найден: " + err.Error()}
+	}
+
+	var assigned int64
+	if err := r.db.Model(&model.Equipment{}).Where("responsible_id = ?", id).Count(&assigned).Error; err != nil {
+		return model.Response[*model.Employee]{Message: err.Error()}
+	}
+	if assigned > 0 {
+		return model.Response[*model.Employee]{
+			Message: fmt.Sprintf("За сотрудником числится оборудование (%d поз.), передайте его другому сотруднику", assigned),
+		}
+	}
+
+	if err := r.db.Delete(&employee).Error; err != nil {
+		return model.Response[*model.Employee]{Message: err.Error()}
+	}
+	return model.Response[*model.Employee]{Model: &employee, Message: "Сотрудник удален"}
+}
+
+// AssignEquipment закрепляет оборудование за сотрудником (employeeID = 0 - снимает
+// ответственность). Смена ответственного фиксируется перемещением с причиной
+// "assignment" без изменения остатков.
+func (r *EmployeeRepository) AssignEquipment(equipmentID, employeeID, userID uint) model.Response[*model.Movement] {
+	movement := &model.Movement{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var equipment model.Equipment
+		if err := tx.First(&equipment, equipmentID).Error; err != nil {
+			return fmt.Errorf("оборудование не найдено")
+		}
+		if employeeID != 0 {
+			if err := tx.First(&model.Employee{}, employeeID).Error; err != nil {
+				return fmt.Errorf("сотрудник не найден")
+			}
+		}
+		if equipment.ResponsibleID == employeeID {
+			return fmt.Errorf("оборудование уже закреплено за этим сотрудником")
+		}
+
+		*movement = model.Movement{
+			EquipmentID:       equipment.ID,
+			FromLocationID:    equipment.LocationID,
+			ToLocationID:      equipment.LocationID,
+			Quantity:          equipment.Quantity,
+			Reason:            "assignment",
+			FromResponsibleID: equipment.ResponsibleID,
+			ToResponsibleID:   employeeID,
+			CreatedByID:       userID,
+			Date:              time.Now(),
+		}
+		if err := tx.Create(movement).Error; err != nil {
+			return err
+		}
+		return setResponsible(tx, &equipment, employeeID)
+	})
+	if err != nil {
+		return model.Response[*model.Movement]{Message: err.Error()}
+	}
+
+	if err := r.db.Preload("Equipment").
+		Preload("FromResponsible").
+		Preload("ToResponsible").
+		Preload("CreatedBy").
+		First(movement, movement.ID).Error; err != nil {
+		return model.Response[*model.Movement]{Message: err.Error()}
+	}
+	return model.Response[*model.Movement]{Model: movement, Message: "Ответственный назначен"}
+}
+
+// checkEmployee проверяет уникальность табельного номера и привязку к учетной записи
+func (r *EmployeeRepository) checkEmployee(employee *model.Employee) error {
+	var count int64
+	if employee.PersonnelNumber != "" {
+		if err := r.db.Model(&model.Employee{}).
+			Where("personnel_number = ? AND id <> ?", employee.PersonnelNumber, employee.ID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("табельный номер %s уже используется", employee.PersonnelNumber)
+		}
+	}
+
+	if employee.UserID != 0 {
+		if err := r.db.First(&model.User{}, employee.UserID).Error; err != nil {
+			return fmt.Errorf("учетная запись не найдена")
+		}
+		if err := r.db.Model(&model.Employee{}).
+			Where("user_id = ? AND id <> ?", employee.UserID, employee.ID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("учетная запись уже привязана к другому сотруднику")
+		}
+	}
+	return nil
+}
+
+// setResponsible меняет материально ответственного за оборудование
+func setResponsible(tx *gorm.DB, equipment *model.Equipment, employeeID uint) error {
+	if equipment.ResponsibleID == employeeID {
+		return nil
+	}
+	equipment.ResponsibleID = employeeID
+
+	var value interface{}
+	if employeeID != 0 {
+		value = employeeID
+	}
+	return tx.Model(equipment).Update("responsible_id", value).Error
+}
+
+func hideUserPassword(employee *model.Employee) {
+	if employee.User != nil {
+		employee.User.Password = ""
+	}
+}
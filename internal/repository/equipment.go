@@ -1,21 +1,28 @@
 package repository
 
 import (
+	"fmt"
+	"time"
+	"tohaboy/internal/config"
 	"tohaboy/internal/model"
 
 	"gorm.io/gorm"
 )
 
 type EquipmentRepository struct {
-	db *gorm.DB
+	db        *gorm.DB
+	numbering config.NumberingConfig
 }
 
-func NewEquipmentRepository(db *gorm.DB) *EquipmentRepository {
-	return &EquipmentRepository{db: db}
+func NewEquipmentRepository(db *gorm.DB, numbering config.NumberingConfig) *EquipmentRepository {
+	return &EquipmentRepository{db: db, numbering: numbering}
 }
 
 func (r *EquipmentRepository) CreateEquipment(equipment *model.Equipment) model.Response[*model.Equipment] {
-	if err := r.db.Create(equipment).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return insertEquipment(tx, r.numbering, equipment)
+	})
+	if err != nil {
 		return model.Response[*model.Equipment]{
 			Model:   nil,
 			Message: err.Error(),
@@ -33,6 +40,8 @@ func (r *EquipmentRepository) GetEquipment(id int) model.Response[*model.Equipme
 
 	if err := r.db.Preload("Location").
 		Preload("Supplier").
+		Preload("Responsible").
+		Preload("Balances.Location").
 		Preload("Movements").
 		First(&equipment, id).Error; err != nil {
 		return model.Response[*model.Equipment]{
@@ -48,7 +57,77 @@ func (r *EquipmentRepository) GetEquipment(id int) model.Response[*model.Equipme
 }
 
 func (r *EquipmentRepository) UpdateEquipment(equipment *model.Equipment) model.Response[*model.Equipment] {
-	if err := r.db.Save(equipment).Error; err != nil {
+	var existing model.Equipment
+	if err := r.db.First(&existing, equipment.ID).Error; err != nil {
+		return model.Response[*model.Equipment]{
+			Model:   nil,
+			Message: "Оборудование не найдено: " + err.Error(),
+		}
+	}
+
+	// Остатки меняются только документами и перемещениями
+	if existing.Quantity != equipment.Quantity || existing.LocationID != equipment.LocationID {
+		return model.Response[*model.Equipment]{
+			Model:   nil,
+			Message: "Количество и местоположение изменяются только документами и перемещениями",
+		}
+	}
+	if existing.ResponsibleID != equipment.ResponsibleID {
+		return model.Response[*model.Equipment]{
+			Model:   nil,
+			Message: "Ответственный назначается через закрепление за сотрудником или документом",
+		}
+	}
+
+	if existing.Status != equipment.Status {
+		return model.Response[*model.Equipment]{
+			Model:   nil,
+			Message: "Статус изменяется операциями выдачи, возврата и ремонта",
+		}
+	}
+
+	// Цена в валюте пересчитывается по текущему курсу, только если изменились
+	// валюта или сумма в ней
+	equipment.Currency = model.NormalizeCurrency(equipment.Currency)
+	if equipment.Currency != "" && equipment.Currency == existing.Currency && equipment.CurrencyPrice == existing.CurrencyPrice {
+		equipment.Price = existing.Price
+		equipment.ExchangeRate, equipment.ExchangeNominal = existing.ExchangeRate, existing.ExchangeNominal
+	} else if err := convertEquipmentPrice(r.db, equipment, time.Now()); err != nil {
+		return model.Response[*model.Equipment]{
+			Model:   nil,
+			Message: err.Error(),
+		}
+	}
+
+	// Накопленная амортизация вводится вручную только до первого начисления,
+	// после него она и стоимость меняются только начислениями
+	var postings int64
+	if err := r.db.Model(&model.DepreciationPosting{}).Where("equipment_id = ?", existing.ID).Count(&postings).Error; err != nil {
+		return model.Response[*model.Equipment]{
+			Model:   nil,
+			Message: err.Error(),
+		}
+	}
+	if postings > 0 {
+		if existing.Price != equipment.Price {
+			return model.Response[*model.Equipment]{
+				Model:   nil,
+				Message: "Стоимость оборудования, по которому начислялась амортизация, не меняется",
+			}
+		}
+		equipment.AccumulatedDepreciation = existing.AccumulatedDepreciation
+	}
+	if err := validateDepreciation(equipment); err != nil {
+		return model.Response[*model.Equipment]{
+			Model:   nil,
+			Message: err.Error(),
+		}
+	}
+
+	// Инвентарный номер выдан счетчиком при создании и не меняется
+	equipment.InventoryNumber = existing.InventoryNumber
+
+	if err := r.db.Omit("Balances", "Responsible").Save(equipment).Error; err != nil {
 		return model.Response[*model.Equipment]{
 			Model:   nil,
 			Message: err.Error(),
@@ -70,7 +149,13 @@ func (r *EquipmentRepository) DeleteEquipment(id int) model.Response[*model.Equi
 		}
 	}
 
-	if err := r.db.Delete(&equipment).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("equipment_id = ?", equipment.ID).Delete(&model.StockBalance{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&equipment).Error
+	})
+	if err != nil {
 		return model.Response[*model.Equipment]{
 			Model:   nil,
 			Message: "Ошибка при удалении: " + err.Error(),
@@ -88,7 +173,8 @@ func (r *EquipmentRepository) GetAllEquipment() model.Response[[]model.Equipment
 
 	if err := r.db.Preload("Location").
 		Preload("Supplier").
-		Preload("Movements").
+		Preload("Responsible").
+		Preload("Balances.Location").
 		Find(&equipment).Error; err != nil {
 		return model.Response[[]model.Equipment]{
 			Model:   nil,
@@ -102,11 +188,54 @@ func (r *EquipmentRepository) GetAllEquipment() model.Response[[]model.Equipment
 	}
 }
 
+// equipmentListSpec поля сортировки и фильтры списка оборудования.
+// Местоположение проверяется по остаткам, а не только по основному месту.
+var equipmentListSpec = listSpec{
+	sortFields: map[string]string{
+		"id":               "id",
+		"name":             "name",
+		"serial_number":    "serial_number",
+		"inventory_number": "inventory_number",
+		"status":           "status",
+		"quantity":         "quantity",
+		"price":            "price",
+		"created_at":       "created_at",
+		"updated_at":       "updated_at",
+	},
+	defaultSort: "name ASC",
+	search:      "name LIKE @value ESCAPE '\\' OR serial_number LIKE @value ESCAPE '\\' OR inventory_number LIKE @value ESCAPE '\\' OR description LIKE @value ESCAPE '\\'",
+	status:      "status = @value",
+	category:    "category_id IN (" + subtreeCategoryValue + ")",
+	location:    "id IN (SELECT equipment_id FROM stock_balances WHERE quantity > 0 AND location_id IN (" + subtreeLocationValue + "))",
+	supplier:    "supplier_id = @value",
+	date:        "created_at",
+}
+
+func (r *EquipmentRepository) ListEquipment(query model.ListQuery) model.Response[*model.Page[model.Equipment]] {
+	page, err := paginate[model.Equipment](r.db, query, equipmentListSpec,
+		"Category", "Location", "Supplier", "Responsible", "Balances.Location")
+	if err != nil {
+		return model.Response[*model.Page[model.Equipment]]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[*model.Page[model.Equipment]]{
+		Model: page,
+	}
+}
+
 func (r *EquipmentRepository) GetEquipmentByLocation(locationID int) model.Response[[]model.Equipment] {
 	var equipment []model.Equipment
 
-	if err := r.db.Where("location_id = ?", locationID).
-		Preload("Location").Preload("Supplier").Preload("Movements").
+	// Оборудование ищется по остаткам в этом местоположении и во всех вложенных,
+	// в Balances попадают только остатки в них
+	if err := r.db.Where("id IN (?)", r.db.Model(&model.StockBalance{}).
+		Select("equipment_id").
+		Where("location_id IN ("+subtreeLocationIDs+") AND quantity > 0", locationID)).
+		Preload("Location").Preload("Supplier").Preload("Responsible").
+		Preload("Balances", "location_id IN ("+subtreeLocationIDs+") AND quantity > 0", locationID).
+		Preload("Balances.Location").
 		Find(&equipment).Error; err != nil {
 		return model.Response[[]model.Equipment]{
 			Model:   nil,
@@ -124,7 +253,7 @@ func (r *EquipmentRepository) GetEquipmentBySupplier(supplierID int) model.Respo
 	var equipment []model.Equipment
 
 	if err := r.db.Where("supplier_id = ?", supplierID).
-		Preload("Location").Preload("Supplier").Preload("Movements").
+		Preload("Location").Preload("Supplier").Preload("Responsible").Preload("Balances.Location").
 		Find(&equipment).Error; err != nil {
 		return model.Response[[]model.Equipment]{
 			Model:   nil,
@@ -137,3 +266,177 @@ func (r *EquipmentRepository) GetEquipmentBySupplier(supplierID int) model.Respo
 		Message: "Оборудование по поставщику загружено",
 	}
 }
+
+// GetEquipmentByIDs возвращает оборудование из списка ids в порядке списка
+func (r *EquipmentRepository) GetEquipmentByIDs(ids []uint) model.Response[[]model.Equipment] {
+	var found []model.Equipment
+
+	if err := r.db.Where("id IN ?", ids).
+		Preload("Location").Preload("Category").
+		Find(&found).Error; err != nil {
+		return model.Response[[]model.Equipment]{
+			Model:   nil,
+			Message: err.Error(),
+		}
+	}
+
+	byID := make(map[uint]model.Equipment, len(found))
+	for _, item := range found {
+		byID[item.ID] = item
+	}
+	equipment := make([]model.Equipment, 0, len(ids))
+	for _, id := range ids {
+		item, ok := byID[id]
+		if !ok {
+			return model.Response[[]model.Equipment]{
+				Model:   nil,
+				Message: fmt.Sprintf("Оборудование #%d не найдено", id),
+			}
+		}
+		equipment = append(equipment, item)
+	}
+
+	return model.Response[[]model.Equipment]{
+		Model:   equipment,
+		Message: "Оборудование загружено",
+	}
+}
+
+// ChangeStatus переводит оборудование в статус to по графу допустимых переходов
+// и записывает переход в историю. Списанное и отсутствующее на остатках
+// оборудование статус не меняет.
+func (r *EquipmentRepository) ChangeStatus(equipmentID uint, to model.EquipmentStatus, userID uint, reason string) model.Response[*model.Equipment] {
+	var equipment model.Equipment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&equipment, equipmentID).Error; err != nil {
+			return fmt.Errorf("оборудование не найдено")
+		}
+
+		// Из ремонта с открытой записью оборудование возвращает завершение обслуживания
+		if equipment.Status == model.EquipmentMaintenance {
+			if open, err := openMaintenance(tx, equipment.ID); err != nil {
+				return err
+			} else if open != nil {
+				return fmt.Errorf("оборудование на обслуживании по записи #%d, завершите обслуживание", open.ID)
+			}
+		}
+
+		return transitStatus(tx, &equipment, to, model.EquipmentStatusChange{
+			UserID: userID,
+			Reason: reason,
+		})
+	})
+	if err != nil {
+		return model.Response[*model.Equipment]{
+			Model:   nil,
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[*model.Equipment]{
+		Model:   &equipment,
+		Message: "Статус оборудования изменен",
+	}
+}
+
+// GetStatusHistory возвращает историю статусов оборудования, новые записи первыми
+func (r *EquipmentRepository) GetStatusHistory(equipmentID uint) model.Response[[]model.EquipmentStatusChange] {
+	var history []model.EquipmentStatusChange
+
+	if err := r.db.Where("equipment_id = ?", equipmentID).
+		Preload("User").
+		Order("created_at DESC, id DESC").
+		Find(&history).Error; err != nil {
+		return model.Response[[]model.EquipmentStatusChange]{
+			Model:   nil,
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[[]model.EquipmentStatusChange]{
+		Model:   history,
+		Message: "История статусов загружена",
+	}
+}
+
+// transitStatus переводит оборудование в статус to с проверкой графа переходов
+// и наличия оборудования на остатках
+func transitStatus(tx *gorm.DB, equipment *model.Equipment, to model.EquipmentStatus, change model.EquipmentStatusChange) error {
+	if equipment.Status == to {
+		return fmt.Errorf("оборудование уже в статусе %q", to)
+	}
+	if !equipment.Status.CanChangeTo(to) {
+		return fmt.Errorf("переход из статуса %q в статус %q недопустим", equipment.Status, to)
+	}
+	if to == model.EquipmentWrittenOff {
+		return fmt.Errorf("оборудование списывается только документом списания")
+	}
+	if equipment.Quantity <= 0 {
+		return fmt.Errorf("оборудования нет на остатках")
+	}
+	return changeStatus(tx, equipment, to, change)
+}
+
+// changeStatus устанавливает статус оборудования в транзакции tx и записывает
+// переход в историю. Граф переходов не проверяется: документы списания и их
+// сторно меняют статус в обход него.
+func changeStatus(tx *gorm.DB, equipment *model.Equipment, to model.EquipmentStatus, change model.EquipmentStatusChange) error {
+	change.EquipmentID = equipment.ID
+	change.FromStatus = equipment.Status
+	change.ToStatus = to
+	if err := tx.Create(&change).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(equipment).Update("status", to).Error; err != nil {
+		return err
+	}
+	equipment.Status = to
+	return nil
+}
+
+// insertEquipment создает оборудование в транзакции tx. Инвентарный номер
+// (и серийный, если он не указан, а у категории задан префикс) выдается счетчиком
+// в той же транзакции, начальное количество становится остатком в основном
+// местоположении.
+func insertEquipment(tx *gorm.DB, numbering config.NumberingConfig, equipment *model.Equipment) error {
+	if equipment.Status == "" {
+		equipment.Status = model.EquipmentAvailable
+	}
+	if !equipment.Status.Valid() {
+		return fmt.Errorf("неизвестный статус оборудования: %s", equipment.Status)
+	}
+	if equipment.Status == model.EquipmentWrittenOff {
+		return fmt.Errorf("оборудование списывается только документом списания")
+	}
+	if err := convertEquipmentPrice(tx, equipment, time.Now()); err != nil {
+		return err
+	}
+	if err := validateDepreciation(equipment); err != nil {
+		return err
+	}
+
+	if equipment.SerialNumber == "" && equipment.CategoryID != 0 {
+		serial, err := nextSerialNumber(tx, equipment.CategoryID)
+		if err != nil {
+			return err
+		}
+		equipment.SerialNumber = serial
+	}
+
+	number, err := nextInventoryNumber(tx, numbering, equipment)
+	if err != nil {
+		return err
+	}
+	equipment.InventoryNumber = number
+
+	if err := tx.Omit("Balances", "Responsible").Create(equipment).Error; err != nil {
+		return err
+	}
+
+	if equipment.Quantity > 0 {
+		if err := adjustBalance(tx, equipment.ID, equipment.LocationID, equipment.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+	"tohaboy/internal/model"
+)
+
+func TestTransitStatus(t *testing.T) {
+	tests := []struct {
+		from     model.EquipmentStatus
+		to       model.EquipmentStatus
+		quantity int
+		err      string // пусто - переход выполняется
+	}{
+		{model.EquipmentAvailable, model.EquipmentInUse, 5, ""},
+		{model.EquipmentInUse, model.EquipmentAvailable, 5, ""},
+		{model.EquipmentInUse, model.EquipmentMaintenance, 5, ""},
+		{model.EquipmentMaintenance, model.EquipmentAvailable, 5, ""},
+		{model.EquipmentAvailable, model.EquipmentAvailable, 5, `оборудование уже в статусе "available"`},
+		{model.EquipmentMaintenance, model.EquipmentInUse, 5, `переход из статуса "maintenance" в статус "in_use" недопустим`},
+		{model.EquipmentAvailable, model.EquipmentWrittenOff, 5, "оборудование списывается только документом списания"},
+		{model.EquipmentWrittenOff, model.EquipmentAvailable, 5, `переход из статуса "written_off" в статус "available" недопустим`},
+		{model.EquipmentWrittenOff, model.EquipmentMaintenance, 5, `переход из статуса "written_off" в статус "maintenance" недопустим`},
+		{model.EquipmentAvailable, model.EquipmentInUse, 0, "оборудования нет на остатках"},
+	}
+
+	for _, tt := range tests {
+		f := newPostingFixture(t)
+		if err := f.db.Model(&model.Equipment{}).Where("id = ?", f.equipment).
+			Updates(map[string]interface{}{"status": tt.from, "quantity": tt.quantity}).Error; err != nil {
+			t.Fatalf("подготовка оборудования: %v", err)
+		}
+		equipment := f.state(t)
+
+		err := transitStatus(f.db, &equipment, tt.to, model.EquipmentStatusChange{UserID: 1, Reason: "проверка"})
+
+		var history []model.EquipmentStatusChange
+		f.db.Where("equipment_id = ?", f.equipment).Find(&history)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("%s -> %s: ошибка %v, ожидалась %q", tt.from, tt.to, err, tt.err)
+			}
+			if got := f.state(t).Status; got != tt.from || len(history) != 0 {
+				t.Errorf("%s -> %s: после отказа статус %s, записей истории %d", tt.from, tt.to, got, len(history))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s -> %s: %v", tt.from, tt.to, err)
+			continue
+		}
+		if got := f.state(t).Status; got != tt.to {
+			t.Errorf("%s -> %s: статус %s", tt.from, tt.to, got)
+		}
+		if len(history) != 1 || history[0].FromStatus != tt.from || history[0].ToStatus != tt.to || history[0].Reason != "проверка" {
+			t.Errorf("%s -> %s: история %+v", tt.from, tt.to, history)
+		}
+	}
+}
+
+func TestChangeStatusOpenMaintenance(t *testing.T) {
+	f := newPostingFixture(t)
+	equipment := NewEquipmentRepository(f.db, f.documents.numbering)
+	maintenance := NewMaintenanceRepository(f.db)
+
+	sent := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	created := maintenance.CreateMaintenance(&model.MaintenanceRecord{
+		EquipmentID: f.equipment,
+		Type:        model.MaintenanceUnplanned,
+		SentAt:      sent,
+		CreatedByID: 1,
+	})
+	if created.Model == nil {
+		t.Fatalf("CreateMaintenance: %s", created.Message)
+	}
+	if got := f.state(t).Status; got != model.EquipmentMaintenance {
+		t.Fatalf("статус %s после передачи в обслуживание", got)
+	}
+
+	// Пока запись открыта, из ремонта выводит только завершение обслуживания
+	want := fmt.Sprintf("оборудование на обслуживании по записи #%d, завершите обслуживание", created.Model.ID)
+	if changed := equipment.ChangeStatus(f.equipment, model.EquipmentAvailable, 1, ""); changed.Model != nil || changed.Message != want {
+		t.Errorf("ответ %q, ожидалась ошибка %q", changed.Message, want)
+	}
+	if got := f.state(t).Status; got != model.EquipmentMaintenance {
+		t.Errorf("статус %s при открытой записи", got)
+	}
+
+	returned := sent.AddDate(0, 0, 7)
+	completed := maintenance.CompleteMaintenance(&model.MaintenanceRecord{
+		ID:         created.Model.ID,
+		ReturnedAt: &returned,
+		Outcome:    model.MaintenanceRepaired,
+	}, 1)
+	if completed.Model == nil {
+		t.Fatalf("CompleteMaintenance: %s", completed.Message)
+	}
+	if got := f.state(t).Status; got != model.EquipmentAvailable {
+		t.Errorf("статус %s после ремонта", got)
+	}
+
+	// Без открытой записи оборудование переводится в ремонт и выводится из него вручную
+	for _, to := range []model.EquipmentStatus{model.EquipmentMaintenance, model.EquipmentAvailable} {
+		if changed := equipment.ChangeStatus(f.equipment, to, 1, "вручную"); changed.Model == nil {
+			t.Errorf("ChangeStatus(%s): %s", to, changed.Message)
+		}
+	}
+}
+
+func TestChangeStatusWrittenOff(t *testing.T) {
+	f := newPostingFixture(t)
+	equipment := NewEquipmentRepository(f.db, f.documents.numbering)
+
+	f.approve(t, f.create(t, model.Document{Type: "write_off", LocationID: f.store}, 5))
+	if got := f.state(t).Status; got != model.EquipmentWrittenOff {
+		t.Fatalf("статус %s после списания", got)
+	}
+
+	for _, to := range []model.EquipmentStatus{model.EquipmentAvailable, model.EquipmentInUse, model.EquipmentMaintenance} {
+		if changed := equipment.ChangeStatus(f.equipment, to, 1, ""); changed.Model != nil {
+			t.Errorf("списанное оборудование переведено в статус %s", to)
+		}
+	}
+	if got := f.state(t).Status; got != model.EquipmentWrittenOff {
+		t.Errorf("статус %s, ожидалось списанное", got)
+	}
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+// SaveRate сохраняет курс валюты на дату, заменяя ранее введенный на ту же дату
+func (r *ExchangeRateRepository) SaveRate(rate *model.ExchangeRate) model.Response[*model.ExchangeRate] {
+	rate.Date = dayStart(rate.Date)
+	if err := saveRates(r.db, []model.ExchangeRate{*rate}); err != nil {
+		return model.Response[*model.ExchangeRate]{
+			Message: err.Error(),
+		}
+	}
+
+	var saved model.ExchangeRate
+	if err := r.db.Where("currency = ? AND date = ?", rate.Currency, rate.Date).First(&saved).Error; err != nil {
+		return model.Response[*model.ExchangeRate]{
+			Message: err.Error(),
+		}
+	}
+	return model.Response[*model.ExchangeRate]{
+		Model:   &saved,
+		Message: "Курс сохранен",
+	}
+}
+
+// ImportRates сохраняет курсы одной транзакцией, заменяя курсы на те же даты
+func (r *ExchangeRateRepository) ImportRates(rates []model.ExchangeRate) model.Response[[]model.ExchangeRate] {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		return saveRates(tx, rates)
+	}); err != nil {
+		return model.Response[[]model.ExchangeRate]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[[]model.ExchangeRate]{
+		Model:   rates,
+		Message: fmt.Sprintf("Загружено курсов: %d", len(rates)),
+	}
+}
+
+func (r *ExchangeRateRepository) DeleteRate(id uint) model.Response[*model.ExchangeRate] {
+	var rate model.ExchangeRate
+	if err := r.db.First(&rate, id).Error; err != nil {
+		return model.Response[*model.ExchangeRate]{
+			Message: "Курс не найден",
+		}
+	}
+	if err := r.db.Delete(&rate).Error; err != nil {
+		return model.Response[*model.ExchangeRate]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[*model.ExchangeRate]{
+		Model:   &rate,
+		Message: "Курс удален",
+	}
+}
+
+// GetRates возвращает курсы валюты currency (пустая - всех валют), новые первыми
+func (r *ExchangeRateRepository) GetRates(currency string) model.Response[[]model.ExchangeRate] {
+	query := r.db.Order("date DESC, currency")
+	if currency != "" {
+		query = query.Where("currency = ?", currency)
+	}
+
+	var rates []model.ExchangeRate
+	if err := query.Find(&rates).Error; err != nil {
+		return model.Response[[]model.ExchangeRate]{
+			Message: err.Error(),
+		}
+	}
+	return model.Response[[]model.ExchangeRate]{
+		Model: rates,
+	}
+}
+
+// GetRate возвращает курс, действующий на дату date: последний введенный не позже нее
+func (r *ExchangeRateRepository) GetRate(currency string, date time.Time) model.Response[*model.ExchangeRate] {
+	rate, err := exchangeRateOn(r.db, currency, date)
+	if err != nil {
+		return model.Response[*model.ExchangeRate]{
+			Message: err.Error(),
+		}
+	}
+	return model.Response[*model.ExchangeRate]{
+		Model: rate,
+	}
+}
+
+func saveRates(tx *gorm.DB, rates []model.ExchangeRate) error {
+	for i := range rates {
+		rates[i].ID = 0
+		rates[i].Date = dayStart(rates[i].Date)
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "currency"}, {Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"nominal", "rate", "source", "updated_at"}),
+		}).Create(&rates[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exchangeRateOn курс валюты, действующий на дату date
+func exchangeRateOn(tx *gorm.DB, currency string, date time.Time) (*model.ExchangeRate, error) {
+	var rate model.ExchangeRate
+	err := tx.Where("currency = ? AND date <= ?", currency, dayStart(date)).
+		Order("date DESC").
+		First(&rate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("нет курса %s на %s", currency, date.Format("02.01.2006"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// applyExchangeRates пересчитывает в рубли цены позиций в валюте по курсу
+// на дату документа. Позиции в рублях не меняются.
+func applyExchangeRates(tx *gorm.DB, doc *model.Document) error {
+	for i := range doc.Items {
+		item := &doc.Items[i]
+		item.Currency = model.NormalizeCurrency(item.Currency)
+		if item.Currency == "" {
+			item.CurrencyPrice = 0
+			item.ExchangeRate = 0
+			item.ExchangeNominal = 0
+			continue
+		}
+
+		if err := checkCurrencyPrice(item.Currency, item.CurrencyPrice); err != nil {
+			return fmt.Errorf("позиция %d: %v", i+1, err)
+		}
+		rate, err := exchangeRateOn(tx, item.Currency, doc.Date)
+		if err != nil {
+			return fmt.Errorf("позиция %d: %v", i+1, err)
+		}
+		item.ExchangeRate, item.ExchangeNominal = rate.Rate, rate.Nominal
+		item.Price = rate.Convert(item.CurrencyPrice)
+		item.Calculate()
+	}
+	return nil
+}
+
+// convertEquipmentPrice пересчитывает в рубли цену оборудования в валюте
+// по курсу на дату date
+func convertEquipmentPrice(tx *gorm.DB, equipment *model.Equipment, date time.Time) error {
+	equipment.Currency = model.NormalizeCurrency(equipment.Currency)
+	if equipment.Currency == "" {
+		equipment.CurrencyPrice = 0
+		equipment.ExchangeRate = 0
+		equipment.ExchangeNominal = 0
+		return nil
+	}
+
+	if err := checkCurrencyPrice(equipment.Currency, equipment.CurrencyPrice); err != nil {
+		return err
+	}
+	rate, err := exchangeRateOn(tx, equipment.Currency, date)
+	if err != nil {
+		return err
+	}
+	equipment.ExchangeRate, equipment.ExchangeNominal = rate.Rate, rate.Nominal
+	equipment.Price = rate.Convert(equipment.CurrencyPrice)
+	return nil
+}
+
+func checkCurrencyPrice(currency string, price model.Money) error {
+	if !model.ValidCurrency(currency) {
+		return fmt.Errorf("неверный код валюты: %s", currency)
+	}
+	if price < 0 {
+		return fmt.Errorf("цена в валюте не может быть отрицательной")
+	}
+	return nil
+}
+
+// dayStart начало дня date в местном времени
+func dayStart(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+}
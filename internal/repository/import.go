@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"fmt"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type ImportRepository struct {
+	db        *gorm.DB
+	numbering config.NumberingConfig
+}
+
+func NewImportRepository(db *gorm.DB, numbering config.NumberingConfig) *ImportRepository {
+	return &ImportRepository{db: db, numbering: numbering}
+}
+
+// FindSerialNumbers возвращает серийные номера из списка, которые уже есть в базе
+func (r *ImportRepository) FindSerialNumbers(serials []string) model.Response[[]string] {
+	found := []string{}
+	if len(serials) == 0 {
+		return model.Response[[]string]{Model: found}
+	}
+
+	if err := r.db.Model(&model.Equipment{}).
+		Where("serial_number IN ?", serials).
+		Pluck("serial_number", &found).Error; err != nil {
+		return model.Response[[]string]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[[]string]{
+		Model: found,
+	}
+}
+
+// ImportEquipment создает оборудование одной транзакцией. Если передан
+// документ acceptance, количество не зачисляется на остатки сразу, а попадает
+// в черновики приемки по образцу acceptance - по одному на местоположение.
+// Остатки появятся при утверждении документов.
+func (r *ImportRepository) ImportEquipment(equipment []model.Equipment, acceptance *model.Document) model.Response[*model.ImportResult] {
+	var documents []model.Document
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		quantities := make([]int, len(equipment))
+		for i := range equipment {
+			if acceptance != nil {
+				quantities[i] = equipment[i].Quantity
+				equipment[i].Quantity = 0
+			}
+			if err := insertEquipment(tx, r.numbering, &equipment[i]); err != nil {
+				return fmt.Errorf("%s (%s): %v", equipment[i].Name, equipment[i].SerialNumber, err)
+			}
+		}
+
+		if acceptance == nil {
+			return nil
+		}
+
+		// Документы создаются в порядке первого появления местоположения в файле
+		byLocation := make(map[uint]int)
+		for i, item := range equipment {
+			if quantities[i] == 0 {
+				continue
+			}
+			index, ok := byLocation[item.LocationID]
+			if !ok {
+				doc := *acceptance
+				doc.LocationID = item.LocationID
+				doc.Items = nil
+				documents = append(documents, doc)
+				index = len(documents) - 1
+				byLocation[item.LocationID] = index
+			}
+			line := model.DocumentItem{
+				EquipmentID: item.ID,
+				Quantity:    quantities[i],
+				Price:       item.Price,
+			}
+			line.Calculate()
+			documents[index].Items = append(documents[index].Items, line)
+		}
+
+		for i := range documents {
+			if err := insertDocument(tx, r.numbering, &documents[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Response[*model.ImportResult]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[*model.ImportResult]{
+		Model: &model.ImportResult{
+			Equipment: equipment,
+			Documents: documents,
+		},
+		Message: fmt.Sprintf("Импортировано оборудования: %d", len(equipment)),
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"fmt"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type InventoryRepository struct {
+	db        *gorm.DB
+	numbering config.NumberingConfig
+	documents *DocumentRepository
+}
+
+func NewInventoryRepository(db *gorm.DB, numbering config.NumberingConfig) *InventoryRepository {
+	return &InventoryRepository{db: db, numbering: numbering, documents: NewDocumentRepository(db, numbering)}
+}
+
+// StartInventory создает черновик инвентаризации местоположения документа doc.
+// Позиции заполняются снимком остатков на момент начала: учетное количество
+// равно остатку, фактическое - нулю, пока позиция не пересчитана. При
+// проведении учетное количество сверяется с остатком на момент утверждения.
+func (r *InventoryRepository) StartInventory(doc *model.Document) model.Response[*model.Document] {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&model.Location{}, doc.LocationID).Error; err != nil {
+			return fmt.Errorf("местоположение не найдено")
+		}
+
+		var started int64
+		if err := tx.Model(&model.Document{}).
+			Where("type = ? AND status = ? AND location_id = ?", "inventory", "draft", doc.LocationID).
+			Count(&started).Error; err != nil {
+			return err
+		}
+		if started > 0 {
+			return fmt.Errorf("в этом местоположении уже идет инвентаризация")
+		}
+
+		var balances []struct {
+			EquipmentID uint
+			Quantity    int
+			Price       model.Money
+		}
+		if err := tx.Table("stock_balances AS b").
+			Select("b.equipment_id, b.quantity, e.price").
+			Joins("JOIN equipment e ON e.id = b.equipment_id").
+			Where("b.location_id = ? AND b.quantity > 0", doc.LocationID).
+			Order("b.equipment_id").
+			Scan(&balances).Error; err != nil {
+			return err
+		}
+
+		doc.Type = "inventory"
+		doc.Status = "draft"
+		doc.Items = make([]model.DocumentItem, 0, len(balances))
+		for _, balance := range balances {
+			item := model.DocumentItem{
+				EquipmentID: balance.EquipmentID,
+				Quantity:    balance.Quantity,
+				Price:       balance.Price,
+			}
+			item.Calculate()
+			doc.Items = append(doc.Items, item)
+		}
+
+		return insertDocument(tx, r.numbering, doc)
+	})
+	if err != nil {
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+
+	return r.documents.GetDocument(doc.ID)
+}
+
+// CountItem записывает фактическое количество оборудования в черновике
+// инвентаризации: add прибавляет quantity к уже подсчитанному (сканирование),
+// иначе заменяет его. Оборудование, которого нет в позициях, добавляется
+// с нулевым учетным количеством как найденное сверх учета.
+func (r *InventoryRepository) CountItem(documentID, equipmentID uint, quantity int, add bool) model.Response[*model.Document] {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var doc model.Document
+		if err := tx.First(&doc, documentID).Error; err != nil {
+			return fmt.Errorf("документ не найден")
+		}
+		if doc.Type != "inventory" {
+			return fmt.Errorf("документ не является инвентаризацией")
+		}
+		if doc.Status != "draft" {
+			return fmt.Errorf("инвентаризация уже завершена")
+		}
+
+		var equipment model.Equipment
+		if err := tx.First(&equipment, equipmentID).Error; err != nil {
+			return fmt.Errorf("оборудование не найдено")
+		}
+
+		var items []model.DocumentItem
+		if err := tx.Where("document_id = ? AND equipment_id = ?", documentID, equipmentID).
+			Limit(1).Find(&items).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			items = append(items, model.DocumentItem{
+				DocumentID:  documentID,
+				EquipmentID: equipmentID,
+				Price:       equipment.Price,
+			})
+		}
+
+		item := items[0]
+		if add {
+			quantity += item.ActualQuantity
+		}
+		if quantity < 0 {
+			return fmt.Errorf("фактическое количество не может быть отрицательным")
+		}
+		item.ActualQuantity = quantity
+		item.Counted = true
+		return tx.Save(&item).Error
+	})
+	if err != nil {
+		return model.Response[*model.Document]{
+			Message: err.Error(),
+		}
+	}
+
+	return r.documents.GetDocument(documentID)
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"tohaboy/internal/model"
+)
+
+// startInventory начинает инвентаризацию склада фикстуры
+func (f *postingFixture) startInventory(t *testing.T, inventories *InventoryRepository) *model.Document {
+	t.Helper()
+	started := inventories.StartInventory(&model.Document{
+		Date:        time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		LocationID:  f.store,
+		CreatedByID: 1,
+	})
+	if started.Model == nil {
+		t.Fatalf("StartInventory: %s", started.Message)
+	}
+	return started.Model
+}
+
+// addEquipment создает оборудование с остатком quantity в местоположении location
+func (f *postingFixture) addEquipment(t *testing.T, name string, price model.Money, location uint, quantity int) uint {
+	t.Helper()
+	equipment := model.Equipment{Name: name, SerialNumber: name, Status: model.EquipmentAvailable, Quantity: quantity, Price: price, LocationID: location}
+	if err := f.db.Create(&equipment).Error; err != nil {
+		t.Fatalf("создание оборудования: %v", err)
+	}
+	if err := f.db.Create(&model.StockBalance{EquipmentID: equipment.ID, LocationID: location, Quantity: quantity}).Error; err != nil {
+		t.Fatalf("создание остатка: %v", err)
+	}
+	return equipment.ID
+}
+
+func TestStartInventory(t *testing.T) {
+	f := newPostingFixture(t)
+	inventories := NewInventoryRepository(f.db, f.documents.numbering)
+	monitor := f.addEquipment(t, "Монитор", 2500000, f.store, 2)
+	f.addEquipment(t, "Кресло", 800000, f.office, 1)
+	// Нулевой остаток на складе в снимок не попадает
+	f.addEquipment(t, "Проектор", 5000000, f.store, 0)
+
+	doc := f.startInventory(t, inventories)
+	if doc.Type != "inventory" || doc.Status != "draft" || !strings.HasPrefix(doc.Number, "ИНВ-") {
+		t.Fatalf("документ %s %s № %s, ожидался черновик инвентаризации с номером ИНВ-", doc.Type, doc.Status, doc.Number)
+	}
+
+	want := map[uint]struct {
+		quantity int
+		price    model.Money
+	}{
+		f.equipment: {5, 10000000},
+		monitor:     {2, 2500000},
+	}
+	if len(doc.Items) != len(want) {
+		t.Fatalf("позиций %d, ожидалось %d", len(doc.Items), len(want))
+	}
+	for _, item := range doc.Items {
+		expected, ok := want[item.EquipmentID]
+		if !ok {
+			t.Errorf("лишняя позиция оборудования %d", item.EquipmentID)
+			continue
+		}
+		if item.Quantity != expected.quantity || item.Price != expected.price || item.TotalPrice != expected.price.Mul(expected.quantity) {
+			t.Errorf("оборудование %d: %d шт. по %d на %d, ожидалось %d шт. по %d",
+				item.EquipmentID, item.Quantity, item.Price, item.TotalPrice, expected.quantity, expected.price)
+		}
+		if item.ActualQuantity != 0 || item.Counted {
+			t.Errorf("оборудование %d: фактически %d (пересчитано %v) до пересчета", item.EquipmentID, item.ActualQuantity, item.Counted)
+		}
+	}
+
+	// Снимок не меняет остатки
+	if got := f.balances(t); !equalBalances(got, map[uint]int{f.store: 5}) {
+		t.Errorf("остатки %v после начала инвентаризации", got)
+	}
+
+	for name, doc := range map[string]*model.Document{
+		"в этом местоположении уже идет инвентаризация": {LocationID: f.store, CreatedByID: 1},
+		"местоположение не найдено":                     {LocationID: 999, CreatedByID: 1},
+	} {
+		if started := inventories.StartInventory(doc); started.Model != nil || started.Message != name {
+			t.Errorf("ответ %q, ожидалась ошибка %q", started.Message, name)
+		}
+	}
+}
+
+func TestCountItem(t *testing.T) {
+	type count struct {
+		equipment string // "base" - оборудование фикстуры, "found" - найденное в офисе
+		quantity  int
+		add       bool
+		err       string
+	}
+	type line struct {
+		expected, actual int
+		counted          bool
+	}
+	tests := []struct {
+		name   string
+		counts []count
+		want   map[string]line
+	}{
+		{
+			name: "не пересчитано",
+			want: map[string]line{"base": {5, 0, false}},
+		},
+		{
+			name:   "ввод количества",
+			counts: []count{{equipment: "base", quantity: 4}},
+			want:   map[string]line{"base": {5, 4, true}},
+		},
+		{
+			name:   "повторный ввод заменяет количество",
+			counts: []count{{equipment: "base", quantity: 4}, {equipment: "base", quantity: 6}},
+			want:   map[string]line{"base": {5, 6, true}},
+		},
+		{
+			name: "сканирования суммируются",
+			counts: []count{
+				{equipment: "base", quantity: 1, add: true},
+				{equipment: "base", quantity: 1, add: true},
+				{equipment: "base", quantity: 1, add: true},
+			},
+			want: map[string]line{"base": {5, 3, true}},
+		},
+		{
+			name:   "сканирование после ввода",
+			counts: []count{{equipment: "base", quantity: 2}, {equipment: "base", quantity: 1, add: true}},
+			want:   map[string]line{"base": {5, 3, true}},
+		},
+		{
+			name:   "оборудование сверх учета",
+			counts: []count{{equipment: "found", quantity: 1, add: true}, {equipment: "found", quantity: 1, add: true}},
+			want:   map[string]line{"base": {5, 0, false}, "found": {0, 2, true}},
+		},
+		{
+			name: "отрицательное количество",
+			counts: []count{
+				{equipment: "base", quantity: 1},
+				{equipment: "base", quantity: -2, add: true, err: "фактическое количество не может быть отрицательным"},
+			},
+			want: map[string]line{"base": {5, 1, true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPostingFixture(t)
+			inventories := NewInventoryRepository(f.db, f.documents.numbering)
+			equipment := map[string]uint{
+				"base":  f.equipment,
+				"found": f.addEquipment(t, "Кресло", 800000, f.office, 1),
+			}
+			doc := f.startInventory(t, inventories)
+
+			for _, c := range tt.counts {
+				counted := inventories.CountItem(doc.ID, equipment[c.equipment], c.quantity, c.add)
+				if c.err != "" {
+					if counted.Model != nil || counted.Message != c.err {
+						t.Fatalf("ответ %q, ожидалась ошибка %q", counted.Message, c.err)
+					}
+					continue
+				}
+				if counted.Model == nil {
+					t.Fatalf("CountItem: %s", counted.Message)
+				}
+			}
+
+			var items []model.DocumentItem
+			f.db.Where("document_id = ?", doc.ID).Find(&items)
+			if len(items) != len(tt.want) {
+				t.Fatalf("позиций %d, ожидалось %d", len(items), len(tt.want))
+			}
+			for name, want := range tt.want {
+				for _, item := range items {
+					if item.EquipmentID != equipment[name] {
+						continue
+					}
+					got := line{item.Quantity, item.ActualQuantity, item.Counted}
+					if got != want {
+						t.Errorf("%s: учет %d, факт %d, пересчитано %v; ожидалось %+v", name, got.expected, got.actual, got.counted, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCountItemRejected(t *testing.T) {
+	f := newPostingFixture(t)
+	inventories := NewInventoryRepository(f.db, f.documents.numbering)
+	transfer := f.create(t, model.Document{Type: "transfer", FromLocationID: f.store, LocationID: f.office}, 1)
+	approved := f.startInventory(t, inventories)
+	if counted := inventories.CountItem(approved.ID, f.equipment, 5, false); counted.Model == nil {
+		t.Fatalf("CountItem: %s", counted.Message)
+	}
+	f.approve(t, approved)
+
+	tests := []struct {
+		name      string
+		document  uint
+		equipment uint
+		err       string
+	}{
+		{"не инвентаризация", transfer.ID, f.equipment, "документ не является инвентаризацией"},
+		{"проведенная инвентаризация", approved.ID, f.equipment, "инвентаризация уже завершена"},
+		{"нет документа", 999, f.equipment, "документ не найден"},
+	}
+	for _, tt := range tests {
+		if counted := inventories.CountItem(tt.document, tt.equipment, 1, true); counted.Model != nil || counted.Message != tt.err {
+			t.Errorf("%s: ответ %q, ожидалась ошибка %q", tt.name, counted.Message, tt.err)
+		}
+	}
+
+	doc := f.startInventory(t, inventories)
+	if counted := inventories.CountItem(doc.ID, 999, 1, true); counted.Model != nil || counted.Message != "оборудование не найдено" {
+		t.Errorf("неизвестное оборудование: ответ %q", counted.Message)
+	}
+}
@@ -1,11 +1,21 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"tohaboy/internal/model"
 
 	"gorm.io/gorm"
 )
 
+// subtreeLocationIDs подзапрос идентификаторов места ? и всех вложенных в него мест
+const subtreeLocationIDs = "SELECT id FROM locations WHERE path LIKE (SELECT path FROM locations WHERE id = ?) || '%'"
+
+// subtreeLocationValue тот же подзапрос для фильтров списков с параметром @value
+const subtreeLocationValue = "SELECT id FROM locations WHERE path LIKE (SELECT path FROM locations WHERE id = @value) || '%'"
+
 type LocationRepository struct {
 	db *gorm.DB
 }
@@ -14,17 +24,33 @@ func NewLocationRepository(db *gorm.DB) *LocationRepository {
 	return &LocationRepository{db: db}
 }
 
+// CreateLocation создает местоположение и вычисляет его путь в дереве
 func (r *LocationRepository) CreateLocation(location *model.Location) model.Response[*model.Location] {
-	result := r.db.Create(location)
-	if result.Error != nil {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		parentPath := ""
+		if location.ParentID != 0 {
+			parent, err := findLocation(tx, location.ParentID)
+			if err != nil {
+				return fmt.Errorf("родительское местоположение: %w", err)
+			}
+			parentPath = parent.Path
+		}
+
+		location.Path = ""
+		if err := tx.Create(location).Error; err != nil {
+			return errors.New("Ошибка при создании местоположения")
+		}
+		location.Path = treePath(parentPath, location.ID)
+		return tx.Model(location).Update("path", location.Path).Error
+	}); err != nil {
 		return model.Response[*model.Location]{
-			Message: "Ошибка при создании местоположения",
+			Message: err.Error(),
 		}
 	}
 
 	return model.Response[*model.Location]{
 		Message: "Местоположение успешно создано",
-		Model:   location,
+		Model:   r.GetLocation(int(location.ID)).Model,
 	}
 }
 
@@ -42,12 +68,15 @@ func (r *LocationRepository) GetLocation(id int) model.Response[*model.Location]
 		}
 	}
 
+	locations := []model.Location{location}
+	fillFullNames(r.db, locations)
 	return model.Response[*model.Location]{
 		Message: "Местоположение успешно получено",
-		Model:   &location,
+		Model:   &locations[0],
 	}
 }
 
+// GetAllLocations возвращает все местоположения в порядке обхода дерева
 func (r *LocationRepository) GetAllLocations() model.Response[[]model.Location] {
 	var locations []model.Location
 	result := r.db.Find(&locations)
@@ -57,57 +86,125 @@ func (r *LocationRepository) GetAllLocations() model.Response[[]model.Location]
 		}
 	}
 
+	fillFullNames(r.db, locations)
+	sort.SliceStable(locations, func(i, j int) bool {
+		return locations[i].FullName < locations[j].FullName
+	})
 	return model.Response[[]model.Location]{
 		Message: "Список местоположений успешно получен",
 		Model:   locations,
 	}
 }
 
+// UpdateLocation обновляет описание местоположения. При смене родителя
+// место переносится вместе со всеми вложенными местами.
 func (r *LocationRepository) UpdateLocation(location *model.Location) model.Response[*model.Location] {
-	result := r.db.Save(location)
-	if result.Error != nil {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		existing, err := findLocation(tx, location.ID)
+		if err != nil {
+			return err
+		}
+		if existing.ParentID != location.ParentID {
+			if err := moveLocation(tx, existing, location.ParentID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(existing).
+			Select("name", "description", "address", "kind").
+			Updates(location).Error; err != nil {
+			return errors.New("Ошибка при обновлении местоположения")
+		}
+		return nil
+	}); err != nil {
 		return model.Response[*model.Location]{
-			Message: "Ошибка при обновлении местоположения",
+			Message: err.Error(),
 		}
 	}
 
 	return model.Response[*model.Location]{
 		Message: "Местоположение успешно обновлено",
-		Model:   location,
+		Model:   r.GetLocation(int(location.ID)).Model,
 	}
 }
 
-func (r *LocationRepository) DeleteLocation(id int) model.Response[*model.Location] {
-	var location model.Location
-	result := r.db.First(&location, id)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			return model.Response[*model.Location]{
-				Message: "Местоположение не найдено",
-			}
+// MoveLocation переносит местоположение со всеми вложенными местами
+// под место parentID (0 - на верхний уровень)
+func (r *LocationRepository) MoveLocation(id, parentID uint) model.Response[*model.Location] {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		location, err := findLocation(tx, id)
+		if err != nil {
+			return err
 		}
+		return moveLocation(tx, location, parentID)
+	}); err != nil {
 		return model.Response[*model.Location]{
-			Message: "Ошибка при получении местоположения",
+			Message: err.Error(),
 		}
 	}
 
-	result = r.db.Delete(&location)
-	if result.Error != nil {
+	return model.Response[*model.Location]{
+		Message: "Местоположение перенесено",
+		Model:   r.GetLocation(int(id)).Model,
+	}
+}
+
+// DeleteLocation удаляет местоположение. Нельзя удалить место, в котором есть
+// вложенные места, остатки оборудования или на которое ссылаются
+// оборудование, документы и перемещения. Проверки и удаление выполняются
+// в одной транзакции, чтобы между ними не появились новые ссылки.
+func (r *LocationRepository) DeleteLocation(id int) model.Response[*model.Location] {
+	var location *model.Location
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if location, err = findLocation(tx, uint(id)); err != nil {
+			return err
+		}
+
+		checks := []struct {
+			query   *gorm.DB
+			message string
+		}{
+			{tx.Model(&model.Location{}).Where("parent_id = ?", id),
+				"в местоположении есть вложенные места"},
+			{tx.Model(&model.StockBalance{}).Where("location_id = ? AND quantity > 0", id),
+				"в местоположении есть остатки оборудования"},
+			{tx.Model(&model.Equipment{}).Where("location_id = ?", id),
+				"местоположение указано основным у оборудования"},
+			{tx.Model(&model.Document{}).Where("location_id = ? OR from_location_id = ?", id, id),
+				"местоположение указано в документах"},
+			{tx.Model(&model.Movement{}).Where("from_location_id = ? OR to_location_id = ?", id, id),
+				"местоположение указано в перемещениях"},
+		}
+		for _, check := range checks {
+			var count int64
+			if err := check.query.Count(&count).Error; err != nil {
+				return errors.New("Ошибка при проверке местоположения")
+			}
+			if count > 0 {
+				return fmt.Errorf("Нельзя удалить местоположение: %s (%d)", check.message, count)
+			}
+		}
+
+		if err := tx.Delete(location).Error; err != nil {
+			return errors.New("Ошибка при удалении местоположения")
+		}
+		return nil
+	}); err != nil {
 		return model.Response[*model.Location]{
-			Message: "Ошибка при удалении местоположения",
+			Message: err.Error(),
 		}
 	}
 
 	return model.Response[*model.Location]{
 		Message: "Местоположение успешно удалено",
-		Model:   &location,
+		Model:   location,
 	}
 }
 
 func (r *LocationRepository) GetLocationByEquipment(equipmentID int) model.Response[[]model.Location] {
 	var locations []model.Location
-	result := r.db.Joins("JOIN equipment ON equipment.location_id = locations.id").
-		Where("equipment.id = ?", equipmentID).
+	result := r.db.Joins("JOIN stock_balances ON stock_balances.location_id = locations.id").
+		Where("stock_balances.equipment_id = ? AND stock_balances.quantity > 0", equipmentID).
 		Find(&locations)
 	if result.Error != nil {
 		return model.Response[[]model.Location]{
@@ -115,8 +212,175 @@ func (r *LocationRepository) GetLocationByEquipment(equipmentID int) model.Respo
 		}
 	}
 
+	fillFullNames(r.db, locations)
 	return model.Response[[]model.Location]{
 		Message: "Местоположения по оборудованию успешно получены",
 		Model:   locations,
 	}
 }
+
+// GetLocationPath возвращает цепочку мест от корня дерева до места id включительно
+func (r *LocationRepository) GetLocationPath(id int) model.Response[[]model.Location] {
+	location, err := findLocation(r.db, uint(id))
+	if err != nil {
+		return model.Response[[]model.Location]{
+			Message: err.Error(),
+		}
+	}
+
+	var locations []model.Location
+	if err := r.db.Where("id IN ?", location.AncestorIDs()).Order("LENGTH(path)").Find(&locations).Error; err != nil {
+		return model.Response[[]model.Location]{
+			Message: "Ошибка при получении пути местоположения",
+		}
+	}
+	fillFullNames(r.db, locations)
+
+	return model.Response[[]model.Location]{
+		Message: "Путь местоположения получен",
+		Model:   locations,
+	}
+}
+
+// GetLocationTree возвращает дерево местоположений. Остатки оборудования
+// и их стоимость суммируются вверх по дереву: у здания - по всем его этажам,
+// помещениям и стеллажам.
+func (r *LocationRepository) GetLocationTree() model.Response[[]model.LocationNode] {
+	var locations []model.Location
+	if err := r.db.Find(&locations).Error; err != nil {
+		return model.Response[[]model.LocationNode]{
+			Message: "Ошибка при получении списка местоположений",
+		}
+	}
+	fillFullNames(r.db, locations)
+
+	var balances []struct {
+		LocationID uint
+		Quantity   int
+		Price      model.Money
+	}
+	if err := r.db.Table("stock_balances b").
+		Select("b.location_id, b.quantity, e.price").
+		Joins("JOIN equipment e ON e.id = b.equipment_id").
+		Where("b.quantity > 0").
+		Scan(&balances).Error; err != nil {
+		return model.Response[[]model.LocationNode]{
+			Message: "Ошибка при получении остатков оборудования",
+		}
+	}
+
+	nodes := make(map[uint]*model.LocationNode, len(locations))
+	for _, location := range locations {
+		nodes[location.ID] = &model.LocationNode{
+			Location: location,
+			Depth:    len(location.AncestorIDs()) - 1,
+		}
+	}
+	for _, balance := range balances {
+		node, ok := nodes[balance.LocationID]
+		if !ok {
+			continue
+		}
+		amount := balance.Price.Mul(balance.Quantity)
+		node.OwnQuantity += balance.Quantity
+		node.OwnAmount += amount
+		for _, ancestorID := range node.AncestorIDs() {
+			if ancestor, ok := nodes[ancestorID]; ok {
+				ancestor.Quantity += balance.Quantity
+				ancestor.Amount += amount
+			}
+		}
+	}
+
+	// Узлы собираются снизу вверх: сначала глубокие, чтобы родитель
+	// получил уже заполненных потомков
+	sort.Slice(locations, func(i, j int) bool {
+		if di, dj := nodes[locations[i].ID].Depth, nodes[locations[j].ID].Depth; di != dj {
+			return di > dj
+		}
+		return locations[i].FullName > locations[j].FullName
+	})
+	roots := []model.LocationNode{}
+	for _, location := range locations {
+		node := nodes[location.ID]
+		if node.Children == nil {
+			node.Children = []model.LocationNode{}
+		}
+		if parent, ok := nodes[location.ParentID]; ok && location.ParentID != 0 {
+			parent.Children = append([]model.LocationNode{*node}, parent.Children...)
+		} else {
+			roots = append([]model.LocationNode{*node}, roots...)
+		}
+	}
+
+	return model.Response[[]model.LocationNode]{
+		Message: "Дерево местоположений получено",
+		Model:   roots,
+	}
+}
+
+func findLocation(db *gorm.DB, id uint) (*model.Location, error) {
+	var location model.Location
+	if err := db.First(&location, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("местоположение %d не найдено", id)
+		}
+		return nil, fmt.Errorf("ошибка при получении местоположения: %w", err)
+	}
+	return &location, nil
+}
+
+// moveLocation переносит место под parentID вместе со всеми вложенными местами
+func moveLocation(tx *gorm.DB, location *model.Location, parentID uint) error {
+	newPath := treePath("", location.ID)
+	if parentID != 0 {
+		parent, err := findLocation(tx, parentID)
+		if err != nil {
+			return fmt.Errorf("родительское местоположение: %w", err)
+		}
+		if location.Contains(parent) {
+			return errors.New("нельзя перенести местоположение внутрь самого себя")
+		}
+		newPath = treePath(parent.Path, location.ID)
+	}
+
+	if err := moveTreeNode(tx, location, location.Path, newPath, parentID); err != nil {
+		return fmt.Errorf("ошибка при переносе местоположения: %w", err)
+	}
+	location.ParentID = parentID
+	location.Path = newPath
+	return nil
+}
+
+// fillFullNames заполняет полные названия мест: названия от корня через " / "
+func fillFullNames(db *gorm.DB, locations []model.Location) {
+	var ids []uint
+	for i := range locations {
+		ids = append(ids, locations[i].AncestorIDs()...)
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	var ancestors []model.Location
+	db.Select("id", "name").Where("id IN ?", ids).Find(&ancestors)
+	names := make(map[uint]string, len(ancestors))
+	for _, ancestor := range ancestors {
+		names[ancestor.ID] = ancestor.Name
+	}
+
+	for i := range locations {
+		parts := make([]string, 0, 4)
+		for _, id := range locations[i].AncestorIDs() {
+			if id == locations[i].ID {
+				parts = append(parts, locations[i].Name)
+			} else if name, ok := names[id]; ok {
+				parts = append(parts, name)
+			}
+		}
+		if len(parts) == 0 {
+			parts = append(parts, locations[i].Name)
+		}
+		locations[i].FullName = strings.Join(parts, " / ")
+	}
+}
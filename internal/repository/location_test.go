@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"tohaboy/internal/model"
+)
+
+func TestDeleteLocation(t *testing.T) {
+	f := newPostingFixture(t)
+	locations := NewLocationRepository(f.db)
+
+	// newLocation создает местоположение и ссылки на него
+	newLocation := func(t *testing.T, then func(id uint)) uint {
+		t.Helper()
+		location := model.Location{Name: "Кладовая"}
+		if err := f.db.Create(&location).Error; err != nil {
+			t.Fatalf("создание местоположения: %v", err)
+		}
+		if then != nil {
+			then(location.ID)
+		}
+		return location.ID
+	}
+	create := func(value interface{}) {
+		if err := f.db.Create(value).Error; err != nil {
+			t.Fatalf("создание ссылки: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(id uint)
+		message string // пусто - удаление разрешено
+	}{
+		{"без ссылок", nil, ""},
+		{"вложенное место", func(id uint) {
+			create(&model.Location{Name: "Полка", ParentID: id})
+		}, "вложенные места"},
+		{"остаток", func(id uint) {
+			create(&model.StockBalance{EquipmentID: f.equipment, LocationID: id, Quantity: 1})
+		}, "остатки оборудования"},
+		{"документ", func(id uint) {
+			create(&model.Document{Type: "transfer", Number: "ПЕР-1", Status: "draft", LocationID: id, Date: time.Now()})
+		}, "в документах"},
+		{"перемещение в место", func(id uint) {
+			create(&model.Movement{EquipmentID: f.equipment, FromLocationID: f.store, ToLocationID: id, Quantity: 1, Date: time.Now()})
+		}, "в перемещениях"},
+		{"перемещение из места", func(id uint) {
+			create(&model.Movement{EquipmentID: f.equipment, FromLocationID: id, ToLocationID: f.office, Quantity: 1, Date: time.Now()})
+		}, "в перемещениях"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := newLocation(t, tt.setup)
+			deleted := locations.DeleteLocation(int(id))
+
+			var count int64
+			f.db.Model(&model.Location{}).Where("id = ?", id).Count(&count)
+			if tt.message == "" {
+				if deleted.Model == nil || count != 0 {
+					t.Fatalf("местоположение не удалено: %s", deleted.Message)
+				}
+				return
+			}
+			if deleted.Model != nil || count != 1 {
+				t.Fatalf("удалено местоположение со ссылкой (%s)", tt.name)
+			}
+			if !strings.Contains(deleted.Message, tt.message) {
+				t.Errorf("сообщение %q, ожидалось упоминание %q", deleted.Message, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type MaintenanceRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceRepository(db *gorm.DB) *MaintenanceRepository {
+	return &MaintenanceRepository{db: db}
+}
+
+// CreateMaintenance передает оборудование в обслуживание: создает запись
+// и переводит оборудование в статус "maintenance". Оборудование, уже
+// переведенное в ремонт без записи, остается в этом статусе.
+func (r *MaintenanceRepository) CreateMaintenance(record *model.MaintenanceRecord) model.Response[*model.MaintenanceRecord] {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var equipment model.Equipment
+		if err := tx.First(&equipment, record.EquipmentID).Error; err != nil {
+			return fmt.Errorf("оборудование не найдено")
+		}
+		if open, err := openMaintenance(tx, equipment.ID); err != nil {
+			return err
+		} else if open != nil {
+			return fmt.Errorf("оборудование уже на обслуживании по записи #%d", open.ID)
+		}
+		if err := checkContractor(tx, record.SupplierID); err != nil {
+			return err
+		}
+
+		record.ReturnedAt = nil
+		record.Outcome = ""
+		if err := tx.Omit("Equipment", "Supplier", "CreatedBy").Create(record).Error; err != nil {
+			return err
+		}
+
+		if equipment.Status == model.EquipmentMaintenance {
+			return nil
+		}
+		return transitStatus(tx, &equipment, model.EquipmentMaintenance, model.EquipmentStatusChange{
+			UserID: record.CreatedByID,
+			Reason: maintenanceReason(record, "передано в обслуживание"),
+		})
+	})
+	if err != nil {
+		return model.Response[*model.MaintenanceRecord]{
+			Message: err.Error(),
+		}
+	}
+
+	return r.reload(record.ID, "Оборудование передано в обслуживание")
+}
+
+// CompleteMaintenance завершает обслуживание: фиксирует дату возврата,
+// стоимость и результат. Отремонтированное оборудование возвращается
+// на склад, не подлежащее ремонту остается в ремонте до списания.
+func (r *MaintenanceRepository) CompleteMaintenance(record *model.MaintenanceRecord, userID uint) model.Response[*model.MaintenanceRecord] {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.MaintenanceRecord
+		if err := tx.First(&existing, record.ID).Error; err != nil {
+			return fmt.Errorf("запись об обслуживании не найдена")
+		}
+		if existing.ReturnedAt != nil {
+			return fmt.Errorf("обслуживание уже завершено")
+		}
+		if record.ReturnedAt.Before(existing.SentAt) {
+			return fmt.Errorf("дата возврата раньше даты передачи в обслуживание")
+		}
+
+		updates := map[string]interface{}{
+			"returned_at": record.ReturnedAt,
+			"cost":        record.Cost,
+			"outcome":     record.Outcome,
+		}
+		if record.Description != "" {
+			updates["description"] = record.Description
+		}
+		if err := tx.Model(&existing).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		var equipment model.Equipment
+		if err := tx.First(&equipment, existing.EquipmentID).Error; err != nil {
+			return fmt.Errorf("оборудование не найдено")
+		}
+		// Списанное за время обслуживания оборудование статус не меняет
+		if record.Outcome != model.MaintenanceRepaired || equipment.Status != model.EquipmentMaintenance {
+			return nil
+		}
+		return transitStatus(tx, &equipment, model.EquipmentAvailable, model.EquipmentStatusChange{
+			UserID: userID,
+			Reason: maintenanceReason(&existing, "обслуживание завершено"),
+		})
+	})
+	if err != nil {
+		return model.Response[*model.MaintenanceRecord]{
+			Message: err.Error(),
+		}
+	}
+
+	return r.reload(record.ID, "Обслуживание завершено")
+}
+
+// UpdateMaintenance исправляет вид, подрядчика, дату передачи, стоимость и описание.
+// Оборудование, дата возврата и результат не меняются.
+func (r *MaintenanceRepository) UpdateMaintenance(record *model.MaintenanceRecord) model.Response[*model.MaintenanceRecord] {
+	var existing model.MaintenanceRecord
+	if err := r.db.First(&existing, record.ID).Error; err != nil {
+		return model.Response[*model.MaintenanceRecord]{
+			Message: "Запись об обслуживании не найдена",
+		}
+	}
+	if err := checkContractor(r.db, record.SupplierID); err != nil {
+		return model.Response[*model.MaintenanceRecord]{
+			Message: err.Error(),
+		}
+	}
+	if existing.ReturnedAt != nil && existing.ReturnedAt.Before(record.SentAt) {
+		return model.Response[*model.MaintenanceRecord]{
+			Message: "Дата передачи в обслуживание позже даты возврата",
+		}
+	}
+
+	var supplierID interface{}
+	if record.SupplierID != 0 {
+		supplierID = record.SupplierID
+	}
+	if err := r.db.Model(&existing).Updates(map[string]interface{}{
+		"type":        record.Type,
+		"supplier_id": supplierID,
+		"sent_at":     record.SentAt,
+		"cost":        record.Cost,
+		"description": record.Description,
+	}).Error; err != nil {
+		return model.Response[*model.MaintenanceRecord]{
+			Message: err.Error(),
+		}
+	}
+
+	return r.reload(record.ID, "Запись об обслуживании обновлена")
+}
+
+func (r *MaintenanceRepository) GetMaintenance(id uint) model.Response[*model.MaintenanceRecord] {
+	return r.reload(id, "")
+}
+
+func (r *MaintenanceRepository) GetAllMaintenance() model.Response[[]model.MaintenanceRecord] {
+	return r.find(r.db)
+}
+
+// GetMaintenanceByEquipment возвращает историю обслуживания оборудования
+func (r *MaintenanceRepository) GetMaintenanceByEquipment(equipmentID uint) model.Response[[]model.MaintenanceRecord] {
+	return r.find(r.db.Where("equipment_id = ?", equipmentID))
+}
+
+// GetOverdueMaintenance возвращает оборудование, плановое обслуживание которого
+// просрочено на дату now. Периодичность берется из категории оборудования,
+// а если в ней не задана - из ближайшей родительской категории. Срок
+// отсчитывается от возврата с последнего планового обслуживания, а для
+// не обслуживавшегося оборудования - от его создания. Списанное, отсутствующее
+// на остатках и находящееся в ремонте оборудование не учитывается.
+func (r *MaintenanceRepository) GetOverdueMaintenance(now time.Time) model.Response[[]model.MaintenanceDue] {
+	var equipment []model.Equipment
+	if err := r.db.Preload("Category").
+		Preload("Location").
+		Where("category_id IS NOT NULL AND category_id <> 0").
+		Where("status NOT IN ? AND quantity > 0",
+			[]model.EquipmentStatus{model.EquipmentWrittenOff, model.EquipmentMaintenance}).
+		Find(&equipment).Error; err != nil {
+		return model.Response[[]model.MaintenanceDue]{
+			Message: err.Error(),
+		}
+	}
+
+	var records []model.MaintenanceRecord
+	if err := r.db.Where("type = ?", model.MaintenancePlanned).Find(&records).Error; err != nil {
+		return model.Response[[]model.MaintenanceDue]{
+			Message: err.Error(),
+		}
+	}
+	last := make(map[uint]time.Time)
+	for _, record := range records {
+		date := record.SentAt
+		if record.ReturnedAt != nil {
+			date = *record.ReturnedAt
+		}
+		if date.After(last[record.EquipmentID]) {
+			last[record.EquipmentID] = date
+		}
+	}
+
+	due := []model.MaintenanceDue{}
+	for _, item := range equipment {
+		if item.Category == nil || item.Category.Effective.MaintenanceIntervalDays <= 0 {
+			continue
+		}
+		interval := item.Category.Effective.MaintenanceIntervalDays
+		row := model.MaintenanceDue{Equipment: item, IntervalDays: interval}
+		from := item.CreatedAt
+		if date, ok := last[item.ID]; ok {
+			row.LastDate = &date
+			from = date
+		}
+		row.DueDate = from.AddDate(0, 0, interval)
+		if !row.DueDate.Before(now) {
+			continue
+		}
+		row.OverdueDays = int(now.Sub(row.DueDate).Hours() / 24)
+		due = append(due, row)
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].DueDate.Equal(due[j].DueDate) {
+			return due[i].DueDate.Before(due[j].DueDate)
+		}
+		return due[i].Equipment.ID < due[j].Equipment.ID
+	})
+
+	return model.Response[[]model.MaintenanceDue]{
+		Model: due,
+	}
+}
+
+func (r *MaintenanceRepository) reload(id uint, message string) model.Response[*model.MaintenanceRecord] {
+	var record model.MaintenanceRecord
+	if err := r.db.Preload("Equipment").Preload("Supplier").Preload("CreatedBy").
+		First(&record, id).Error; err != nil {
+		return model.Response[*model.MaintenanceRecord]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[*model.MaintenanceRecord]{
+		Model:   &record,
+		Message: message,
+	}
+}
+
+func (r *MaintenanceRepository) find(query *gorm.DB) model.Response[[]model.MaintenanceRecord] {
+	var records []model.MaintenanceRecord
+	if err := query.Preload("Equipment").Preload("Supplier").Preload("CreatedBy").
+		Order("sent_at DESC, id DESC").
+		Find(&records).Error; err != nil {
+		return model.Response[[]model.MaintenanceRecord]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[[]model.MaintenanceRecord]{
+		Model: records,
+	}
+}
+
+// openMaintenance возвращает незавершенную запись об обслуживании оборудования или nil
+func openMaintenance(tx *gorm.DB, equipmentID uint) (*model.MaintenanceRecord, error) {
+	var record model.MaintenanceRecord
+	err := tx.Where("equipment_id = ? AND returned_at IS NULL", equipmentID).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func checkContractor(tx *gorm.DB, supplierID uint) error {
+	if supplierID == 0 {
+		return nil
+	}
+	if err := tx.First(&model.Supplier{}, supplierID).Error; err != nil {
+		return fmt.Errorf("подрядчик не найден")
+	}
+	return nil
+}
+
+func maintenanceReason(record *model.MaintenanceRecord, action string) string {
+	if record.Description == "" {
+		return fmt.Sprintf("Обслуживание #%d: %s", record.ID, action)
+	}
+	return fmt.Sprintf("Обслуживание #%d: %s (%s)", record.ID, action, record.Description)
+}
@@ -1,23 +1,41 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"tohaboy/internal/config"
 	"tohaboy/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MovementRepository struct {
-	db *gorm.DB
+	db        *gorm.DB
+	numbering config.NumberingConfig
 }
 
-func NewMovementRepository(db *gorm.DB) *MovementRepository {
-	return &MovementRepository{db: db}
+func NewMovementRepository(db *gorm.DB, numbering config.NumberingConfig) *MovementRepository {
+	return &MovementRepository{db: db, numbering: numbering}
 }
 
-func (r *MovementRepository) CreateMovement(movement *model.Movement) model.Response[*model.Movement] {
+// CreateMovement перемещает оборудование. Если передан документ doc, он создается
+// в той же транзакции и становится основанием перемещения: при ошибке не остается
+// ни черновика, ни израсходованного номера.
+func (r *MovementRepository) CreateMovement(movement *model.Movement, doc *model.Document) model.Response[*model.Movement] {
 	// Начинаем транзакцию
 	tx := r.db.Begin()
 
+	if doc != nil {
+		if err := insertDocument(tx, r.numbering, doc); err != nil {
+			tx.Rollback()
+			return model.Response[*model.Movement]{
+				Message: fmt.Sprintf("Ошибка создания документа: %v", err),
+			}
+		}
+		movement.DocumentID = doc.ID
+	}
+
 	// Получаем оборудование для проверки и обновления
 	var equipment model.Equipment
 	if err := tx.First(&equipment, movement.EquipmentID).Error; err != nil {
@@ -27,31 +45,54 @@ func (r *MovementRepository) CreateMovement(movement *model.Movement) model.Resp
 		}
 	}
 
-	// Проверяем достаточность количества
-	if movement.Quantity > equipment.Quantity {
+	// Ответственный меняется, только если в перемещении указан новый
+	movement.FromResponsibleID = equipment.ResponsibleID
+	if movement.ToResponsibleID == 0 {
+		movement.ToResponsibleID = equipment.ResponsibleID
+	}
+
+	// Создаем запись о перемещении
+	if err := tx.Create(movement).Error; err != nil {
 		tx.Rollback()
 		return model.Response[*model.Movement]{
-			Message: "Недостаточное количество оборудования",
+			Message: err.Error(),
 		}
 	}
 
-	// Создаем запись о перемещении
-	if err := tx.Create(movement).Error; err != nil {
+	// Списываем остаток в исходном местоположении и зачисляем в конечном
+	if err := moveStock(tx, equipment.ID, movement.FromLocationID, movement.ToLocationID, movement.Quantity); err != nil {
 		tx.Rollback()
 		return model.Response[*model.Movement]{
 			Message: err.Error(),
 		}
 	}
 
-	// Обновляем местоположение оборудования
-	equipment.LocationID = movement.ToLocationID
-	if err := tx.Save(&equipment).Error; err != nil {
+	if err := syncEquipmentStock(tx, &equipment); err != nil {
 		tx.Rollback()
 		return model.Response[*model.Movement]{
 			Message: err.Error(),
 		}
 	}
 
+	if err := setResponsible(tx, &equipment, movement.ToResponsibleID); err != nil {
+		tx.Rollback()
+		return model.Response[*model.Movement]{
+			Message: err.Error(),
+		}
+	}
+
+	// Документ-основание проведен самим перемещением
+	if movement.DocumentID != 0 {
+		if err := tx.Model(&model.Document{}).
+			Where("id = ? AND status = ?", movement.DocumentID, "draft").
+			Updates(map[string]interface{}{"status": "completed", "approved_by_id": movement.CreatedByID}).Error; err != nil {
+			tx.Rollback()
+			return model.Response[*model.Movement]{
+				Message: err.Error(),
+			}
+		}
+	}
+
 	// Подтверждаем транзакцию
 	if err := tx.Commit().Error; err != nil {
 		return model.Response[*model.Movement]{
@@ -63,6 +104,8 @@ func (r *MovementRepository) CreateMovement(movement *model.Movement) model.Resp
 	if err := r.db.Preload("Equipment").
 		Preload("FromLocation").
 		Preload("ToLocation").
+		Preload("FromResponsible").
+		Preload("ToResponsible").
 		Preload("CreatedBy").
 		First(movement, movement.ID).Error; err != nil {
 		return model.Response[*model.Movement]{
@@ -81,6 +124,8 @@ func (r *MovementRepository) GetMovement(id uint) model.Response[*model.Movement
 	if err := r.db.Preload("Equipment").
 		Preload("FromLocation").
 		Preload("ToLocation").
+		Preload("FromResponsible").
+		Preload("ToResponsible").
 		Preload("CreatedBy").
 		First(&movement, id).Error; err != nil {
 		return model.Response[*model.Movement]{
@@ -93,20 +138,132 @@ func (r *MovementRepository) GetMovement(id uint) model.Response[*model.Movement
 	}
 }
 
-func (r *MovementRepository) UpdateMovement(equipmentMovement *model.Movement) model.Response[*model.Movement] {
-	if err := r.db.Save(equipmentMovement).Error; err != nil {
-		return model.Response[*model.Movement]{}
+// UpdateMovement изменяет перемещение без документа-основания: его изменение
+// остатков отменяется и проводится заново с новыми данными в одной транзакции.
+// Перемещения, проведенные документом, меняются только сторно документа.
+func (r *MovementRepository) UpdateMovement(movement *model.Movement) model.Response[*model.Movement] {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		existing, err := findStandaloneMovement(tx, movement.ID)
+		if err != nil {
+			return err
+		}
+		if err := undoMovement(tx, existing); err != nil {
+			return err
+		}
+
+		var equipment model.Equipment
+		if err := tx.First(&equipment, movement.EquipmentID).Error; err != nil {
+			return errors.New("Оборудование не найдено")
+		}
+
+		movement.DocumentID = 0
+		movement.CreatedByID = existing.CreatedByID
+		if movement.Date.IsZero() {
+			movement.Date = existing.Date
+		}
+		movement.FromResponsibleID = equipment.ResponsibleID
+		if movement.ToResponsibleID == 0 {
+			movement.ToResponsibleID = equipment.ResponsibleID
+		}
+
+		if err := shiftStock(tx, movement, 1); err != nil {
+			return err
+		}
+		if err := syncEquipmentStock(tx, &equipment); err != nil {
+			return err
+		}
+		if err := setResponsible(tx, &equipment, movement.ToResponsibleID); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Save(movement).Error
+	}); err != nil {
+		return model.Response[*model.Movement]{
+			Message: err.Error(),
+		}
 	}
 
-	return model.Response[*model.Movement]{}
+	return r.GetMovement(movement.ID)
 }
 
+// DeleteMovement удаляет перемещение без документа-основания и возвращает
+// оборудование в исходное местоположение и прежнему ответственному
 func (r *MovementRepository) DeleteMovement(id uint) model.Response[*model.Movement] {
-	if err := r.db.Delete(&model.Movement{}, id).Error; err != nil {
-		return model.Response[*model.Movement]{}
+	var movement *model.Movement
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		existing, err := findStandaloneMovement(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := undoMovement(tx, existing); err != nil {
+			return err
+		}
+		movement = existing
+		return tx.Delete(&model.Movement{}, id).Error
+	}); err != nil {
+		return model.Response[*model.Movement]{
+			Message: err.Error(),
+		}
 	}
 
-	return model.Response[*model.Movement]{}
+	return model.Response[*model.Movement]{
+		Model: movement,
+	}
+}
+
+// findStandaloneMovement загружает перемещение и проверяет, что оно не проведено документом
+func findStandaloneMovement(tx *gorm.DB, id uint) (*model.Movement, error) {
+	var movement model.Movement
+	if err := tx.First(&movement, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("Перемещение %d не найдено", id)
+		}
+		return nil, err
+	}
+	if movement.DocumentID != 0 {
+		var doc model.Document
+		number := fmt.Sprintf("#%d", movement.DocumentID)
+		if err := tx.Select("number").First(&doc, movement.DocumentID).Error; err == nil {
+			number = doc.Number
+		}
+		return nil, fmt.Errorf("Перемещение проведено документом № %s, его можно отменить только сторно документа", number)
+	}
+	return &movement, nil
+}
+
+// undoMovement отменяет изменение остатков перемещением movement
+// и возвращает оборудование прежнему материально ответственному
+func undoMovement(tx *gorm.DB, movement *model.Movement) error {
+	if err := shiftStock(tx, movement, -1); err != nil {
+		return err
+	}
+
+	var equipment model.Equipment
+	if err := tx.First(&equipment, movement.EquipmentID).Error; err != nil {
+		return fmt.Errorf("оборудование #%d не найдено", movement.EquipmentID)
+	}
+	if err := syncEquipmentStock(tx, &equipment); err != nil {
+		return err
+	}
+	return setResponsible(tx, &equipment, movement.FromResponsibleID)
+}
+
+// shiftStock проводит (sign = 1) или отменяет (sign = -1) изменение остатков
+// перемещением: количество уходит из исходного местоположения и приходит в конечное.
+// Нулевое местоположение означает приход извне или списание.
+func shiftStock(tx *gorm.DB, movement *model.Movement, sign int) error {
+	from, to := movement.FromLocationID, movement.ToLocationID
+	if sign < 0 {
+		from, to = to, from
+	}
+	if from != 0 {
+		if err := adjustBalance(tx, movement.EquipmentID, from, -movement.Quantity); err != nil {
+			return err
+		}
+	}
+	if to != 0 {
+		return adjustBalance(tx, movement.EquipmentID, to, movement.Quantity)
+	}
+	return nil
 }
 
 func (r *MovementRepository) GetAllMovements() model.Response[[]model.Movement] {
@@ -115,6 +272,8 @@ func (r *MovementRepository) GetAllMovements() model.Response[[]model.Movement]
 	if err := r.db.Preload("Equipment").
 		Preload("FromLocation").
 		Preload("ToLocation").
+		Preload("FromResponsible").
+		Preload("ToResponsible").
 		Preload("CreatedBy").
 		Order("date DESC").
 		Find(&movements).Error; err != nil {
@@ -128,12 +287,46 @@ func (r *MovementRepository) GetAllMovements() model.Response[[]model.Movement]
 	}
 }
 
+// movementListSpec поля сортировки и фильтры списка перемещений.
+// Тип фильтрует по причине, поиск идет по оборудованию.
+var movementListSpec = listSpec{
+	sortFields: map[string]string{
+		"id":       "id",
+		"date":     "date",
+		"quantity": "quantity",
+		"reason":   "reason",
+	},
+	defaultSort: "date DESC",
+	search:      "equipment_id IN (SELECT id FROM equipment WHERE name LIKE @value ESCAPE '\\' OR serial_number LIKE @value ESCAPE '\\')",
+	kind:        "reason = @value",
+	category:    "equipment_id IN (SELECT id FROM equipment WHERE category_id IN (" + subtreeCategoryValue + "))",
+	location:    "from_location_id IN (" + subtreeLocationValue + ") OR to_location_id IN (" + subtreeLocationValue + ")",
+	supplier:    "equipment_id IN (SELECT id FROM equipment WHERE supplier_id = @value)",
+	date:        "date",
+}
+
+func (r *MovementRepository) ListMovements(query model.ListQuery) model.Response[*model.Page[model.Movement]] {
+	page, err := paginate[model.Movement](r.db, query, movementListSpec,
+		"Equipment", "FromLocation", "ToLocation", "FromResponsible", "ToResponsible", "CreatedBy")
+	if err != nil {
+		return model.Response[*model.Page[model.Movement]]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[*model.Page[model.Movement]]{
+		Model: page,
+	}
+}
+
 func (r *MovementRepository) GetMovementsByEquipment(equipmentID uint) model.Response[[]model.Movement] {
 	var movements []model.Movement
 
 	if err := r.db.Preload("Equipment").
 		Preload("FromLocation").
 		Preload("ToLocation").
+		Preload("FromResponsible").
+		Preload("ToResponsible").
 		Preload("CreatedBy").
 		Where("equipment_id = ?", equipmentID).
 		Order("date DESC").
@@ -154,6 +347,8 @@ func (r *MovementRepository) GetMovementsByLocation(locationID uint) model.Respo
 	if err := r.db.Preload("Equipment").
 		Preload("FromLocation").
 		Preload("ToLocation").
+		Preload("FromResponsible").
+		Preload("ToResponsible").
 		Preload("CreatedBy").
 		Where("from_location_id = ? OR to_location_id = ?", locationID, locationID).
 		Order("date DESC").
@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"fmt"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// postDocument проводит утверждаемый документ: изменяет остатки оборудования
+// и фиксирует каждое изменение записью Movement со ссылкой на документ.
+// Вызывается внутри транзакции утверждения, любая ошибка откатывает её целиком.
+func postDocument(tx *gorm.DB, doc *model.Document, userID uint) error {
+	if doc.ResponsibleID != 0 {
+		if err := tx.First(&model.Employee{}, doc.ResponsibleID).Error; err != nil {
+			return fmt.Errorf("ответственный сотрудник не найден")
+		}
+	}
+
+	var items []model.DocumentItem
+	if err := tx.Where("document_id = ?", doc.ID).Find(&items).Error; err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		var equipment model.Equipment
+		if err := tx.First(&equipment, item.EquipmentID).Error; err != nil {
+			return fmt.Errorf("оборудование в позиции %d не найдено", i+1)
+		}
+
+		// Приемка и перемещение закрепляют оборудование за ответственным документа
+		p := posting{tx: tx, doc: doc, userID: userID, fromResponsible: equipment.ResponsibleID, toResponsible: equipment.ResponsibleID}
+		if doc.ResponsibleID != 0 && (doc.Type == "acceptance" || doc.Type == "transfer") {
+			p.toResponsible = doc.ResponsibleID
+		}
+
+		var err error
+		switch doc.Type {
+		case "acceptance":
+			err = postAcceptance(p, &equipment, item)
+		case "write_off":
+			err = postWriteOff(p, &equipment, item)
+		case "transfer":
+			err = postTransfer(p, &equipment, item)
+		default:
+			return fmt.Errorf("неизвестный тип документа: %s", doc.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("позиция %d (%s): %v", i+1, equipment.Name, err)
+		}
+
+		if err := refreshEquipment(p, &equipment, doc.Type == "write_off"); err != nil {
+			return err
+		}
+
+		responsible := p.toResponsible
+		if equipment.Quantity == 0 {
+			// за полностью списанным оборудованием никто не отвечает
+			responsible = 0
+		}
+		if err := setResponsible(tx, &equipment, responsible); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reverseDocument отменяет проводки документа original: для каждой его записи
+// Movement создается обратная запись со ссылкой на сторнирующий документ reversal,
+// а оборудование возвращается прежнему материально ответственному
+func reverseDocument(tx *gorm.DB, original, reversal *model.Document, userID uint) error {
+	var movements []model.Movement
+	if err := postedMovements(tx, original.ID).Find(&movements).Error; err != nil {
+		return err
+	}
+
+	for _, movement := range movements {
+		var equipment model.Equipment
+		if err := tx.First(&equipment, movement.EquipmentID).Error; err != nil {
+			return fmt.Errorf("оборудование #%d не найдено", movement.EquipmentID)
+		}
+
+		if movement.ToLocationID != 0 {
+			if err := adjustBalance(tx, equipment.ID, movement.ToLocationID, -movement.Quantity); err != nil {
+				return fmt.Errorf("%s: %v", equipment.Name, err)
+			}
+		}
+		if movement.FromLocationID != 0 {
+			if err := adjustBalance(tx, equipment.ID, movement.FromLocationID, movement.Quantity); err != nil {
+				return fmt.Errorf("%s: %v", equipment.Name, err)
+			}
+		}
+
+		p := posting{tx: tx, doc: reversal, userID: userID, fromResponsible: movement.ToResponsibleID, toResponsible: movement.FromResponsibleID}
+		if err := p.movement(equipment.ID, movement.ToLocationID, movement.FromLocationID, movement.Quantity, movement.Reason); err != nil {
+			return err
+		}
+
+		if err := refreshEquipment(p, &equipment, false); err != nil {
+			return err
+		}
+		if err := setResponsible(tx, &equipment, movement.FromResponsibleID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// postedMovements выбирает записи Movement, сделанные проведением документа docID.
+// Проводки инвентаризации сделаны ее корректирующими документами.
+func postedMovements(tx *gorm.DB, docID uint) *gorm.DB {
+	return tx.Model(&model.Movement{}).
+		Where("document_id = ? OR document_id IN (SELECT id FROM documents WHERE inventory_id = ?)", docID, docID)
+}
+
+// refreshEquipment пересчитывает остатки оборудования и его статус:
+// полностью списанное помечается списанным, снова оприходованное становится доступным.
+// Переход записывается в историю статусов со ссылкой на документ p.doc.
+func refreshEquipment(p posting, equipment *model.Equipment, writeOff bool) error {
+	if err := syncEquipmentStock(p.tx, equipment); err != nil {
+		return err
+	}
+
+	status := equipment.Status
+	if equipment.Quantity == 0 && writeOff {
+		status = model.EquipmentWrittenOff
+	} else if equipment.Quantity > 0 && equipment.Status == model.EquipmentWrittenOff {
+		status = model.EquipmentAvailable
+	}
+	if status == equipment.Status {
+		return nil
+	}
+	return changeStatus(p.tx, equipment, status, model.EquipmentStatusChange{
+		UserID:     p.userID,
+		DocumentID: p.doc.ID,
+		Reason:     fmt.Sprintf("Документ № %s", p.doc.Number),
+	})
+}
+
+// postAcceptance оприходует оборудование на место документа
+func postAcceptance(p posting, equipment *model.Equipment, item model.DocumentItem) error {
+	if err := adjustBalance(p.tx, equipment.ID, p.doc.LocationID, item.Quantity); err != nil {
+		return err
+	}
+	return p.movement(equipment.ID, 0, p.doc.LocationID, item.Quantity, "acceptance")
+}
+
+// postWriteOff списывает оборудование с места документа
+func postWriteOff(p posting, equipment *model.Equipment, item model.DocumentItem) error {
+	if err := adjustBalance(p.tx, equipment.ID, p.doc.LocationID, -item.Quantity); err != nil {
+		return err
+	}
+	if equipment.Quantity-item.Quantity == 0 {
+		p.toResponsible = 0
+	}
+	return p.movement(equipment.ID, p.doc.LocationID, 0, item.Quantity, "write_off")
+}
+
+// postInventory проводит инвентаризацию: расхождения фактического количества
+// с учетным оформляются корректирующими документами - приемкой излишков и
+// списанием недостачи, которые проводятся в той же транзакции и ссылаются
+// на инвентаризацию через InventoryID. Сама инвентаризация остатки не меняет.
+// Пока идет пересчет, остатки могут измениться, поэтому учетное количество
+// позиций сверяется с текущим остатком и обновляется в документе.
+func postInventory(tx *gorm.DB, numbering config.NumberingConfig, doc *model.Document, userID uint) error {
+	var items []model.DocumentItem
+	if err := tx.Where("document_id = ?", doc.ID).Order("id").Find(&items).Error; err != nil {
+		return err
+	}
+
+	var surpluses, shortages []model.DocumentItem
+	for _, item := range items {
+		balance, err := stockBalance(tx, item.EquipmentID, doc.LocationID)
+		if err != nil {
+			return err
+		}
+		if balance != item.Quantity {
+			item.Quantity = balance
+			item.Calculate()
+			if err := tx.Model(&item).Select("quantity", "total_price", "vat_amount").Updates(&item).Error; err != nil {
+				return err
+			}
+		}
+
+		diff := item.ActualQuantity - item.Quantity
+		if diff == 0 {
+			continue
+		}
+		correction := model.DocumentItem{
+			EquipmentID: item.EquipmentID,
+			Quantity:    diff,
+			Price:       item.Price,
+			VATRate:     item.VATRate,
+		}
+		if diff > 0 {
+			correction.Calculate()
+			surpluses = append(surpluses, correction)
+			continue
+		}
+		correction.Quantity = -diff
+		correction.Calculate()
+		shortages = append(shortages, correction)
+	}
+
+	corrections := []struct {
+		docType string
+		comment string
+		items   []model.DocumentItem
+	}{
+		{"acceptance", "Оприходование излишков", surpluses},
+		{"write_off", "Списание недостачи", shortages},
+	}
+	for _, correction := range corrections {
+		if len(correction.items) == 0 {
+			continue
+		}
+		date := doc.Date
+		corr := &model.Document{
+			Type:         correction.docType,
+			Date:         doc.Date,
+			Status:       "completed",
+			LocationID:   doc.LocationID,
+			Comment:      fmt.Sprintf("%s по результатам инвентаризации № %s", correction.comment, doc.Number),
+			CreatedByID:  userID,
+			ApprovedByID: userID,
+			Basis:        "Инвентаризация",
+			BasisNumber:  doc.Number,
+			BasisDate:    &date,
+			InventoryID:  doc.ID,
+			Items:        correction.items,
+		}
+		// Излишки закрепляются за ответственным инвентаризации
+		if correction.docType == "acceptance" {
+			corr.ResponsibleID = doc.ResponsibleID
+		}
+		if err := insertDocument(tx, numbering, corr); err != nil {
+			return err
+		}
+		if err := postDocument(tx, corr, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// postTransfer перемещает оборудование из FromLocationID документа (или из
+// основного местоположения оборудования) в место документа. Перемещения,
+// созданные через MovementService, уже изменили остатки и повторно не проводятся.
+func postTransfer(p posting, equipment *model.Equipment, item model.DocumentItem) error {
+	var posted int64
+	if err := p.tx.Model(&model.Movement{}).
+		Where("document_id = ? AND equipment_id = ?", p.doc.ID, equipment.ID).
+		Count(&posted).Error; err != nil {
+		return err
+	}
+	if posted > 0 {
+		return nil
+	}
+
+	from := p.doc.FromLocationID
+	if from == 0 {
+		from = equipment.LocationID
+	}
+	if from == p.doc.LocationID {
+		return fmt.Errorf("начальное и конечное местоположение совпадают")
+	}
+
+	if err := moveStock(p.tx, equipment.ID, from, p.doc.LocationID, item.Quantity); err != nil {
+		return err
+	}
+	return p.movement(equipment.ID, from, p.doc.LocationID, item.Quantity, "transfer")
+}
+
+// posting контекст проводки одной позиции документа: транзакция, документ,
+// автор и смена материально ответственного, которую фиксируют записи Movement
+type posting struct {
+	tx              *gorm.DB
+	doc             *model.Document
+	userID          uint
+	fromResponsible uint
+	toResponsible   uint
+}
+
+func (p posting) movement(equipmentID, fromID, toID uint, quantity int, reason string) error {
+	movement := &model.Movement{
+		EquipmentID:       equipmentID,
+		FromLocationID:    fromID,
+		ToLocationID:      toID,
+		Quantity:          quantity,
+		Reason:            reason,
+		FromResponsibleID: p.fromResponsible,
+		ToResponsibleID:   p.toResponsible,
+		CreatedByID:       p.userID,
+		DocumentID:        p.doc.ID,
+		Date:              p.doc.Date,
+	}
+	return p.tx.Create(movement).Error
+}
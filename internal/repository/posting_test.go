@@ -0,0 +1,563 @@
+package repository
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+	"tohaboy/internal/storage"
+
+	"gorm.io/gorm"
+)
+
+// postingFixture база с двумя местоположениями, сотрудником и оборудованием:
+// 5 единиц на складе, закрепленных за сотрудником keeper
+type postingFixture struct {
+	db        *gorm.DB
+	documents *DocumentRepository
+	store     uint
+	office    uint
+	keeper    uint
+	employee  uint
+	equipment uint
+}
+
+func newPostingFixture(t *testing.T) *postingFixture {
+	t.Helper()
+	s := storage.NewStorage(filepath.Join(t.TempDir(), "invent.db"))
+	if err := s.MigrateUp(); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	db := s.GetDB()
+
+	numbering := config.NumberingConfig{Sequences: map[string]config.SequenceConfig{}}
+	for docType, prefix := range map[string]string{"acceptance": "ПРМ", "write_off": "СПС", "transfer": "ПЕР", "inventory": "ИНВ"} {
+		numbering.Sequences[docType] = config.SequenceConfig{Prefix: prefix, Format: config.DefaultNumberFormat, Reset: config.ResetYearly}
+	}
+
+	store := model.Location{Name: "Склад"}
+	office := model.Location{Name: "Офис"}
+	keeper := model.Employee{Name: "Кладовщик"}
+	employee := model.Employee{Name: "Инженер"}
+	for _, value := range []interface{}{&store, &office, &keeper, &employee} {
+		if err := db.Create(value).Error; err != nil {
+			t.Fatalf("создание справочников: %v", err)
+		}
+	}
+	equipment := model.Equipment{
+		Name:          "Ноутбук",
+		SerialNumber:  "SN-1",
+		Status:        model.EquipmentAvailable,
+		Quantity:      5,
+		Price:         10000000,
+		LocationID:    store.ID,
+		ResponsibleID: keeper.ID,
+	}
+	if err := db.Create(&equipment).Error; err != nil {
+		t.Fatalf("создание оборудования: %v", err)
+	}
+	if err := db.Create(&model.StockBalance{EquipmentID: equipment.ID, LocationID: store.ID, Quantity: 5}).Error; err != nil {
+		t.Fatalf("создание остатка: %v", err)
+	}
+
+	return &postingFixture{
+		db:        db,
+		documents: NewDocumentRepository(db, numbering),
+		store:     store.ID,
+		office:    office.ID,
+		keeper:    keeper.ID,
+		employee:  employee.ID,
+		equipment: equipment.ID,
+	}
+}
+
+// create создает черновик документа с одной позицией оборудования фикстуры
+func (f *postingFixture) create(t *testing.T, doc model.Document, quantity int) *model.Document {
+	t.Helper()
+	doc.Status = "draft"
+	doc.Date = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	doc.CreatedByID = 1
+	item := model.DocumentItem{EquipmentID: f.equipment, Quantity: quantity, ActualQuantity: quantity, Price: 10000000}
+	item.Calculate()
+	doc.Items = []model.DocumentItem{item}
+
+	created := f.documents.CreateDocument(&doc)
+	if created.Model == nil {
+		t.Fatalf("CreateDocument: %s", created.Message)
+	}
+	return created.Model
+}
+
+// approve утверждает документ и требует успеха
+func (f *postingFixture) approve(t *testing.T, doc *model.Document) {
+	t.Helper()
+	if approved := f.documents.ApproveDocument(doc.ID, 1); approved.Model == nil {
+		t.Fatalf("ApproveDocument: %s", approved.Message)
+	}
+}
+
+// balances остатки оборудования фикстуры по местоположениям
+func (f *postingFixture) balances(t *testing.T) map[uint]int {
+	t.Helper()
+	var balances []model.StockBalance
+	if err := f.db.Where("equipment_id = ?", f.equipment).Find(&balances).Error; err != nil {
+		t.Fatalf("остатки: %v", err)
+	}
+	result := make(map[uint]int)
+	for _, balance := range balances {
+		result[balance.LocationID] = balance.Quantity
+	}
+	return result
+}
+
+// state оборудование фикстуры после проводок
+func (f *postingFixture) state(t *testing.T) model.Equipment {
+	t.Helper()
+	var equipment model.Equipment
+	if err := f.db.First(&equipment, f.equipment).Error; err != nil {
+		t.Fatalf("оборудование: %v", err)
+	}
+	return equipment
+}
+
+// movements записи Movement документа
+func (f *postingFixture) movements(t *testing.T, docID uint) []model.Movement {
+	t.Helper()
+	var movements []model.Movement
+	if err := f.db.Where("document_id = ?", docID).Order("id").Find(&movements).Error; err != nil {
+		t.Fatalf("движения: %v", err)
+	}
+	return movements
+}
+
+func equalBalances(a, b map[uint]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for location, quantity := range a {
+		if b[location] != quantity {
+			return false
+		}
+	}
+	return true
+}
+
+func TestApproveDocument(t *testing.T) {
+	// Местоположения и сотрудники в ожиданиях задаются ролями фикстуры
+	const (
+		store = iota + 1
+		office
+		keeper
+		employee
+	)
+	tests := []struct {
+		name     string
+		docType  string
+		from     int
+		location int
+		// Ответственный документа
+		responsible int
+		quantity    int
+		// Ожидаемое состояние после утверждения
+		err         string
+		balances    map[int]int
+		status      model.EquipmentStatus
+		holder      int
+		movementsTo int
+	}{
+		{
+			name: "приемка закрепляет за ответственным документа", docType: "acceptance",
+			location: office, responsible: employee, quantity: 3,
+			balances: map[int]int{store: 5, office: 3}, status: model.EquipmentAvailable, holder: employee, movementsTo: office,
+		},
+		{
+			name: "приемка без ответственного", docType: "acceptance",
+			location: store, quantity: 2,
+			balances: map[int]int{store: 7}, status: model.EquipmentAvailable, holder: keeper, movementsTo: store,
+		},
+		{
+			name: "перемещение из основного местоположения", docType: "transfer",
+			location: office, responsible: employee, quantity: 2,
+			balances: map[int]int{store: 3, office: 2}, status: model.EquipmentAvailable, holder: employee, movementsTo: office,
+		},
+		{
+			name: "перемещение всего остатка", docType: "transfer",
+			from: store, location: office, quantity: 5,
+			balances: map[int]int{office: 5}, status: model.EquipmentAvailable, holder: keeper, movementsTo: office,
+		},
+		{
+			name: "частичное списание", docType: "write_off",
+			location: store, quantity: 2,
+			balances: map[int]int{store: 3}, status: model.EquipmentAvailable, holder: keeper,
+		},
+		{
+			name: "полное списание снимает ответственного", docType: "write_off",
+			location: store, quantity: 5,
+			balances: map[int]int{}, status: model.EquipmentWrittenOff,
+		},
+		{
+			name: "списание больше остатка", docType: "write_off",
+			location: store, quantity: 6,
+			err: "недостаточно оборудования в местоположении: в наличии 5, требуется 6",
+		},
+		{
+			name: "списание с места без остатка", docType: "write_off",
+			location: office, quantity: 1,
+			err: "недостаточно оборудования в местоположении: в наличии 0, требуется 1",
+		},
+		{
+			name: "перемещение в то же местоположение", docType: "transfer",
+			location: store, responsible: employee, quantity: 1,
+			err: "начальное и конечное местоположение совпадают",
+		},
+		{
+			name: "перемещение с места без остатка", docType: "transfer",
+			from: office, location: store, quantity: 1,
+			err: "недостаточно оборудования в местоположении",
+		},
+	}
+	for _, tt := range tests {
+		f := newPostingFixture(t)
+		ids := map[int]uint{store: f.store, office: f.office, keeper: f.keeper, employee: f.employee}
+
+		doc := f.create(t, model.Document{
+			Type:           tt.docType,
+			FromLocationID: ids[tt.from],
+			LocationID:     ids[tt.location],
+			ResponsibleID:  ids[tt.responsible],
+		}, tt.quantity)
+		approved := f.documents.ApproveDocument(doc.ID, 1)
+
+		if tt.err != "" {
+			if approved.Model != nil || !strings.Contains(approved.Message, tt.err) {
+				t.Errorf("%s: ответ %q, ожидалась ошибка %q", tt.name, approved.Message, tt.err)
+				continue
+			}
+			// Ошибка откатывает проводку целиком: остатки и документ не меняются
+			if got := f.balances(t); !equalBalances(got, map[uint]int{f.store: 5}) {
+				t.Errorf("%s: после ошибки остатки %v", tt.name, got)
+			}
+			var stored model.Document
+			f.db.First(&stored, doc.ID)
+			if stored.Status != "draft" || len(f.movements(t, doc.ID)) != 0 {
+				t.Errorf("%s: после ошибки документ в статусе %q с движениями", tt.name, stored.Status)
+			}
+			continue
+		}
+
+		if approved.Model == nil {
+			t.Errorf("%s: %s", tt.name, approved.Message)
+			continue
+		}
+		if approved.Model.Status != "completed" || approved.Model.ApprovedByID != 1 {
+			t.Errorf("%s: документ в статусе %q, утвердил %d", tt.name, approved.Model.Status, approved.Model.ApprovedByID)
+		}
+
+		want := make(map[uint]int)
+		total := 0
+		for location, quantity := range tt.balances {
+			want[ids[location]] = quantity
+			total += quantity
+		}
+		if got := f.balances(t); !equalBalances(got, want) {
+			t.Errorf("%s: остатки %v, ожидались %v", tt.name, got, want)
+		}
+
+		equipment := f.state(t)
+		if equipment.Quantity != total || equipment.Status != tt.status || equipment.ResponsibleID != ids[tt.holder] {
+			t.Errorf("%s: оборудование %d шт., статус %q, ответственный %d; ожидалось %d шт., %q, %d",
+				tt.name, equipment.Quantity, equipment.Status, equipment.ResponsibleID, total, tt.status, ids[tt.holder])
+		}
+
+		movements := f.movements(t, doc.ID)
+		if len(movements) != 1 {
+			t.Errorf("%s: движений %d, ожидалось 1", tt.name, len(movements))
+			continue
+		}
+		movement := movements[0]
+		from := ids[tt.from]
+		switch tt.docType {
+		case "acceptance":
+			from = 0
+		case "transfer":
+			if from == 0 {
+				from = f.store
+			}
+		case "write_off":
+			from = ids[tt.location]
+		}
+		if movement.Reason != tt.docType || movement.Quantity != tt.quantity ||
+			movement.FromLocationID != from || movement.ToLocationID != ids[tt.movementsTo] ||
+			movement.FromResponsibleID != f.keeper || movement.ToResponsibleID != ids[tt.holder] {
+			t.Errorf("%s: движение %+v", tt.name, movement)
+		}
+	}
+}
+
+func TestApproveDocumentNotDraft(t *testing.T) {
+	f := newPostingFixture(t)
+	doc := f.create(t, model.Document{Type: "acceptance", LocationID: f.store}, 1)
+	f.approve(t, doc)
+
+	if approved := f.documents.ApproveDocument(doc.ID, 1); approved.Model != nil || approved.Message != "Можно утвердить только черновик" {
+		t.Errorf("повторное утверждение: %q", approved.Message)
+	}
+	if got := f.balances(t); !equalBalances(got, map[uint]int{f.store: 6}) {
+		t.Errorf("остатки %v, документ проведен дважды", got)
+	}
+}
+
+// reverse сторнирует документ от имени пользователя 2
+func (f *postingFixture) reverse(doc *model.Document) model.Response[*model.Document] {
+	return f.documents.ReverseDocument(doc.ID, &model.Document{
+		Date:        time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		CreatedByID: 2,
+		Comment:     "Ошибочный документ",
+	})
+}
+
+func TestReverseDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      func(f *postingFixture) model.Document
+		quantity int
+	}{
+		{"приемка", func(f *postingFixture) model.Document {
+			return model.Document{Type: "acceptance", LocationID: f.office, ResponsibleID: f.employee}
+		}, 3},
+		{"перемещение", func(f *postingFixture) model.Document {
+			return model.Document{Type: "transfer", LocationID: f.office, ResponsibleID: f.employee}
+		}, 2},
+		{"частичное списание", func(f *postingFixture) model.Document {
+			return model.Document{Type: "write_off", LocationID: f.store}
+		}, 2},
+		{"полное списание", func(f *postingFixture) model.Document {
+			return model.Document{Type: "write_off", LocationID: f.store}
+		}, 5},
+	}
+	for _, tt := range tests {
+		f := newPostingFixture(t)
+		doc := f.create(t, tt.doc(f), tt.quantity)
+		f.approve(t, doc)
+
+		reversed := f.reverse(doc)
+		if reversed.Model == nil {
+			t.Errorf("%s: %s", tt.name, reversed.Message)
+			continue
+		}
+		reversal := reversed.Model
+		if reversal.Type != doc.Type || reversal.Status != "completed" || reversal.ReversalOfID != doc.ID ||
+			reversal.ApprovedByID != 2 || reversal.LocationID != doc.LocationID || len(reversal.Items) != 1 {
+			t.Errorf("%s: сторнирующий документ %+v", tt.name, reversal)
+		}
+		if reversal.Number == "" || reversal.Number == doc.Number {
+			t.Errorf("%s: номер сторнирующего документа %q, исходного %q", tt.name, reversal.Number, doc.Number)
+		}
+
+		// Остатки, статус и ответственный возвращаются к состоянию до проведения
+		if got := f.balances(t); !equalBalances(got, map[uint]int{f.store: 5}) {
+			t.Errorf("%s: остатки %v, ожидались %v", tt.name, got, map[uint]int{f.store: 5})
+		}
+		equipment := f.state(t)
+		if equipment.Quantity != 5 || equipment.Status != model.EquipmentAvailable || equipment.ResponsibleID != f.keeper {
+			t.Errorf("%s: оборудование %d шт., статус %q, ответственный %d", tt.name, equipment.Quantity, equipment.Status, equipment.ResponsibleID)
+		}
+
+		// Каждое движение исходного документа отменяется обратным
+		posted := f.movements(t, doc.ID)
+		back := f.movements(t, reversal.ID)
+		if len(posted) != 1 || len(back) != 1 {
+			t.Errorf("%s: движений %d и %d, ожидалось по одному", tt.name, len(posted), len(back))
+			continue
+		}
+		if back[0].FromLocationID != posted[0].ToLocationID || back[0].ToLocationID != posted[0].FromLocationID ||
+			back[0].FromResponsibleID != posted[0].ToResponsibleID || back[0].ToResponsibleID != posted[0].FromResponsibleID ||
+			back[0].Quantity != posted[0].Quantity || back[0].Reason != posted[0].Reason || back[0].CreatedByID != 2 {
+			t.Errorf("%s: обратное движение %+v для %+v", tt.name, back[0], posted[0])
+		}
+	}
+}
+
+func TestReverseDocumentRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		// doc готовит документ, который затем сторнируется
+		doc func(t *testing.T, f *postingFixture) *model.Document
+		err string
+	}{
+		{
+			name: "черновик",
+			doc: func(t *testing.T, f *postingFixture) *model.Document {
+				return f.create(t, model.Document{Type: "acceptance", LocationID: f.store}, 1)
+			},
+			err: "Сторнировать можно только проведенный документ",
+		},
+		{
+			name: "сторнирующий документ",
+			doc: func(t *testing.T, f *postingFixture) *model.Document {
+				doc := f.create(t, model.Document{Type: "acceptance", LocationID: f.store}, 1)
+				f.approve(t, doc)
+				return f.reverse(doc).Model
+			},
+			err: "Сторнирующий документ нельзя сторнировать",
+		},
+		{
+			name: "повторное сторно",
+			doc: func(t *testing.T, f *postingFixture) *model.Document {
+				doc := f.create(t, model.Document{Type: "acceptance", LocationID: f.store}, 1)
+				f.approve(t, doc)
+				f.reverse(doc)
+				return doc
+			},
+			err: "Документ уже сторнирован",
+		},
+		{
+			name: "корректирующий документ инвентаризации",
+			doc: func(t *testing.T, f *postingFixture) *model.Document {
+				inventory := f.inventory(t, 4)
+				var correction model.Document
+				if err := f.db.Where("inventory_id = ?", inventory.ID).First(&correction).Error; err != nil {
+					t.Fatalf("корректирующий документ: %v", err)
+				}
+				return &correction
+			},
+			err: "Корректирующий документ сторнируется вместе с инвентаризацией",
+		},
+		{
+			name: "инвентаризация без расхождений",
+			doc: func(t *testing.T, f *postingFixture) *model.Document {
+				return f.inventory(t, 5)
+			},
+			err: "У документа нет проводок, сторнировать нечего",
+		},
+		{
+			// Оприходованное уже списано - вернуть остаток нельзя, сторно откатывается
+			name: "остаток уже израсходован",
+			doc: func(t *testing.T, f *postingFixture) *model.Document {
+				doc := f.create(t, model.Document{Type: "acceptance", LocationID: f.office}, 3)
+				f.approve(t, doc)
+				f.approve(t, f.create(t, model.Document{Type: "write_off", LocationID: f.office}, 3))
+				return doc
+			},
+			err: "недостаточно оборудования в местоположении: в наличии 0, требуется 3",
+		},
+	}
+	for _, tt := range tests {
+		f := newPostingFixture(t)
+		doc := tt.doc(t, f)
+		if doc == nil {
+			t.Errorf("%s: документ не подготовлен", tt.name)
+			continue
+		}
+
+		var before int64
+		f.db.Model(&model.Document{}).Count(&before)
+		balances := f.balances(t)
+
+		reversed := f.reverse(doc)
+		if reversed.Model != nil || !strings.Contains(reversed.Message, tt.err) {
+			t.Errorf("%s: ответ %q, ожидалась ошибка %q", tt.name, reversed.Message, tt.err)
+			continue
+		}
+
+		// Отказ не создает сторнирующий документ и не меняет остатки
+		var after int64
+		f.db.Model(&model.Document{}).Count(&after)
+		if after != before {
+			t.Errorf("%s: документов стало %d, было %d", tt.name, after, before)
+		}
+		if got := f.balances(t); !equalBalances(got, balances) {
+			t.Errorf("%s: остатки %v, были %v", tt.name, got, balances)
+		}
+	}
+}
+
+// inventory проводит инвентаризацию склада с фактическим количеством actual
+func (f *postingFixture) inventory(t *testing.T, actual int) *model.Document {
+	t.Helper()
+	item := model.DocumentItem{EquipmentID: f.equipment, Quantity: 5, ActualQuantity: actual, Price: 10000000, Counted: true}
+	item.Calculate()
+	created := f.documents.CreateDocument(&model.Document{
+		Type:        "inventory",
+		Status:      "draft",
+		Date:        time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		LocationID:  f.store,
+		CreatedByID: 1,
+		Items:       []model.DocumentItem{item},
+	})
+	if created.Model == nil {
+		t.Fatalf("CreateDocument: %s", created.Message)
+	}
+	f.approve(t, created.Model)
+	return created.Model
+}
+
+func TestApproveInventory(t *testing.T) {
+	tests := []struct {
+		name string
+		// Перемещение со склада в офис, проведенное во время пересчета
+		moved  int
+		actual int
+		// Ожидаемый остаток склада и корректирующие документы
+		balance  int
+		surplus  int
+		shortage int
+	}{
+		{name: "без расхождений", actual: 5, balance: 5},
+		{name: "недостача", actual: 4, balance: 4, shortage: 1},
+		{name: "излишек", actual: 7, balance: 7, surplus: 2},
+		{name: "перемещение во время пересчета", moved: 2, actual: 3, balance: 3},
+		{name: "перемещение и недостача", moved: 2, actual: 2, balance: 2, shortage: 1},
+		{name: "перемещение и излишек", moved: 2, actual: 4, balance: 4, surplus: 1},
+	}
+	for _, tt := range tests {
+		f := newPostingFixture(t)
+		inventories := NewInventoryRepository(f.db, f.documents.numbering)
+
+		started := inventories.StartInventory(&model.Document{
+			Date:        time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			LocationID:  f.store,
+			CreatedByID: 1,
+		})
+		if started.Model == nil {
+			t.Fatalf("StartInventory: %s", started.Message)
+		}
+		if tt.moved > 0 {
+			f.approve(t, f.create(t, model.Document{Type: "transfer", FromLocationID: f.store, LocationID: f.office}, tt.moved))
+		}
+		if counted := inventories.CountItem(started.Model.ID, f.equipment, tt.actual, false); counted.Model == nil {
+			t.Fatalf("CountItem: %s", counted.Message)
+		}
+		f.approve(t, started.Model)
+
+		if got := f.balances(t)[f.store]; got != tt.balance {
+			t.Errorf("%s: остаток склада %d, ожидалось %d", tt.name, got, tt.balance)
+		}
+
+		var corrections []model.Document
+		f.db.Preload("Items").Where("inventory_id = ?", started.Model.ID).Find(&corrections)
+		surplus, shortage := 0, 0
+		for _, correction := range corrections {
+			for _, item := range correction.Items {
+				switch correction.Type {
+				case "acceptance":
+					surplus += item.Quantity
+				case "write_off":
+					shortage += item.Quantity
+				}
+			}
+		}
+		if surplus != tt.surplus || shortage != tt.shortage {
+			t.Errorf("%s: оприходовано %d, списано %d; ожидалось %d и %d", tt.name, surplus, shortage, tt.surplus, tt.shortage)
+		}
+
+		// Учетное количество в документе - остаток на момент проведения
+		var item model.DocumentItem
+		f.db.Where("document_id = ?", started.Model.ID).First(&item)
+		if want := 5 - tt.moved; item.Quantity != want || item.TotalPrice != model.Money(10000000).Mul(want) {
+			t.Errorf("%s: учетное количество %d на %d, ожидалось %d", tt.name, item.Quantity, item.TotalPrice, want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// listSpec описывает, как model.ListQuery применяется к таблице: допустимые
+// поля сортировки и SQL-условия фильтров. Условия используют именованный
+// параметр @value; пустое условие означает, что фильтр не поддерживается.
+// Для поиска @value - шаблон likePattern, поэтому каждое сравнение LIKE
+// в условии search записывается с ESCAPE '\'.
+type listSpec struct {
+	sortFields  map[string]string // поле ListQuery.SortBy -> колонка
+	defaultSort string            // сортировка по умолчанию, например "date DESC"
+	search      string
+	status      string
+	kind        string
+	category    string
+	location    string
+	supplier    string
+	date        string // колонка для диапазона дат
+}
+
+// paginate выбирает страницу записей T с учетом фильтров и сортировки query
+// и считает общее количество подходящих записей
+func paginate[T any](db *gorm.DB, query model.ListQuery, spec listSpec, preloads ...string) (*model.Page[T], error) {
+	filtered, err := applyFilters(db.Model(new(T)), query, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := filtered.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	order, err := sortOrder(query, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	page, size := query.Page, query.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+
+	items := make([]T, 0, size)
+	find := filtered.Session(&gorm.Session{}).Order(order).Offset((page - 1) * size).Limit(size)
+	for _, preload := range preloads {
+		find = find.Preload(preload)
+	}
+	if err := find.Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return &model.Page[T]{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: size,
+	}, nil
+}
+
+func applyFilters(db *gorm.DB, query model.ListQuery, spec listSpec) (*gorm.DB, error) {
+	filters := []struct {
+		name      string
+		condition string
+		value     interface{}
+		set       bool
+	}{
+		{"поиск", spec.search, likePattern(strings.TrimSpace(query.Search)), strings.TrimSpace(query.Search) != ""},
+		{"статус", spec.status, query.Status, query.Status != ""},
+		{"тип", spec.kind, query.Type, query.Type != ""},
+		{"категория", spec.category, query.CategoryID, query.CategoryID != 0},
+		{"местоположение", spec.location, query.LocationID, query.LocationID != 0},
+		{"поставщик", spec.supplier, query.SupplierID, query.SupplierID != 0},
+	}
+
+	for _, filter := range filters {
+		if !filter.set {
+			continue
+		}
+		if filter.condition == "" {
+			return nil, fmt.Errorf("фильтр \"%s\" не поддерживается для этого списка", filter.name)
+		}
+		db = db.Where("("+filter.condition+")", sql.Named("value", filter.value))
+	}
+
+	if query.DateFrom != "" || query.DateTo != "" {
+		if spec.date == "" {
+			return nil, fmt.Errorf("фильтр по дате не поддерживается для этого списка")
+		}
+	}
+	if query.DateFrom != "" {
+		from, err := time.ParseInLocation("2006-01-02", query.DateFrom, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("неверная начальная дата: %v", err)
+		}
+		db = db.Where(spec.date+" >= ?", from)
+	}
+	if query.DateTo != "" {
+		to, err := time.ParseInLocation("2006-01-02", query.DateTo, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("неверная конечная дата: %v", err)
+		}
+		db = db.Where(spec.date+" < ?", to.AddDate(0, 0, 1))
+	}
+
+	return db, nil
+}
+
+// likeEscaper экранирует служебные символы LIKE обратной косой чертой
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePattern шаблон LIKE для поиска подстроки text: % и _ в тексте
+// ищутся как обычные символы при условии ESCAPE '\'
+func likePattern(text string) string {
+	return "%" + likeEscaper.Replace(text) + "%"
+}
+
+// sortOrder строит ORDER BY только из колонок спецификации; id добавляется
+// последним, чтобы порядок страниц был стабильным
+func sortOrder(query model.ListQuery, spec listSpec) (string, error) {
+	if query.SortBy == "" {
+		return spec.defaultSort + ", id DESC", nil
+	}
+
+	column, ok := spec.sortFields[query.SortBy]
+	if !ok {
+		return "", fmt.Errorf("сортировка по полю %q не поддерживается", query.SortBy)
+	}
+
+	direction := "ASC"
+	switch strings.ToLower(query.SortOrder) {
+	case "", "asc":
+	case "desc":
+		direction = "DESC"
+	default:
+		return "", fmt.Errorf("неверное направление сортировки: %s", query.SortOrder)
+	}
+
+	return fmt.Sprintf("%s %s, id %s", column, direction, direction), nil
+}
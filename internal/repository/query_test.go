@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"tohaboy/internal/model"
+)
+
+func TestPaginateSearchEscapesWildcards(t *testing.T) {
+	f := newPostingFixture(t)
+	for i, name := range []string{"Кабель 10%", "Кабель 100 м", "Адаптер USB_C", "Адаптер USB-C", `Путь C:\data`} {
+		equipment := model.Equipment{Name: name, SerialNumber: fmt.Sprintf("Q-%d", i), Status: model.EquipmentAvailable, Quantity: 1}
+		if err := f.db.Create(&equipment).Error; err != nil {
+			t.Fatalf("создание оборудования: %v", err)
+		}
+	}
+
+	tests := []struct {
+		search string
+		want   []string
+	}{
+		{"10%", []string{"Кабель 10%"}},
+		{"USB_C", []string{"Адаптер USB_C"}},
+		{`C:\d`, []string{`Путь C:\data`}},
+		{"Кабель", []string{"Кабель 10%", "Кабель 100 м"}},
+		{"%", []string{"Кабель 10%"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			page, err := paginate[model.Equipment](f.db, model.ListQuery{Search: tt.search}, equipmentListSpec)
+			if err != nil {
+				t.Fatalf("paginate: %v", err)
+			}
+			var got []string
+			for _, item := range page.Items {
+				got = append(got, item.Name)
+			}
+			if len(got) != len(tt.want) || page.Total != int64(len(tt.want)) {
+				t.Fatalf("поиск %q нашел %v (всего %d), ожидалось %v", tt.search, got, page.Total, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("поиск %q нашел %v, ожидалось %v", tt.search, got, tt.want)
+				}
+			}
+		})
+	}
+}
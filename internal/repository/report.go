@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// reportEquipment оборудование с названиями справочников и фильтрами по категории,
+// поставщику и статусу. Фильтр по местоположению зависит от отчета.
+func reportEquipment(db *gorm.DB, filter model.ReportFilter) *gorm.DB {
+	query := db.Table("equipment AS e").
+		Joins("LEFT JOIN categories c ON c.id = e.category_id").
+		Joins("LEFT JOIN suppliers s ON s.id = e.supplier_id").
+		Joins("LEFT JOIN employees emp ON emp.id = e.responsible_id")
+	if filter.CategoryID != 0 {
+		query = query.Where("e.category_id IN ("+subtreeCategoryIDs+")", filter.CategoryID)
+	}
+	if filter.SupplierID != 0 {
+		query = query.Where("e.supplier_id = ?", filter.SupplierID)
+	}
+	if filter.Status != "" {
+		query = query.Where("e.status = ?", filter.Status)
+	}
+	return query
+}
+
+const reportEquipmentColumns = `e.id AS equipment_id, e.name, e.serial_number, e.status, e.price,
+	COALESCE(c.name, '') AS category, COALESCE(s.name, '') AS supplier, COALESCE(emp.name, '') AS responsible`
+
+// GetRegister возвращает реестр оборудования: текущие остатки по местоположениям.
+// Оборудование без остатков попадает в реестр с нулевым количеством
+// в основном местоположении.
+func (r *ReportRepository) GetRegister(filter model.ReportFilter) model.Response[[]model.StockRow] {
+	query := reportEquipment(r.db, filter).
+		Select(reportEquipmentColumns + ", COALESCE(l.name, '') AS location, COALESCE(b.quantity, 0) AS closing").
+		Joins("LEFT JOIN stock_balances b ON b.equipment_id = e.id").
+		Joins("LEFT JOIN locations l ON l.id = COALESCE(b.location_id, e.location_id)")
+	if filter.LocationID != 0 {
+		query = query.Where("COALESCE(b.location_id, e.location_id) IN ("+subtreeLocationIDs+")", filter.LocationID)
+	}
+
+	rows := []model.StockRow{}
+	if err := query.Order("category, location, e.name, e.id").Scan(&rows).Error; err != nil {
+		return model.Response[[]model.StockRow]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[[]model.StockRow]{
+		Model: rows,
+	}
+}
+
+// GetTurnover возвращает остатки на начало и конец периода и обороты за период
+// по оборудованию в местоположениях. Остатки восстанавливаются от текущих:
+// движения после периода отменяются в обратном порядке. Количество, введенное
+// при создании оборудования без документа, считается остатком на начало периода,
+// оборудование, созданное после периода, в отчет не попадает.
+func (r *ReportRepository) GetTurnover(filter model.ReportFilter) model.Response[[]model.StockRow] {
+	start, end, err := reportPeriod(filter)
+	if err != nil {
+		return model.Response[[]model.StockRow]{
+			Message: err.Error(),
+		}
+	}
+
+	type key struct{ equipment, location uint }
+	totals := make(map[key]*model.StockRow)
+	row := func(equipmentID, locationID uint) *model.StockRow {
+		k := key{equipmentID, locationID}
+		if totals[k] == nil {
+			totals[k] = &model.StockRow{}
+		}
+		return totals[k]
+	}
+
+	var balances []model.StockBalance
+	if err := r.db.Find(&balances).Error; err != nil {
+		return model.Response[[]model.StockRow]{
+			Message: err.Error(),
+		}
+	}
+	for _, balance := range balances {
+		row(balance.EquipmentID, balance.LocationID).Closing = balance.Quantity
+	}
+
+	var movements []model.Movement
+	if err := r.db.Where("date >= ?", start).Find(&movements).Error; err != nil {
+		return model.Response[[]model.StockRow]{
+			Message: err.Error(),
+		}
+	}
+	for _, movement := range movements {
+		after := !movement.Date.Before(end)
+		if movement.ToLocationID != 0 {
+			if after {
+				row(movement.EquipmentID, movement.ToLocationID).Closing -= movement.Quantity
+			} else {
+				row(movement.EquipmentID, movement.ToLocationID).Incoming += movement.Quantity
+			}
+		}
+		if movement.FromLocationID != 0 {
+			if after {
+				row(movement.EquipmentID, movement.FromLocationID).Closing += movement.Quantity
+			} else {
+				row(movement.EquipmentID, movement.FromLocationID).Outgoing += movement.Quantity
+			}
+		}
+	}
+
+	var equipment []struct {
+		model.StockRow
+		CreatedAt time.Time
+	}
+	if err := reportEquipment(r.db, filter).
+		Select(reportEquipmentColumns + ", e.created_at").
+		Scan(&equipment).Error; err != nil {
+		return model.Response[[]model.StockRow]{
+			Message: err.Error(),
+		}
+	}
+	info := make(map[uint]model.StockRow, len(equipment))
+	for _, item := range equipment {
+		if item.CreatedAt.Before(end) {
+			info[item.EquipmentID] = item.StockRow
+		}
+	}
+
+	var locations []model.Location
+	if err := r.db.Find(&locations).Error; err != nil {
+		return model.Response[[]model.StockRow]{
+			Message: err.Error(),
+		}
+	}
+	locationNames := make(map[uint]string, len(locations))
+	inFilter := make(map[uint]bool, len(locations))
+	var filterLocation *model.Location
+	for i, location := range locations {
+		locationNames[location.ID] = location.Name
+		if location.ID == filter.LocationID {
+			filterLocation = &locations[i]
+		}
+	}
+	for i := range locations {
+		inFilter[locations[i].ID] = filterLocation != nil && filterLocation.Contains(&locations[i])
+	}
+
+	rows := []model.StockRow{}
+	for k, total := range totals {
+		item, ok := info[k.equipment]
+		if !ok || (filter.LocationID != 0 && !inFilter[k.location]) {
+			continue
+		}
+		if total.Closing == 0 && total.Incoming == 0 && total.Outgoing == 0 {
+			continue
+		}
+		item.Location = locationNames[k.location]
+		item.Closing = total.Closing
+		item.Incoming = total.Incoming
+		item.Outgoing = total.Outgoing
+		item.Opening = total.Closing - total.Incoming + total.Outgoing
+		rows = append(rows, item)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.EquipmentID < b.EquipmentID
+	})
+
+	return model.Response[[]model.StockRow]{
+		Model: rows,
+	}
+}
+
+// GetWriteOffs возвращает списания по проведенным документам за период.
+// Списания, отмененные сторнирующим документом (в том числе сторно
+// инвентаризации, по которой списана недостача), не учитываются.
+func (r *ReportRepository) GetWriteOffs(filter model.ReportFilter) model.Response[[]model.WriteOffRow] {
+	query := reportEquipment(r.db, filter).
+		Select(`m.date, COALESCE(d.number, '') AS document_number, e.name, e.serial_number,
+			COALESCE(c.name, '') AS category, COALESCE(l.name, '') AS location,
+			m.quantity, e.price, COALESCE(d.comment, '') AS reason`).
+		Joins("JOIN movements m ON m.equipment_id = e.id").
+		Joins("LEFT JOIN documents d ON d.id = m.document_id").
+		Joins("LEFT JOIN locations l ON l.id = m.from_location_id").
+		Where("m.reason = ? AND COALESCE(m.to_location_id, 0) = 0", "write_off").
+		Where("NOT EXISTS (SELECT 1 FROM documents rev WHERE rev.reversal_of_id IN (m.document_id, d.inventory_id))")
+	if filter.LocationID != 0 {
+		query = query.Where("m.from_location_id IN ("+subtreeLocationIDs+")", filter.LocationID)
+	}
+	if filter.DateFrom != "" {
+		from, err := time.ParseInLocation("2006-01-02", filter.DateFrom, time.Local)
+		if err != nil {
+			return model.Response[[]model.WriteOffRow]{
+				Message: fmt.Sprintf("неверная начальная дата: %v", err),
+			}
+		}
+		query = query.Where("m.date >= ?", from)
+	}
+	if filter.DateTo != "" {
+		to, err := time.ParseInLocation("2006-01-02", filter.DateTo, time.Local)
+		if err != nil {
+			return model.Response[[]model.WriteOffRow]{
+				Message: fmt.Sprintf("неверная конечная дата: %v", err),
+			}
+		}
+		query = query.Where("m.date < ?", to.AddDate(0, 0, 1))
+	}
+
+	rows := []model.WriteOffRow{}
+	if err := query.Order("m.date, m.id").Scan(&rows).Error; err != nil {
+		return model.Response[[]model.WriteOffRow]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[[]model.WriteOffRow]{
+		Model: rows,
+	}
+}
+
+// reportPeriod возвращает начало периода и начало дня, следующего за его концом.
+// Без начальной даты период начинается с конечной, без конечной - заканчивается сегодня.
+func reportPeriod(filter model.ReportFilter) (time.Time, time.Time, error) {
+	today := time.Now()
+	to := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+	if filter.DateTo != "" {
+		var err error
+		if to, err = time.ParseInLocation("2006-01-02", filter.DateTo, time.Local); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("неверная конечная дата: %v", err)
+		}
+	}
+
+	from := to
+	if filter.DateFrom != "" {
+		var err error
+		if from, err = time.ParseInLocation("2006-01-02", filter.DateFrom, time.Local); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("неверная начальная дата: %v", err)
+		}
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("начало периода позже его окончания")
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+	"tohaboy/internal/config"
 	"tohaboy/internal/model"
 
 	"gorm.io/gorm"
@@ -22,16 +24,21 @@ type EquipmentRepositoryInterface interface {
 	CreateEquipment(equipment *model.Equipment) model.Response[*model.Equipment]
 	GetEquipment(id int) model.Response[*model.Equipment]
 	GetAllEquipment() model.Response[[]model.Equipment]
+	ListEquipment(query model.ListQuery) model.Response[*model.Page[model.Equipment]]
 	UpdateEquipment(equipment *model.Equipment) model.Response[*model.Equipment]
 	DeleteEquipment(id int) model.Response[*model.Equipment]
 	GetEquipmentByLocation(locationID int) model.Response[[]model.Equipment]
 	GetEquipmentBySupplier(supplierID int) model.Response[[]model.Equipment]
+	GetEquipmentByIDs(ids []uint) model.Response[[]model.Equipment]
+	ChangeStatus(equipmentID uint, to model.EquipmentStatus, userID uint, reason string) model.Response[*model.Equipment]
+	GetStatusHistory(equipmentID uint) model.Response[[]model.EquipmentStatusChange]
 }
 
 type SupplierRepositoryInterface interface {
 	CreateSupplier(supplier *model.Supplier) model.Response[*model.Supplier]
 	GetSupplier(id int) model.Response[*model.Supplier]
 	GetAllSuppliers() model.Response[[]model.Supplier]
+	ListSuppliers(query model.ListQuery) model.Response[*model.Page[model.Supplier]]
 	UpdateSupplier(supplier *model.Supplier) model.Response[*model.Supplier]
 	DeleteSupplier(id int) model.Response[*model.Supplier]
 	GetSupplierByEquipment(equipmentID int) model.Response[[]model.Supplier]
@@ -44,12 +51,16 @@ type LocationRepositoryInterface interface {
 	UpdateLocation(location *model.Location) model.Response[*model.Location]
 	DeleteLocation(id int) model.Response[*model.Location]
 	GetLocationByEquipment(equipmentID int) model.Response[[]model.Location]
+	MoveLocation(id, parentID uint) model.Response[*model.Location]
+	GetLocationPath(id int) model.Response[[]model.Location]
+	GetLocationTree() model.Response[[]model.LocationNode]
 }
 
 type MovementRepositoryInterface interface {
-	CreateMovement(movement *model.Movement) model.Response[*model.Movement]
+	CreateMovement(movement *model.Movement, doc *model.Document) model.Response[*model.Movement]
 	GetMovement(id uint) model.Response[*model.Movement]
 	GetAllMovements() model.Response[[]model.Movement]
+	ListMovements(query model.ListQuery) model.Response[*model.Page[model.Movement]]
 	GetMovementsByEquipment(equipmentID uint) model.Response[[]model.Movement]
 	GetMovementsByLocation(locationID uint) model.Response[[]model.Movement]
 	UpdateMovement(movement *model.Movement) model.Response[*model.Movement]
@@ -60,9 +71,12 @@ type DocumentRepositoryInterface interface {
 	CreateDocument(doc *model.Document) model.Response[*model.Document]
 	GetDocument(id uint) model.Response[*model.Document]
 	GetAllDocuments() model.Response[[]model.Document]
+	ListDocuments(query model.ListQuery) model.Response[*model.Page[model.Document]]
 	UpdateDocument(doc *model.Document) model.Response[*model.Document]
 	DeleteDocument(id uint) model.Response[*model.Document]
 	ApproveDocument(id uint, approvedByID uint) model.Response[*model.Document]
+	CancelDocument(id uint, canceledByID uint, reason string) model.Response[*model.Document]
+	ReverseDocument(id uint, reversal *model.Document) model.Response[*model.Document]
 	GetDB() *gorm.DB
 }
 
@@ -74,26 +88,113 @@ type CategoryRepositoryInterface interface {
 	DeleteCategory(id int) model.Response[*model.Category]
 }
 
+type EmployeeRepositoryInterface interface {
+	CreateEmployee(employee *model.Employee) model.Response[*model.Employee]
+	GetEmployee(id uint) model.Response[*model.Employee]
+	GetAllEmployees() model.Response[[]model.Employee]
+	UpdateEmployee(employee *model.Employee) model.Response[*model.Employee]
+	DeleteEmployee(id uint) model.Response[*model.Employee]
+	AssignEquipment(equipmentID, employeeID, userID uint) model.Response[*model.Movement]
+}
+
+type AuditRepositoryInterface interface {
+	GetAuditEntries(filter model.AuditFilter) model.Response[[]model.AuditEntry]
+}
+
+type SearchRepositoryInterface interface {
+	Search(query model.SearchQuery) model.Response[[]model.SearchHit]
+}
+
+type ReportRepositoryInterface interface {
+	GetRegister(filter model.ReportFilter) model.Response[[]model.StockRow]
+	GetTurnover(filter model.ReportFilter) model.Response[[]model.StockRow]
+	GetWriteOffs(filter model.ReportFilter) model.Response[[]model.WriteOffRow]
+}
+
+type ImportRepositoryInterface interface {
+	FindSerialNumbers(serials []string) model.Response[[]string]
+	ImportEquipment(equipment []model.Equipment, acceptance *model.Document) model.Response[*model.ImportResult]
+}
+
+type InventoryRepositoryInterface interface {
+	StartInventory(doc *model.Document) model.Response[*model.Document]
+	CountItem(documentID, equipmentID uint, quantity int, add bool) model.Response[*model.Document]
+}
+
+type ScanRepositoryInterface interface {
+	FindEquipmentByCode(code string) model.Response[*model.Equipment]
+	AddTransferItem(documentID, equipmentID uint) model.Response[*model.DocumentItem]
+}
+
+type MaintenanceRepositoryInterface interface {
+	CreateMaintenance(record *model.MaintenanceRecord) model.Response[*model.MaintenanceRecord]
+	CompleteMaintenance(record *model.MaintenanceRecord, userID uint) model.Response[*model.MaintenanceRecord]
+	UpdateMaintenance(record *model.MaintenanceRecord) model.Response[*model.MaintenanceRecord]
+	GetMaintenance(id uint) model.Response[*model.MaintenanceRecord]
+	GetAllMaintenance() model.Response[[]model.MaintenanceRecord]
+	GetMaintenanceByEquipment(equipmentID uint) model.Response[[]model.MaintenanceRecord]
+	GetOverdueMaintenance(now time.Time) model.Response[[]model.MaintenanceDue]
+}
+
+type DepreciationRepositoryInterface interface {
+	RunDepreciation(period time.Time, userID uint) model.Response[*model.DepreciationRun]
+	CancelDepreciation(id uint) model.Response[*model.DepreciationRun]
+	GetDepreciationRun(id uint) model.Response[*model.DepreciationRun]
+	GetDepreciationRuns() model.Response[[]model.DepreciationRun]
+	GetDepreciationSchedule(filter model.ReportFilter) model.Response[*model.DepreciationSchedule]
+}
+
+type ExchangeRateRepositoryInterface interface {
+	SaveRate(rate *model.ExchangeRate) model.Response[*model.ExchangeRate]
+	ImportRates(rates []model.ExchangeRate) model.Response[[]model.ExchangeRate]
+	DeleteRate(id uint) model.Response[*model.ExchangeRate]
+	GetRates(currency string) model.Response[[]model.ExchangeRate]
+	GetRate(currency string, date time.Time) model.Response[*model.ExchangeRate]
+}
+
 type Repository struct {
 	AuthRepositoryInterface
-	User      UserRepositoryInterface
-	Equipment EquipmentRepositoryInterface
-	Supplier  SupplierRepositoryInterface
-	Location  LocationRepositoryInterface
-	Movement  MovementRepositoryInterface
-	Document  DocumentRepositoryInterface
-	Category  CategoryRepositoryInterface
+	User         UserRepositoryInterface
+	Equipment    EquipmentRepositoryInterface
+	Supplier     SupplierRepositoryInterface
+	Location     LocationRepositoryInterface
+	Movement     MovementRepositoryInterface
+	Document     DocumentRepositoryInterface
+	Category     CategoryRepositoryInterface
+	Employee     EmployeeRepositoryInterface
+	Audit        AuditRepositoryInterface
+	Search       SearchRepositoryInterface
+	Import       ImportRepositoryInterface
+	Report       ReportRepositoryInterface
+	Inventory    InventoryRepositoryInterface
+	Scan         ScanRepositoryInterface
+	Maintenance  MaintenanceRepositoryInterface
+	Depreciation DepreciationRepositoryInterface
+	Rates        ExchangeRateRepositoryInterface
 }
 
-func NewRepository(db *gorm.DB) *Repository {
+// NewRepository создает репозитории. Настройки нумерации нужны репозиториям
+// оборудования, документов, импорта и инвентаризации, которые выдают номера
+// в транзакции создания.
+func NewRepository(db *gorm.DB, numbering config.NumberingConfig) *Repository {
 	return &Repository{
 		AuthRepositoryInterface: NewAuthRepo(db),
 		User:                    NewUserRepository(db),
-		Equipment:               NewEquipmentRepository(db),
+		Equipment:               NewEquipmentRepository(db, numbering),
 		Supplier:                NewSupplierRepository(db),
 		Location:                NewLocationRepository(db),
-		Movement:                NewMovementRepository(db),
-		Document:                NewDocumentRepository(db),
+		Movement:                NewMovementRepository(db, numbering),
+		Document:                NewDocumentRepository(db, numbering),
 		Category:                NewCategoryRepository(db),
+		Employee:                NewEmployeeRepository(db),
+		Audit:                   NewAuditRepository(db),
+		Search:                  NewSearchRepository(db),
+		Import:                  NewImportRepository(db, numbering),
+		Report:                  NewReportRepository(db),
+		Inventory:               NewInventoryRepository(db, numbering),
+		Scan:                    NewScanRepository(db),
+		Maintenance:             NewMaintenanceRepository(db),
+		Depreciation:            NewDepreciationRepository(db),
+		Rates:                   NewExchangeRateRepository(db),
 	}
 }
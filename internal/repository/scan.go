@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type ScanRepository struct {
+	db *gorm.DB
+}
+
+func NewScanRepository(db *gorm.DB) *ScanRepository {
+	return &ScanRepository{db: db}
+}
+
+// FindEquipmentByCode ищет оборудование по инвентарному, затем по серийному номеру.
+// Если оборудование не найдено, Model равен nil, а Message пуст.
+func (r *ScanRepository) FindEquipmentByCode(code string) model.Response[*model.Equipment] {
+	for _, column := range []string{"inventory_number", "serial_number"} {
+		var equipment model.Equipment
+		err := r.db.Preload("Location").
+			Preload("Category").
+			Preload("Responsible").
+			Preload("Balances", "quantity > 0").
+			Preload("Balances.Location").
+			Where(column+" = ?", code).
+			First(&equipment).Error
+		if err == nil {
+			return model.Response[*model.Equipment]{
+				Model: &equipment,
+			}
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.Response[*model.Equipment]{
+				Message: err.Error(),
+			}
+		}
+	}
+
+	return model.Response[*model.Equipment]{}
+}
+
+// AddTransferItem добавляет единицу оборудования в черновик перемещения.
+// Повторное сканирование увеличивает количество в позиции.
+func (r *ScanRepository) AddTransferItem(documentID, equipmentID uint) model.Response[*model.DocumentItem] {
+	var item model.DocumentItem
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var doc model.Document
+		if err := tx.First(&doc, documentID).Error; err != nil {
+			return fmt.Errorf("документ не найден")
+		}
+		if doc.Type != "transfer" {
+			return fmt.Errorf("документ не является перемещением")
+		}
+		if doc.Status != "draft" {
+			return fmt.Errorf("добавлять позиции можно только в черновик")
+		}
+
+		var equipment model.Equipment
+		if err := tx.First(&equipment, equipmentID).Error; err != nil {
+			return fmt.Errorf("оборудование не найдено")
+		}
+
+		var items []model.DocumentItem
+		if err := tx.Where("document_id = ? AND equipment_id = ?", documentID, equipmentID).
+			Limit(1).Find(&items).Error; err != nil {
+			return err
+		}
+		item = model.DocumentItem{DocumentID: documentID, EquipmentID: equipmentID, Price: equipment.Price}
+		if len(items) > 0 {
+			item = items[0]
+		}
+		item.Quantity++
+		item.Calculate()
+
+		// Перемещать можно только то, что есть в месте отправления
+		from := doc.FromLocationID
+		if from == 0 {
+			from = equipment.LocationID
+		}
+		var balance model.StockBalance
+		if err := tx.Where("equipment_id = ? AND location_id = ?", equipmentID, from).
+			Limit(1).Find(&balance).Error; err != nil {
+			return err
+		}
+		if balance.Quantity < item.Quantity {
+			return fmt.Errorf("в месте отправления доступно %d шт.", balance.Quantity)
+		}
+
+		return tx.Save(&item).Error
+	})
+	if err != nil {
+		return model.Response[*model.DocumentItem]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[*model.DocumentItem]{
+		Model: &item,
+	}
+}
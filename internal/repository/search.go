@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"tohaboy/internal/model"
+	"tohaboy/internal/storage"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// russianEndings окончания, которые отбрасываются перед префиксным поиском,
+// чтобы "ноутбуки" находил "ноутбук", а "серверов" - "сервер" (от длинных к коротким)
+var russianEndings = []string{
+	"ями", "ами", "ого", "его", "ому", "ему", "ыми", "ими", "иях",
+	"ах", "ях", "ов", "ев", "ей", "ой", "ий", "ый", "ая", "яя", "ое", "ее", "ые", "ие",
+	"ам", "ям", "ом", "ем", "ую", "юю",
+	"а", "я", "ы", "и", "у", "ю", "е", "о", "ь", "й",
+}
+
+// SearchRepository ищет по индексу search_index (FTS5), а если драйвер SQLite
+// собран без FTS5 - по тем же колонкам через LIKE
+type SearchRepository struct {
+	db  *gorm.DB
+	fts bool
+}
+
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	var used int
+	db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&used)
+	return &SearchRepository{
+		db:  db,
+		fts: used == 1 && db.Migrator().HasTable("search_index"),
+	}
+}
+
+func (r *SearchRepository) Search(query model.SearchQuery) model.Response[[]model.SearchHit] {
+	terms := searchTerms(query.Text)
+	if len(terms) == 0 {
+		return model.Response[[]model.SearchHit]{
+			Model: []model.SearchHit{},
+		}
+	}
+
+	limit := query.Limit
+	if limit < 1 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	sources, err := searchSources(query.Entities)
+	if err != nil {
+		return model.Response[[]model.SearchHit]{
+			Message: err.Error(),
+		}
+	}
+
+	var hits []model.SearchHit
+	if r.fts {
+		hits, err = r.searchFTS(terms, sources, limit)
+	} else {
+		hits, err = r.searchLike(terms, sources, limit)
+	}
+	if err != nil {
+		return model.Response[[]model.SearchHit]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[[]model.SearchHit]{
+		Model: hits,
+	}
+}
+
+// searchFTS ранжирует результаты по bm25, совпадения в заголовке весят больше
+func (r *SearchRepository) searchFTS(terms []string, sources []storage.SearchSource, limit int) ([]model.SearchHit, error) {
+	match := make([]string, len(terms))
+	for i, term := range terms {
+		match[i] = `"` + term + `"*`
+	}
+
+	entities := make([]string, len(sources))
+	for i, src := range sources {
+		entities[i] = src.Entity
+	}
+
+	hits := []model.SearchHit{}
+	err := r.db.Raw(`SELECT entity, entity_id AS id,
+			highlight(search_index, 2, '[', ']') AS title,
+			snippet(search_index, 3, '[', ']', '…', 12) AS snippet,
+			-bm25(search_index, 0, 0, 10.0, 1.0) AS rank
+		FROM search_index
+		WHERE search_index MATCH ? AND entity IN ?
+		ORDER BY rank DESC
+		LIMIT ?`, strings.Join(match, " "), entities, limit).
+		Scan(&hits).Error
+	return hits, err
+}
+
+// searchLike ищет подстроки без учета морфологии и ранжирует совпадения в заголовке выше
+func (r *SearchRepository) searchLike(terms []string, sources []storage.SearchSource, limit int) ([]model.SearchHit, error) {
+	hits := []model.SearchHit{}
+
+	for _, src := range sources {
+		body := src.BodyExpr("")
+		query := r.db.Table(src.Table).Select(fmt.Sprintf("id, %s AS title, %s AS body", src.Title, body))
+		// LIKE в SQLite не учитывает регистр только для латиницы,
+		// поэтому кириллические слова ищутся в трех вариантах написания
+		for _, term := range terms {
+			var conditions []string
+			var args []interface{}
+			for i, variant := range caseVariants(term) {
+				name := fmt.Sprintf("term%d", i)
+				conditions = append(conditions, fmt.Sprintf(`%s LIKE @%s ESCAPE '\' OR %s LIKE @%s ESCAPE '\'`, src.Title, name, body, name))
+				args = append(args, sql.Named(name, likePattern(variant)))
+			}
+			query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+		}
+
+		var rows []struct {
+			ID    uint
+			Title string
+			Body  string
+		}
+		if err := query.Limit(limit).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			rank := 1.0
+			if containsAny(row.Title, terms) {
+				rank = 2.0
+			}
+			hits = append(hits, model.SearchHit{
+				Entity:  src.Entity,
+				ID:      row.ID,
+				Title:   row.Title,
+				Snippet: excerpt(row.Body, terms),
+				Rank:    rank,
+			})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Rank > hits[j].Rank
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func searchSources(entities []string) ([]storage.SearchSource, error) {
+	all := storage.SearchSources()
+	if len(entities) == 0 {
+		return all, nil
+	}
+
+	var sources []storage.SearchSource
+	for _, entity := range entities {
+		found := false
+		for _, src := range all {
+			if src.Entity == entity {
+				sources = append(sources, src)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("неизвестный тип для поиска: %s", entity)
+		}
+	}
+	return sources, nil
+}
+
+// searchTerms разбивает строку поиска на слова и сокращает русские слова до основы
+func searchTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, searchStem(word))
+	}
+	return terms
+}
+
+func searchStem(word string) string {
+	runes := []rune(word)
+	if len(runes) < 5 || !unicode.Is(unicode.Cyrillic, runes[0]) {
+		return word
+	}
+
+	for _, ending := range russianEndings {
+		size := len([]rune(ending))
+		if len(runes)-size >= 4 && strings.HasSuffix(word, ending) {
+			return string(runes[:len(runes)-size])
+		}
+	}
+	return word
+}
+
+// caseVariants возвращает слово в нижнем регистре, с заглавной буквы и прописными
+func caseVariants(term string) []string {
+	runes := []rune(term)
+	capitalized := string(unicode.ToUpper(runes[0])) + string(runes[1:])
+	variants := []string{term}
+	for _, variant := range []string{capitalized, strings.ToUpper(term)} {
+		if variant != variants[len(variants)-1] {
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
+
+func containsAny(text string, terms []string) bool {
+	text = strings.ToLower(text)
+	for _, term := range terms {
+		if strings.Contains(text, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// excerpt возвращает фрагмент текста вокруг первого совпадения
+func excerpt(text string, terms []string) string {
+	const radius = 40
+
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	start := 0
+	for _, term := range terms {
+		if i := strings.Index(string(lower), term); i >= 0 {
+			start = len([]rune(string(lower)[:i]))
+			break
+		}
+	}
+
+	from := max(start-radius, 0)
+	to := min(start+radius, len(runes))
+	snippet := strings.TrimSpace(string(runes[from:to]))
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
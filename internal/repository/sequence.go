@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// nextDocumentNumber выдает документу следующий номер по правилу нумерации его типа.
+// Счетчик увеличивается одним UPSERT в транзакции создания документа. Storage
+// начинает транзакции с блокировкой записи (BEGIN IMMEDIATE) и ждет занятую базу,
+// поэтому параллельные создания документов выполняются по очереди и получают
+// разные номера, а при откате номер не расходуется.
+func nextDocumentNumber(tx *gorm.DB, numbering config.NumberingConfig, doc *model.Document) (string, error) {
+	rule, ok := numbering.Sequences[doc.Type]
+	if !ok {
+		return "", fmt.Errorf("нумерация для типа документа %q не настроена", doc.Type)
+	}
+
+	number, err := nextNumber(tx, rule, doc.Type, doc.Date, doc.LocationID, &model.Document{}, "number")
+	if err != nil {
+		return "", fmt.Errorf("ошибка выдачи номера документа: %v", err)
+	}
+	return number, nil
+}
+
+// nextInventoryNumber выдает оборудованию следующий инвентарный номер
+func nextInventoryNumber(tx *gorm.DB, numbering config.NumberingConfig, equipment *model.Equipment) (string, error) {
+	rule, ok := numbering.Sequences[config.EquipmentSequence]
+	if !ok {
+		return "", fmt.Errorf("нумерация оборудования не настроена")
+	}
+
+	number, err := nextNumber(tx, rule, config.EquipmentSequence, time.Now(), equipment.LocationID, &model.Equipment{}, "inventory_number")
+	if err != nil {
+		return "", fmt.Errorf("ошибка выдачи инвентарного номера: %v", err)
+	}
+	return number, nil
+}
+
+// nextSerialNumber выдает серийный номер оборудованию категории categoryID по префиксу
+// серийных номеров категории (в том числе унаследованному от родительской). Для каждого
+// префикса ведется свой счетчик. Если префикс не задан, возвращает пустую строку.
+func nextSerialNumber(tx *gorm.DB, categoryID uint) (string, error) {
+	category, err := findCategory(tx, categoryID)
+	if err != nil {
+		return "", err
+	}
+	prefix := category.Effective.SerialPrefix
+	if prefix == "" {
+		return "", nil
+	}
+
+	rule := config.SequenceConfig{Prefix: prefix, Format: "{prefix}{seq:000000}"}
+	number, err := nextNumber(tx, rule, "serial:"+prefix, time.Now(), 0, &model.Equipment{}, "serial_number")
+	if err != nil {
+		return "", fmt.Errorf("ошибка выдачи серийного номера: %v", err)
+	}
+	return number, nil
+}
+
+// nextNumber увеличивает счетчик kind и собирает номер по правилу rule.
+// Номера, уже занятые в колонке column таблицы table (например, выданные
+// до появления счетчиков), пропускаются.
+func nextNumber(tx *gorm.DB, rule config.SequenceConfig, kind string, date time.Time, locationID uint, table interface{}, column string) (string, error) {
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	sequence := model.NumberSequence{
+		DocumentType: kind,
+		Period:       rule.Period(date),
+	}
+	if rule.PerLocation {
+		sequence.LocationID = locationID
+	}
+
+	for {
+		if err := incrementSequence(tx, &sequence); err != nil {
+			return "", err
+		}
+
+		number := rule.Render(sequence.Value, date, sequence.LocationID)
+
+		var taken int64
+		if err := tx.Model(table).Where(column+" = ?", number).Count(&taken).Error; err != nil {
+			return "", err
+		}
+		if taken == 0 {
+			return number, nil
+		}
+	}
+}
+
+// AssignInventoryNumbers выдает инвентарные номера оборудованию, у которого их нет:
+// созданному до появления нумерации или в обход репозитория (демонстрационные данные).
+// Возвращает количество пронумерованного оборудования.
+func AssignInventoryNumbers(db *gorm.DB, numbering config.NumberingConfig) (int, error) {
+	var equipment []model.Equipment
+	if err := db.Where("inventory_number IS NULL OR inventory_number = ''").Order("id").Find(&equipment).Error; err != nil {
+		return 0, err
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for i := range equipment {
+			number, err := nextInventoryNumber(tx, numbering, &equipment[i])
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&equipment[i]).UpdateColumn("inventory_number", number).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(equipment), nil
+}
+
+// incrementSequence увеличивает счетчик на единицу, создавая его при первом обращении
+func incrementSequence(tx *gorm.DB, sequence *model.NumberSequence) error {
+	sequence.ID = 0
+	sequence.Value = 1
+	sequence.UpdatedAt = time.Now()
+
+	return tx.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "document_type"}, {Name: "period"}, {Name: "location_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"value":      gorm.Expr("value + 1"),
+				"updated_at": sequence.UpdatedAt,
+			}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "value"}}},
+	).Create(sequence).Error
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+func TestNextDocumentNumber(t *testing.T) {
+	f := newPostingFixture(t)
+	numbering := config.NumberingConfig{Sequences: map[string]config.SequenceConfig{
+		"acceptance": {Prefix: "ПРМ", Format: config.DefaultNumberFormat, Reset: config.ResetYearly},
+		"transfer":   {Prefix: "ПЕР", Format: "{prefix}-{yy}{mm}-{seq:00}", Reset: config.ResetMonthly},
+		"write_off":  {Prefix: "СПС", Format: "{prefix}-{location}-{seq}", Reset: config.ResetNever, PerLocation: true},
+	}}
+	next := func(t *testing.T, doc model.Document) string {
+		t.Helper()
+		number, err := nextDocumentNumber(f.db, numbering, &doc)
+		if err != nil {
+			t.Fatalf("nextDocumentNumber: %v", err)
+		}
+		return number
+	}
+	date := func(year int, month time.Month) time.Time {
+		return time.Date(year, month, 10, 0, 0, 0, 0, time.UTC)
+	}
+
+	// Номер, выданный до появления счетчиков, пропускается
+	if err := f.db.Create(&model.Document{Type: "acceptance", Number: "ПРМ-2024-003", Status: "draft", Date: date(2024, 1)}).Error; err != nil {
+		t.Fatalf("создание документа: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		doc  model.Document
+		want string
+	}{
+		{"первый номер года", model.Document{Type: "acceptance", Date: date(2024, 1)}, "ПРМ-2024-001"},
+		{"следующий номер", model.Document{Type: "acceptance", Date: date(2024, 11)}, "ПРМ-2024-002"},
+		{"занятый номер пропускается", model.Document{Type: "acceptance", Date: date(2024, 12)}, "ПРМ-2024-004"},
+		{"сброс в новом году", model.Document{Type: "acceptance", Date: date(2025, 1)}, "ПРМ-2025-001"},
+		{"предыдущий год продолжается", model.Document{Type: "acceptance", Date: date(2024, 12)}, "ПРМ-2024-005"},
+		{"ежемесячная нумерация", model.Document{Type: "transfer", Date: date(2024, 1)}, "ПЕР-2401-01"},
+		{"тот же месяц", model.Document{Type: "transfer", Date: date(2024, 1)}, "ПЕР-2401-02"},
+		{"сброс в новом месяце", model.Document{Type: "transfer", Date: date(2024, 2)}, "ПЕР-2402-01"},
+		{"счетчик местоположения", model.Document{Type: "write_off", LocationID: f.store}, fmt.Sprintf("СПС-%d-1", f.store)},
+		{"счетчик другого местоположения", model.Document{Type: "write_off", LocationID: f.office}, fmt.Sprintf("СПС-%d-1", f.office)},
+		{"сквозная нумерация", model.Document{Type: "write_off", LocationID: f.store, Date: date(2030, 6)}, fmt.Sprintf("СПС-%d-2", f.store)},
+	}
+	for _, tt := range tests {
+		if got := next(t, tt.doc); got != tt.want {
+			t.Errorf("%s: %s, ожидалось %s", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := nextDocumentNumber(f.db, numbering, &model.Document{Type: "inventory"}); err == nil {
+		t.Error("номер выдан типу документа без правила нумерации")
+	}
+
+	// Откат транзакции не расходует номер
+	rollback := errors.New("откат")
+	if err := f.db.Transaction(func(tx *gorm.DB) error {
+		if number, err := nextDocumentNumber(tx, numbering, &model.Document{Type: "acceptance", Date: date(2025, 3)}); err != nil || number != "ПРМ-2025-002" {
+			t.Errorf("номер в транзакции %s (%v), ожидалось ПРМ-2025-002", number, err)
+		}
+		return rollback
+	}); !errors.Is(err, rollback) {
+		t.Fatalf("транзакция: %v", err)
+	}
+	if got := next(t, model.Document{Type: "acceptance", Date: date(2025, 3)}); got != "ПРМ-2025-002" {
+		t.Errorf("после отката выдан %s, ожидалось ПРМ-2025-002", got)
+	}
+}
+
+func TestCreateDocumentConcurrentNumbers(t *testing.T) {
+	f := newPostingFixture(t)
+	const workers, perWorker = 8, 10
+
+	numbers := make(chan string, workers*perWorker)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				created := f.documents.CreateDocument(&model.Document{
+					Type:        "acceptance",
+					Status:      "draft",
+					Date:        time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+					LocationID:  f.store,
+					CreatedByID: 1,
+				})
+				if created.Model == nil {
+					t.Errorf("CreateDocument: %s", created.Message)
+					return
+				}
+				numbers <- created.Model.Number
+			}
+		}()
+	}
+	wg.Wait()
+	close(numbers)
+
+	seen := make(map[string]bool)
+	for number := range numbers {
+		if seen[number] {
+			t.Errorf("номер %s выдан дважды", number)
+		}
+		seen[number] = true
+	}
+	if len(seen) != workers*perWorker {
+		t.Errorf("выдано %d номеров, ожидалось %d", len(seen), workers*perWorker)
+	}
+	for i := 1; i <= workers*perWorker; i++ {
+		if number := fmt.Sprintf("ПРМ-2024-%03d", i); !seen[number] {
+			t.Errorf("пропущен номер %s", number)
+		}
+	}
+}
+
+func TestNextSerialNumber(t *testing.T) {
+	f := newPostingFixture(t)
+	categories := NewCategoryRepository(f.db)
+	create := func(category model.Category) uint {
+		created := categories.CreateCategory(&category)
+		if created.Model == nil {
+			t.Fatalf("CreateCategory: %s", created.Message)
+		}
+		return created.Model.ID
+	}
+	printers := create(model.Category{Name: "Принтеры", CategoryDefaults: model.CategoryDefaults{SerialPrefix: "PRN-"}})
+	laser := create(model.Category{Name: "Лазерные", ParentID: printers})
+	scanners := create(model.Category{Name: "Сканеры", CategoryDefaults: model.CategoryDefaults{SerialPrefix: "SCN-"}})
+	furniture := create(model.Category{Name: "Мебель"})
+
+	tests := []struct {
+		name     string
+		category uint
+		want     string
+	}{
+		{"префикс категории", printers, "PRN-000001"},
+		{"префикс родительской категории", laser, "PRN-000002"},
+		{"свой счетчик префикса", scanners, "SCN-000001"},
+		{"без префикса", furniture, ""},
+	}
+	for _, tt := range tests {
+		got, err := nextSerialNumber(f.db, tt.category)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: %q, ожидалось %q", tt.name, got, tt.want)
+		}
+	}
+	if _, err := nextSerialNumber(f.db, 999); err == nil {
+		t.Error("серийный номер выдан для несуществующей категории")
+	}
+}
+
+func TestAssignInventoryNumbers(t *testing.T) {
+	f := newPostingFixture(t)
+	numbering := config.NumberingConfig{Sequences: map[string]config.SequenceConfig{
+		config.EquipmentSequence: {Prefix: "ИН", Format: "{prefix}-{seq:0000}", Reset: config.ResetNever},
+	}}
+	for i := 0; i < 2; i++ {
+		if err := f.db.Create(&model.Equipment{Name: "Стул", SerialNumber: fmt.Sprintf("CH-%d", i), Status: model.EquipmentAvailable, Quantity: 1}).Error; err != nil {
+			t.Fatalf("создание оборудования: %v", err)
+		}
+	}
+
+	assigned, err := AssignInventoryNumbers(f.db, numbering)
+	if err != nil {
+		t.Fatalf("AssignInventoryNumbers: %v", err)
+	}
+	if assigned != 3 {
+		t.Errorf("пронумеровано %d, ожидалось 3", assigned)
+	}
+
+	var numbers []string
+	f.db.Model(&model.Equipment{}).Order("id").Pluck("inventory_number", &numbers)
+	want := []string{"ИН-0001", "ИН-0002", "ИН-0003"}
+	if fmt.Sprint(numbers) != fmt.Sprint(want) {
+		t.Errorf("инвентарные номера %v, ожидалось %v", numbers, want)
+	}
+
+	// Повторный вызов не меняет выданные номера
+	if assigned, err := AssignInventoryNumbers(f.db, numbering); err != nil || assigned != 0 {
+		t.Errorf("повторно пронумеровано %d (%v)", assigned, err)
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"fmt"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// adjustBalance изменяет остаток оборудования в местоположении на delta.
+// Остаток не может стать отрицательным; нулевые остатки удаляются.
+func adjustBalance(tx *gorm.DB, equipmentID, locationID uint, delta int) error {
+	if locationID == 0 {
+		return fmt.Errorf("местоположение не указано")
+	}
+
+	var balance model.StockBalance
+	if err := tx.Where("equipment_id = ? AND location_id = ?", equipmentID, locationID).
+		Limit(1).
+		Find(&balance).Error; err != nil {
+		return err
+	}
+
+	if balance.Quantity+delta < 0 {
+		return fmt.Errorf("недостаточно оборудования в местоположении: в наличии %d, требуется %d", balance.Quantity, -delta)
+	}
+
+	balance.EquipmentID = equipmentID
+	balance.LocationID = locationID
+	balance.Quantity += delta
+
+	if balance.Quantity == 0 {
+		if balance.ID == 0 {
+			return nil
+		}
+		return tx.Delete(&balance).Error
+	}
+	return tx.Save(&balance).Error
+}
+
+// stockBalance возвращает текущий остаток оборудования в местоположении
+func stockBalance(tx *gorm.DB, equipmentID, locationID uint) (int, error) {
+	var balances []model.StockBalance
+	if err := tx.Where("equipment_id = ? AND location_id = ?", equipmentID, locationID).
+		Limit(1).
+		Find(&balances).Error; err != nil {
+		return 0, err
+	}
+	if len(balances) == 0 {
+		return 0, nil
+	}
+	return balances[0].Quantity, nil
+}
+
+// moveStock переносит количество оборудования из одного местоположения в другое
+func moveStock(tx *gorm.DB, equipmentID, fromID, toID uint, quantity int) error {
+	if err := adjustBalance(tx, equipmentID, fromID, -quantity); err != nil {
+		return err
+	}
+	return adjustBalance(tx, equipmentID, toID, quantity)
+}
+
+// syncEquipmentStock пересчитывает общее количество оборудования по остаткам.
+// LocationID остается основным местоположением, пока там есть остаток,
+// иначе переходит к местоположению с наибольшим остатком.
+func syncEquipmentStock(tx *gorm.DB, equipment *model.Equipment) error {
+	var balances []model.StockBalance
+	if err := tx.Where("equipment_id = ?", equipment.ID).
+		Order("quantity DESC").
+		Find(&balances).Error; err != nil {
+		return err
+	}
+
+	total := 0
+	keepLocation := false
+	for _, balance := range balances {
+		total += balance.Quantity
+		if balance.LocationID == equipment.LocationID {
+			keepLocation = true
+		}
+	}
+
+	equipment.Quantity = total
+	if !keepLocation && len(balances) > 0 {
+		equipment.LocationID = balances[0].LocationID
+	}
+
+	return tx.Model(equipment).
+		Select("Quantity", "LocationID").
+		Updates(equipment).Error
+}
@@ -102,6 +102,33 @@ func (r *SupplierRepository) GetAllSuppliers() model.Response[[]model.Supplier]
 	}
 }
 
+// supplierListSpec поля сортировки и фильтры списка поставщиков.
+// Категория и местоположение относятся к поставляемому оборудованию.
+var supplierListSpec = listSpec{
+	sortFields: map[string]string{
+		"id":   "id",
+		"name": "name",
+	},
+	defaultSort: "name ASC",
+	search:      "name LIKE @value ESCAPE '\\' OR description LIKE @value ESCAPE '\\' OR address LIKE @value ESCAPE '\\' OR phone LIKE @value ESCAPE '\\'",
+	category:    "id IN (SELECT supplier_id FROM equipment WHERE category_id IN (" + subtreeCategoryValue + "))",
+	location: `id IN (SELECT e.supplier_id FROM equipment e
+		JOIN stock_balances b ON b.equipment_id = e.id WHERE b.location_id IN (` + subtreeLocationValue + `) AND b.quantity > 0)`,
+}
+
+func (r *SupplierRepository) ListSuppliers(query model.ListQuery) model.Response[*model.Page[model.Supplier]] {
+	page, err := paginate[model.Supplier](r.db, query, supplierListSpec)
+	if err != nil {
+		return model.Response[*model.Page[model.Supplier]]{
+			Message: err.Error(),
+		}
+	}
+
+	return model.Response[*model.Page[model.Supplier]]{
+		Model: page,
+	}
+}
+
 func (r *SupplierRepository) GetSupplierByEquipment(equipmentID int) model.Response[[]model.Supplier] {
 	var suppliers []model.Supplier
 	result := r.db.Joins("JOIN equipment ON equipment.supplier_id = suppliers.id").
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// Справочники-деревья (местоположения, категории) хранят у каждого узла
+// родителя parent_id и путь path из идентификаторов от корня: "/1/4/7/".
+// Поддерево узла выбирается одним условием path LIKE '<путь узла>%'.
+
+// treePath путь узла id под родителем с путем parentPath ("" - узел верхнего уровня)
+func treePath(parentPath string, id uint) string {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	return fmt.Sprintf("%s%d/", parentPath, id)
+}
+
+// moveTreeNode переносит узел node под родителя parentID: заменяет префикс
+// oldPath на newPath в путях узла и всех его потомков. Пути обновляются
+// запросом по модели узла, чтобы перенос попал в журнал аудита.
+func moveTreeNode(tx *gorm.DB, node interface{}, oldPath, newPath string, parentID uint) error {
+	subtree := reflect.New(reflect.Indirect(reflect.ValueOf(node)).Type()).Interface()
+	if err := tx.Model(subtree).Where("path LIKE ?", oldPath+"%").
+		Update("path", gorm.Expr("? || substr(path, ?)", newPath, len(oldPath)+1)).Error; err != nil {
+		return err
+	}
+
+	var parent interface{}
+	if parentID != 0 {
+		parent = parentID
+	}
+	return tx.Model(node).Update("parent_id", parent).Error
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+type AuditService struct {
+	repo    repository.AuditRepositoryInterface
+	session *Session
+}
+
+func NewAuditService(repo repository.AuditRepositoryInterface, session *Session) *AuditService {
+	return &AuditService{repo: repo, session: session}
+}
+
+// GetAuditEntries возвращает записи журнала по сущности, пользователю и периоду
+func (s *AuditService) GetAuditEntries(filter model.AuditFilter) *model.AuditListResponse {
+	if _, err := s.session.Authorize("AuditService.GetAuditEntries"); err != nil {
+		return &model.AuditListResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetAuditEntries(filter)
+	return &model.AuditListResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// GetEntityHistory возвращает историю изменений одной записи, например ("equipment", 5)
+func (s *AuditService) GetEntityHistory(entity string, id uint) *model.AuditListResponse {
+	if _, err := s.session.Authorize("AuditService.GetEntityHistory"); err != nil {
+		return &model.AuditListResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetAuditEntries(model.AuditFilter{Entity: entity, EntityID: id})
+	return &model.AuditListResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
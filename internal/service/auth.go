@@ -3,25 +3,27 @@ package service
 import (
 	"fmt"
 	"log"
-	"strconv"
 	"time"
+	"tohaboy/internal/config"
 	"tohaboy/internal/model"
 	"tohaboy/internal/repository"
 
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/spf13/viper"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService struct {
-	repo repository.AuthRepositoryInterface
+	repo    repository.AuthRepositoryInterface
+	session *Session
+	cfg     *config.Config
 }
 
-func NewAuthService(repo repository.AuthRepositoryInterface) *AuthService {
-	service := &AuthService{repo: repo}
-	// Создаем администратора при инициализации сервиса
-	if err := service.createAdminIfNotExists(); err != nil {
-		log.Printf("[service] error creating admin: %v", err)
+func NewAuthService(repo repository.AuthRepositoryInterface, session *Session, cfg *config.Config) *AuthService {
+	service := &AuthService{repo: repo, session: session, cfg: cfg}
+	// Создаем администратора при инициализации сервиса, если база не должна оставаться пустой
+	if cfg.Database.Seed != config.SeedNone {
+		if err := service.createAdminIfNotExists(); err != nil {
+			log.Printf("[service] error creating admin: %v", err)
+		}
 	}
 	return service
 }
@@ -39,7 +41,7 @@ func (s *AuthService) createAdminIfNotExists() error {
 	}
 
 	// Создаем нового администратора
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("admin"), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(s.cfg.Auth.AdminPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
@@ -47,7 +49,7 @@ func (s *AuthService) createAdminIfNotExists() error {
 	newAdmin := &model.User{
 		Username: "admin",
 		Password: string(hashedPassword),
-		Role:     "admin",
+		Role:     model.RoleAdmin,
 	}
 
 	_, err = s.repo.Register(newAdmin)
@@ -80,17 +82,14 @@ func (s *AuthService) Login(user map[string]string) (*model.LoginResponse, error
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
-	claims := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Issuer:    strconv.FormatUint(uint64(login.ID), 10),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-	})
-
-	token, err := claims.SignedString([]byte(viper.GetString("SECRET_KEY")))
+	token, err := s.session.issueToken(login.ID)
 	if err != nil {
 		log.Printf("[service] could not generate token: %v", err)
 		return nil, fmt.Errorf("internal server error")
 	}
 
+	s.session.Start(token)
+
 	// Don't expose password hash in response
 	login.Password = ""
 	return &model.LoginResponse{
@@ -105,6 +104,21 @@ func (s *AuthService) Register(user map[string]string) (*model.User, error) {
 		return nil, fmt.Errorf("username and password are required")
 	}
 
+	// Пользователей заводит администратор. Самостоятельная регистрация
+	// включается настройкой auth.allow_signup и дает только роль аудитора.
+	role := user["role"]
+	if role == "" {
+		role = model.RoleAuditor
+	}
+	if !isValidRole(role) {
+		return nil, fmt.Errorf("invalid role: %s", role)
+	}
+	if role != model.RoleAuditor || !s.cfg.Auth.AllowSignup {
+		if _, err := s.session.Authorize("AuthService.Register"); err != nil {
+			return nil, err
+		}
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user["password"]), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %v", err)
@@ -113,7 +127,7 @@ func (s *AuthService) Register(user map[string]string) (*model.User, error) {
 	newUser := &model.User{
 		Username:  user["username"],
 		Password:  string(hashedPassword),
-		Role:      user["role"], // If role is not provided, it will be empty
+		Role:      role,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -127,3 +141,30 @@ func (s *AuthService) Register(user map[string]string) (*model.User, error) {
 	reg.Password = ""
 	return reg, nil
 }
+
+// SignupAllowed сообщает, разрешена ли самостоятельная регистрация
+func (s *AuthService) SignupAllowed() bool {
+	return s.cfg.Auth.AllowSignup
+}
+
+// Authenticate восстанавливает сессию по ранее выданному токену,
+// например после перезапуска приложения
+func (s *AuthService) Authenticate(token string) (*model.User, error) {
+	if _, err := s.session.parseToken(token); err != nil {
+		log.Printf("[service] authenticate error: %v", err)
+		return nil, ErrUnauthorized
+	}
+
+	s.session.Start(token)
+	user, err := s.session.CurrentUser()
+	if err != nil {
+		s.session.End()
+		return nil, err
+	}
+	return user, nil
+}
+
+// Logout завершает текущую сессию
+func (s *AuthService) Logout() {
+	s.session.End()
+}
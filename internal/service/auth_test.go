@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowSignup bool
+		session     string // роль вошедшего пользователя, пусто - без сессии
+		role        string
+		want        string // роль созданного пользователя, пусто - отказ
+		err         error
+	}{
+		{name: "без сессии", role: model.RoleAuditor, err: ErrUnauthorized},
+		{name: "самостоятельная регистрация", allowSignup: true, want: model.RoleAuditor},
+		{name: "самостоятельная регистрация аудитора", allowSignup: true, role: model.RoleAuditor, want: model.RoleAuditor},
+		{name: "самостоятельная регистрация менеджера", allowSignup: true, role: model.RoleManager, err: ErrUnauthorized},
+		{name: "самостоятельная регистрация администратора", allowSignup: true, role: model.RoleAdmin, err: ErrUnauthorized},
+		{name: "менеджер регистрирует менеджера", session: model.RoleManager, role: model.RoleManager, err: ErrForbidden},
+		{name: "аудитор регистрирует аудитора", session: model.RoleAuditor, role: model.RoleAuditor, err: ErrForbidden},
+		{name: "администратор регистрирует менеджера", session: model.RoleAdmin, role: model.RoleManager, want: model.RoleManager},
+		{name: "администратор регистрирует администратора", session: model.RoleAdmin, role: model.RoleAdmin, want: model.RoleAdmin},
+		{name: "администратор без выбора роли", session: model.RoleAdmin, want: model.RoleAuditor},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newServiceFixture(t)
+			f.cfg.Auth.AllowSignup = tt.allowSignup
+			auth := NewAuthService(repository.NewRepository(f.db, f.cfg.Numbering).AuthRepositoryInterface, f.session, f.cfg)
+			if tt.session != "" {
+				f.login(t, tt.session)
+			}
+
+			username := fmt.Sprintf("user%d", i)
+			user, err := auth.Register(map[string]string{"username": username, "password": "secret", "role": tt.role})
+			if tt.want == "" {
+				if err != tt.err {
+					t.Fatalf("ошибка %v, ожидалась %v", err, tt.err)
+				}
+				var count int64
+				f.db.Model(&model.User{}).Where("username = ?", username).Count(&count)
+				if count != 0 {
+					t.Errorf("пользователь создан несмотря на отказ")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Register: %v", err)
+			}
+			if user.Role != tt.want || user.Password != "" {
+				t.Errorf("создан пользователь с ролью %q (пароль в ответе %q), ожидалась роль %q", user.Role, user.Password, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	f := newServiceFixture(t)
+	f.cfg.Auth.AllowSignup = true
+	auth := NewAuthService(repository.NewRepository(f.db, f.cfg.Numbering).AuthRepositoryInterface, f.session, f.cfg)
+
+	for _, user := range []map[string]string{
+		{"username": "", "password": "secret"},
+		{"username": "user", "password": ""},
+		{"username": "user", "password": "secret", "role": "root"},
+	} {
+		if _, err := auth.Register(user); err == nil {
+			t.Errorf("зарегистрирован пользователь %v", user)
+		}
+	}
+}
@@ -1,19 +1,30 @@
 package service
 
 import (
+	"fmt"
+	"strings"
 	"tohaboy/internal/model"
 	"tohaboy/internal/repository"
 )
 
 type CategoryService struct {
-	repo repository.CategoryRepositoryInterface
+	repo    repository.CategoryRepositoryInterface
+	session *Session
 }
 
-func NewCategoryService(repo repository.CategoryRepositoryInterface) CategoryServiceInterface {
-	return &CategoryService{repo: repo}
+func NewCategoryService(repo repository.CategoryRepositoryInterface, session *Session) CategoryServiceInterface {
+	return &CategoryService{repo: repo, session: session}
 }
 
 func (s *CategoryService) CreateCategory(category *model.Category) *model.CategoryResponse {
+	if _, err := s.session.Authorize("CategoryService.CreateCategory"); err != nil {
+		return &model.CategoryResponse{Message: err.Error()}
+	}
+
+	if err := validateCategory(category); err != nil {
+		return &model.CategoryResponse{Message: err.Error()}
+	}
+
 	response := s.repo.CreateCategory(category)
 	return &model.CategoryResponse{
 		Model:   response.Model,
@@ -22,6 +33,10 @@ func (s *CategoryService) CreateCategory(category *model.Category) *model.Catego
 }
 
 func (s *CategoryService) GetCategory(id int) *model.CategoryResponse {
+	if _, err := s.session.Authorize("CategoryService.GetCategory"); err != nil {
+		return &model.CategoryResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetCategory(id)
 	return &model.CategoryResponse{
 		Model:   response.Model,
@@ -30,6 +45,10 @@ func (s *CategoryService) GetCategory(id int) *model.CategoryResponse {
 }
 
 func (s *CategoryService) GetAllCategories() *model.CategoryListResponse {
+	if _, err := s.session.Authorize("CategoryService.GetAllCategories"); err != nil {
+		return &model.CategoryListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetAllCategories()
 	return &model.CategoryListResponse{
 		Model:   response.Model,
@@ -38,6 +57,14 @@ func (s *CategoryService) GetAllCategories() *model.CategoryListResponse {
 }
 
 func (s *CategoryService) UpdateCategory(category *model.Category) *model.CategoryResponse {
+	if _, err := s.session.Authorize("CategoryService.UpdateCategory"); err != nil {
+		return &model.CategoryResponse{Message: err.Error()}
+	}
+
+	if err := validateCategory(category); err != nil {
+		return &model.CategoryResponse{Message: err.Error()}
+	}
+
 	response := s.repo.UpdateCategory(category)
 	return &model.CategoryResponse{
 		Model:   response.Model,
@@ -46,9 +73,51 @@ func (s *CategoryService) UpdateCategory(category *model.Category) *model.Catego
 }
 
 func (s *CategoryService) DeleteCategory(id int) *model.CategoryResponse {
+	if _, err := s.session.Authorize("CategoryService.DeleteCategory"); err != nil {
+		return &model.CategoryResponse{Message: err.Error()}
+	}
+
 	response := s.repo.DeleteCategory(id)
 	return &model.CategoryResponse{
 		Model:   response.Model,
 		Message: response.Message,
 	}
 }
+
+func validateCategory(category *model.Category) error {
+	category.OKOF = strings.TrimSpace(category.OKOF)
+	category.Unit = strings.TrimSpace(category.Unit)
+	category.SerialPrefix = strings.TrimSpace(category.SerialPrefix)
+	if category.ParentID != 0 && category.ParentID == category.ID {
+		return fmt.Errorf("категория не может быть подкатегорией самой себя")
+	}
+	if category.MaintenanceIntervalDays < 0 {
+		return fmt.Errorf("периодичность обслуживания не может быть отрицательной")
+	}
+	switch category.DepreciationMethod {
+	case "", model.DepreciationStraightLine, model.DepreciationReducingBalance:
+	default:
+		return fmt.Errorf("неизвестный способ амортизации: %s", category.DepreciationMethod)
+	}
+	if category.UsefulLifeMonths < 0 {
+		return fmt.Errorf("срок полезного использования не может быть отрицательным")
+	}
+	if category.DepreciationFactor < 0 {
+		return fmt.Errorf("коэффициент ускорения не может быть отрицательным")
+	}
+	if category.DepreciationGroup != 0 {
+		from, to, ok := model.DepreciationGroupLife(category.DepreciationGroup)
+		if !ok {
+			return fmt.Errorf("амортизационная группа должна быть от 1 до 10")
+		}
+		if category.UsefulLifeMonths != 0 && !model.FitsDepreciationGroup(category.DepreciationGroup, category.UsefulLifeMonths) {
+			if to == 0 {
+				return fmt.Errorf("срок полезного использования %d мес. не соответствует %d амортизационной группе (от %d мес.)",
+					category.UsefulLifeMonths, category.DepreciationGroup, from)
+			}
+			return fmt.Errorf("срок полезного использования %d мес. не соответствует %d амортизационной группе (от %d до %d мес.)",
+				category.UsefulLifeMonths, category.DepreciationGroup, from, to)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+type DepreciationService struct {
+	repo       repository.DepreciationRepositoryInterface
+	categories repository.CategoryRepositoryInterface
+	docService DocumentServiceInterface
+	session    *Session
+	cfg        *config.Config
+}
+
+func NewDepreciationService(
+	repo repository.DepreciationRepositoryInterface,
+	categories repository.CategoryRepositoryInterface,
+	docService DocumentServiceInterface,
+	session *Session,
+	cfg *config.Config,
+) *DepreciationService {
+	return &DepreciationService{
+		repo:       repo,
+		categories: categories,
+		docService: docService,
+		session:    session,
+		cfg:        cfg,
+	}
+}
+
+// RunDepreciation начисляет амортизацию за месяц period ("2006-01")
+func (s *DepreciationService) RunDepreciation(period string) *model.DepreciationRunResponse {
+	user, err := s.session.Authorize("DepreciationService.RunDepreciation")
+	if err != nil {
+		return &model.DepreciationRunResponse{Message: err.Error()}
+	}
+
+	month, err := time.ParseInLocation("2006-01", period, time.Local)
+	if err != nil {
+		return &model.DepreciationRunResponse{Message: fmt.Sprintf("неверный месяц начисления: %v", err)}
+	}
+
+	response := s.repo.RunDepreciation(month, user.ID)
+	return &model.DepreciationRunResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// CancelDepreciation отменяет последнее начисление амортизации
+func (s *DepreciationService) CancelDepreciation(id uint) *model.DepreciationRunResponse {
+	if _, err := s.session.Authorize("DepreciationService.CancelDepreciation"); err != nil {
+		return &model.DepreciationRunResponse{Message: err.Error()}
+	}
+
+	response := s.repo.CancelDepreciation(id)
+	return &model.DepreciationRunResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// GetDepreciationRun возвращает начисление с проводками по оборудованию
+func (s *DepreciationService) GetDepreciationRun(id uint) *model.DepreciationRunResponse {
+	if _, err := s.session.Authorize("DepreciationService.GetDepreciationRun"); err != nil {
+		return &model.DepreciationRunResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetDepreciationRun(id)
+	return &model.DepreciationRunResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *DepreciationService) GetDepreciationRuns() *model.DepreciationRunListResponse {
+	if _, err := s.session.Authorize("DepreciationService.GetDepreciationRuns"); err != nil {
+		return &model.DepreciationRunListResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetDepreciationRuns()
+	return &model.DepreciationRunListResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// ExportSchedule выгружает график амортизации за период filter в XLSX
+func (s *DepreciationService) ExportSchedule(filter model.ReportFilter) *model.ReportResponse {
+	if _, err := s.session.Authorize("DepreciationService.ExportSchedule"); err != nil {
+		return &model.ReportResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetDepreciationSchedule(filter)
+	if response.Model == nil {
+		return &model.ReportResponse{Message: response.Message}
+	}
+	schedule := response.Model
+
+	subtitle := []string{
+		fmt.Sprintf("за период с %s по %s",
+			schedule.Months[0].Format("01.2006"), schedule.Months[len(schedule.Months)-1].Format("01.2006")),
+		organizationName(s.cfg.Organization.Name, s.cfg.Organization.INN, s.cfg.Organization.KPP),
+	}
+	if filter.CategoryID != 0 {
+		if category := s.categories.GetCategory(int(filter.CategoryID)); category.Model != nil {
+			subtitle = append(subtitle, "Категория: "+category.Model.FullName)
+		}
+	}
+
+	content, err := NewExportService(s.docService, s.cfg).ExportDepreciationSchedule(schedule, subtitle)
+	if err != nil {
+		return &model.ReportResponse{Message: err.Error()}
+	}
+	return &model.ReportResponse{
+		Content: base64.StdEncoding.EncodeToString(content),
+		Message: "Отчет успешно сформирован",
+	}
+}
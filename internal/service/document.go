@@ -3,21 +3,32 @@ package service
 import (
 	"encoding/base64"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
+	"tohaboy/internal/config"
 	"tohaboy/internal/model"
 	"tohaboy/internal/repository"
 )
 
 type DocumentService struct {
-	repo repository.DocumentRepositoryInterface
+	repo    repository.DocumentRepositoryInterface
+	session *Session
+	cfg     *config.Config
 }
 
-func NewDocumentService(repo repository.DocumentRepositoryInterface) *DocumentService {
-	return &DocumentService{repo: repo}
+func NewDocumentService(repo repository.DocumentRepositoryInterface, session *Session, cfg *config.Config) *DocumentService {
+	return &DocumentService{repo: repo, session: session, cfg: cfg}
 }
 
 func (s *DocumentService) CreateDocument(doc *model.Document) *model.DocumentResponse {
+	user, err := s.session.Authorize("DocumentService.CreateDocument")
+	if err != nil {
+		return &model.DocumentResponse{Message: err.Error()}
+	}
+
+	// Автором документа всегда считается пользователь текущей сессии
+	doc.CreatedByID = user.ID
+
 	// Валидация документа
 	if err := s.validateDocument(doc); err != nil {
 		return &model.DocumentResponse{
@@ -25,25 +36,6 @@ func (s *DocumentService) CreateDocument(doc *model.Document) *model.DocumentRes
 		}
 	}
 
-	// Генерируем уникальный номер документа
-	var uniqueNumber string
-	var attempts int
-	for attempts = 0; attempts < 10; attempts++ {
-		proposedNumber := s.generateDocumentNumber(doc.Type)
-		var count int64
-		err := s.repo.GetDB().Model(&model.Document{}).Where("number = ?", proposedNumber).Count(&count).Error
-		if err == nil && count == 0 {
-			uniqueNumber = proposedNumber
-			break
-		}
-	}
-	if attempts == 10 {
-		return &model.DocumentResponse{
-			Message: "Не удалось сгенерировать уникальный номер документа",
-		}
-	}
-	doc.Number = uniqueNumber
-
 	// Устанавливаем статус черновика
 	doc.Status = "draft"
 
@@ -52,9 +44,9 @@ func (s *DocumentService) CreateDocument(doc *model.Document) *model.DocumentRes
 		doc.Date = time.Now()
 	}
 
-	// Рассчитываем TotalPrice для каждой позиции
+	// Рассчитываем стоимость и НДС каждой позиции
 	for i := range doc.Items {
-		doc.Items[i].TotalPrice = doc.Items[i].Price * float64(doc.Items[i].Quantity)
+		doc.Items[i].Calculate()
 	}
 
 	response := s.repo.CreateDocument(doc)
@@ -65,6 +57,10 @@ func (s *DocumentService) CreateDocument(doc *model.Document) *model.DocumentRes
 }
 
 func (s *DocumentService) GetDocument(id uint) *model.DocumentResponse {
+	if _, err := s.session.Authorize("DocumentService.GetDocument"); err != nil {
+		return &model.DocumentResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetDocument(id)
 	return &model.DocumentResponse{
 		Model:   response.Model,
@@ -73,6 +69,10 @@ func (s *DocumentService) GetDocument(id uint) *model.DocumentResponse {
 }
 
 func (s *DocumentService) GetAllDocuments() *model.DocumentListResponse {
+	if _, err := s.session.Authorize("DocumentService.GetAllDocuments"); err != nil {
+		return &model.DocumentListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetAllDocuments()
 	return &model.DocumentListResponse{
 		Model:   response.Model,
@@ -80,7 +80,31 @@ func (s *DocumentService) GetAllDocuments() *model.DocumentListResponse {
 	}
 }
 
+// ListDocuments возвращает страницу списка с учетом фильтров и сортировки
+func (s *DocumentService) ListDocuments(query model.ListQuery) *model.DocumentPageResponse {
+	if _, err := s.session.Authorize("DocumentService.ListDocuments"); err != nil {
+		return &model.DocumentPageResponse{Message: err.Error()}
+	}
+
+	response := s.repo.ListDocuments(query)
+	return &model.DocumentPageResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
 func (s *DocumentService) UpdateDocument(doc *model.Document) *model.DocumentResponse {
+	if _, err := s.session.Authorize("DocumentService.UpdateDocument"); err != nil {
+		return &model.DocumentResponse{Message: err.Error()}
+	}
+
+	for i := range doc.Items {
+		if !doc.Items[i].VATRate.Valid() {
+			return &model.DocumentResponse{Message: fmt.Sprintf("неизвестная ставка НДС в позиции %d", i+1)}
+		}
+		doc.Items[i].Calculate()
+	}
+
 	response := s.repo.UpdateDocument(doc)
 	return &model.DocumentResponse{
 		Model:   response.Model,
@@ -89,6 +113,10 @@ func (s *DocumentService) UpdateDocument(doc *model.Document) *model.DocumentRes
 }
 
 func (s *DocumentService) DeleteDocument(id uint) *model.DocumentResponse {
+	if _, err := s.session.Authorize("DocumentService.DeleteDocument"); err != nil {
+		return &model.DocumentResponse{Message: err.Error()}
+	}
+
 	response := s.repo.DeleteDocument(id)
 	return &model.DocumentResponse{
 		Model:   response.Model,
@@ -97,7 +125,79 @@ func (s *DocumentService) DeleteDocument(id uint) *model.DocumentResponse {
 }
 
 func (s *DocumentService) ApproveDocument(id uint, approvedByID uint) *model.DocumentResponse {
-	response := s.repo.ApproveDocument(id, approvedByID)
+	user, err := s.session.Authorize("DocumentService.ApproveDocument")
+	if err != nil {
+		return &model.DocumentResponse{Message: err.Error()}
+	}
+
+	// Утверждающим всегда считается пользователь текущей сессии,
+	// переданный клиентом ID должен с ним совпадать
+	if approvedByID != 0 && approvedByID != user.ID {
+		return &model.DocumentResponse{Message: model.MessageForbidden}
+	}
+
+	response := s.repo.ApproveDocument(id, user.ID)
+	return &model.DocumentResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// CancelDocument отменяет черновик с указанием причины
+func (s *DocumentService) CancelDocument(id uint, userID uint, reason string) *model.DocumentResponse {
+	user, err := s.session.Authorize("DocumentService.CancelDocument")
+	if err != nil {
+		return &model.DocumentResponse{Message: err.Error()}
+	}
+	if userID != 0 && userID != user.ID {
+		return &model.DocumentResponse{Message: model.MessageForbidden}
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		return &model.DocumentResponse{
+			Message: "причина отмены не указана",
+		}
+	}
+
+	response := s.repo.CancelDocument(id, user.ID, reason)
+	return &model.DocumentResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// ReverseDocument сторнирует проведенный документ: создает связанный с ним
+// зеркальный документ, отменяющий его движения по остаткам
+func (s *DocumentService) ReverseDocument(id uint, userID uint, reason string) *model.DocumentResponse {
+	user, err := s.session.Authorize("DocumentService.ReverseDocument")
+	if err != nil {
+		return &model.DocumentResponse{Message: err.Error()}
+	}
+	if userID != 0 && userID != user.ID {
+		return &model.DocumentResponse{Message: model.MessageForbidden}
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		return &model.DocumentResponse{
+			Message: "причина сторнирования не указана",
+		}
+	}
+
+	original := s.repo.GetDocument(id)
+	if original.Model == nil {
+		return &model.DocumentResponse{
+			Message: original.Message,
+		}
+	}
+
+	// Номер сторнирующему документу выдает репозиторий
+	reversal := &model.Document{
+		Date:        time.Now(),
+		CreatedByID: user.ID,
+		Comment:     fmt.Sprintf("Сторно документа № %s: %s", original.Model.Number, reason),
+	}
+
+	response := s.repo.ReverseDocument(id, reversal)
 	return &model.DocumentResponse{
 		Model:   response.Model,
 		Message: response.Message,
@@ -105,10 +205,19 @@ func (s *DocumentService) ApproveDocument(id uint, approvedByID uint) *model.Doc
 }
 
 func (s *DocumentService) ExportDocument(id uint) *model.DocumentExportResponse {
+	if _, err := s.session.Authorize("DocumentService.ExportDocument"); err != nil {
+		return &model.DocumentExportResponse{Message: err.Error()}
+	}
+
 	// старый экспорт в удобный для нас формат
 	// Создаем сервис экспорта
-	exportService := NewExportService(s)
-	content, err := exportService.ExportDocument(id)
+	exportService := NewExportService(s, s.cfg)
+	export := exportService.ExportDocument
+	if s.cfg.Export.Format == config.ExportGOST {
+		// формат по умолчанию задан в настройках
+		export = exportService.ExportDocumentGOST
+	}
+	content, err := export(id)
 	if err != nil {
 		return &model.DocumentExportResponse{
 			Message: fmt.Sprintf("Ошибка экспорта документа: %v", err),
@@ -123,7 +232,11 @@ func (s *DocumentService) ExportDocument(id uint) *model.DocumentExportResponse
 }
 
 func (s *DocumentService) ExportDocumentGOST(id uint) *model.DocumentExportResponse {
-    exportService := NewExportService(s)
+    if _, err := s.session.Authorize("DocumentService.ExportDocumentGOST"); err != nil {
+        return &model.DocumentExportResponse{Message: err.Error()}
+    }
+
+    exportService := NewExportService(s, s.cfg)
     content, err := exportService.ExportDocumentGOST(id)
     if err != nil {
         return &model.DocumentExportResponse{Message: fmt.Sprintf("Ошибка экспорта (ГОСТ): %v", err)}
@@ -131,6 +244,26 @@ func (s *DocumentService) ExportDocumentGOST(id uint) *model.DocumentExportRespo
     return &model.DocumentExportResponse{Content: base64.StdEncoding.EncodeToString(content), Message: "Документ ГОСТ успешно экспортирован"}
 }
 
+// ExportDocumentPDF выгружает документ в PDF для печати
+func (s *DocumentService) ExportDocumentPDF(id uint) *model.DocumentExportResponse {
+	if _, err := s.session.Authorize("DocumentService.ExportDocumentPDF"); err != nil {
+		return &model.DocumentExportResponse{Message: err.Error()}
+	}
+
+	exportService := NewExportService(s, s.cfg)
+	content, err := exportService.ExportDocumentPDF(id)
+	if err != nil {
+		return &model.DocumentExportResponse{
+			Message: fmt.Sprintf("Ошибка экспорта документа в PDF: %v", err),
+		}
+	}
+
+	return &model.DocumentExportResponse{
+		Content: base64.StdEncoding.EncodeToString(content),
+		Message: "Документ PDF успешно экспортирован",
+	}
+}
+
 // Вспомогательные методы
 
 func (s *DocumentService) validateDocument(doc *model.Document) error {
@@ -156,7 +289,11 @@ func (s *DocumentService) validateDocument(doc *model.Document) error {
 			return fmt.Errorf("оборудование не указано в позиции %d", i+1)
 		}
 
-		if item.Quantity <= 0 {
+		// В инвентаризации учетное количество найденного сверх учета оборудования равно нулю
+		if doc.Type == "inventory" && item.Quantity < 0 {
+			return fmt.Errorf("учетное количество не может быть отрицательным в позиции %d", i+1)
+		}
+		if doc.Type != "inventory" && item.Quantity <= 0 {
 			return fmt.Errorf("количество должно быть больше нуля в позиции %d", i+1)
 		}
 
@@ -167,49 +304,11 @@ func (s *DocumentService) validateDocument(doc *model.Document) error {
 		if item.Price < 0 {
 			return fmt.Errorf("цена не может быть отрицательной в позиции %d", i+1)
 		}
-	}
 
-	return nil
-}
-
-func (s *DocumentService) generateDocumentNumber(docType string) string {
-	// Получаем префикс в зависимости от типа документа
-	prefix := map[string]string{
-		"inventory":  "ИНВ",
-		"transfer":   "ПЕР",
-		"write_off":  "СПС",
-		"acceptance": "ПРМ",
-	}[docType]
-
-	// Получаем текущий год
-	year := time.Now().Year()
-
-	// Получаем максимальный номер для текущего года и типа документа
-	var maxNumber string
-	pattern := fmt.Sprintf("%s-%d-%%", prefix, year)
-	err := s.repo.GetDB().Model(&model.Document{}).
-		Where("type = ? AND number LIKE ?", docType, pattern).
-		Select("number").
-		Order("number DESC").
-		Limit(1).
-		Scan(&maxNumber).Error
-
-	if err != nil {
-		// Если нет документов для этого года, начинаем с 1
-		return fmt.Sprintf("%s-%d-001", prefix, year)
-	}
-
-	// Извлекаем номер из последнего документа
-	lastNumber := "001"
-	if len(maxNumber) >= 11 {
-		lastNumber = maxNumber[8:11]
-	}
-
-	// Преобразуем номер в число и увеличиваем на 1
-	count, err := strconv.Atoi(lastNumber)
-	if err != nil {
-		count = 1
+		if !item.VATRate.Valid() {
+			return fmt.Errorf("неизвестная ставка НДС в позиции %d", i+1)
+		}
 	}
 
-	return fmt.Sprintf("%s-%d-%03d", prefix, year, count+1)
+	return nil
 }
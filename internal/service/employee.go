@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+type EmployeeService struct {
+	repo    repository.EmployeeRepositoryInterface
+	session *Session
+}
+
+func NewEmployeeService(repo repository.EmployeeRepositoryInterface, session *Session) EmployeeServiceInterface {
+	return &EmployeeService{repo: repo, session: session}
+}
+
+func (s *EmployeeService) CreateEmployee(employee *model.Employee) *model.EmployeeResponse {
+	if _, err := s.session.Authorize("EmployeeService.CreateEmployee"); err != nil {
+		return &model.EmployeeResponse{Message: err.Error()}
+	}
+
+	if err := validateEmployee(employee); err != nil {
+		return &model.EmployeeResponse{Message: err.Error()}
+	}
+
+	response := s.repo.CreateEmployee(employee)
+	return &model.EmployeeResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *EmployeeService) GetEmployee(id uint) *model.EmployeeResponse {
+	if _, err := s.session.Authorize("EmployeeService.GetEmployee"); err != nil {
+		return &model.EmployeeResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetEmployee(id)
+	return &model.EmployeeResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *EmployeeService) GetAllEmployees() *model.EmployeeListResponse {
+	if _, err := s.session.Authorize("EmployeeService.GetAllEmployees"); err != nil {
+		return &model.EmployeeListResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetAllEmployees()
+	return &model.EmployeeListResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *EmployeeService) UpdateEmployee(employee *model.Employee) *model.EmployeeResponse {
+	if _, err := s.session.Authorize("EmployeeService.UpdateEmployee"); err != nil {
+		return &model.EmployeeResponse{Message: err.Error()}
+	}
+
+	if err := validateEmployee(employee); err != nil {
+		return &model.EmployeeResponse{Message: err.Error()}
+	}
+
+	response := s.repo.UpdateEmployee(employee)
+	return &model.EmployeeResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *EmployeeService) DeleteEmployee(id uint) *model.EmployeeResponse {
+	if _, err := s.session.Authorize("EmployeeService.DeleteEmployee"); err != nil {
+		return &model.EmployeeResponse{Message: err.Error()}
+	}
+
+	response := s.repo.DeleteEmployee(id)
+	return &model.EmployeeResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// AssignEquipment закрепляет оборудование за материально ответственным сотрудником,
+// employeeID = 0 снимает ответственность
+func (s *EmployeeService) AssignEquipment(equipmentID uint, employeeID uint) *model.MovementResponse {
+	user, err := s.session.Authorize("EmployeeService.AssignEquipment")
+	if err != nil {
+		return &model.MovementResponse{Message: err.Error()}
+	}
+
+	response := s.repo.AssignEquipment(equipmentID, employeeID, user.ID)
+	return &model.MovementResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func validateEmployee(employee *model.Employee) error {
+	employee.Name = strings.TrimSpace(employee.Name)
+	employee.PersonnelNumber = strings.TrimSpace(employee.PersonnelNumber)
+	if employee.Name == "" {
+		return fmt.Errorf("ФИО сотрудника не указано")
+	}
+	return nil
+}
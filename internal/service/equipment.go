@@ -1,19 +1,25 @@
 package service
 
 import (
+	"strings"
 	"tohaboy/internal/model"
 	"tohaboy/internal/repository"
 )
 
 type EquipmentService struct {
-	repo repository.EquipmentRepositoryInterface
+	repo    repository.EquipmentRepositoryInterface
+	session *Session
 }
 
-func NewEquipmentService(repo repository.EquipmentRepositoryInterface) *EquipmentService {
-	return &EquipmentService{repo: repo}
+func NewEquipmentService(repo repository.EquipmentRepositoryInterface, session *Session) *EquipmentService {
+	return &EquipmentService{repo: repo, session: session}
 }
 
 func (s *EquipmentService) CreateEquipment(equipment *model.Equipment) *model.EquipmentResponse {
+	if _, err := s.session.Authorize("EquipmentService.CreateEquipment"); err != nil {
+		return &model.EquipmentResponse{Message: err.Error()}
+	}
+
 	response := s.repo.CreateEquipment(equipment)
 	return &model.EquipmentResponse{
 		Model:   response.Model,
@@ -22,6 +28,10 @@ func (s *EquipmentService) CreateEquipment(equipment *model.Equipment) *model.Eq
 }
 
 func (s *EquipmentService) GetEquipment(id int) *model.EquipmentResponse {
+	if _, err := s.session.Authorize("EquipmentService.GetEquipment"); err != nil {
+		return &model.EquipmentResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetEquipment(id)
 	return &model.EquipmentResponse{
 		Model:   response.Model,
@@ -30,6 +40,10 @@ func (s *EquipmentService) GetEquipment(id int) *model.EquipmentResponse {
 }
 
 func (s *EquipmentService) GetAllEquipment() *model.EquipmentListResponse {
+	if _, err := s.session.Authorize("EquipmentService.GetAllEquipment"); err != nil {
+		return &model.EquipmentListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetAllEquipment()
 	return &model.EquipmentListResponse{
 		Model:   response.Model,
@@ -37,7 +51,24 @@ func (s *EquipmentService) GetAllEquipment() *model.EquipmentListResponse {
 	}
 }
 
+// ListEquipment возвращает страницу списка с учетом фильтров и сортировки
+func (s *EquipmentService) ListEquipment(query model.ListQuery) *model.EquipmentPageResponse {
+	if _, err := s.session.Authorize("EquipmentService.ListEquipment"); err != nil {
+		return &model.EquipmentPageResponse{Message: err.Error()}
+	}
+
+	response := s.repo.ListEquipment(query)
+	return &model.EquipmentPageResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
 func (s *EquipmentService) UpdateEquipment(equipment *model.Equipment) *model.EquipmentResponse {
+	if _, err := s.session.Authorize("EquipmentService.UpdateEquipment"); err != nil {
+		return &model.EquipmentResponse{Message: err.Error()}
+	}
+
 	response := s.repo.UpdateEquipment(equipment)
 	return &model.EquipmentResponse{
 		Model:   response.Model,
@@ -46,6 +77,10 @@ func (s *EquipmentService) UpdateEquipment(equipment *model.Equipment) *model.Eq
 }
 
 func (s *EquipmentService) DeleteEquipment(id int) *model.EquipmentResponse {
+	if _, err := s.session.Authorize("EquipmentService.DeleteEquipment"); err != nil {
+		return &model.EquipmentResponse{Message: err.Error()}
+	}
+
 	response := s.repo.DeleteEquipment(id)
 	return &model.EquipmentResponse{
 		Model:   response.Model,
@@ -54,6 +89,10 @@ func (s *EquipmentService) DeleteEquipment(id int) *model.EquipmentResponse {
 }
 
 func (s *EquipmentService) GetEquipmentByLocation(locationID int) *model.EquipmentListResponse {
+	if _, err := s.session.Authorize("EquipmentService.GetEquipmentByLocation"); err != nil {
+		return &model.EquipmentListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetEquipmentByLocation(locationID)
 	return &model.EquipmentListResponse{
 		Model:   response.Model,
@@ -62,9 +101,54 @@ func (s *EquipmentService) GetEquipmentByLocation(locationID int) *model.Equipme
 }
 
 func (s *EquipmentService) GetEquipmentBySupplier(supplierID int) *model.EquipmentListResponse {
+	if _, err := s.session.Authorize("EquipmentService.GetEquipmentBySupplier"); err != nil {
+		return &model.EquipmentListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetEquipmentBySupplier(supplierID)
 	return &model.EquipmentListResponse{
 		Model:   response.Model,
 		Message: response.Message,
 	}
 }
+
+// IssueEquipment выдает оборудование в эксплуатацию
+func (s *EquipmentService) IssueEquipment(id uint, reason string) *model.EquipmentResponse {
+	return s.changeStatus("EquipmentService.IssueEquipment", id, model.EquipmentInUse, reason)
+}
+
+// ReturnEquipment возвращает оборудование из эксплуатации или ремонта на склад
+func (s *EquipmentService) ReturnEquipment(id uint, reason string) *model.EquipmentResponse {
+	return s.changeStatus("EquipmentService.ReturnEquipment", id, model.EquipmentAvailable, reason)
+}
+
+// SendToRepair передает оборудование в ремонт или на обслуживание
+func (s *EquipmentService) SendToRepair(id uint, reason string) *model.EquipmentResponse {
+	return s.changeStatus("EquipmentService.SendToRepair", id, model.EquipmentMaintenance, reason)
+}
+
+// GetStatusHistory возвращает историю статусов оборудования
+func (s *EquipmentService) GetStatusHistory(id uint) *model.EquipmentStatusHistoryResponse {
+	if _, err := s.session.Authorize("EquipmentService.GetStatusHistory"); err != nil {
+		return &model.EquipmentStatusHistoryResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetStatusHistory(id)
+	return &model.EquipmentStatusHistoryResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *EquipmentService) changeStatus(method string, id uint, to model.EquipmentStatus, reason string) *model.EquipmentResponse {
+	user, err := s.session.Authorize(method)
+	if err != nil {
+		return &model.EquipmentResponse{Message: err.Error()}
+	}
+
+	response := s.repo.ChangeStatus(id, to, user.ID, strings.TrimSpace(reason))
+	return &model.EquipmentResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
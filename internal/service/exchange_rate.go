@@ -0,0 +1,193 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+type ExchangeRateService struct {
+	repo    repository.ExchangeRateRepositoryInterface
+	session *Session
+}
+
+func NewExchangeRateService(repo repository.ExchangeRateRepositoryInterface, session *Session) *ExchangeRateService {
+	return &ExchangeRateService{repo: repo, session: session}
+}
+
+// SaveRate вводит курс валюты на дату вручную. Курс на ту же дату заменяется.
+func (s *ExchangeRateService) SaveRate(rate *model.ExchangeRate) *model.ExchangeRateResponse {
+	if _, err := s.session.Authorize("ExchangeRateService.SaveRate"); err != nil {
+		return &model.ExchangeRateResponse{Message: err.Error()}
+	}
+
+	rate.Currency = model.NormalizeCurrency(rate.Currency)
+	if rate.Nominal == 0 {
+		rate.Nominal = 1
+	}
+	if err := validateRate(rate); err != nil {
+		return &model.ExchangeRateResponse{Message: err.Error()}
+	}
+	rate.Source = model.RateSourceManual
+
+	response := s.repo.SaveRate(rate)
+	return &model.ExchangeRateResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *ExchangeRateService) DeleteRate(id uint) *model.ExchangeRateResponse {
+	if _, err := s.session.Authorize("ExchangeRateService.DeleteRate"); err != nil {
+		return &model.ExchangeRateResponse{Message: err.Error()}
+	}
+
+	response := s.repo.DeleteRate(id)
+	return &model.ExchangeRateResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// GetRates возвращает курсы валюты currency, пустая - всех валют
+func (s *ExchangeRateService) GetRates(currency string) *model.ExchangeRateListResponse {
+	if _, err := s.session.Authorize("ExchangeRateService.GetRates"); err != nil {
+		return &model.ExchangeRateListResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetRates(model.NormalizeCurrency(currency))
+	return &model.ExchangeRateListResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// GetRate возвращает курс валюты, действующий на дату date ("2006-01-02")
+func (s *ExchangeRateService) GetRate(currency string, date string) *model.ExchangeRateResponse {
+	if _, err := s.session.Authorize("ExchangeRateService.GetRate"); err != nil {
+		return &model.ExchangeRateResponse{Message: err.Error()}
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return &model.ExchangeRateResponse{Message: fmt.Sprintf("неверная дата: %v", err)}
+	}
+	response := s.repo.GetRate(model.NormalizeCurrency(currency), day)
+	return &model.ExchangeRateResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// ImportCBR загружает курсы из файла ежедневных курсов ЦБ РФ (XML_daily),
+// переданного в base64. Курсы на ту же дату заменяются.
+func (s *ExchangeRateService) ImportCBR(content string) *model.ExchangeRateListResponse {
+	if _, err := s.session.Authorize("ExchangeRateService.ImportCBR"); err != nil {
+		return &model.ExchangeRateListResponse{Message: err.Error()}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return &model.ExchangeRateListResponse{Message: "Не удалось прочитать файл: " + err.Error()}
+	}
+	rates, err := parseCBRRates(data)
+	if err != nil {
+		return &model.ExchangeRateListResponse{Message: err.Error()}
+	}
+
+	response := s.repo.ImportRates(rates)
+	return &model.ExchangeRateListResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func validateRate(rate *model.ExchangeRate) error {
+	if rate.Currency == "" {
+		return fmt.Errorf("курс рубля не вводится")
+	}
+	if !model.ValidCurrency(rate.Currency) {
+		return fmt.Errorf("неверный код валюты: %s", rate.Currency)
+	}
+	if rate.Date.IsZero() {
+		return fmt.Errorf("дата курса не указана")
+	}
+	if rate.Nominal <= 0 {
+		return fmt.Errorf("номинал должен быть больше нуля")
+	}
+	if rate.Rate <= 0 {
+		return fmt.Errorf("курс должен быть больше нуля")
+	}
+	return nil
+}
+
+// cbrRates файл ежедневных курсов ЦБ РФ:
+//
+//	<ValCurs Date="18.10.2026" name="Foreign Currency Market">
+//	  <Valute ID="R01235">
+//	    <CharCode>USD</CharCode><Nominal>1</Nominal><Value>92,5058</Value>
+//	  </Valute>
+//	</ValCurs>
+type cbrRates struct {
+	XMLName xml.Name `xml:"ValCurs"`
+	Date    string   `xml:"Date,attr"`
+	Valutes []struct {
+		CharCode string `xml:"CharCode"`
+		Nominal  string `xml:"Nominal"`
+		Value    string `xml:"Value"`
+	} `xml:"Valute"`
+}
+
+// parseCBRRates разбирает файл курсов ЦБ РФ в кодировке windows-1251 или UTF-8
+func parseCBRRates(data []byte) ([]model.ExchangeRate, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		if strings.EqualFold(charset, "windows-1251") || strings.EqualFold(charset, "cp1251") {
+			return charmap.Windows1251.NewDecoder().Reader(input), nil
+		}
+		return nil, fmt.Errorf("неподдерживаемая кодировка %s", charset)
+	}
+
+	var file cbrRates
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("файл не похож на курсы ЦБ РФ: %v", err)
+	}
+	date, err := time.ParseInLocation("02.01.2006", file.Date, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("неверная дата курсов «%s»", file.Date)
+	}
+	if len(file.Valutes) == 0 {
+		return nil, fmt.Errorf("в файле нет курсов валют")
+	}
+
+	rates := make([]model.ExchangeRate, 0, len(file.Valutes))
+	for _, valute := range file.Valutes {
+		rate := model.ExchangeRate{
+			Currency: model.NormalizeCurrency(valute.CharCode),
+			Date:     date,
+			Source:   model.RateSourceCBR,
+		}
+		rate.Nominal, err = strconv.Atoi(strings.TrimSpace(valute.Nominal))
+		if err != nil {
+			return nil, fmt.Errorf("неверный номинал %s: «%s»", valute.CharCode, valute.Nominal)
+		}
+		rate.Rate, err = model.ParseFixedRate(valute.Value)
+		if err != nil {
+			return nil, fmt.Errorf("неверный курс %s: «%s»", valute.CharCode, valute.Value)
+		}
+		if err := validateRate(&rate); err != nil {
+			return nil, fmt.Errorf("%s: %v", valute.CharCode, err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
@@ -4,41 +4,24 @@ import (
 	"bytes"
 	"fmt"
 	"time"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
 
 	"github.com/xuri/excelize/v2"
 )
 
 type ExportService struct {
 	docService DocumentServiceInterface
+	cfg        *config.Config
 }
 
-func NewExportService(docService DocumentServiceInterface) *ExportService {
+func NewExportService(docService DocumentServiceInterface, cfg *config.Config) *ExportService {
 	return &ExportService{
 		docService: docService,
+		cfg:        cfg,
 	}
 }
 
-type DocumentData struct {
-	Number      string
-	Date        string
-	Location    string
-	CreatedBy   string
-	ApprovedBy  string
-	Items       []ItemData
-	TotalItems  int
-	TotalPrice  float64
-	DateCreated string
-}
-
-type ItemData struct {
-	Number       int
-	Name         string
-	SerialNumber string
-	Quantity     int
-	Price        float64
-	TotalPrice   float64
-}
-
 func (s *ExportService) ExportDocument(docID uint) ([]byte, error) {
 	// Получаем документ
 	response := s.docService.GetDocument(docID)
@@ -69,12 +52,7 @@ func (s *ExportService) ExportDocument(docID uint) ([]byte, error) {
 	f.SetSheetName("Sheet1", sheetName)
 
 	// Устанавливаем заголовок документа
-	docType := map[string]string{
-		"inventory":  "АКТ ИНВЕНТАРИЗАЦИИ",
-		"transfer":   "АКТ ПЕРЕМЕЩЕНИЯ",
-		"write_off":  "АКТ СПИСАНИЯ",
-		"acceptance": "АКТ ПРИЕМКИ",
-	}[doc.Type]
+	docType := documentTitles[doc.Type]
 
 	// Форматирование заголовка
 	f.SetCellValue(sheetName, "A1", fmt.Sprintf("%s №%s", docType, doc.Number))
@@ -84,9 +62,12 @@ func (s *ExportService) ExportDocument(docID uint) ([]byte, error) {
 	if doc.ApprovedBy != nil {
 		f.SetCellValue(sheetName, "A5", fmt.Sprintf("Утвердил: %s", doc.ApprovedBy.Username))
 	}
+	if line := reversalLine(doc); line != "" {
+		f.SetCellValue(sheetName, "A6", line)
+	}
 
 	// Заголовки таблицы
-	headers := []string{"№", "Наименование", "Серийный номер", "Количество", "Цена", "Сумма"}
+	headers := []string{"№", "Наименование", "Серийный номер", "Количество", "Цена", "Сумма", "Ставка НДС", "Сумма НДС", "Всего с НДС", "Цена в валюте"}
 	for i, header := range headers {
 		cell := fmt.Sprintf("%c7", 'A'+i)
 		f.SetCellValue(sheetName, cell, header)
@@ -94,7 +75,7 @@ func (s *ExportService) ExportDocument(docID uint) ([]byte, error) {
 
 	// Данные таблицы
 	var totalItems int
-	var totalPrice float64
+	var totalPrice, totalVAT model.Money
 	for i, item := range doc.Items {
 		// Проверяем наличие оборудования
 		if item.Equipment.ID == 0 {
@@ -106,18 +87,27 @@ func (s *ExportService) ExportDocument(docID uint) ([]byte, error) {
 		f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), item.Equipment.Name)
 		f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), item.Equipment.SerialNumber)
 		f.SetCellValue(sheetName, fmt.Sprintf("D%d", row), item.Quantity)
-		f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), item.Price)
-		f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), item.TotalPrice)
+		f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), item.Price.Float64())
+		f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), item.TotalPrice.Float64())
+		f.SetCellValue(sheetName, fmt.Sprintf("G%d", row), item.VATRate.Label())
+		f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), item.VATAmount.Float64())
+		f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), item.TotalWithVAT().Float64())
+		if item.Currency != "" {
+			f.SetCellValue(sheetName, fmt.Sprintf("J%d", row), currencyPrice(item.CurrencyPrice, item.Currency, item.ExchangeRate, item.ExchangeNominal))
+		}
 
 		totalItems += item.Quantity
 		totalPrice += item.TotalPrice
+		totalVAT += item.VATAmount
 	}
 
 	// Итоги
 	lastRow := len(doc.Items) + 8
 	f.SetCellValue(sheetName, fmt.Sprintf("A%d", lastRow+1), "Итого:")
 	f.SetCellValue(sheetName, fmt.Sprintf("D%d", lastRow+1), totalItems)
-	f.SetCellValue(sheetName, fmt.Sprintf("F%d", lastRow+1), totalPrice)
+	f.SetCellValue(sheetName, fmt.Sprintf("F%d", lastRow+1), totalPrice.Float64())
+	f.SetCellValue(sheetName, fmt.Sprintf("H%d", lastRow+1), totalVAT.Float64())
+	f.SetCellValue(sheetName, fmt.Sprintf("I%d", lastRow+1), (totalPrice + totalVAT).Float64())
 
 	// Подписи
 	f.SetCellValue(sheetName, fmt.Sprintf("A%d", lastRow+3), fmt.Sprintf("Создал: _____________ %s", doc.CreatedBy.Username))
@@ -133,6 +123,10 @@ func (s *ExportService) ExportDocument(docID uint) ([]byte, error) {
 	f.SetColWidth(sheetName, "D", "D", 10) // Количество
 	f.SetColWidth(sheetName, "E", "E", 12) // Цена
 	f.SetColWidth(sheetName, "F", "F", 12) // Сумма
+	f.SetColWidth(sheetName, "G", "G", 10) // Ставка НДС
+	f.SetColWidth(sheetName, "H", "H", 12) // Сумма НДС
+	f.SetColWidth(sheetName, "I", "I", 14) // Всего с НДС
+	f.SetColWidth(sheetName, "J", "J", 24) // Цена в валюте
 
 	// Сохраняем в буфер
 	var buf bytes.Buffer
@@ -143,159 +137,20 @@ func (s *ExportService) ExportDocument(docID uint) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// Шаблоны документов
-const inventoryActTemplate = `
-                                        УТВЕРЖДАЮ
-                                        {{if .ApprovedBy}}
-                                        ______________________ {{.ApprovedBy}}
-                                        {{end}}
-                                        "___" ____________ 20__ г.
-
-                          АКТ ИНВЕНТАРИЗАЦИИ №{{.Number}}
-                                от {{.Date}}
-
-Местонахождение: {{.Location}}
-
-Комиссия в составе:
-Председатель: {{.CreatedBy}}
-{{if .ApprovedBy}}Члены комиссии: {{.ApprovedBy}}{{end}}
-
-произвела инвентаризацию материальных ценностей, находящихся на балансе организации.
-
-В результате инвентаризации установлено следующее:
-
-+-----+------------------------+---------------+----------+------------+-------------+
-| №   | Наименование          | Серийный №   | Кол-во   | Цена, руб. | Сумма, руб. |
-+-----+------------------------+---------------+----------+------------+-------------+
-{{range .Items}}
-| {{printf "%-4d" .Number}} | {{printf "%-22s" .Name}} | {{printf "%-13s" .SerialNumber}} | {{printf "%-8d" .Quantity}} | {{printf "%-10.2f" .Price}} | {{printf "%-11.2f" .TotalPrice}} |
-{{end}}
-+-----+------------------------+---------------+----------+------------+-------------+
-
-Итого наименований: {{len .Items}}
-Общее количество: {{.TotalItems}}
-Общая стоимость: {{printf "%.2f" .TotalPrice}} руб.
-
-Председатель комиссии: _____________ {{.CreatedBy}}
-{{if .ApprovedBy}}
-Члены комиссии:       _____________ {{.ApprovedBy}}
-{{end}}
-
-Дата составления: {{.DateCreated}}
-`
-
-const transferActTemplate = `
-                                        УТВЕРЖДАЮ
-                                        {{if .ApprovedBy}}
-                                        ______________________ {{.ApprovedBy}}
-                                        {{end}}
-                                        "___" ____________ 20__ г.
-
-                          АКТ ПЕРЕМЕЩЕНИЯ №{{.Number}}
-                                от {{.Date}}
-
-Местонахождение: {{.Location}}
-
-Материально-ответственное лицо: {{.CreatedBy}}
-
-Настоящий акт составлен о перемещении следующих материальных ценностей:
-
-+-----+------------------------+---------------+----------+------------+-------------+
-| №   | Наименование          | Серийный №   | Кол-во   | Цена, руб. | Сумма, руб. |
-+-----+------------------------+---------------+----------+------------+-------------+
-{{range .Items}}
-| {{printf "%-4d" .Number}} | {{printf "%-22s" .Name}} | {{printf "%-13s" .SerialNumber}} | {{printf "%-8d" .Quantity}} | {{printf "%-10.2f" .Price}} | {{printf "%-11.2f" .TotalPrice}} |
-{{end}}
-+-----+------------------------+---------------+----------+------------+-------------+
-
-Итого наименований: {{len .Items}}
-Общее количество: {{.TotalItems}}
-Общая стоимость: {{printf "%.2f" .TotalPrice}} руб.
-
-Сдал:    _____________ {{.CreatedBy}}
-{{if .ApprovedBy}}
-Принял:  _____________ {{.ApprovedBy}}
-{{end}}
-
-Дата составления: {{.DateCreated}}
-`
-
-const writeOffActTemplate = `
-                                        УТВЕРЖДАЮ
-                                        {{if .ApprovedBy}}
-                                        ______________________ {{.ApprovedBy}}
-                                        {{end}}
-                                        "___" ____________ 20__ г.
-
-                          АКТ СПИСАНИЯ №{{.Number}}
-                                от {{.Date}}
-
-Местонахождение: {{.Location}}
-
-Комиссия в составе:
-Председатель: {{.CreatedBy}}
-{{if .ApprovedBy}}Члены комиссии: {{.ApprovedBy}}{{end}}
-
-произвела проверку состояния материальных ценностей и установила необходимость их списания:
-
-+-----+------------------------+---------------+----------+------------+-------------+
-| №   | Наименование          | Серийный №   | Кол-во   | Цена, руб. | Сумма, руб. |
-+-----+------------------------+---------------+----------+------------+-------------+
-{{range .Items}}
-| {{printf "%-4d" .Number}} | {{printf "%-22s" .Name}} | {{printf "%-13s" .SerialNumber}} | {{printf "%-8d" .Quantity}} | {{printf "%-10.2f" .Price}} | {{printf "%-11.2f" .TotalPrice}} |
-{{end}}
-+-----+------------------------+---------------+----------+------------+-------------+
-
-Итого наименований: {{len .Items}}
-Общее количество: {{.TotalItems}}
-Общая стоимость: {{printf "%.2f" .TotalPrice}} руб.
-
-Заключение комиссии: Указанные материальные ценности подлежат списанию.
-
-Председатель комиссии: _____________ {{.CreatedBy}}
-{{if .ApprovedBy}}
-Члены комиссии:       _____________ {{.ApprovedBy}}
-{{end}}
-
-Дата составления: {{.DateCreated}}
-`
-
-const acceptanceActTemplate = `
-                                        УТВЕРЖДАЮ
-                                        {{if .ApprovedBy}}
-                                        ______________________ {{.ApprovedBy}}
-                                        {{end}}
-                                        "___" ____________ 20__ г.
-
-                          АКТ ПРИЕМКИ №{{.Number}}
-                                от {{.Date}}
-
-Местонахождение: {{.Location}}
-
-Комиссия в составе:
-Председатель: {{.CreatedBy}}
-{{if .ApprovedBy}}Члены комиссии: {{.ApprovedBy}}{{end}}
-
-произвела приемку следующих материальных ценностей:
-
-+-----+------------------------+---------------+----------+------------+-------------+
-| №   | Наименование          | Серийный №   | Кол-во   | Цена, руб. | Сумма, руб. |
-+-----+------------------------+---------------+----------+------------+-------------+
-{{range .Items}}
-| {{printf "%-4d" .Number}} | {{printf "%-22s" .Name}} | {{printf "%-13s" .SerialNumber}} | {{printf "%-8d" .Quantity}} | {{printf "%-10.2f" .Price}} | {{printf "%-11.2f" .TotalPrice}} |
-{{end}}
-+-----+------------------------+---------------+----------+------------+-------------+
-
-Итого наименований: {{len .Items}}
-Общее количество: {{.TotalItems}}
-Общая стоимость: {{printf "%.2f" .TotalPrice}} руб.
-
-Заключение комиссии: Материальные ценности соответствуют сопроводительным документам.
-
-Председатель комиссии: _____________ {{.CreatedBy}}
-{{if .ApprovedBy}}
-Члены комиссии:       _____________ {{.ApprovedBy}}
-{{end}}
+// currencyPrice цена в валюте с примененным курсом: "1200.00 USD по 92.5058",
+// "15000.00 JPY по 61.2345 за 100"
+func currencyPrice(price model.Money, currency string, rate model.FixedRate, nominal int) string {
+	if nominal > 1 {
+		return fmt.Sprintf("%s %s по %s за %d", price, currency, rate, nominal)
+	}
+	return fmt.Sprintf("%s %s по %s", price, currency, rate)
+}
 
-Дата составления: {{.DateCreated}}
-`
+// unitOf единица измерения оборудования для печатных форм: из его категории
+// (с учетом родительских), а если там не задана - из настроек выгрузки
+func (s *ExportService) unitOf(equipment *model.Equipment) string {
+	if equipment.Category != nil && equipment.Category.Effective.Unit != "" {
+		return equipment.Category.Effective.Unit
+	}
+	return s.cfg.Export.Unit
+}
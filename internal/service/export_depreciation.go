@@ -0,0 +1,114 @@
+package service
+
+import (
+	"tohaboy/internal/model"
+)
+
+var depreciationMethods = map[string]string{
+	model.DepreciationStraightLine:    "Линейный",
+	model.DepreciationReducingBalance: "Уменьшаемого остатка",
+}
+
+// ExportDepreciationSchedule выгружает график амортизации в XLSX: оборудование
+// по категориям с остаточной стоимостью на начало и конец периода и суммами
+// амортизации по месяцам. Месяцы, рассчитанные по прогнозу, отмечены звездочкой.
+func (s *ExportService) ExportDepreciationSchedule(schedule *model.DepreciationSchedule, subtitle []string) ([]byte, error) {
+	columns := []reportColumn{
+		{Title: "№", Width: 6},
+		{Title: "Инв. номер", Width: 14},
+		{Title: "Наименование", Width: 32},
+		{Title: "ОКОФ", Width: 16},
+		{Title: "Аморт. группа", Width: 8, Format: cellInt},
+		{Title: "Ввод в эксплуатацию", Width: 13},
+		{Title: "Способ", Width: 16},
+		{Title: "СПИ, мес.", Width: 8, Format: cellInt},
+		{Title: "Кол-во", Width: 8, Format: cellInt},
+		{Title: "Первоначальная стоимость", Width: 16, Format: cellMoney},
+		{Title: "Остаточная стоимость на начало", Width: 16, Format: cellMoney},
+	}
+	forecast := false
+	for i, month := range schedule.Months {
+		title := month.Format("01.2006")
+		if schedule.Forecast[i] {
+			title += "*"
+			forecast = true
+		}
+		columns = append(columns, reportColumn{Title: title, Group: "Амортизация", Width: 12, Format: cellMoney})
+	}
+	columns = append(columns,
+		reportColumn{Title: "Итого за период", Width: 14, Format: cellMoney},
+		reportColumn{Title: "Остаточная стоимость на конец", Width: 16, Format: cellMoney},
+	)
+	if forecast {
+		subtitle = append(subtitle, "* суммы рассчитаны по прогнозу, начисление за месяц не проводилось")
+	}
+
+	w := newXLSXReport("Амортизация", "График амортизации основных средств", subtitle, columns)
+
+	months := len(schedule.Months)
+	total, categoryTotal := newScheduleTotal(months), newScheduleTotal(months)
+	rows := schedule.Rows
+	for i, row := range rows {
+		if i == 0 || row.Category != rows[i-1].Category {
+			w.group("Категория: " + orDefault(row.Category, "без категории"))
+			categoryTotal = newScheduleTotal(months)
+		}
+
+		commissioned := ""
+		if row.CommissionedAt != nil {
+			commissioned = row.CommissionedAt.Format("02.01.2006")
+		}
+		period := model.Money(0)
+		var group interface{}
+		if row.Group != 0 {
+			group = row.Group
+		}
+		values := []interface{}{i + 1, row.InventoryNumber, row.Name, row.OKOF, group, commissioned,
+			orDefault(depreciationMethods[row.Method], row.Method), row.UsefulLifeMonths, row.Quantity, row.Cost, row.Opening}
+		for _, amount := range row.Amounts {
+			values = append(values, amount)
+			period += amount
+		}
+		values = append(values, period, row.Closing)
+		w.add(values...)
+		total.add(row)
+		categoryTotal.add(row)
+
+		if i == len(rows)-1 || row.Category != rows[i+1].Category {
+			w.total("Итого по категории", categoryTotal.values()...)
+		}
+	}
+	w.total("Всего", total.values()...)
+
+	return w.bytes()
+}
+
+// scheduleTotal итоги графика амортизации
+type scheduleTotal struct {
+	cost, opening, closing model.Money
+	amounts                []model.Money
+}
+
+func newScheduleTotal(months int) *scheduleTotal {
+	return &scheduleTotal{amounts: make([]model.Money, months)}
+}
+
+func (t *scheduleTotal) add(row model.DepreciationScheduleRow) {
+	t.cost += row.Cost
+	t.opening += row.Opening
+	t.closing += row.Closing
+	for i, amount := range row.Amounts {
+		t.amounts[i] += amount
+	}
+}
+
+// values значения строки итогов: подпись занимает столбцы до первоначальной стоимости
+func (t *scheduleTotal) values() []interface{} {
+	values := []interface{}{nil, nil, nil, nil, nil, nil, nil, nil, nil, t.cost, t.opening}
+	period := model.Money(0)
+	for _, amount := range t.amounts {
+		values = append(values, amount)
+		period += amount
+	}
+	return append(values, period, t.closing)
+}
@@ -3,12 +3,16 @@ package service
 import (
     "bytes"
     "fmt"
+    "strings"
     "time"
+    "tohaboy/internal/model"
 
     "github.com/xuri/excelize/v2"
 )
 
-// ExportDocumentGOST создает Excel-файл по официальной форме (Гост) для указанного документа
+// ExportDocumentGOST создает Excel-файл по официальной форме (Гост) для указанного документа.
+// Инвентаризация выгружается в форму № ИНВ-19 по шаблону internal/files/law.XLS,
+// остальные документы - в упрощенный акт.
 func (s *ExportService) ExportDocumentGOST(docID uint) ([]byte, error) {
     // Получаем документ как обычно
     response := s.docService.GetDocument(docID)
@@ -24,6 +28,13 @@ func (s *ExportService) ExportDocumentGOST(docID uint) ([]byte, error) {
         return nil, fmt.Errorf("создатель документа не найден")
     }
 
+    // Формы, для которых есть официальный шаблон, заполняются по нему.
+    // Сторно печатается упрощенным актом с отметкой: унифицированная форма
+    // исходного документа его не предусматривает.
+    if form, ok := unifiedForms[doc.Type]; ok && doc.ReversalOfID == 0 {
+        return s.exportUnifiedForm(form, doc)
+    }
+
     f := excelize.NewFile()
     defer func() {
         _ = f.Close()
@@ -32,27 +43,36 @@ func (s *ExportService) ExportDocumentGOST(docID uint) ([]byte, error) {
     sheet := "Форма"
     f.SetSheetName("Sheet1", sheet)
 
-    // Шапка в зависимости от типа документа
+    // Шапка в зависимости от типа документа. Шаблонов унифицированных форм
+    // для этих типов нет, поэтому номер формы в шапке не указывается.
     var formName string
     switch doc.Type {
-    case "inventory":
-        formName = "Форма № ИНВ-1"
     case "transfer":
-        formName = "Форма № МБ-1"
+        formName = "Акт о перемещении"
     case "write_off":
-        formName = "Форма № МБ-8"
+        formName = "Акт о списании"
     case "acceptance":
-        formName = "Форма № ОС-1"
+        formName = "Акт о приеме"
+    case "inventory":
+        formName = "Акт инвентаризации"
     default:
         formName = "Форма документа"
     }
 
     f.SetCellValue(sheet, "A1", formName)
     f.MergeCell(sheet, "A1", "F1")
-    f.SetCellValue(sheet, "A2", fmt.Sprintf("Акт № %s", doc.Number))
+    number := fmt.Sprintf("№ %s", doc.Number)
+    if line := reversalLine(doc); line != "" {
+        number += ". " + line
+    }
+    f.SetCellValue(sheet, "A2", number)
     f.MergeCell(sheet, "A2", "F2")
     f.SetCellValue(sheet, "A3", fmt.Sprintf("от %s", doc.Date.Format("02.01.2006")))
     f.MergeCell(sheet, "A3", "F3")
+    if org := s.organizationLine(); org != "" {
+        f.SetCellValue(sheet, "A4", org)
+        f.MergeCell(sheet, "A4", "F4")
+    }
 
     // Заголовки таблицы (приближены к ГОСТ)
     headers := []string{"№", "Наименование", "Серийный номер", "Ед.изм.", "Кол-во", "Сумма"}
@@ -63,7 +83,7 @@ func (s *ExportService) ExportDocumentGOST(docID uint) ([]byte, error) {
 
     // Данные таблицы
     var totalQty int
-    var totalPrice float64
+    var totalPrice model.Money
     for i, item := range doc.Items {
         if item.Equipment.ID == 0 {
             continue
@@ -72,9 +92,9 @@ func (s *ExportService) ExportDocumentGOST(docID uint) ([]byte, error) {
         f.SetCellValue(sheet, fmt.Sprintf("A%d", row), i+1)
         f.SetCellValue(sheet, fmt.Sprintf("B%d", row), item.Equipment.Name)
         f.SetCellValue(sheet, fmt.Sprintf("C%d", row), item.Equipment.SerialNumber)
-        f.SetCellValue(sheet, fmt.Sprintf("D%d", row), "шт.")
+        f.SetCellValue(sheet, fmt.Sprintf("D%d", row), s.unitOf(&item.Equipment))
         f.SetCellValue(sheet, fmt.Sprintf("E%d", row), item.Quantity)
-        f.SetCellValue(sheet, fmt.Sprintf("F%d", row), item.TotalPrice)
+        f.SetCellValue(sheet, fmt.Sprintf("F%d", row), item.TotalPrice.Float64())
         totalQty += item.Quantity
         totalPrice += item.TotalPrice
     }
@@ -82,7 +102,7 @@ func (s *ExportService) ExportDocumentGOST(docID uint) ([]byte, error) {
     lastRow := len(doc.Items) + 6
     f.SetCellValue(sheet, fmt.Sprintf("A%d", lastRow), "Итого:")
     f.SetCellValue(sheet, fmt.Sprintf("E%d", lastRow), totalQty)
-    f.SetCellValue(sheet, fmt.Sprintf("F%d", lastRow), totalPrice)
+    f.SetCellValue(sheet, fmt.Sprintf("F%d", lastRow), totalPrice.Float64())
 
     // Подписи
     f.SetCellValue(sheet, fmt.Sprintf("A%d", lastRow+2), fmt.Sprintf("Создал: _____________ %s", doc.CreatedBy.Username))
@@ -90,6 +110,10 @@ func (s *ExportService) ExportDocumentGOST(docID uint) ([]byte, error) {
         f.SetCellValue(sheet, fmt.Sprintf("A%d", lastRow+3), fmt.Sprintf("Утвердил: _____________ %s", doc.ApprovedBy.Username))
     }
     f.SetCellValue(sheet, fmt.Sprintf("A%d", lastRow+4), fmt.Sprintf("Дата составления: %s", time.Now().Format("02.01.2006")))
+    if org := s.cfg.Organization; org.Head != "" || org.Accountant != "" {
+        f.SetCellValue(sheet, fmt.Sprintf("A%d", lastRow+6), fmt.Sprintf("Руководитель: _____________ %s", org.Head))
+        f.SetCellValue(sheet, fmt.Sprintf("A%d", lastRow+7), fmt.Sprintf("Главный бухгалтер: _____________ %s", org.Accountant))
+    }
 
     var buf bytes.Buffer
     if err := f.Write(&buf); err != nil {
@@ -97,3 +121,26 @@ func (s *ExportService) ExportDocumentGOST(docID uint) ([]byte, error) {
     }
     return buf.Bytes(), nil
 }
+
+// organizationLine собирает строку с реквизитами организации из настроек
+func (s *ExportService) organizationLine() string {
+    org := s.cfg.Organization
+    if org.Name == "" {
+        return ""
+    }
+
+    parts := []string{fmt.Sprintf("Организация: %s", org.Name)}
+    if org.INN != "" {
+        parts = append(parts, "ИНН "+org.INN)
+    }
+    if org.KPP != "" {
+        parts = append(parts, "КПП "+org.KPP)
+    }
+    if org.OKPO != "" {
+        parts = append(parts, "ОКПО "+org.OKPO)
+    }
+    if org.Address != "" {
+        parts = append(parts, org.Address)
+    }
+    return strings.Join(parts, ", ")
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+	"tohaboy/internal/model"
+)
+
+// documentTitles заголовки печатных форм по типам документов
+var documentTitles = map[string]string{
+	"inventory":  "АКТ ИНВЕНТАРИЗАЦИИ",
+	"transfer":   "АКТ ПЕРЕМЕЩЕНИЯ",
+	"write_off":  "АКТ СПИСАНИЯ",
+	"acceptance": "АКТ ПРИЕМКИ",
+}
+
+var documentStatuses = map[string]string{
+	"draft":     "Черновик",
+	"completed": "Проведен",
+	"canceled":  "Отменен",
+}
+
+// ExportDocumentPDF создает PDF-файл документа любого типа: шапка с QR-кодом
+// номера документа, таблица позиций с итогами и блок подписей.
+func (s *ExportService) ExportDocumentPDF(docID uint) ([]byte, error) {
+	response := s.docService.GetDocument(docID)
+	if response.Model == nil {
+		return nil, fmt.Errorf("документ не найден")
+	}
+	doc := response.Model
+
+	if doc.Location == nil {
+		return nil, fmt.Errorf("местоположение документа не найдено")
+	}
+	if doc.CreatedBy == nil {
+		return nil, fmt.Errorf("создатель документа не найден")
+	}
+
+	title, ok := documentTitles[doc.Type]
+	if !ok {
+		title = "ДОКУМЕНТ"
+	}
+
+	w := newPDFWriter("P", fmt.Sprintf("%s № %s от %s", title, doc.Number, doc.Date.Format("02.01.2006")))
+
+	// QR-код в правом верхнем углу позволяет найти документ сканером
+	pageWidth, _ := w.pdf.GetPageSize()
+	if err := w.qrCode("DOC:"+doc.Number, pageWidth-15-25, 10, 25); err != nil {
+		return nil, err
+	}
+	if org := s.organizationLine(); org != "" {
+		w.pdf.SetRightMargin(15 + 30)
+		w.text(org)
+		w.pdf.SetRightMargin(15)
+	}
+	w.pdf.SetY(40)
+
+	w.title(fmt.Sprintf("%s № %s", title, doc.Number), 14)
+	w.title(fmt.Sprintf("от %s", doc.Date.Format("02.01.2006")), 11)
+	if line := reversalLine(doc); line != "" {
+		w.title(line, 11)
+	}
+	w.space(4)
+
+	if doc.Type == "transfer" {
+		from := "основное местоположение оборудования"
+		if doc.FromLocation != nil {
+			from = doc.FromLocation.Name
+		}
+		w.field("Откуда", from)
+		w.field("Куда", doc.Location.Name)
+	} else {
+		w.field("Местонахождение", doc.Location.Name)
+	}
+	if doc.Responsible != nil {
+		w.field("Материально ответственное лицо", employeeLine(doc.Responsible))
+	}
+	w.field("Основание", basisLine(doc))
+	w.field("Статус", documentStatuses[doc.Status])
+	w.field("Комментарий", doc.Comment)
+	w.field("Причина отмены", doc.CancelReason)
+	if doc.Reversal != nil {
+		w.field("Сторнирован", fmt.Sprintf("документом № %s от %s", doc.Reversal.Number, doc.Reversal.Date.Format("02.01.2006")))
+	}
+	w.space(4)
+
+	columns, rows, total := s.pdfItems(doc)
+	w.table(columns, rows, total)
+	if vat, withVAT, ok := documentVAT(doc); ok {
+		w.space(2)
+		w.field("Сумма НДС", formatAmount(vat))
+		w.field("Всего с НДС", formatAmount(withVAT))
+	}
+	w.space(8)
+
+	var signatures []pdfSignature
+	signatures = append(signatures, pdfSignature{Role: "Составил", Name: doc.CreatedBy.Username})
+	if doc.ApprovedBy != nil {
+		signatures = append(signatures, pdfSignature{Role: "Утвердил", Name: doc.ApprovedBy.Username})
+	}
+	if doc.Responsible != nil {
+		signatures = append(signatures, pdfSignature{Role: "Материально ответственное лицо", Name: doc.Responsible.Name})
+	}
+	if org := s.cfg.Organization; org.Head != "" || org.Accountant != "" {
+		signatures = append(signatures,
+			pdfSignature{Role: "Руководитель", Name: org.Head},
+			pdfSignature{Role: "Главный бухгалтер", Name: org.Accountant},
+		)
+	}
+	w.signatures(signatures)
+	w.space(4)
+	w.text(fmt.Sprintf("Дата составления: %s", time.Now().Format("02.01.2006")))
+
+	return w.bytes()
+}
+
+// pdfItems таблица позиций документа. Для инвентаризации выводятся учетное
+// и фактическое количество и расхождение, для остальных - количество.
+func (s *ExportService) pdfItems(doc *model.Document) ([]pdfColumn, [][]string, []string) {
+	var rows [][]string
+
+	if doc.Type == "inventory" {
+		columns := []pdfColumn{
+			{Title: "№", Width: 8, Align: "C"},
+			{Title: "Наименование", Width: 52, Align: "L"},
+			{Title: "Серийный номер", Width: 28, Align: "L"},
+			{Title: "Ед.", Width: 10, Align: "C"},
+			{Title: "По учету", Width: 16, Align: "R"},
+			{Title: "Факт", Width: 16, Align: "R"},
+			{Title: "Разница", Width: 16, Align: "R"},
+			{Title: "Цена", Width: 16, Align: "R"},
+			{Title: "Сумма", Width: 18, Align: "R"},
+		}
+		var quantity, actual int
+		var sum model.Money
+		for _, item := range doc.Items {
+			if item.Equipment.ID == 0 {
+				continue
+			}
+			rows = append(rows, []string{
+				strconv.Itoa(len(rows) + 1),
+				item.Equipment.Name,
+				item.Equipment.SerialNumber,
+				s.unitOf(&item.Equipment),
+				strconv.Itoa(item.Quantity),
+				strconv.Itoa(item.ActualQuantity),
+				strconv.Itoa(item.ActualQuantity - item.Quantity),
+				formatAmount(item.Price),
+				formatAmount(item.TotalPrice),
+			})
+			quantity += item.Quantity
+			actual += item.ActualQuantity
+			sum += item.TotalPrice
+		}
+		total := []string{"", "Итого", "", "", strconv.Itoa(quantity), strconv.Itoa(actual), strconv.Itoa(actual - quantity), "", formatAmount(sum)}
+		return columns, rows, total
+	}
+
+	columns := []pdfColumn{
+		{Title: "№", Width: 8, Align: "C"},
+		{Title: "Наименование", Width: 72, Align: "L"},
+		{Title: "Серийный номер", Width: 32, Align: "L"},
+		{Title: "Ед.", Width: 12, Align: "C"},
+		{Title: "Кол-во", Width: 16, Align: "R"},
+		{Title: "Цена", Width: 20, Align: "R"},
+		{Title: "Сумма", Width: 20, Align: "R"},
+	}
+	var quantity int
+	var sum model.Money
+	for _, item := range doc.Items {
+		if item.Equipment.ID == 0 {
+			continue
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(len(rows) + 1),
+			item.Equipment.Name,
+			item.Equipment.SerialNumber,
+			s.unitOf(&item.Equipment),
+			strconv.Itoa(item.Quantity),
+			formatAmount(item.Price),
+			formatAmount(item.TotalPrice),
+		})
+		quantity += item.Quantity
+		sum += item.TotalPrice
+	}
+	total := []string{"", "Итого", "", "", strconv.Itoa(quantity), "", formatAmount(sum)}
+	return columns, rows, total
+}
+
+// formatAmount сумма с двумя знаками после запятой
+func formatAmount(value model.Money) string {
+	return value.String()
+}
+
+// documentVAT сумма НДС и стоимость с НДС позиций документа. ok ложно, если
+// ни одна позиция не облагается НДС.
+func documentVAT(doc *model.Document) (vat, withVAT model.Money, ok bool) {
+	for _, item := range doc.Items {
+		if item.VATRate != model.VATNone {
+			ok = true
+		}
+		vat += item.VATAmount
+		withVAT += item.TotalWithVAT()
+	}
+	return vat, withVAT, ok
+}
+
+func employeeLine(employee *model.Employee) string {
+	if employee.Position == "" {
+		return employee.Name
+	}
+	return employee.Position + ", " + employee.Name
+}
+
+// reversalLine отметка сторнирующего документа со ссылкой на исходный.
+// Сторно повторяет тип и позиции исходного документа, поэтому без отметки
+// его печатная форма не отличается от обычного акта.
+func reversalLine(doc *model.Document) string {
+	if doc.ReversalOfID == 0 {
+		return ""
+	}
+	if doc.ReversalOf == nil {
+		return fmt.Sprintf("Сторно документа #%d", doc.ReversalOfID)
+	}
+	return fmt.Sprintf("Сторно документа № %s от %s", doc.ReversalOf.Number, doc.ReversalOf.Date.Format("02.01.2006"))
+}
+
+// basisLine основание документа: вид, номер и дата
+func basisLine(doc *model.Document) string {
+	line := doc.Basis
+	if doc.BasisNumber != "" {
+		line += " № " + doc.BasisNumber
+	}
+	if doc.BasisDate != nil {
+		line += " от " + doc.BasisDate.Format("02.01.2006")
+	}
+	return line
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"strings"
+	"tohaboy/internal/files"
+	"tohaboy/internal/model"
+)
+
+// formINV19 сличительная ведомость результатов инвентаризации (форма № ИНВ-19).
+// В ведомость попадают только позиции, по которым фактическое количество
+// расходится с учетным. Излишки и недостачи считаются окончательными,
+// так как пересортица и исправления учета в документе не ведутся.
+var formINV19 = &unifiedForm{
+	Name:     "ИНВ-19",
+	Template: files.Law,
+	Header:   "стр1",
+	Table: formTable{
+		Sheet:      "стр2",
+		FirstRow:   7,
+		Rows:       18,
+		TotalRow:   25,
+		TotalLabel: "H",
+		Totals:     []string{"I", "J", "K", "L", "AA", "AB", "AE", "AF"},
+		PageLabel:  "B1",
+	},
+	header: inv19Header,
+	footer: inv19Footer,
+	rows:   inv19Rows,
+}
+
+// Коды единиц измерения по ОКЕИ
+var okeiCodes = map[string]string{
+	"шт":     "796",
+	"шт.":    "796",
+	"компл":  "839",
+	"компл.": "839",
+	"упак":   "778",
+	"упак.":  "778",
+	"кг":     "166",
+	"м":      "006",
+}
+
+var monthsGenitive = []string{
+	"января", "февраля", "марта", "апреля", "мая", "июня",
+	"июля", "августа", "сентября", "октября", "ноября", "декабря",
+}
+
+func inv19Header(s *ExportService, doc *model.Document) map[string]interface{} {
+	org := s.cfg.Organization
+
+	// Инвентаризация завершена, когда документ проведен
+	finished := doc.Date
+	if doc.Status == "completed" {
+		finished = doc.UpdatedAt
+	}
+
+	values := map[string]interface{}{
+		"A6":  organizationName(org.Name, org.INN, org.KPP),
+		"Q6":  org.OKPO,
+		"Q14": doc.Date.Format("02.01.2006"),
+		"Q15": finished.Format("02.01.2006"),
+		"L20": doc.Number,
+		"M20": doc.Date.Format("02.01.2006"),
+		"D30": doc.Date.Format("02"),
+		"F30": monthsGenitive[doc.Date.Month()-1],
+		"I30": doc.Date.Format("2006"),
+	}
+	if doc.Location != nil {
+		values["A8"] = doc.Location.Name
+	}
+	if doc.Basis != "" {
+		// Вид основания заменяет подсказку "приказ, постановление, распоряжение"
+		values["J11"] = doc.Basis
+	}
+	if doc.BasisNumber != "" {
+		values["Q11"] = doc.BasisNumber
+	}
+	if doc.BasisDate != nil {
+		values["Q12"] = doc.BasisDate.Format("02.01.2006")
+	}
+	if doc.Responsible != nil {
+		values["B25"] = doc.Responsible.Position
+		values["H25"] = doc.Responsible.Name
+	}
+	return values
+}
+
+func inv19Footer(s *ExportService, doc *model.Document) map[string]interface{} {
+	values := map[string]interface{}{
+		"G27": s.cfg.Organization.Accountant,
+	}
+	if doc.Responsible != nil {
+		values["Z27"] = doc.Responsible.Position
+		values["AH27"] = doc.Responsible.Name
+	}
+	return values
+}
+
+func inv19Rows(s *ExportService, doc *model.Document) []formRow {
+	var rows []formRow
+	for _, item := range doc.Items {
+		diff := item.ActualQuantity - item.Quantity
+		if diff == 0 || item.Equipment.ID == 0 {
+			continue
+		}
+
+		unit := s.unitOf(&item.Equipment)
+
+		row := formRow{
+			"A": len(rows) + 1,
+			"B": item.Equipment.Name,
+			"C": item.Equipment.ID,
+			"D": okeiCodes[strings.ToLower(unit)],
+			"E": unit,
+			"F": item.Equipment.SerialNumber,
+		}
+		sum := item.Price.Mul(diff).Abs().Float64()
+		if diff > 0 {
+			row["I"], row["J"] = diff, sum
+			row["AA"], row["AB"] = diff, sum
+		} else {
+			row["K"], row["L"] = -diff, sum
+			row["AE"], row["AF"] = -diff, sum
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// organizationName название организации с ИНН и КПП для шапки формы
+func organizationName(name, inn, kpp string) string {
+	if name == "" {
+		return ""
+	}
+	parts := []string{name}
+	if inn != "" {
+		parts = append(parts, "ИНН "+inn)
+	}
+	if kpp != "" {
+		parts = append(parts, "КПП "+kpp)
+	}
+	return strings.Join(parts, ", ")
+}
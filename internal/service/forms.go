@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"tohaboy/internal/model"
+	"tohaboy/internal/xls"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// unifiedForm описывает унифицированную форму, заполняемую по официальному шаблону.
+// Шапка заполняется на листе Header, позиции - в таблице на листе Table.Sheet.
+// Если позиции не помещаются на лист, он копируется для продолжения таблицы.
+type unifiedForm struct {
+	Name     string
+	Template []byte
+	Header   string
+	Table    formTable
+
+	// header возвращает значения ячеек листа шапки
+	header func(s *ExportService, doc *model.Document) map[string]interface{}
+	// footer возвращает значения ячеек (подписи), заполняемые на каждом листе таблицы
+	footer func(s *ExportService, doc *model.Document) map[string]interface{}
+	// rows возвращает строки таблицы: значения по буквам столбцов
+	rows func(s *ExportService, doc *model.Document) []formRow
+}
+
+// formTable расположение таблицы позиций на листе шаблона
+type formTable struct {
+	Sheet      string
+	FirstRow   int      // первая строка позиций
+	Rows       int      // количество строк позиций на листе
+	TotalRow   int      // строка итогов листа
+	TotalLabel string   // столбец подписи строки итогов
+	Totals     []string // столбцы, суммируемые в итогах
+	PageLabel  string   // ячейка для номера листа, если листов несколько
+}
+
+type formRow map[string]interface{}
+
+// unifiedForms формы, для которых есть официальный шаблон, по типам документов.
+// Шаблонов ИНВ-1 (инвентаризация), ОС-1 (приемка и перемещение) и МБ-8 (списание)
+// пока нет: их документы выгружаются упрощенным актом, см. ExportDocumentGOST.
+var unifiedForms = map[string]*unifiedForm{
+	"inventory": formINV19,
+}
+
+// exportUnifiedForm заполняет шаблон формы данными документа
+func (s *ExportService) exportUnifiedForm(form *unifiedForm, doc *model.Document) ([]byte, error) {
+	wb, err := xls.Open(form.Template)
+	if err != nil {
+		return nil, fmt.Errorf("шаблон формы %s: %v", form.Name, err)
+	}
+	table := wb.Sheet(form.Table.Sheet)
+	if table == nil || wb.Sheet(form.Header) == nil {
+		return nil, fmt.Errorf("шаблон формы %s: лист не найден", form.Name)
+	}
+
+	f, err := wb.Excelize()
+	if err != nil {
+		return nil, fmt.Errorf("шаблон формы %s: %v", form.Name, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if err := setCells(f, form.Header, form.header(s, doc)); err != nil {
+		return nil, err
+	}
+
+	rows := form.rows(s, doc)
+	perPage := form.Table.Rows
+	pages := (len(rows) + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+
+	// Листы продолжения копируются до заполнения, пока таблица пуста
+	sheets := []string{form.Table.Sheet}
+	source, err := f.GetSheetIndex(form.Table.Sheet)
+	if err != nil {
+		return nil, err
+	}
+	for page := 2; page <= pages; page++ {
+		name := fmt.Sprintf("%s (%d)", form.Table.Sheet, page)
+		index, err := f.NewSheet(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.CopySheet(source, index); err != nil {
+			return nil, err
+		}
+		if !table.PrintArea.Empty() {
+			if err := xls.SetPrintArea(f, name, table.PrintArea); err != nil {
+				return nil, err
+			}
+		}
+		sheets = append(sheets, name)
+	}
+
+	footer := form.footer(s, doc)
+	grandTotals := make(map[string]float64)
+	for page, sheet := range sheets {
+		first := page * perPage
+		last := min(first+perPage, len(rows))
+
+		pageTotals := make(map[string]float64)
+		for i, row := range rows[first:last] {
+			for col, value := range row {
+				if err := f.SetCellValue(sheet, fmt.Sprintf("%s%d", col, form.Table.FirstRow+i), value); err != nil {
+					return nil, err
+				}
+				if number, ok := formNumber(value); ok {
+					pageTotals[col] += number
+					grandTotals[col] += number
+				}
+			}
+		}
+
+		if err := setCells(f, sheet, footer); err != nil {
+			return nil, err
+		}
+		if err := form.Table.setTotals(f, sheet, form.Table.TotalRow, pageTotals); err != nil {
+			return nil, err
+		}
+		if pages > 1 {
+			if err := f.SetCellValue(sheet, form.Table.PageLabel, fmt.Sprintf("Лист %d из %d", page+1, pages)); err != nil {
+				return nil, err
+			}
+			if err := f.SetCellValue(sheet, fmt.Sprintf("%s%d", form.Table.TotalLabel, form.Table.TotalRow), "Итого по листу"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// На последнем листе под итогами листа добавляется итог по всей форме
+	if pages > 1 {
+		last := sheets[len(sheets)-1]
+		row := form.Table.TotalRow + 1
+		if err := f.DuplicateRow(last, form.Table.TotalRow); err != nil {
+			return nil, err
+		}
+		if err := form.Table.setTotals(f, last, row, grandTotals); err != nil {
+			return nil, err
+		}
+		if err := f.SetCellValue(last, fmt.Sprintf("%s%d", form.Table.TotalLabel, row), "Всего"); err != nil {
+			return nil, err
+		}
+		if !table.PrintArea.Empty() {
+			area := table.PrintArea
+			area.LastRow++
+			if err := xls.SetPrintArea(f, last, area); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	f.SetActiveSheet(0)
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("ошибка при сохранении файла: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// setTotals записывает суммы в строку итогов, нулевые суммы остаются пустыми
+func (t formTable) setTotals(f *excelize.File, sheet string, row int, totals map[string]float64) error {
+	for _, col := range t.Totals {
+		var value interface{}
+		if total := totals[col]; total != 0 {
+			value = math.Round(total*100) / 100
+		}
+		if err := f.SetCellValue(sheet, fmt.Sprintf("%s%d", col, row), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setCells(f *excelize.File, sheet string, values map[string]interface{}) error {
+	for ref, value := range values {
+		if err := f.SetCellValue(sheet, ref, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,506 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+	"tohaboy/internal/xls"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// headerSearchRows сколько первых строк файла просматривается в поисках заголовков.
+// Над таблицей в накладных поставщиков часто есть шапка с реквизитами.
+const headerSearchRows = 20
+
+// importFields поля оборудования, заполняемые при импорте, и варианты заголовков
+// столбцов для них (в нижнем регистре, без точек и двоеточий на конце)
+var importFields = map[string][]string{
+	"name":          {"наименование", "название", "наименование товара", "товар", "оборудование", "name"},
+	"serial_number": {"серийный номер", "серийный №", "зав номер", "заводской номер", "зав №", "s/n", "sn", "serial", "serial number", "serial_number"},
+	"description":   {"описание", "характеристики", "description"},
+	"category":      {"категория", "группа", "category"},
+	"supplier":      {"поставщик", "supplier"},
+	"location":      {"местоположение", "местонахождение", "склад", "location"},
+	"quantity":      {"количество", "кол-во", "кол", "quantity", "qty"},
+	"price":         {"цена", "стоимость", "цена за единицу", "price"},
+}
+
+type ImportService struct {
+	repo       repository.ImportRepositoryInterface
+	categories repository.CategoryRepositoryInterface
+	suppliers  repository.SupplierRepositoryInterface
+	locations  repository.LocationRepositoryInterface
+	session    *Session
+}
+
+func NewImportService(
+	repo repository.ImportRepositoryInterface,
+	categories repository.CategoryRepositoryInterface,
+	suppliers repository.SupplierRepositoryInterface,
+	locations repository.LocationRepositoryInterface,
+	session *Session,
+) *ImportService {
+	return &ImportService{
+		repo:       repo,
+		categories: categories,
+		suppliers:  suppliers,
+		locations:  locations,
+		session:    session,
+	}
+}
+
+// PreviewImport разбирает файл и проверяет строки, ничего не записывая в базу
+func (s *ImportService) PreviewImport(request model.ImportRequest) *model.ImportPreviewResponse {
+	if _, err := s.session.Authorize("ImportService.PreviewImport"); err != nil {
+		return &model.ImportPreviewResponse{Message: err.Error()}
+	}
+
+	preview, err := s.preview(request)
+	if err != nil {
+		return &model.ImportPreviewResponse{Message: err.Error()}
+	}
+	return &model.ImportPreviewResponse{
+		Model:   preview,
+		Message: fmt.Sprintf("Строк без ошибок: %d, с ошибками: %d", preview.Valid, preview.Invalid),
+	}
+}
+
+// CommitImport повторно проверяет файл и создает оборудование одной транзакцией.
+// Если хотя бы в одной строке есть ошибка, не импортируется ничего.
+func (s *ImportService) CommitImport(request model.ImportRequest) *model.ImportResultResponse {
+	user, err := s.session.Authorize("ImportService.CommitImport")
+	if err != nil {
+		return &model.ImportResultResponse{Message: err.Error()}
+	}
+
+	preview, err := s.preview(request)
+	if err != nil {
+		return &model.ImportResultResponse{Message: err.Error()}
+	}
+	if preview.Invalid > 0 {
+		return &model.ImportResultResponse{
+			Message: fmt.Sprintf("В файле есть строки с ошибками (%d), импорт не выполнен", preview.Invalid),
+		}
+	}
+	if preview.Valid == 0 {
+		return &model.ImportResultResponse{Message: "В файле нет строк для импорта"}
+	}
+
+	equipment := make([]model.Equipment, len(preview.Rows))
+	for i, row := range preview.Rows {
+		equipment[i] = row.Equipment
+		// Справочники нужны только для предпросмотра, связи задаются идентификаторами
+		equipment[i].Category = nil
+		equipment[i].Supplier = nil
+		equipment[i].Location = nil
+	}
+
+	var acceptance *model.Document
+	if request.CreateDocument {
+		acceptance = &model.Document{
+			Type:        "acceptance",
+			Status:      "draft",
+			Date:        time.Now(),
+			CreatedByID: user.ID,
+			Comment:     fmt.Sprintf("Импорт из файла %s", request.FileName),
+		}
+	}
+
+	response := s.repo.ImportEquipment(equipment, acceptance)
+	return &model.ImportResultResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *ImportService) preview(request model.ImportRequest) (*model.ImportPreview, error) {
+	table, err := readImportTable(request)
+	if err != nil {
+		return nil, err
+	}
+
+	headerRow, columns, err := importColumns(table, request.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup, err := s.importLookup(request.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &model.ImportPreview{
+		Columns: make(map[string]string),
+		Rows:    []model.ImportRow{},
+	}
+	for field, index := range columns {
+		preview.Columns[field] = table[headerRow][index]
+	}
+
+	serialRows := make(map[string]int)
+	for i := headerRow + 1; i < len(table); i++ {
+		values := make(map[string]string)
+		empty := true
+		for field, index := range columns {
+			if index < len(table[i]) {
+				values[field] = strings.TrimSpace(table[i][index])
+				if values[field] != "" {
+					empty = false
+				}
+			}
+		}
+		if empty {
+			continue
+		}
+
+		row := lookup.row(i+1, values, request)
+		if serial := row.Equipment.SerialNumber; serial != "" {
+			if first, ok := serialRows[serial]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("серийный номер %s повторяется (строка %d)", serial, first))
+			} else {
+				serialRows[serial] = row.Row
+			}
+		}
+		preview.Rows = append(preview.Rows, row)
+	}
+
+	serials := make([]string, 0, len(serialRows))
+	for serial := range serialRows {
+		serials = append(serials, serial)
+	}
+	existing := s.repo.FindSerialNumbers(serials)
+	if existing.Model == nil {
+		return nil, fmt.Errorf("ошибка проверки серийных номеров: %s", existing.Message)
+	}
+	taken := make(map[string]bool, len(existing.Model))
+	for _, serial := range existing.Model {
+		taken[serial] = true
+	}
+
+	for i := range preview.Rows {
+		row := &preview.Rows[i]
+		if taken[row.Equipment.SerialNumber] {
+			row.Errors = append(row.Errors, fmt.Sprintf("оборудование с серийным номером %s уже есть", row.Equipment.SerialNumber))
+		}
+		if len(row.Errors) == 0 {
+			preview.Valid++
+		} else {
+			preview.Invalid++
+		}
+	}
+
+	return preview, nil
+}
+
+// importLookup справочники, в которых строки файла ищутся по названию
+type importLookup struct {
+	categories map[string]*model.Category
+	suppliers  map[string]*model.Supplier
+	locations  map[string]*model.Location
+	// location местоположение по умолчанию для строк, где оно не указано
+	location *model.Location
+}
+
+func (s *ImportService) importLookup(locationID uint) (*importLookup, error) {
+	categories := s.categories.GetAllCategories()
+	if categories.Model == nil && categories.Message != "" {
+		return nil, fmt.Errorf("ошибка загрузки категорий: %s", categories.Message)
+	}
+	suppliers := s.suppliers.GetAllSuppliers()
+	if suppliers.Model == nil && suppliers.Message != "" {
+		return nil, fmt.Errorf("ошибка загрузки поставщиков: %s", suppliers.Message)
+	}
+	locations := s.locations.GetAllLocations()
+	if locations.Model == nil && locations.Message != "" {
+		return nil, fmt.Errorf("ошибка загрузки местоположений: %s", locations.Message)
+	}
+
+	lookup := &importLookup{
+		categories: make(map[string]*model.Category),
+		suppliers:  make(map[string]*model.Supplier),
+		locations:  make(map[string]*model.Location),
+	}
+	for i := range categories.Model {
+		lookup.categories[normalizeName(categories.Model[i].Name)] = &categories.Model[i]
+		lookup.categories[normalizeName(categories.Model[i].FullName)] = &categories.Model[i]
+	}
+	for i := range suppliers.Model {
+		// Оборудование поставщика в предпросмотре не нужно
+		suppliers.Model[i].Equipment = nil
+		lookup.suppliers[normalizeName(suppliers.Model[i].Name)] = &suppliers.Model[i]
+	}
+	for i := range locations.Model {
+		lookup.locations[normalizeName(locations.Model[i].Name)] = &locations.Model[i]
+		// Вложенное место можно указать и полным названием: "Главный офис / Серверная"
+		lookup.locations[normalizeName(locations.Model[i].FullName)] = &locations.Model[i]
+		if locations.Model[i].ID == locationID {
+			lookup.location = &locations.Model[i]
+		}
+	}
+	if locationID != 0 && lookup.location == nil {
+		return nil, fmt.Errorf("местоположение по умолчанию не найдено")
+	}
+	return lookup, nil
+}
+
+// row заполняет оборудование из значений строки и проверяет их
+func (l *importLookup) row(number int, values map[string]string, request model.ImportRequest) model.ImportRow {
+	row := model.ImportRow{
+		Row: number,
+		Equipment: model.Equipment{
+			Name:         values["name"],
+			SerialNumber: values["serial_number"],
+			Description:  values["description"],
+			Status:       "available",
+			Quantity:     1,
+		},
+		Errors: []string{},
+	}
+	equipment := &row.Equipment
+
+	if equipment.Name == "" {
+		row.Errors = append(row.Errors, "не указано наименование")
+	}
+
+	if name := values["category"]; name != "" {
+		if category, ok := l.categories[normalizeName(name)]; ok {
+			equipment.CategoryID, equipment.Category = category.ID, category
+		} else {
+			row.Errors = append(row.Errors, fmt.Sprintf("неизвестная категория «%s»", name))
+		}
+	}
+	// Без серийного номера можно загрузить оборудование категории с префиксом
+	// серийных номеров: номер выдается при создании
+	if equipment.SerialNumber == "" && (equipment.Category == nil || equipment.Category.Effective.SerialPrefix == "") {
+		row.Errors = append(row.Errors, "не указан серийный номер")
+	}
+	if name := values["supplier"]; name != "" {
+		if supplier, ok := l.suppliers[normalizeName(name)]; ok {
+			equipment.SupplierID, equipment.Supplier = supplier.ID, supplier
+		} else {
+			row.Errors = append(row.Errors, fmt.Sprintf("неизвестный поставщик «%s»", name))
+		}
+	}
+
+	if value := values["quantity"]; value != "" {
+		quantity, err := parseImportNumber(value)
+		if err != nil || quantity < 0 || quantity != float64(int(quantity)) {
+			row.Errors = append(row.Errors, fmt.Sprintf("некорректное количество «%s»", value))
+		} else {
+			equipment.Quantity = int(quantity)
+		}
+	}
+	if value := values["price"]; value != "" {
+		price, err := parseImportMoney(value)
+		if err != nil || price < 0 {
+			row.Errors = append(row.Errors, fmt.Sprintf("некорректная цена «%s»", value))
+		} else {
+			equipment.Price = price
+		}
+	}
+
+	location := l.location
+	if name := values["location"]; name != "" {
+		var ok bool
+		if location, ok = l.locations[normalizeName(name)]; !ok {
+			row.Errors = append(row.Errors, fmt.Sprintf("неизвестное местоположение «%s»", name))
+		}
+	}
+	if location != nil {
+		equipment.LocationID, equipment.Location = location.ID, location
+	} else if values["location"] == "" && (equipment.Quantity > 0 || request.CreateDocument) {
+		row.Errors = append(row.Errors, "не указано местоположение")
+	}
+
+	return row
+}
+
+// readImportTable читает ячейки файла импорта построчно
+func readImportTable(request model.ImportRequest) ([][]string, error) {
+	data, err := base64.StdEncoding.DecodeString(request.Content)
+	if err != nil {
+		return nil, fmt.Errorf("некорректное содержимое файла: %v", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(request.FileName)); ext {
+	case ".csv", ".txt":
+		return readCSV(data)
+	case ".xlsx", ".xlsm":
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения файла Excel: %v", err)
+		}
+		defer f.Close()
+		return readSheet(f, request.Sheet)
+	case ".xls":
+		wb, err := xls.Open(data)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения файла Excel: %v", err)
+		}
+		f, err := wb.Excelize()
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения файла Excel: %v", err)
+		}
+		defer f.Close()
+		return readSheet(f, request.Sheet)
+	default:
+		return nil, fmt.Errorf("неподдерживаемый формат файла %q: нужен .xlsx, .xls или .csv", ext)
+	}
+}
+
+func readSheet(f *excelize.File, sheet string) ([][]string, error) {
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+	rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("лист %q: %v", sheet, err)
+	}
+	return rows, nil
+}
+
+// readCSV читает CSV в UTF-8 или Windows-1251 (так сохраняет Excel).
+// Разделитель определяется по первой строке: ";", "," или табуляция.
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		decoded, err := charmap.Windows1251.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения CSV: %v", err)
+		}
+		data = decoded
+	}
+
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	comma, best := ';', -1
+	for _, candidate := range []rune{';', ',', '\t'} {
+		if n := bytes.Count(firstLine, []byte(string(candidate))); n > best {
+			comma, best = candidate, n
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения CSV: %v", err)
+		}
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+// importColumns находит строку заголовков и номера столбцов полей.
+// Явно заданные сопоставления проверяются первыми, остальные поля
+// определяются по известным вариантам заголовков.
+func importColumns(table [][]string, mapping map[string]string) (int, map[string]int, error) {
+	for field := range mapping {
+		if _, ok := importFields[field]; !ok {
+			return 0, nil, fmt.Errorf("неизвестное поле импорта %q", field)
+		}
+	}
+
+	for i := 0; i < len(table) && i < headerSearchRows; i++ {
+		headers := make(map[string]int)
+		for j, value := range table[i] {
+			if name := normalizeName(value); name != "" {
+				if _, ok := headers[name]; !ok {
+					headers[name] = j
+				}
+			}
+		}
+
+		columns := make(map[string]int)
+		for field, header := range mapping {
+			if index, ok := headers[normalizeName(header)]; ok {
+				columns[field] = index
+			}
+		}
+		if len(columns) < len(mapping) {
+			continue
+		}
+		for field, variants := range importFields {
+			if _, ok := columns[field]; ok {
+				continue
+			}
+			for _, variant := range variants {
+				if index, ok := headers[variant]; ok {
+					columns[field] = index
+					break
+				}
+			}
+		}
+
+		if _, ok := columns["name"]; ok {
+			return i, columns, nil
+		}
+	}
+
+	if len(mapping) > 0 {
+		headers := make([]string, 0, len(mapping))
+		for _, header := range mapping {
+			headers = append(headers, "«"+header+"»")
+		}
+		sort.Strings(headers)
+		return 0, nil, fmt.Errorf("не найдена строка заголовков со столбцами %s", strings.Join(headers, ", "))
+	}
+	return 0, nil, fmt.Errorf("не найдена строка заголовков: нужен хотя бы столбец «Наименование»")
+}
+
+// normalizeName приводит название к виду для сравнения: нижний регистр,
+// одиночные пробелы, без точек и двоеточий на конце
+func normalizeName(name string) string {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	name = strings.ReplaceAll(name, ".", "")
+	return strings.TrimRight(name, ":")
+}
+
+// parseImportMoney разбирает сумму в рублях с тем же разбором, что и parseImportNumber,
+// без промежуточного перевода в число с плавающей точкой
+func parseImportMoney(value string) (model.Money, error) {
+	return model.ParseMoney(trimCurrency(value))
+}
+
+// parseImportNumber разбирает число в русской или английской записи:
+// "1 234,50", "1234.5", "1 234,50 руб."
+func parseImportNumber(value string) (float64, error) {
+	value = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f':
+			return -1
+		case ',':
+			return '.'
+		}
+		return r
+	}, trimCurrency(value))
+	return strconv.ParseFloat(value, 64)
+}
+
+// trimCurrency убирает пробелы по краям и обозначение рубля
+func trimCurrency(value string) string {
+	value = strings.TrimSpace(value)
+	for _, suffix := range []string{"₽", "руб.", "руб", "р."} {
+		value = strings.TrimSuffix(value, suffix)
+	}
+	return value
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+// InventoryService ведет инвентаризацию местоположения: снимок учетных остатков
+// при начале, пересчет и сканирование оборудования, расчет расхождений и
+// сличительная ведомость. Проводится инвентаризация утверждением документа
+// в DocumentService: излишки оприходуются, недостача списывается
+// корректирующими документами.
+type InventoryService struct {
+	repo       repository.InventoryRepositoryInterface
+	documents  repository.DocumentRepositoryInterface
+	docService DocumentServiceInterface
+	session    *Session
+	cfg        *config.Config
+}
+
+func NewInventoryService(
+	repo repository.InventoryRepositoryInterface,
+	documents repository.DocumentRepositoryInterface,
+	docService DocumentServiceInterface,
+	session *Session,
+	cfg *config.Config,
+) *InventoryService {
+	return &InventoryService{
+		repo:       repo,
+		documents:  documents,
+		docService: docService,
+		session:    session,
+		cfg:        cfg,
+	}
+}
+
+// StartInventory начинает инвентаризацию в местоположении doc.LocationID.
+// Из doc берутся также дата, ответственный, основание и комментарий.
+func (s *InventoryService) StartInventory(doc *model.Document) *model.DocumentResponse {
+	user, err := s.session.Authorize("InventoryService.StartInventory")
+	if err != nil {
+		return &model.DocumentResponse{Message: err.Error()}
+	}
+
+	if doc.LocationID == 0 {
+		return &model.DocumentResponse{
+			Message: "местоположение не указано",
+		}
+	}
+
+	doc.CreatedByID = user.ID
+	if doc.Date.IsZero() {
+		doc.Date = time.Now()
+	}
+
+	response := s.repo.StartInventory(doc)
+	return &model.DocumentResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// CountItem записывает фактическое количество оборудования, пересчитанного вручную
+func (s *InventoryService) CountItem(documentID uint, equipmentID uint, quantity int) *model.DocumentResponse {
+	if _, err := s.session.Authorize("InventoryService.CountItem"); err != nil {
+		return &model.DocumentResponse{Message: err.Error()}
+	}
+
+	response := s.repo.CountItem(documentID, equipmentID, quantity, false)
+	return &model.DocumentResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// ScanItem увеличивает фактическое количество отсканированного оборудования на единицу
+func (s *InventoryService) ScanItem(documentID uint, equipmentID uint) *model.DocumentResponse {
+	if _, err := s.session.Authorize("InventoryService.ScanItem"); err != nil {
+		return &model.DocumentResponse{Message: err.Error()}
+	}
+
+	response := s.repo.CountItem(documentID, equipmentID, 1, true)
+	return &model.DocumentResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// GetDiscrepancies возвращает позиции инвентаризации с излишками и недостачей.
+// Не пересчитанное оборудование считается недостающим.
+func (s *InventoryService) GetDiscrepancies(documentID uint) *model.InventoryDiscrepanciesResponse {
+	if _, err := s.session.Authorize("InventoryService.GetDiscrepancies"); err != nil {
+		return &model.InventoryDiscrepanciesResponse{Message: err.Error()}
+	}
+
+	response := s.documents.GetDocument(documentID)
+	if response.Model == nil {
+		return &model.InventoryDiscrepanciesResponse{Message: response.Message}
+	}
+	doc := response.Model
+	if doc.Type != "inventory" {
+		return &model.InventoryDiscrepanciesResponse{
+			Message: "документ не является инвентаризацией",
+		}
+	}
+
+	result := &model.InventoryDiscrepancies{
+		DocumentID: doc.ID,
+		Number:     doc.Number,
+		Status:     doc.Status,
+		Lines:      make([]model.InventoryLine, 0, len(doc.Items)),
+	}
+	for _, item := range doc.Items {
+		line := model.InventoryLine{
+			EquipmentID:  item.EquipmentID,
+			Expected:     item.Quantity,
+			Actual:       item.ActualQuantity,
+			Difference:   item.ActualQuantity - item.Quantity,
+			Price:        item.Price,
+			Counted:      item.Counted,
+			Unexpected:   item.Quantity == 0,
+			Name:         item.Equipment.Name,
+			SerialNumber: item.Equipment.SerialNumber,
+		}
+		line.Amount = item.Price.Mul(line.Difference)
+		result.Lines = append(result.Lines, line)
+
+		if item.Counted {
+			result.Counted++
+		} else {
+			result.Uncounted++
+		}
+		switch {
+		case line.Difference > 0:
+			result.Surpluses++
+			result.SurplusQuantity += line.Difference
+			result.SurplusAmount += line.Amount
+		case line.Difference < 0:
+			result.Shortages++
+			result.ShortageQuantity -= line.Difference
+			result.ShortageAmount -= line.Amount
+		}
+	}
+
+	return &model.InventoryDiscrepanciesResponse{
+		Model: result,
+	}
+}
+
+// ExportStatement выгружает сличительную ведомость по форме ИНВ-19
+func (s *InventoryService) ExportStatement(documentID uint) *model.DocumentExportResponse {
+	if _, err := s.session.Authorize("InventoryService.ExportStatement"); err != nil {
+		return &model.DocumentExportResponse{Message: err.Error()}
+	}
+
+	response := s.documents.GetDocument(documentID)
+	if response.Model == nil {
+		return &model.DocumentExportResponse{Message: response.Message}
+	}
+	if response.Model.Type != "inventory" {
+		return &model.DocumentExportResponse{
+			Message: "документ не является инвентаризацией",
+		}
+	}
+
+	content, err := NewExportService(s.docService, s.cfg).ExportDocumentGOST(documentID)
+	if err != nil {
+		return &model.DocumentExportResponse{
+			Message: fmt.Sprintf("Ошибка экспорта сличительной ведомости: %v", err),
+		}
+	}
+
+	return &model.DocumentExportResponse{
+		Content: base64.StdEncoding.EncodeToString(content),
+		Message: "Сличительная ведомость успешно экспортирована",
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+func TestGetDiscrepancies(t *testing.T) {
+	f := newServiceFixture(t)
+	user := f.login(t, model.RoleManager)
+
+	store := model.Location{Name: "Склад"}
+	office := model.Location{Name: "Офис"}
+	for _, location := range []*model.Location{&store, &office} {
+		if err := f.db.Create(location).Error; err != nil {
+			t.Fatalf("создание местоположения: %v", err)
+		}
+	}
+	equipment := func(name string, price model.Money, location uint, quantity int) uint {
+		equipment := model.Equipment{Name: name, SerialNumber: name, Status: model.EquipmentAvailable, Quantity: quantity, Price: price, LocationID: location}
+		if err := f.db.Create(&equipment).Error; err != nil {
+			t.Fatalf("создание оборудования: %v", err)
+		}
+		if err := f.db.Create(&model.StockBalance{EquipmentID: equipment.ID, LocationID: location, Quantity: quantity}).Error; err != nil {
+			t.Fatalf("создание остатка: %v", err)
+		}
+		return equipment.ID
+	}
+	laptop := equipment("Ноутбук", 10000000, store.ID, 5)
+	monitor := equipment("Монитор", 2500000, store.ID, 2)
+	projector := equipment("Проектор", 5000000, store.ID, 1)
+	chair := equipment("Кресло", 800000, office.ID, 1)
+
+	inventories := repository.NewInventoryRepository(f.db, f.cfg.Numbering)
+	documents := repository.NewDocumentRepository(f.db, f.cfg.Numbering)
+	svc := NewInventoryService(inventories, documents, nil, f.session, f.cfg)
+
+	started := svc.StartInventory(&model.Document{LocationID: store.ID, Date: time.Now()})
+	if started.Model == nil {
+		t.Fatalf("StartInventory: %s", started.Message)
+	}
+	if started.Model.CreatedByID != user.ID {
+		t.Errorf("автор документа %d, ожидался %d", started.Model.CreatedByID, user.ID)
+	}
+	doc := started.Model.ID
+
+	// Ноутбуков недостает двух, монитор не пересчитан, проектор на месте,
+	// а кресло из офиса найдено на складе дважды
+	for _, count := range []struct {
+		equipment uint
+		quantity  int
+		add       bool
+	}{
+		{laptop, 3, false},
+		{projector, 1, false},
+		{chair, 1, true},
+		{chair, 1, true},
+	} {
+		if counted := inventories.CountItem(doc, count.equipment, count.quantity, count.add); counted.Model == nil {
+			t.Fatalf("CountItem: %s", counted.Message)
+		}
+	}
+
+	response := svc.GetDiscrepancies(doc)
+	if response.Model == nil {
+		t.Fatalf("GetDiscrepancies: %s", response.Message)
+	}
+	got := response.Model
+
+	lines := map[uint]model.InventoryLine{}
+	for _, line := range got.Lines {
+		lines[line.EquipmentID] = line
+	}
+	wantLines := map[uint]struct {
+		expected, actual, difference int
+		amount                       model.Money
+		counted, unexpected          bool
+	}{
+		laptop:    {5, 3, -2, -20000000, true, false},
+		monitor:   {2, 0, -2, -5000000, false, false},
+		projector: {1, 1, 0, 0, true, false},
+		chair:     {0, 2, 2, 1600000, true, true},
+	}
+	if len(lines) != len(wantLines) {
+		t.Fatalf("строк %d, ожидалось %d", len(lines), len(wantLines))
+	}
+	for id, want := range wantLines {
+		line := lines[id]
+		if line.Expected != want.expected || line.Actual != want.actual || line.Difference != want.difference ||
+			line.Amount != want.amount || line.Counted != want.counted || line.Unexpected != want.unexpected {
+			t.Errorf("%s: %+v, ожидалось %+v", line.Name, line, want)
+		}
+	}
+
+	totals := model.InventoryDiscrepancies{
+		Counted:          3,
+		Uncounted:        1,
+		Surpluses:        1,
+		Shortages:        2,
+		SurplusQuantity:  2,
+		ShortageQuantity: 4,
+		SurplusAmount:    1600000,
+		ShortageAmount:   25000000,
+	}
+	got.DocumentID, got.Number, got.Status, got.Lines = 0, "", "", nil
+	if !reflect.DeepEqual(*got, totals) {
+		t.Errorf("итоги %+v, ожидалось %+v", *got, totals)
+	}
+}
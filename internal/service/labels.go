@@ -0,0 +1,150 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"tohaboy/internal/barcode"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+// Лист A4 с этикетками 70 x 37 мм: три столбца по восемь этикеток
+const (
+	labelColumns = 3
+	labelRows    = 8
+	labelWidth   = 70.0
+	labelHeight  = 37.125
+	labelPadding = 3.0
+	labelQRSize  = 20.0
+	labelBarcode = 9.0
+)
+
+// labelCode содержимое QR-кода этикетки: префикс отличает оборудование
+// от документов ("DOC:") при сканировании
+func labelCode(equipment model.Equipment) string {
+	return "EQ:" + equipment.InventoryNumber
+}
+
+type LabelService struct {
+	repo    repository.EquipmentRepositoryInterface
+	session *Session
+}
+
+func NewLabelService(repo repository.EquipmentRepositoryInterface, session *Session) *LabelService {
+	return &LabelService{repo: repo, session: session}
+}
+
+// PrintLabels формирует PDF с этикетками для наклейки на оборудование:
+// название, местоположение, инвентарный номер, штрихкод Code128 и QR-код
+func (s *LabelService) PrintLabels(request model.LabelRequest) *model.LabelSheetResponse {
+	if _, err := s.session.Authorize("LabelService.PrintLabels"); err != nil {
+		return &model.LabelSheetResponse{Message: err.Error()}
+	}
+
+	if len(request.EquipmentIDs) == 0 {
+		return &model.LabelSheetResponse{Message: "оборудование не выбрано"}
+	}
+	copies := request.Copies
+	if copies <= 0 {
+		copies = 1
+	}
+	if request.Skip < 0 || request.Skip >= labelColumns*labelRows {
+		return &model.LabelSheetResponse{
+			Message: fmt.Sprintf("пропустить можно от 0 до %d этикеток", labelColumns*labelRows-1),
+		}
+	}
+
+	response := s.repo.GetEquipmentByIDs(request.EquipmentIDs)
+	if response.Model == nil {
+		return &model.LabelSheetResponse{Message: response.Message}
+	}
+
+	w := &pdfWriter{pdf: newPDF("P")}
+	w.pdf.SetMargins(0, 0, 0)
+	w.pdf.SetAutoPageBreak(false, 0)
+
+	position := request.Skip
+	for _, equipment := range response.Model {
+		if equipment.InventoryNumber == "" {
+			return &model.LabelSheetResponse{
+				Message: fmt.Sprintf("у оборудования %s нет инвентарного номера", equipment.Name),
+			}
+		}
+		for i := 0; i < copies; i++ {
+			if position%(labelColumns*labelRows) == 0 || w.pdf.PageNo() == 0 {
+				w.pdf.AddPage()
+			}
+			cell := position % (labelColumns * labelRows)
+			x := float64(cell%labelColumns) * labelWidth
+			y := float64(cell/labelColumns) * labelHeight
+			if err := s.label(w, equipment, x, y); err != nil {
+				return &model.LabelSheetResponse{Message: err.Error()}
+			}
+			position++
+		}
+	}
+
+	content, err := w.bytes()
+	if err != nil {
+		return &model.LabelSheetResponse{Message: err.Error()}
+	}
+
+	return &model.LabelSheetResponse{
+		Content: base64.StdEncoding.EncodeToString(content),
+		Message: fmt.Sprintf("Этикеток: %d", position-request.Skip),
+	}
+}
+
+// label печатает одну этикетку с левым верхним углом в точке (x, y)
+func (s *LabelService) label(w *pdfWriter, equipment model.Equipment, x, y float64) error {
+	pdf := w.pdf
+	left, top := x+labelPadding, y+labelPadding
+	textWidth := labelWidth - 3*labelPadding - labelQRSize
+
+	if err := w.qrCode(labelCode(equipment), x+labelWidth-labelPadding-labelQRSize, top, labelQRSize); err != nil {
+		return err
+	}
+
+	// Название в две строки, не поместившееся обрезается
+	pdf.SetFont(pdfFont, "B", 8)
+	lines := pdf.SplitText(equipment.Name, textWidth)
+	if len(lines) > 2 {
+		lines = lines[:2]
+		lines[1] += "…"
+	}
+	pdf.SetXY(left, top)
+	for _, line := range lines {
+		pdf.SetX(left)
+		pdf.CellFormat(textWidth, 3.5, line, "", 1, "L", false, 0, "")
+	}
+
+	pdf.SetFont(pdfFont, "", 7)
+	if equipment.Location != nil {
+		pdf.SetXY(left, top+8)
+		pdf.CellFormat(textWidth, 3.5, fitText(w, equipment.Location.Name, textWidth), "", 0, "L", false, 0, "")
+	}
+
+	pdf.SetFont(pdfFont, "B", 11)
+	pdf.SetXY(left, top+13)
+	pdf.CellFormat(textWidth, 5, equipment.InventoryNumber, "", 0, "L", false, 0, "")
+
+	// Номер с кириллицей штрихкодом не кодируется, на этикетке остается QR-код
+	err := w.barcode(equipment.InventoryNumber, left, y+labelHeight-labelPadding-labelBarcode, labelWidth-2*labelPadding, labelBarcode)
+	if errors.Is(err, barcode.ErrNotEncodable) {
+		return nil
+	}
+	return err
+}
+
+// fitText обрезает текст до ширины width мм текущим шрифтом
+func fitText(w *pdfWriter, text string, width float64) string {
+	if w.pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && w.pdf.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
@@ -1,19 +1,29 @@
 package service
 
 import (
+	"fmt"
 	"tohaboy/internal/model"
 	"tohaboy/internal/repository"
 )
 
 type LocationService struct {
-	repo repository.LocationRepositoryInterface
+	repo    repository.LocationRepositoryInterface
+	session *Session
 }
 
-func NewLocationService(repo repository.LocationRepositoryInterface) *LocationService {
-	return &LocationService{repo: repo}
+func NewLocationService(repo repository.LocationRepositoryInterface, session *Session) *LocationService {
+	return &LocationService{repo: repo, session: session}
 }
 
 func (s *LocationService) CreateLocation(location *model.Location) *model.LocationResponse {
+	if _, err := s.session.Authorize("LocationService.CreateLocation"); err != nil {
+		return &model.LocationResponse{Message: err.Error()}
+	}
+
+	if err := validateLocation(location); err != nil {
+		return &model.LocationResponse{Message: err.Error()}
+	}
+
 	response := s.repo.CreateLocation(location)
 	return &model.LocationResponse{
 		Model:   response.Model,
@@ -22,6 +32,10 @@ func (s *LocationService) CreateLocation(location *model.Location) *model.Locati
 }
 
 func (s *LocationService) GetLocation(id int) *model.LocationResponse {
+	if _, err := s.session.Authorize("LocationService.GetLocation"); err != nil {
+		return &model.LocationResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetLocation(id)
 	return &model.LocationResponse{
 		Model:   response.Model,
@@ -30,6 +44,10 @@ func (s *LocationService) GetLocation(id int) *model.LocationResponse {
 }
 
 func (s *LocationService) GetAllLocations() *model.LocationListResponse {
+	if _, err := s.session.Authorize("LocationService.GetAllLocations"); err != nil {
+		return &model.LocationListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetAllLocations()
 	return &model.LocationListResponse{
 		Model:   response.Model,
@@ -38,6 +56,14 @@ func (s *LocationService) GetAllLocations() *model.LocationListResponse {
 }
 
 func (s *LocationService) UpdateLocation(location *model.Location) *model.LocationResponse {
+	if _, err := s.session.Authorize("LocationService.UpdateLocation"); err != nil {
+		return &model.LocationResponse{Message: err.Error()}
+	}
+
+	if err := validateLocation(location); err != nil {
+		return &model.LocationResponse{Message: err.Error()}
+	}
+
 	response := s.repo.UpdateLocation(location)
 	return &model.LocationResponse{
 		Model:   response.Model,
@@ -46,6 +72,10 @@ func (s *LocationService) UpdateLocation(location *model.Location) *model.Locati
 }
 
 func (s *LocationService) DeleteLocation(id int) *model.LocationResponse {
+	if _, err := s.session.Authorize("LocationService.DeleteLocation"); err != nil {
+		return &model.LocationResponse{Message: err.Error()}
+	}
+
 	response := s.repo.DeleteLocation(id)
 	return &model.LocationResponse{
 		Model:   response.Model,
@@ -54,9 +84,62 @@ func (s *LocationService) DeleteLocation(id int) *model.LocationResponse {
 }
 
 func (s *LocationService) GetLocationByEquipment(equipmentID int) *model.LocationListResponse {
+	if _, err := s.session.Authorize("LocationService.GetLocationByEquipment"); err != nil {
+		return &model.LocationListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetLocationByEquipment(equipmentID)
 	return &model.LocationListResponse{
 		Model:   response.Model,
 		Message: response.Message,
 	}
 }
+
+func (s *LocationService) MoveLocation(id, parentID uint) *model.LocationResponse {
+	if _, err := s.session.Authorize("LocationService.MoveLocation"); err != nil {
+		return &model.LocationResponse{Message: err.Error()}
+	}
+
+	response := s.repo.MoveLocation(id, parentID)
+	return &model.LocationResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *LocationService) GetLocationPath(id int) *model.LocationListResponse {
+	if _, err := s.session.Authorize("LocationService.GetLocationPath"); err != nil {
+		return &model.LocationListResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetLocationPath(id)
+	return &model.LocationListResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *LocationService) GetLocationTree() *model.LocationTreeResponse {
+	if _, err := s.session.Authorize("LocationService.GetLocationTree"); err != nil {
+		return &model.LocationTreeResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetLocationTree()
+	return &model.LocationTreeResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func validateLocation(location *model.Location) error {
+	if location == nil {
+		return fmt.Errorf("местоположение не передано")
+	}
+	if !model.ValidLocationKind(location.Kind) {
+		return fmt.Errorf("неизвестный вид местоположения: %s", location.Kind)
+	}
+	if location.ParentID != 0 && location.ParentID == location.ID {
+		return fmt.Errorf("местоположение не может быть вложено само в себя")
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+type MaintenanceService struct {
+	repo    repository.MaintenanceRepositoryInterface
+	session *Session
+}
+
+func NewMaintenanceService(repo repository.MaintenanceRepositoryInterface, session *Session) *MaintenanceService {
+	return &MaintenanceService{repo: repo, session: session}
+}
+
+// CreateMaintenance передает оборудование в ремонт или на обслуживание.
+// Без даты передачи используется текущая.
+func (s *MaintenanceService) CreateMaintenance(record *model.MaintenanceRecord) *model.MaintenanceResponse {
+	user, err := s.session.Authorize("MaintenanceService.CreateMaintenance")
+	if err != nil {
+		return &model.MaintenanceResponse{Message: err.Error()}
+	}
+
+	if record.SentAt.IsZero() {
+		record.SentAt = time.Now()
+	}
+	if err := validateMaintenance(record); err != nil {
+		return &model.MaintenanceResponse{Message: err.Error()}
+	}
+	record.CreatedByID = user.ID
+
+	response := s.repo.CreateMaintenance(record)
+	return &model.MaintenanceResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// CompleteMaintenance завершает обслуживание записи record.ID с датой возврата,
+// стоимостью и результатом из record. Без даты возврата используется текущая.
+func (s *MaintenanceService) CompleteMaintenance(record *model.MaintenanceRecord) *model.MaintenanceResponse {
+	user, err := s.session.Authorize("MaintenanceService.CompleteMaintenance")
+	if err != nil {
+		return &model.MaintenanceResponse{Message: err.Error()}
+	}
+
+	if record.ReturnedAt == nil || record.ReturnedAt.IsZero() {
+		now := time.Now()
+		record.ReturnedAt = &now
+	}
+	record.Description = strings.TrimSpace(record.Description)
+	if record.Outcome != model.MaintenanceRepaired && record.Outcome != model.MaintenanceUnrepairable {
+		return &model.MaintenanceResponse{Message: "Результат обслуживания не указан"}
+	}
+	if record.Cost < 0 {
+		return &model.MaintenanceResponse{Message: "Стоимость обслуживания не может быть отрицательной"}
+	}
+
+	response := s.repo.CompleteMaintenance(record, user.ID)
+	return &model.MaintenanceResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *MaintenanceService) UpdateMaintenance(record *model.MaintenanceRecord) *model.MaintenanceResponse {
+	if _, err := s.session.Authorize("MaintenanceService.UpdateMaintenance"); err != nil {
+		return &model.MaintenanceResponse{Message: err.Error()}
+	}
+
+	if err := validateMaintenance(record); err != nil {
+		return &model.MaintenanceResponse{Message: err.Error()}
+	}
+
+	response := s.repo.UpdateMaintenance(record)
+	return &model.MaintenanceResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *MaintenanceService) GetMaintenance(id uint) *model.MaintenanceResponse {
+	if _, err := s.session.Authorize("MaintenanceService.GetMaintenance"); err != nil {
+		return &model.MaintenanceResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetMaintenance(id)
+	return &model.MaintenanceResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func (s *MaintenanceService) GetAllMaintenance() *model.MaintenanceListResponse {
+	if _, err := s.session.Authorize("MaintenanceService.GetAllMaintenance"); err != nil {
+		return &model.MaintenanceListResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetAllMaintenance()
+	return &model.MaintenanceListResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// GetMaintenanceByEquipment возвращает историю обслуживания оборудования, новые записи первыми
+func (s *MaintenanceService) GetMaintenanceByEquipment(equipmentID uint) *model.MaintenanceListResponse {
+	if _, err := s.session.Authorize("MaintenanceService.GetMaintenanceByEquipment"); err != nil {
+		return &model.MaintenanceListResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetMaintenanceByEquipment(equipmentID)
+	return &model.MaintenanceListResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+// GetOverdueMaintenance возвращает оборудование с просроченным плановым обслуживанием
+// по периодичности, заданной в категориях
+func (s *MaintenanceService) GetOverdueMaintenance() *model.MaintenanceDueResponse {
+	if _, err := s.session.Authorize("MaintenanceService.GetOverdueMaintenance"); err != nil {
+		return &model.MaintenanceDueResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetOverdueMaintenance(time.Now())
+	return &model.MaintenanceDueResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
+func validateMaintenance(record *model.MaintenanceRecord) error {
+	record.Description = strings.TrimSpace(record.Description)
+	if record.EquipmentID == 0 {
+		return fmt.Errorf("оборудование не указано")
+	}
+	if record.Type != model.MaintenancePlanned && record.Type != model.MaintenanceUnplanned {
+		return fmt.Errorf("неизвестный вид обслуживания: %s", record.Type)
+	}
+	if record.SentAt.IsZero() {
+		return fmt.Errorf("дата передачи в обслуживание не указана")
+	}
+	if record.Cost < 0 {
+		return fmt.Errorf("стоимость обслуживания не может быть отрицательной")
+	}
+	return nil
+}
@@ -8,15 +8,23 @@ import (
 )
 
 type MovementService struct {
-	repo repository.MovementRepositoryInterface
-	docs DocumentServiceInterface
+	repo    repository.MovementRepositoryInterface
+	session *Session
 }
 
-func NewMovementService(repo repository.MovementRepositoryInterface, docs DocumentServiceInterface) *MovementService {
-	return &MovementService{repo: repo, docs: docs}
+func NewMovementService(repo repository.MovementRepositoryInterface, session *Session) *MovementService {
+	return &MovementService{repo: repo, session: session}
 }
 
 func (s *MovementService) CreateMovement(movement *model.Movement) *model.MovementResponse {
+	user, err := s.session.Authorize("MovementService.CreateMovement")
+	if err != nil {
+		return &model.MovementResponse{Message: err.Error()}
+	}
+
+	// Перемещение и его документ создает пользователь текущей сессии
+	movement.CreatedByID = user.ID
+
 	// Валидация
 	if err := s.validateMovement(movement); err != nil {
 		return &model.MovementResponse{
@@ -27,13 +35,15 @@ func (s *MovementService) CreateMovement(movement *model.Movement) *model.Moveme
 	// Устанавливаем дату создания
 	movement.Date = time.Now()
 
-	// Создаем документ перемещения
+	// Документ перемещения создается и проводится вместе с самим перемещением
 	doc := &model.Document{
-		Type:        "transfer",
-		Date:        movement.Date,
-		Status:      "completed",
-		LocationID:  movement.ToLocationID,
-		CreatedByID: movement.CreatedByID,
+		Type:           "transfer",
+		Date:           movement.Date,
+		Status:         "draft",
+		LocationID:     movement.ToLocationID,
+		FromLocationID: movement.FromLocationID,
+		ResponsibleID:  movement.ToResponsibleID,
+		CreatedByID:    movement.CreatedByID,
 		Items: []model.DocumentItem{
 			{
 				EquipmentID: movement.EquipmentID,
@@ -41,20 +51,11 @@ func (s *MovementService) CreateMovement(movement *model.Movement) *model.Moveme
 			},
 		},
 	}
-
-	// Создаем документ
-	docResponse := s.docs.CreateDocument(doc)
-	if docResponse.Model == nil {
-		return &model.MovementResponse{
-			Message: fmt.Sprintf("Ошибка создания документа: %s", docResponse.Message),
-		}
+	for i := range doc.Items {
+		doc.Items[i].Calculate()
 	}
 
-	// Связываем движение с созданным документом
-	movement.DocumentID = docResponse.Model.ID
-
-	// Создаем запись о перемещении
-	response := s.repo.CreateMovement(movement)
+	response := s.repo.CreateMovement(movement, doc)
 	return &model.MovementResponse{
 		Model:   response.Model,
 		Message: response.Message,
@@ -62,6 +63,10 @@ func (s *MovementService) CreateMovement(movement *model.Movement) *model.Moveme
 }
 
 func (s *MovementService) GetMovement(id uint) *model.MovementResponse {
+	if _, err := s.session.Authorize("MovementService.GetMovement"); err != nil {
+		return &model.MovementResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetMovement(id)
 	return &model.MovementResponse{
 		Model:   response.Model,
@@ -70,6 +75,10 @@ func (s *MovementService) GetMovement(id uint) *model.MovementResponse {
 }
 
 func (s *MovementService) GetAllMovements() *model.MovementListResponse {
+	if _, err := s.session.Authorize("MovementService.GetAllMovements"); err != nil {
+		return &model.MovementListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetAllMovements()
 	return &model.MovementListResponse{
 		Model:   response.Model,
@@ -77,7 +86,30 @@ func (s *MovementService) GetAllMovements() *model.MovementListResponse {
 	}
 }
 
+// ListMovements возвращает страницу списка с учетом фильтров и сортировки
+func (s *MovementService) ListMovements(query model.ListQuery) *model.MovementPageResponse {
+	if _, err := s.session.Authorize("MovementService.ListMovements"); err != nil {
+		return &model.MovementPageResponse{Message: err.Error()}
+	}
+
+	response := s.repo.ListMovements(query)
+	return &model.MovementPageResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
 func (s *MovementService) UpdateMovement(movement *model.Movement) *model.MovementResponse {
+	if _, err := s.session.Authorize("MovementService.UpdateMovement"); err != nil {
+		return &model.MovementResponse{Message: err.Error()}
+	}
+
+	if err := s.validateMovement(movement); err != nil {
+		return &model.MovementResponse{
+			Message: err.Error(),
+		}
+	}
+
 	response := s.repo.UpdateMovement(movement)
 	return &model.MovementResponse{
 		Model:   response.Model,
@@ -86,6 +118,10 @@ func (s *MovementService) UpdateMovement(movement *model.Movement) *model.Moveme
 }
 
 func (s *MovementService) DeleteMovement(id uint) *model.MovementResponse {
+	if _, err := s.session.Authorize("MovementService.DeleteMovement"); err != nil {
+		return &model.MovementResponse{Message: err.Error()}
+	}
+
 	response := s.repo.DeleteMovement(id)
 	return &model.MovementResponse{
 		Model:   response.Model,
@@ -94,6 +130,10 @@ func (s *MovementService) DeleteMovement(id uint) *model.MovementResponse {
 }
 
 func (s *MovementService) GetMovementsByEquipment(equipmentID uint) *model.MovementListResponse {
+	if _, err := s.session.Authorize("MovementService.GetMovementsByEquipment"); err != nil {
+		return &model.MovementListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetMovementsByEquipment(equipmentID)
 	return &model.MovementListResponse{
 		Model:   response.Model,
@@ -102,6 +142,10 @@ func (s *MovementService) GetMovementsByEquipment(equipmentID uint) *model.Movem
 }
 
 func (s *MovementService) GetMovementsByLocation(locationID uint) *model.MovementListResponse {
+	if _, err := s.session.Authorize("MovementService.GetMovementsByLocation"); err != nil {
+		return &model.MovementListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetMovementsByLocation(locationID)
 	return &model.MovementListResponse{
 		Model:   response.Model,
@@ -132,9 +176,5 @@ func (s *MovementService) validateMovement(movement *model.Movement) error {
 		return fmt.Errorf("количество должно быть больше нуля")
 	}
 
-	if movement.CreatedByID == 0 {
-		return fmt.Errorf("пользователь не указан")
-	}
-
 	return nil
 }
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"tohaboy/internal/barcode"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	pdfFont       = "Go"
+	pdfFontSize   = 10
+	pdfLineHeight = 5
+)
+
+// pdfColumn столбец таблицы: заголовок, ширина в мм и выравнивание ("L", "C", "R")
+type pdfColumn struct {
+	Title string
+	Width float64
+	Align string
+}
+
+// pdfSignature строка блока подписей: должность или роль и расшифровка подписи
+type pdfSignature struct {
+	Role string
+	Name string
+}
+
+// pdfWriter печатная форма в формате PDF. Шрифты Go встраиваются в файл
+// и содержат кириллицу, в нижнем колонтитуле печатается номер страницы.
+type pdfWriter struct {
+	pdf *fpdf.Fpdf
+}
+
+// newPDFWriter создает документ A4 с первой страницей. orientation: "P" или "L",
+// footer - текст нижнего колонтитула рядом с номером страницы.
+func newPDFWriter(orientation, footer string) *pdfWriter {
+	pdf := newPDF(orientation)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AliasNbPages("")
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont(pdfFont, "", 8)
+		pdf.CellFormat(0, pdfLineHeight, footer, "T", 0, "L", false, 0, "")
+		pdf.SetX(15)
+		pdf.CellFormat(0, pdfLineHeight, fmt.Sprintf("Страница %d из {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	pdf.AddPage()
+	pdf.SetFont(pdfFont, "", pdfFontSize)
+	return &pdfWriter{pdf: pdf}
+}
+
+// newPDF создает пустой документ A4 со встроенными шрифтами
+func newPDF(orientation string) *fpdf.Fpdf {
+	pdf := fpdf.New(orientation, "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFont, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", gobold.TTF)
+	return pdf
+}
+
+// contentWidth ширина области печати между полями
+func (w *pdfWriter) contentWidth() float64 {
+	pageWidth, _ := w.pdf.GetPageSize()
+	left, _, right, _ := w.pdf.GetMargins()
+	return pageWidth - left - right
+}
+
+// ensureSpace начинает новую страницу, если до нижнего поля осталось меньше height мм
+func (w *pdfWriter) ensureSpace(height float64) bool {
+	_, pageHeight := w.pdf.GetPageSize()
+	_, bottom := w.pdf.GetAutoPageBreak()
+	if w.pdf.GetY()+height > pageHeight-bottom {
+		w.pdf.AddPage()
+		return true
+	}
+	return false
+}
+
+// title печатает заголовок по центру
+func (w *pdfWriter) title(text string, size float64) {
+	w.pdf.SetFont(pdfFont, "B", size)
+	w.pdf.MultiCell(0, size*0.5, text, "", "C", false)
+	w.pdf.SetFont(pdfFont, "", pdfFontSize)
+}
+
+// text печатает абзац с переносом строк
+func (w *pdfWriter) text(text string) {
+	w.pdf.MultiCell(0, pdfLineHeight, text, "", "L", false)
+}
+
+// field печатает строку "Подпись: значение", пустые значения пропускаются
+func (w *pdfWriter) field(label, value string) {
+	if value == "" {
+		return
+	}
+	w.pdf.SetFont(pdfFont, "B", pdfFontSize)
+	labelWidth := w.pdf.GetStringWidth(label+": ") + 1
+	w.pdf.CellFormat(labelWidth, pdfLineHeight, label+":", "", 0, "L", false, 0, "")
+	w.pdf.SetFont(pdfFont, "", pdfFontSize)
+	w.pdf.MultiCell(0, pdfLineHeight, value, "", "L", false)
+}
+
+// space добавляет вертикальный отступ
+func (w *pdfWriter) space(height float64) {
+	w.pdf.Ln(height)
+}
+
+// table печатает таблицу с переносом текста в ячейках. При переходе на новую
+// страницу шапка таблицы повторяется. Строка total (если есть) печатается жирным.
+func (w *pdfWriter) table(columns []pdfColumn, rows [][]string, total []string) {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Title
+	}
+
+	w.pdf.SetFont(pdfFont, "B", 9)
+	w.ensureSpace(w.rowHeight(columns, header) + pdfLineHeight)
+	w.tableRow(columns, header, true, "C")
+
+	for _, row := range rows {
+		w.pdf.SetFont(pdfFont, "", 9)
+		if w.ensureSpace(w.rowHeight(columns, row)) {
+			w.pdf.SetFont(pdfFont, "B", 9)
+			w.tableRow(columns, header, true, "C")
+			w.pdf.SetFont(pdfFont, "", 9)
+		}
+		w.tableRow(columns, row, false, "")
+	}
+
+	if total != nil {
+		w.pdf.SetFont(pdfFont, "B", 9)
+		w.ensureSpace(w.rowHeight(columns, total))
+		w.tableRow(columns, total, false, "")
+	}
+	w.pdf.SetFont(pdfFont, "", pdfFontSize)
+}
+
+func (w *pdfWriter) rowHeight(columns []pdfColumn, values []string) float64 {
+	lines := 1
+	for i, column := range columns {
+		if i >= len(values) {
+			break
+		}
+		if n := len(w.pdf.SplitText(values[i], column.Width-2)); n > lines {
+			lines = n
+		}
+	}
+	return float64(lines)*pdfLineHeight*0.9 + 2
+}
+
+func (w *pdfWriter) tableRow(columns []pdfColumn, values []string, fill bool, align string) {
+	height := w.rowHeight(columns, values)
+	x, y := w.pdf.GetXY()
+	w.pdf.SetFillColor(230, 230, 230)
+
+	for i, column := range columns {
+		style := "D"
+		if fill {
+			style = "FD"
+		}
+		w.pdf.Rect(x, y, column.Width, height, style)
+
+		if i < len(values) {
+			cellAlign := column.Align
+			if align != "" {
+				cellAlign = align
+			}
+			for n, line := range w.pdf.SplitText(values[i], column.Width-2) {
+				w.pdf.SetXY(x+1, y+1+float64(n)*pdfLineHeight*0.9)
+				w.pdf.CellFormat(column.Width-2, pdfLineHeight*0.9, line, "", 0, cellAlign, false, 0, "")
+			}
+		}
+		x += column.Width
+	}
+
+	left, _, _, _ := w.pdf.GetMargins()
+	w.pdf.SetXY(left, y+height)
+}
+
+// signatures печатает блок подписей: роль, линия для подписи и расшифровка.
+// Блок не разрывается между страницами.
+func (w *pdfWriter) signatures(list []pdfSignature) {
+	if len(list) == 0 {
+		return
+	}
+	w.ensureSpace(float64(len(list)) * 12)
+
+	width := w.contentWidth()
+	roleWidth, signWidth := width*0.35, width*0.25
+	nameWidth := width - roleWidth - signWidth - 10
+	for _, signature := range list {
+		w.pdf.CellFormat(roleWidth, pdfLineHeight+2, signature.Role, "", 0, "L", false, 0, "")
+		w.pdf.CellFormat(signWidth, pdfLineHeight+2, "", "B", 0, "C", false, 0, "")
+		w.pdf.CellFormat(10, pdfLineHeight+2, "", "", 0, "C", false, 0, "")
+		w.pdf.CellFormat(nameWidth, pdfLineHeight+2, signature.Name, "B", 1, "C", false, 0, "")
+
+		w.pdf.SetFont(pdfFont, "", 7)
+		w.pdf.CellFormat(roleWidth, 4, "", "", 0, "L", false, 0, "")
+		w.pdf.CellFormat(signWidth, 4, "(подпись)", "", 0, "C", false, 0, "")
+		w.pdf.CellFormat(10, 4, "", "", 0, "C", false, 0, "")
+		w.pdf.CellFormat(nameWidth, 4, "(расшифровка подписи)", "", 1, "C", false, 0, "")
+		w.pdf.SetFont(pdfFont, "", pdfFontSize)
+		w.pdf.Ln(1)
+	}
+}
+
+// qrCode печатает QR-код с текстом content в точке (x, y) размером size мм
+func (w *pdfWriter) qrCode(content string, x, y, size float64) error {
+	png, err := qrcode.Encode(content, qrcode.Medium, 256)
+	if err != nil {
+		return fmt.Errorf("ошибка создания QR-кода: %v", err)
+	}
+
+	options := fpdf.ImageOptions{ImageType: "PNG"}
+	name := "qr:" + content
+	w.pdf.RegisterImageOptionsReader(name, options, bytes.NewReader(png))
+	w.pdf.ImageOptions(name, x, y, size, size, false, options, 0, "")
+	return nil
+}
+
+// barcode печатает штрихкод Code128 с текстом content, вписанный по центру
+// в прямоугольник (x, y, width, height) вместе со свободными полями
+func (w *pdfWriter) barcode(content string, x, y, width, height float64) error {
+	modules, err := barcode.Code128(content)
+	if err != nil {
+		return fmt.Errorf("ошибка создания штрихкода: %w", err)
+	}
+
+	// Слишком широкие модули плохо читаются сканером, поэтому ширина ограничена
+	module := math.Min(width/float64(len(modules)+2*barcode.QuietZone), 0.5)
+	x += (width - module*float64(len(modules))) / 2
+
+	w.pdf.SetFillColor(0, 0, 0)
+	for i := 0; i < len(modules); {
+		if !modules[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(modules) && modules[i] {
+			i++
+		}
+		w.pdf.Rect(x+module*float64(start), y, module*float64(i-start), height, "F")
+	}
+	return nil
+}
+
+// bytes возвращает содержимое PDF-файла
+func (w *pdfWriter) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := w.pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("ошибка при сохранении PDF: %v", err)
+	}
+	return buf.Bytes(), nil
+}
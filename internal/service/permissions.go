@@ -0,0 +1,142 @@
+package service
+
+import "tohaboy/internal/model"
+
+// Наборы ролей для матрицы прав
+var (
+	anyRole     = []string{model.RoleAdmin, model.RoleManager, model.RoleAuditor}
+	editorRoles = []string{model.RoleAdmin, model.RoleManager}
+	adminOnly   = []string{model.RoleAdmin}
+	auditRoles  = []string{model.RoleAdmin, model.RoleAuditor}
+)
+
+// permissions описывает, какие роли могут вызывать каждый метод сервисов,
+// привязанных к Wails. Метод, отсутствующий в матрице, запрещен всем.
+var permissions = map[string][]string{
+	"UserService.GetCurrentUser": anyRole,
+	"UserService.GetUser":        adminOnly,
+	"UserService.GetByID":        adminOnly,
+	"UserService.GetByName":      adminOnly,
+	"UserService.Update":         adminOnly,
+	"UserService.Delete":         adminOnly,
+	"AuthService.Register":       adminOnly,
+
+	"EquipmentService.CreateEquipment":        editorRoles,
+	"EquipmentService.GetEquipment":           anyRole,
+	"EquipmentService.GetAllEquipment":        anyRole,
+	"EquipmentService.ListEquipment":          anyRole,
+	"EquipmentService.UpdateEquipment":        editorRoles,
+	"EquipmentService.DeleteEquipment":        adminOnly,
+	"EquipmentService.GetEquipmentByLocation": anyRole,
+	"EquipmentService.GetEquipmentBySupplier": anyRole,
+	"EquipmentService.IssueEquipment":         editorRoles,
+	"EquipmentService.ReturnEquipment":        editorRoles,
+	"EquipmentService.SendToRepair":           editorRoles,
+	"EquipmentService.GetStatusHistory":       anyRole,
+
+	"SupplierService.CreateSupplier":         editorRoles,
+	"SupplierService.GetSupplier":            anyRole,
+	"SupplierService.GetAllSuppliers":        anyRole,
+	"SupplierService.ListSuppliers":          anyRole,
+	"SupplierService.UpdateSupplier":         editorRoles,
+	"SupplierService.DeleteSupplier":         adminOnly,
+	"SupplierService.GetSupplierByEquipment": anyRole,
+
+	"LocationService.CreateLocation":         editorRoles,
+	"LocationService.GetLocation":            anyRole,
+	"LocationService.GetAllLocations":        anyRole,
+	"LocationService.UpdateLocation":         editorRoles,
+	"LocationService.DeleteLocation":         adminOnly,
+	"LocationService.GetLocationByEquipment": anyRole,
+	"LocationService.MoveLocation":           editorRoles,
+	"LocationService.GetLocationPath":        anyRole,
+	"LocationService.GetLocationTree":        anyRole,
+
+	"MovementService.CreateMovement":          editorRoles,
+	"MovementService.GetMovement":             anyRole,
+	"MovementService.GetAllMovements":         anyRole,
+	"MovementService.ListMovements":           anyRole,
+	"MovementService.UpdateMovement":          adminOnly,
+	"MovementService.DeleteMovement":          adminOnly,
+	"MovementService.GetMovementsByEquipment": anyRole,
+	"MovementService.GetMovementsByLocation":  anyRole,
+
+	"DocumentService.CreateDocument":     editorRoles,
+	"DocumentService.GetDocument":        anyRole,
+	"DocumentService.GetAllDocuments":    anyRole,
+	"DocumentService.ListDocuments":      anyRole,
+	"DocumentService.UpdateDocument":     editorRoles,
+	"DocumentService.DeleteDocument":     editorRoles,
+	"DocumentService.ApproveDocument":    editorRoles,
+	"DocumentService.CancelDocument":     editorRoles,
+	"DocumentService.ReverseDocument":    editorRoles,
+	"DocumentService.ExportDocument":     anyRole,
+	"DocumentService.ExportDocumentGOST": anyRole,
+	"DocumentService.ExportDocumentPDF":  anyRole,
+
+	"CategoryService.CreateCategory":   editorRoles,
+	"CategoryService.GetCategory":      anyRole,
+	"CategoryService.GetAllCategories": anyRole,
+	"CategoryService.UpdateCategory":   editorRoles,
+	"CategoryService.DeleteCategory":   adminOnly,
+
+	"EmployeeService.CreateEmployee":  editorRoles,
+	"EmployeeService.GetEmployee":     anyRole,
+	"EmployeeService.GetAllEmployees": anyRole,
+	"EmployeeService.UpdateEmployee":  editorRoles,
+	"EmployeeService.DeleteEmployee":  adminOnly,
+	"EmployeeService.AssignEquipment": editorRoles,
+
+	"AuditService.GetAuditEntries":  auditRoles,
+	"AuditService.GetEntityHistory": auditRoles,
+
+	"SearchService.Search": anyRole,
+
+	"ImportService.PreviewImport": editorRoles,
+	"ImportService.CommitImport":  editorRoles,
+
+	"ReportService.EquipmentRegister": anyRole,
+	"ReportService.StockBalance":      anyRole,
+	"ReportService.Turnover":          anyRole,
+	"ReportService.WrittenOff":        anyRole,
+
+	"InventoryService.StartInventory":   editorRoles,
+	"InventoryService.CountItem":        editorRoles,
+	"InventoryService.ScanItem":         editorRoles,
+	"InventoryService.GetDiscrepancies": anyRole,
+	"InventoryService.ExportStatement":  anyRole,
+
+	"LabelService.PrintLabels": anyRole,
+
+	"ScanService.Resolve":        anyRole,
+	"ScanService.ScanToDocument": editorRoles,
+
+	"MaintenanceService.CreateMaintenance":         editorRoles,
+	"MaintenanceService.CompleteMaintenance":       editorRoles,
+	"MaintenanceService.UpdateMaintenance":         editorRoles,
+	"MaintenanceService.GetMaintenance":            anyRole,
+	"MaintenanceService.GetAllMaintenance":         anyRole,
+	"MaintenanceService.GetMaintenanceByEquipment": anyRole,
+	"MaintenanceService.GetOverdueMaintenance":     anyRole,
+
+	"DepreciationService.RunDepreciation":     editorRoles,
+	"DepreciationService.CancelDepreciation":  adminOnly,
+	"DepreciationService.GetDepreciationRun":  anyRole,
+	"DepreciationService.GetDepreciationRuns": anyRole,
+	"DepreciationService.ExportSchedule":      anyRole,
+
+	"ExchangeRateService.SaveRate":   editorRoles,
+	"ExchangeRateService.DeleteRate": adminOnly,
+	"ExchangeRateService.GetRates":   anyRole,
+	"ExchangeRateService.GetRate":    anyRole,
+	"ExchangeRateService.ImportCBR":  editorRoles,
+}
+
+func hasPermission(role, method string) bool {
+	for _, allowed := range permissions[method] {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
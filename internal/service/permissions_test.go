@@ -0,0 +1,173 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+// publicMethods методы, привязанные к Wails, которые вызываются без сессии
+var publicMethods = map[string]bool{
+	"AuthService.Login":         true,
+	"AuthService.Authenticate":  true,
+	"AuthService.Logout":        true,
+	"AuthService.SignupAllowed": true,
+}
+
+// boundMethods методы сервисов, которые main привязывает к Wails:
+// имя в виде "EquipmentService.DeleteEquipment" -> метод
+func boundMethods(svc *Service) map[string]reflect.Value {
+	methods := make(map[string]reflect.Value)
+	value := reflect.ValueOf(svc).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() != reflect.Interface || field.IsNil() {
+			continue
+		}
+		service := field.Elem()
+		name := service.Type().Elem().Name()
+		for m := 0; m < service.NumMethod(); m++ {
+			methods[name+"."+service.Type().Method(m).Name] = service.Method(m)
+		}
+	}
+	return methods
+}
+
+// callMessage вызывает метод с нулевыми аргументами и возвращает текст отказа:
+// ошибку или поле Message ответа
+func callMessage(method reflect.Value) (message string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("паника до проверки прав: %v", r)
+		}
+	}()
+
+	args := make([]reflect.Value, method.Type().NumIn())
+	for i := range args {
+		args[i] = reflect.Zero(method.Type().In(i))
+	}
+	for _, result := range method.Call(args) {
+		if result.Type().Implements(reflect.TypeOf((*error)(nil)).Elem()) {
+			if !result.IsNil() {
+				return result.Interface().(error).Error(), nil
+			}
+			continue
+		}
+		if result.Kind() == reflect.Ptr && !result.IsNil() {
+			result = result.Elem()
+		}
+		if result.Kind() == reflect.Struct {
+			if field := result.FieldByName("Message"); field.IsValid() && field.Kind() == reflect.String {
+				return field.String(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("ответ без ошибки и сообщения")
+}
+
+func TestPermissionsCoverBoundMethods(t *testing.T) {
+	f := newServiceFixture(t)
+	methods := boundMethods(NewService(repository.NewRepository(f.db, f.cfg.Numbering), f.cfg))
+
+	var names []string
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := permissions[name]; !ok && !publicMethods[name] {
+			t.Errorf("метод %s не описан в матрице прав", name)
+		}
+	}
+
+	for name, roles := range permissions {
+		if _, ok := methods[name]; !ok {
+			t.Errorf("в матрице прав метод %s, которого нет у привязанных сервисов", name)
+		}
+		if len(roles) == 0 {
+			t.Errorf("метод %s не разрешен ни одной роли", name)
+		}
+		for _, role := range roles {
+			if !isValidRole(role) {
+				t.Errorf("метод %s разрешен неизвестной роли %q", name, role)
+			}
+		}
+	}
+}
+
+func TestBoundMethodsAuthorize(t *testing.T) {
+	f := newServiceFixture(t)
+	svc := NewService(repository.NewRepository(f.db, f.cfg.Numbering), f.cfg)
+	f.session = svc.Session
+	methods := boundMethods(svc)
+
+	// Регистрация проверяет права только для ролей выше аудитора, см. TestRegister
+	var names []string
+	for name := range permissions {
+		if name != "AuthService.Register" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	// Без сессии закрытые методы требуют входа
+	f.session.End()
+	for _, name := range names {
+		if message, err := callMessage(methods[name]); err != nil || message != model.MessageUnauthorized {
+			t.Errorf("%s без сессии: %q (%v), ожидалось %q", name, message, err, model.MessageUnauthorized)
+		}
+	}
+
+	// Роль, которой метод не разрешен, получает отказ до выполнения метода
+	for _, role := range []string{model.RoleAdmin, model.RoleManager, model.RoleAuditor} {
+		f.login(t, role)
+		for _, name := range names {
+			if hasPermission(role, name) {
+				continue
+			}
+			if message, err := callMessage(methods[name]); err != nil || message != model.MessageForbidden {
+				t.Errorf("%s (%s): %q (%v), ожидалось %q", name, role, message, err, model.MessageForbidden)
+			}
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	f := newServiceFixture(t)
+
+	if _, err := f.session.Authorize("EquipmentService.GetEquipment"); err != ErrUnauthorized {
+		t.Errorf("без сессии: %v, ожидалось %v", err, ErrUnauthorized)
+	}
+
+	tests := []struct {
+		role   string
+		method string
+		err    error
+	}{
+		{model.RoleAdmin, "EquipmentService.DeleteEquipment", nil},
+		{model.RoleManager, "EquipmentService.DeleteEquipment", ErrForbidden},
+		{model.RoleManager, "EquipmentService.CreateEquipment", nil},
+		{model.RoleAuditor, "EquipmentService.CreateEquipment", ErrForbidden},
+		{model.RoleAuditor, "EquipmentService.GetEquipment", nil},
+		{model.RoleAuditor, "AuditService.GetAuditEntries", nil},
+		{model.RoleManager, "AuditService.GetAuditEntries", ErrForbidden},
+		{model.RoleAdmin, "UserService.Delete", nil},
+		{model.RoleManager, "UserService.Delete", ErrForbidden},
+		// Метода нет в матрице - запрещен всем
+		{model.RoleAdmin, "EquipmentService.Unknown", ErrForbidden},
+	}
+	for _, tt := range tests {
+		user := f.login(t, tt.role)
+		got, err := f.session.Authorize(tt.method)
+		if err != tt.err {
+			t.Errorf("%s -> %s: %v, ожидалось %v", tt.role, tt.method, err, tt.err)
+			continue
+		}
+		if err == nil && (got == nil || got.ID != user.ID) {
+			t.Errorf("%s -> %s: пользователь %+v", tt.role, tt.method, got)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+var equipmentStatuses = map[string]string{
+	"available":   "Доступно",
+	"in_use":      "Используется",
+	"maintenance": "На обслуживании",
+	"written_off": "Списано",
+}
+
+type ReportService struct {
+	repo       repository.ReportRepositoryInterface
+	categories repository.CategoryRepositoryInterface
+	suppliers  repository.SupplierRepositoryInterface
+	locations  repository.LocationRepositoryInterface
+	session    *Session
+	cfg        *config.Config
+}
+
+func NewReportService(
+	repo repository.ReportRepositoryInterface,
+	categories repository.CategoryRepositoryInterface,
+	suppliers repository.SupplierRepositoryInterface,
+	locations repository.LocationRepositoryInterface,
+	session *Session,
+	cfg *config.Config,
+) *ReportService {
+	return &ReportService{
+		repo:       repo,
+		categories: categories,
+		suppliers:  suppliers,
+		locations:  locations,
+		session:    session,
+		cfg:        cfg,
+	}
+}
+
+// EquipmentRegister реестр оборудования с текущими остатками,
+// сгруппированный по категориям и местоположениям
+func (s *ReportService) EquipmentRegister(filter model.ReportFilter) *model.ReportResponse {
+	if _, err := s.session.Authorize("ReportService.EquipmentRegister"); err != nil {
+		return &model.ReportResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetRegister(filter)
+	if response.Model == nil {
+		return &model.ReportResponse{Message: response.Message}
+	}
+
+	w, err := newReport(filter.Format, "Реестр", "Реестр оборудования", s.subtitle(fmt.Sprintf("на %s", time.Now().Format("02.01.2006")), filter), []reportColumn{
+		{Title: "№", Width: 6},
+		{Title: "Наименование", Width: 36},
+		{Title: "Серийный номер", Width: 20},
+		{Title: "Статус", Width: 16},
+		{Title: "Поставщик", Width: 22},
+		{Title: "Материально ответственное лицо", Width: 24},
+		{Title: "Кол-во", Width: 10, Format: cellInt},
+		{Title: "Цена", Width: 14, Format: cellMoney},
+		{Title: "Сумма", Width: 16, Format: cellMoney},
+	})
+	if err != nil {
+		return &model.ReportResponse{Message: err.Error()}
+	}
+
+	rows := response.Model
+	var total, categoryTotal, locationTotal stockTotal
+	for i, row := range rows {
+		if i == 0 || row.Category != rows[i-1].Category {
+			w.group("Категория: " + orDefault(row.Category, "без категории"))
+			categoryTotal = stockTotal{}
+		}
+		if i == 0 || row.Category != rows[i-1].Category || row.Location != rows[i-1].Location {
+			w.group("Местоположение: " + orDefault(row.Location, "не указано"))
+			locationTotal = stockTotal{}
+		}
+
+		sum := amount(row.Closing, row.Price)
+		w.add(i+1, row.Name, row.SerialNumber, equipmentStatuses[row.Status], row.Supplier, row.Responsible, row.Closing, row.Price, sum)
+		for _, t := range []*stockTotal{&total, &categoryTotal, &locationTotal} {
+			t.add(row.Closing, sum)
+		}
+
+		last := i == len(rows)-1
+		if last || row.Category != rows[i+1].Category || row.Location != rows[i+1].Location {
+			w.total("Итого по местоположению", nil, nil, nil, nil, nil, nil, locationTotal.quantity, nil, locationTotal.sum)
+		}
+		if last || row.Category != rows[i+1].Category {
+			w.total("Итого по категории", nil, nil, nil, nil, nil, nil, categoryTotal.quantity, nil, categoryTotal.sum)
+		}
+	}
+	w.total("Всего", nil, nil, nil, nil, nil, nil, total.quantity, nil, total.sum)
+
+	return reportResponse(w)
+}
+
+// StockBalance остатки оборудования по местоположениям на конец дня filter.DateTo
+func (s *ReportService) StockBalance(filter model.ReportFilter) *model.ReportResponse {
+	if _, err := s.session.Authorize("ReportService.StockBalance"); err != nil {
+		return &model.ReportResponse{Message: err.Error()}
+	}
+
+	if filter.DateTo == "" {
+		filter.DateTo = time.Now().Format("2006-01-02")
+	}
+	filter.DateFrom = ""
+	date, err := time.Parse("2006-01-02", filter.DateTo)
+	if err != nil {
+		return &model.ReportResponse{Message: fmt.Sprintf("неверная дата: %v", err)}
+	}
+
+	response := s.repo.GetTurnover(filter)
+	if response.Model == nil {
+		return &model.ReportResponse{Message: response.Message}
+	}
+
+	w, err := newReport(filter.Format, "Остатки", "Остатки оборудования", s.subtitle(fmt.Sprintf("на конец дня %s", date.Format("02.01.2006")), filter), []reportColumn{
+		{Title: "№", Width: 6},
+		{Title: "Наименование", Width: 36},
+		{Title: "Серийный номер", Width: 20},
+		{Title: "Категория", Width: 22},
+		{Title: "Материально ответственное лицо", Width: 24},
+		{Title: "Кол-во", Width: 10, Format: cellInt},
+		{Title: "Цена", Width: 14, Format: cellMoney},
+		{Title: "Сумма", Width: 16, Format: cellMoney},
+	})
+	if err != nil {
+		return &model.ReportResponse{Message: err.Error()}
+	}
+
+	var rows []model.StockRow
+	for _, row := range response.Model {
+		if row.Closing != 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	var total, locationTotal stockTotal
+	for i, row := range rows {
+		if i == 0 || row.Location != rows[i-1].Location {
+			w.group("Местоположение: " + orDefault(row.Location, "не указано"))
+			locationTotal = stockTotal{}
+		}
+
+		sum := amount(row.Closing, row.Price)
+		w.add(i+1, row.Name, row.SerialNumber, row.Category, row.Responsible, row.Closing, row.Price, sum)
+		total.add(row.Closing, sum)
+		locationTotal.add(row.Closing, sum)
+
+		if i == len(rows)-1 || row.Location != rows[i+1].Location {
+			w.total("Итого по местоположению", nil, nil, nil, nil, nil, locationTotal.quantity, nil, locationTotal.sum)
+		}
+	}
+	w.total("Всего", nil, nil, nil, nil, nil, total.quantity, nil, total.sum)
+
+	return reportResponse(w)
+}
+
+// Turnover оборотная ведомость: остатки на начало и конец периода,
+// поступление и выбытие за период по местоположениям. Без начальной даты
+// период начинается с первого числа месяца конечной даты.
+func (s *ReportService) Turnover(filter model.ReportFilter) *model.ReportResponse {
+	if _, err := s.session.Authorize("ReportService.Turnover"); err != nil {
+		return &model.ReportResponse{Message: err.Error()}
+	}
+
+	if filter.DateTo == "" {
+		filter.DateTo = time.Now().Format("2006-01-02")
+	}
+	to, err := time.Parse("2006-01-02", filter.DateTo)
+	if err != nil {
+		return &model.ReportResponse{Message: fmt.Sprintf("неверная конечная дата: %v", err)}
+	}
+	if filter.DateFrom == "" {
+		filter.DateFrom = to.Format("2006-01") + "-01"
+	}
+	from, err := time.Parse("2006-01-02", filter.DateFrom)
+	if err != nil {
+		return &model.ReportResponse{Message: fmt.Sprintf("неверная начальная дата: %v", err)}
+	}
+
+	response := s.repo.GetTurnover(filter)
+	if response.Model == nil {
+		return &model.ReportResponse{Message: response.Message}
+	}
+
+	period := fmt.Sprintf("за период с %s по %s", from.Format("02.01.2006"), to.Format("02.01.2006"))
+	w, err := newReport(filter.Format, "Оборотная ведомость", "Оборотная ведомость по оборудованию", s.subtitle(period, filter), []reportColumn{
+		{Title: "№", Width: 6},
+		{Title: "Наименование", Width: 32},
+		{Title: "Серийный номер", Width: 18},
+		{Title: "Цена", Width: 12, Format: cellMoney},
+		{Title: "Кол-во", Group: "Остаток на начало", Width: 9, Format: cellInt},
+		{Title: "Сумма", Group: "Остаток на начало", Width: 14, Format: cellMoney},
+		{Title: "Кол-во", Group: "Поступило", Width: 9, Format: cellInt},
+		{Title: "Сумма", Group: "Поступило", Width: 14, Format: cellMoney},
+		{Title: "Кол-во", Group: "Выбыло", Width: 9, Format: cellInt},
+		{Title: "Сумма", Group: "Выбыло", Width: 14, Format: cellMoney},
+		{Title: "Кол-во", Group: "Остаток на конец", Width: 9, Format: cellInt},
+		{Title: "Сумма", Group: "Остаток на конец", Width: 14, Format: cellMoney},
+	})
+	if err != nil {
+		return &model.ReportResponse{Message: err.Error()}
+	}
+
+	rows := response.Model
+	var total, locationTotal turnoverTotal
+	for i, row := range rows {
+		if i == 0 || row.Location != rows[i-1].Location {
+			w.group("Местоположение: " + orDefault(row.Location, "не указано"))
+			locationTotal = turnoverTotal{}
+		}
+
+		w.add(i+1, row.Name, row.SerialNumber, row.Price,
+			row.Opening, amount(row.Opening, row.Price),
+			row.Incoming, amount(row.Incoming, row.Price),
+			row.Outgoing, amount(row.Outgoing, row.Price),
+			row.Closing, amount(row.Closing, row.Price))
+		total.add(row)
+		locationTotal.add(row)
+
+		if i == len(rows)-1 || row.Location != rows[i+1].Location {
+			w.total("Итого по местоположению", locationTotal.values()...)
+		}
+	}
+	w.total("Всего", total.values()...)
+
+	return reportResponse(w)
+}
+
+// WrittenOff списанное оборудование за период по проведенным документам списания
+func (s *ReportService) WrittenOff(filter model.ReportFilter) *model.ReportResponse {
+	if _, err := s.session.Authorize("ReportService.WrittenOff"); err != nil {
+		return &model.ReportResponse{Message: err.Error()}
+	}
+
+	response := s.repo.GetWriteOffs(filter)
+	if response.Model == nil {
+		return &model.ReportResponse{Message: response.Message}
+	}
+
+	period := "за все время"
+	switch {
+	case filter.DateFrom != "" && filter.DateTo != "":
+		period = fmt.Sprintf("за период с %s по %s", reportDate(filter.DateFrom), reportDate(filter.DateTo))
+	case filter.DateFrom != "":
+		period = fmt.Sprintf("с %s", reportDate(filter.DateFrom))
+	case filter.DateTo != "":
+		period = fmt.Sprintf("по %s", reportDate(filter.DateTo))
+	}
+
+	w, err := newReport(filter.Format, "Списание", "Списанное оборудование", s.subtitle(period, filter), []reportColumn{
+		{Title: "№", Width: 6},
+		{Title: "Дата", Width: 12},
+		{Title: "Документ", Width: 16},
+		{Title: "Наименование", Width: 32},
+		{Title: "Серийный номер", Width: 18},
+		{Title: "Категория", Width: 20},
+		{Title: "Местоположение", Width: 20},
+		{Title: "Кол-во", Width: 9, Format: cellInt},
+		{Title: "Цена", Width: 13, Format: cellMoney},
+		{Title: "Сумма", Width: 15, Format: cellMoney},
+		{Title: "Основание", Width: 30},
+	})
+	if err != nil {
+		return &model.ReportResponse{Message: err.Error()}
+	}
+
+	var total stockTotal
+	for i, row := range response.Model {
+		sum := amount(row.Quantity, row.Price)
+		w.add(i+1, row.Date.Format("02.01.2006"), row.DocumentNumber, row.Name, row.SerialNumber,
+			row.Category, row.Location, row.Quantity, row.Price, sum, row.Reason)
+		total.add(row.Quantity, sum)
+	}
+	w.total("Всего", nil, nil, nil, nil, nil, nil, nil, total.quantity, nil, total.sum, nil)
+
+	return reportResponse(w)
+}
+
+// subtitle строки под заголовком отчета: период или дата, организация и фильтры
+func (s *ReportService) subtitle(period string, filter model.ReportFilter) []string {
+	lines := []string{period, organizationName(s.cfg.Organization.Name, s.cfg.Organization.INN, s.cfg.Organization.KPP)}
+
+	if filter.CategoryID != 0 {
+		if response := s.categories.GetCategory(int(filter.CategoryID)); response.Model != nil {
+			lines = append(lines, "Категория: "+response.Model.FullName)
+		}
+	}
+	if filter.LocationID != 0 {
+		if response := s.locations.GetLocation(int(filter.LocationID)); response.Model != nil {
+			lines = append(lines, "Местоположение: "+response.Model.FullName)
+		}
+	}
+	if filter.SupplierID != 0 {
+		if response := s.suppliers.GetSupplier(int(filter.SupplierID)); response.Model != nil {
+			lines = append(lines, "Поставщик: "+response.Model.Name)
+		}
+	}
+	if filter.Status != "" {
+		lines = append(lines, "Статус: "+orDefault(equipmentStatuses[filter.Status], filter.Status))
+	}
+	return lines
+}
+
+// reportWriter табличный отчет: строки, заголовки групп и строки итогов
+// печатаются по порядку, bytes возвращает готовый файл
+type reportWriter interface {
+	add(values ...interface{})
+	group(label string)
+	total(label string, values ...interface{})
+	bytes() ([]byte, error)
+}
+
+// newReport создает отчет в формате format: XLSX (по умолчанию) или PDF
+func newReport(format, sheet, title string, subtitle []string, columns []reportColumn) (reportWriter, error) {
+	switch format {
+	case "", model.ReportFormatXLSX:
+		return newXLSXReport(sheet, title, subtitle, columns), nil
+	case model.ReportFormatPDF:
+		return newPDFReport(title, subtitle, columns), nil
+	}
+	return nil, fmt.Errorf("неизвестный формат отчета: %s", format)
+}
+
+func reportResponse(w reportWriter) *model.ReportResponse {
+	content, err := w.bytes()
+	if err != nil {
+		return &model.ReportResponse{Message: err.Error()}
+	}
+	return &model.ReportResponse{
+		Content: base64.StdEncoding.EncodeToString(content),
+		Message: "Отчет успешно сформирован",
+	}
+}
+
+type stockTotal struct {
+	quantity int
+	sum      model.Money
+}
+
+func (t *stockTotal) add(quantity int, sum model.Money) {
+	t.quantity += quantity
+	t.sum += sum
+}
+
+type turnoverTotal struct {
+	opening, incoming, outgoing, closing stockTotal
+}
+
+func (t *turnoverTotal) add(row model.StockRow) {
+	t.opening.add(row.Opening, amount(row.Opening, row.Price))
+	t.incoming.add(row.Incoming, amount(row.Incoming, row.Price))
+	t.outgoing.add(row.Outgoing, amount(row.Outgoing, row.Price))
+	t.closing.add(row.Closing, amount(row.Closing, row.Price))
+}
+
+// values значения строки итогов оборотной ведомости
+func (t *turnoverTotal) values() []interface{} {
+	return []interface{}{nil, nil, nil, nil,
+		t.opening.quantity, t.opening.sum,
+		t.incoming.quantity, t.incoming.sum,
+		t.outgoing.quantity, t.outgoing.sum,
+		t.closing.quantity, t.closing.sum,
+	}
+}
+
+// amount стоимость количества по цене
+func amount(quantity int, price model.Money) model.Money {
+	return price.Mul(quantity)
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// reportDate переводит дату "2006-01-02" в формат отчета
+func reportDate(value string) string {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return value
+	}
+	return date.Format("02.01.2006")
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"tohaboy/internal/model"
+)
+
+// pdfReport табличный отчет в формате PDF с теми же столбцами, группами
+// и итогами, что и xlsxReport. Ширины столбцов распределяются по ширине
+// альбомного листа пропорционально ширинам столбцов XLSX, шапка таблицы
+// повторяется на каждой странице.
+type pdfReport struct {
+	w       *pdfWriter
+	columns []pdfColumn
+	groups  []string
+}
+
+// newPDFReport создает документ, печатает заголовок, строки подзаголовка
+// (пустые пропускаются) и шапку таблицы
+func newPDFReport(title string, subtitle []string, columns []reportColumn) *pdfReport {
+	w := newPDFWriter("L", fmt.Sprintf("%s, сформирован %s", title, time.Now().Format("02.01.2006 15:04")))
+	r := &pdfReport{w: w}
+
+	var widths float64
+	for _, column := range columns {
+		widths += column.Width
+	}
+	for _, column := range columns {
+		align := "L"
+		if column.Format != cellText {
+			align = "R"
+		}
+		r.columns = append(r.columns, pdfColumn{
+			Title: column.Title,
+			Width: column.Width / widths * w.contentWidth(),
+			Align: align,
+		})
+		r.groups = append(r.groups, column.Group)
+	}
+
+	w.title(title, 14)
+	for _, line := range subtitle {
+		if line != "" {
+			w.pdf.MultiCell(0, pdfLineHeight, line, "", "C", false)
+		}
+	}
+	w.space(4)
+	r.printHeader()
+	return r
+}
+
+// add печатает строку таблицы
+func (r *pdfReport) add(values ...interface{}) {
+	r.row(r.columns, r.cells(values), false, "")
+}
+
+// group печатает заголовок группы строк на всю ширину таблицы
+func (r *pdfReport) group(label string) {
+	r.row([]pdfColumn{{Width: r.w.contentWidth(), Align: "L"}}, []string{label}, true, "B")
+}
+
+// total печатает строку итогов: подпись в первых столбцах до первого
+// непустого значения, суммы - в своих столбцах
+func (r *pdfReport) total(label string, values ...interface{}) {
+	span := 1
+	for span < len(values) && values[span] == nil {
+		span++
+	}
+
+	labelColumn := pdfColumn{Align: "L"}
+	for _, column := range r.columns[:span] {
+		labelColumn.Width += column.Width
+	}
+	columns := append([]pdfColumn{labelColumn}, r.columns[span:]...)
+	cells := append([]string{label}, r.cells(values)[span:]...)
+	r.row(columns, cells, false, "B")
+}
+
+// bytes возвращает содержимое PDF-файла
+func (r *pdfReport) bytes() ([]byte, error) {
+	return r.w.bytes()
+}
+
+// printHeader печатает шапку таблицы. Если у столбцов есть группы, шапка
+// двухстрочная, как в XLSX: над столбцами группы - ее общий заголовок,
+// остальные столбцы занимают обе строки.
+func (r *pdfReport) printHeader() {
+	pdf := r.w.pdf
+	pdf.SetFont(pdfFont, "B", 9)
+	defer pdf.SetFont(pdfFont, "", pdfFontSize)
+
+	lineHeight := pdfLineHeight * 0.9
+	height := func(text string, width float64) float64 {
+		return float64(len(pdf.SplitText(text, width-2)))*lineHeight + 2
+	}
+	var groupHeight, titleHeight, fullHeight float64
+	for i, column := range r.columns {
+		if r.groups[i] == "" {
+			fullHeight = math.Max(fullHeight, height(column.Title, column.Width))
+			continue
+		}
+		groupHeight = math.Max(groupHeight, height(r.groups[i], r.groupWidth(i)))
+		titleHeight = math.Max(titleHeight, height(column.Title, column.Width))
+	}
+	if groupHeight+titleHeight < fullHeight {
+		titleHeight = fullHeight - groupHeight
+	}
+	fullHeight = groupHeight + titleHeight
+
+	x, y := pdf.GetXY()
+	pdf.SetFillColor(230, 230, 230)
+	for i, column := range r.columns {
+		switch {
+		case r.groups[i] == "":
+			r.headerCell(x, y, column.Width, fullHeight, column.Title)
+		default:
+			if i == 0 || r.groups[i-1] != r.groups[i] {
+				r.headerCell(x, y, r.groupWidth(i), groupHeight, r.groups[i])
+			}
+			r.headerCell(x, y+groupHeight, column.Width, titleHeight, column.Title)
+		}
+		x += column.Width
+	}
+
+	left, _, _, _ := pdf.GetMargins()
+	pdf.SetXY(left, y+fullHeight)
+}
+
+// groupWidth общая ширина столбцов группы, начинающейся со столбца first
+func (r *pdfReport) groupWidth(first int) float64 {
+	width := 0.0
+	for i := first; i < len(r.columns) && r.groups[i] == r.groups[first]; i++ {
+		width += r.columns[i].Width
+	}
+	return width
+}
+
+// headerCell печатает ячейку шапки с текстом по центру
+func (r *pdfReport) headerCell(x, y, width, height float64, text string) {
+	pdf := r.w.pdf
+	lineHeight := pdfLineHeight * 0.9
+	pdf.Rect(x, y, width, height, "FD")
+
+	lines := pdf.SplitText(text, width-2)
+	top := y + (height-float64(len(lines))*lineHeight)/2
+	for n, line := range lines {
+		pdf.SetXY(x+1, top+float64(n)*lineHeight)
+		pdf.CellFormat(width-2, lineHeight, line, "", 0, "C", false, 0, "")
+	}
+}
+
+// row печатает строку таблицы шрифтом style, при переходе на новую страницу
+// сначала повторяется шапка
+func (r *pdfReport) row(columns []pdfColumn, cells []string, fill bool, style string) {
+	r.w.pdf.SetFont(pdfFont, style, 9)
+	height := r.w.rowHeight(columns, cells)
+	if r.w.ensureSpace(height) {
+		r.printHeader()
+		r.w.pdf.SetFont(pdfFont, style, 9)
+	}
+	r.w.tableRow(columns, cells, fill, "")
+	r.w.pdf.SetFont(pdfFont, "", pdfFontSize)
+}
+
+// cells значения строки в виде текста ячеек
+func (r *pdfReport) cells(values []interface{}) []string {
+	cells := make([]string, len(r.columns))
+	for i := range cells {
+		if i >= len(values) || values[i] == nil {
+			continue
+		}
+		switch value := values[i].(type) {
+		case model.Money:
+			cells[i] = formatAmount(value)
+		case int:
+			cells[i] = strconv.Itoa(value)
+		case string:
+			cells[i] = value
+		default:
+			cells[i] = fmt.Sprint(value)
+		}
+	}
+	return cells
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+	"unicode"
+)
+
+// Префиксы содержимого QR-кодов на этикетках и печатных формах
+const (
+	scanEquipmentPrefix = "EQ:"
+	scanDocumentPrefix  = "DOC:"
+)
+
+// errScanNotFound код не соответствует никакому оборудованию
+var errScanNotFound = errors.New("не найдено")
+
+// scanLayout переводит символы, набранные сканером в русской раскладке клавиатуры,
+// в символы тех же клавиш латинской раскладки: сканер в режиме эмуляции
+// клавиатуры передает нажатия клавиш, а не символы
+var scanLayout = strings.NewReplacer(
+	"й", "q", "ц", "w", "у", "e", "к", "r", "е", "t", "н", "y", "г", "u", "ш", "i", "щ", "o", "з", "p", "х", "[", "ъ", "]",
+	"ф", "a", "ы", "s", "в", "d", "а", "f", "п", "g", "р", "h", "о", "j", "л", "k", "д", "l", "ж", ";", "э", "'",
+	"я", "z", "ч", "x", "с", "c", "м", "v", "и", "b", "т", "n", "ь", "m", "б", ",", "ю", ".",
+	"Й", "Q", "Ц", "W", "У", "E", "К", "R", "Е", "T", "Н", "Y", "Г", "U", "Ш", "I", "Щ", "O", "З", "P", "Х", "{", "Ъ", "}",
+	"Ф", "A", "Ы", "S", "В", "D", "А", "F", "П", "G", "Р", "H", "О", "J", "Л", "K", "Д", "L", "Ж", ":", "Э", "\"",
+	"Я", "Z", "Ч", "X", "С", "C", "М", "V", "И", "B", "Т", "N", "Ь", "M", "Б", "<", "Ю", ">",
+)
+
+// ScanService находит оборудование по отсканированным штрихкодам и QR-кодам
+// этикеток и добавляет его в черновики перемещения и инвентаризации
+type ScanService struct {
+	repo      repository.ScanRepositoryInterface
+	documents repository.DocumentRepositoryInterface
+	inventory repository.InventoryRepositoryInterface
+	session   *Session
+}
+
+func NewScanService(
+	repo repository.ScanRepositoryInterface,
+	documents repository.DocumentRepositoryInterface,
+	inventory repository.InventoryRepositoryInterface,
+	session *Session,
+) *ScanService {
+	return &ScanService{repo: repo, documents: documents, inventory: inventory, session: session}
+}
+
+// Resolve возвращает оборудование по коду: содержимому QR-кода этикетки,
+// инвентарному или серийному номеру
+func (s *ScanService) Resolve(code string) *model.ScanResponse {
+	if _, err := s.session.Authorize("ScanService.Resolve"); err != nil {
+		return &model.ScanResponse{Message: err.Error()}
+	}
+
+	codes := splitScanCodes(code)
+	if len(codes) == 0 {
+		return &model.ScanResponse{Message: "код не указан"}
+	}
+
+	result, err := s.resolve(codes[0])
+	if err != nil {
+		return &model.ScanResponse{Message: err.Error()}
+	}
+	return &model.ScanResponse{
+		Model: result,
+	}
+}
+
+// ScanToDocument добавляет отсканированное оборудование в черновик документа:
+// в перемещение - по единице на каждое сканирование, в инвентаризацию - в
+// фактическое количество. Коды можно передавать списком или одной строкой,
+// разделенной переводами строк, как их вводит сканер. Коды, по которым
+// оборудование не найдено, попадают в Unknown, а остальные ошибки (код
+// документа, ошибка базы, отказ в добавлении позиции) - в Errors с причиной;
+// ни то, ни другое не прерывает обработку остальных кодов.
+func (s *ScanService) ScanToDocument(documentID uint, codes []string) *model.ScanBatchResponse {
+	if _, err := s.session.Authorize("ScanService.ScanToDocument"); err != nil {
+		return &model.ScanBatchResponse{Message: err.Error()}
+	}
+
+	response := s.documents.GetDocument(documentID)
+	if response.Model == nil {
+		return &model.ScanBatchResponse{Message: response.Message}
+	}
+	doc := response.Model
+	if doc.Status != "draft" {
+		return &model.ScanBatchResponse{Message: "добавлять позиции можно только в черновик"}
+	}
+	if doc.Type != "transfer" && doc.Type != "inventory" {
+		return &model.ScanBatchResponse{Message: "сканирование поддерживается для перемещения и инвентаризации"}
+	}
+
+	batch := &model.ScanBatch{
+		Unknown: []string{},
+		Errors:  []string{},
+	}
+	for _, code := range splitScanCodes(codes...) {
+		result, err := s.resolve(code)
+		if errors.Is(err, errScanNotFound) {
+			batch.Unknown = append(batch.Unknown, code)
+			continue
+		}
+		if err != nil {
+			batch.Errors = append(batch.Errors, fmt.Sprintf("%s: %v", code, err))
+			continue
+		}
+
+		message := ""
+		if doc.Type == "transfer" {
+			message = s.repo.AddTransferItem(doc.ID, result.Equipment.ID).Message
+		} else {
+			message = s.inventory.CountItem(doc.ID, result.Equipment.ID, 1, true).Message
+		}
+		if message != "" {
+			batch.Errors = append(batch.Errors, fmt.Sprintf("%s (%s): %s", code, result.Equipment.Name, message))
+			continue
+		}
+		batch.Added++
+	}
+
+	response = s.documents.GetDocument(documentID)
+	if response.Model == nil {
+		return &model.ScanBatchResponse{Message: response.Message}
+	}
+	batch.Document = response.Model
+
+	return &model.ScanBatchResponse{
+		Model:   batch,
+		Message: fmt.Sprintf("Добавлено: %d, не найдено: %d, ошибок: %d", batch.Added, len(batch.Unknown), len(batch.Errors)),
+	}
+}
+
+// resolve ищет оборудование по очищенному коду. Если код не найден, он повторяется
+// в латинской раскладке на случай сканера, работающего в русской. Ненайденный
+// код возвращает ошибку errScanNotFound.
+func (s *ScanService) resolve(code string) (*model.ScanResult, error) {
+	candidates := []string{code}
+	if latin := scanLayout.Replace(code); latin != code {
+		candidates = append(candidates, latin)
+	}
+
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, scanDocumentPrefix) {
+			return nil, fmt.Errorf("отсканирован код документа № %s, а не оборудования", strings.TrimPrefix(candidate, scanDocumentPrefix))
+		}
+
+		response := s.repo.FindEquipmentByCode(strings.TrimPrefix(candidate, scanEquipmentPrefix))
+		if response.Message != "" {
+			return nil, fmt.Errorf("%s", response.Message)
+		}
+		if response.Model != nil {
+			return &model.ScanResult{Code: candidate, Equipment: response.Model}, nil
+		}
+	}
+	return nil, fmt.Errorf("оборудование с кодом %s %w", code, errScanNotFound)
+}
+
+// splitScanCodes разбивает ввод сканера на коды: сканер завершает каждый код
+// переводом строки или табуляцией. Пробелы по краям и управляющие символы удаляются.
+func splitScanCodes(input ...string) []string {
+	var codes []string
+	for _, text := range input {
+		for _, line := range strings.FieldsFunc(text, func(r rune) bool {
+			return r == '\n' || r == '\r' || r == '\t'
+		}) {
+			code := strings.TrimSpace(strings.Map(func(r rune) rune {
+				if unicode.IsControl(r) {
+					return -1
+				}
+				return r
+			}, line))
+			if code != "" {
+				codes = append(codes, code)
+			}
+		}
+	}
+	return codes
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+type SearchService struct {
+	repo    repository.SearchRepositoryInterface
+	session *Session
+}
+
+func NewSearchService(repo repository.SearchRepositoryInterface, session *Session) *SearchService {
+	return &SearchService{repo: repo, session: session}
+}
+
+// Search ищет оборудование, документы, поставщиков и местоположения по тексту
+func (s *SearchService) Search(query model.SearchQuery) *model.SearchResponse {
+	if _, err := s.session.Authorize("SearchService.Search"); err != nil {
+		return &model.SearchResponse{Message: err.Error()}
+	}
+
+	response := s.repo.Search(query)
+	return &model.SearchResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"tohaboy/internal/config"
 	"tohaboy/internal/model"
 	"tohaboy/internal/repository"
 )
@@ -8,6 +9,9 @@ import (
 type AuthServiceInterface interface {
 	Login(user map[string]string) (*model.LoginResponse, error)
 	Register(user map[string]string) (*model.User, error)
+	SignupAllowed() bool
+	Authenticate(token string) (*model.User, error)
+	Logout()
 }
 
 type UserServiceInterface interface {
@@ -18,16 +22,22 @@ type EquipmentServiceInterface interface {
 	CreateEquipment(equipment *model.Equipment) *model.EquipmentResponse
 	GetEquipment(id int) *model.EquipmentResponse
 	GetAllEquipment() *model.EquipmentListResponse
+	ListEquipment(query model.ListQuery) *model.EquipmentPageResponse
 	UpdateEquipment(equipment *model.Equipment) *model.EquipmentResponse
 	DeleteEquipment(id int) *model.EquipmentResponse
 	GetEquipmentByLocation(locationID int) *model.EquipmentListResponse
 	GetEquipmentBySupplier(supplierID int) *model.EquipmentListResponse
+	IssueEquipment(id uint, reason string) *model.EquipmentResponse
+	ReturnEquipment(id uint, reason string) *model.EquipmentResponse
+	SendToRepair(id uint, reason string) *model.EquipmentResponse
+	GetStatusHistory(id uint) *model.EquipmentStatusHistoryResponse
 }
 
 type SupplierServiceInterface interface {
 	CreateSupplier(supplier *model.Supplier) *model.SupplierResponse
 	GetSupplier(id int) *model.SupplierResponse
 	GetAllSuppliers() *model.SupplierListResponse
+	ListSuppliers(query model.ListQuery) *model.SupplierPageResponse
 	UpdateSupplier(supplier *model.Supplier) *model.SupplierResponse
 	DeleteSupplier(id int) *model.SupplierResponse
 	GetSupplierByEquipment(equipmentID int) *model.SupplierListResponse
@@ -40,12 +50,16 @@ type LocationServiceInterface interface {
 	UpdateLocation(location *model.Location) *model.LocationResponse
 	DeleteLocation(id int) *model.LocationResponse
 	GetLocationByEquipment(equipmentID int) *model.LocationListResponse
+	MoveLocation(id, parentID uint) *model.LocationResponse
+	GetLocationPath(id int) *model.LocationListResponse
+	GetLocationTree() *model.LocationTreeResponse
 }
 
 type MovementServiceInterface interface {
 	CreateMovement(movement *model.Movement) *model.MovementResponse
 	GetMovement(id uint) *model.MovementResponse
 	GetAllMovements() *model.MovementListResponse
+	ListMovements(query model.ListQuery) *model.MovementPageResponse
 	UpdateMovement(movement *model.Movement) *model.MovementResponse
 	DeleteMovement(id uint) *model.MovementResponse
 	GetMovementsByEquipment(equipmentID uint) *model.MovementListResponse
@@ -56,9 +70,12 @@ type DocumentServiceInterface interface {
 	CreateDocument(doc *model.Document) *model.DocumentResponse
 	GetDocument(id uint) *model.DocumentResponse
 	GetAllDocuments() *model.DocumentListResponse
+	ListDocuments(query model.ListQuery) *model.DocumentPageResponse
 	UpdateDocument(doc *model.Document) *model.DocumentResponse
 	DeleteDocument(id uint) *model.DocumentResponse
 	ApproveDocument(id uint, approvedByID uint) *model.DocumentResponse
+	CancelDocument(id uint, userID uint, reason string) *model.DocumentResponse
+	ReverseDocument(id uint, userID uint, reason string) *model.DocumentResponse
 	ExportDocument(id uint) *model.DocumentExportResponse
 }
 
@@ -70,27 +87,125 @@ type CategoryServiceInterface interface {
 	DeleteCategory(id int) *model.CategoryResponse
 }
 
+type EmployeeServiceInterface interface {
+	CreateEmployee(employee *model.Employee) *model.EmployeeResponse
+	GetEmployee(id uint) *model.EmployeeResponse
+	GetAllEmployees() *model.EmployeeListResponse
+	UpdateEmployee(employee *model.Employee) *model.EmployeeResponse
+	DeleteEmployee(id uint) *model.EmployeeResponse
+	AssignEquipment(equipmentID uint, employeeID uint) *model.MovementResponse
+}
+
+type AuditServiceInterface interface {
+	GetAuditEntries(filter model.AuditFilter) *model.AuditListResponse
+	GetEntityHistory(entity string, id uint) *model.AuditListResponse
+}
+
+type SearchServiceInterface interface {
+	Search(query model.SearchQuery) *model.SearchResponse
+}
+
+type ReportServiceInterface interface {
+	EquipmentRegister(filter model.ReportFilter) *model.ReportResponse
+	StockBalance(filter model.ReportFilter) *model.ReportResponse
+	Turnover(filter model.ReportFilter) *model.ReportResponse
+	WrittenOff(filter model.ReportFilter) *model.ReportResponse
+}
+
+type ImportServiceInterface interface {
+	PreviewImport(request model.ImportRequest) *model.ImportPreviewResponse
+	CommitImport(request model.ImportRequest) *model.ImportResultResponse
+}
+
+type InventoryServiceInterface interface {
+	StartInventory(doc *model.Document) *model.DocumentResponse
+	CountItem(documentID uint, equipmentID uint, quantity int) *model.DocumentResponse
+	ScanItem(documentID uint, equipmentID uint) *model.DocumentResponse
+	GetDiscrepancies(documentID uint) *model.InventoryDiscrepanciesResponse
+	ExportStatement(documentID uint) *model.DocumentExportResponse
+}
+
+type LabelServiceInterface interface {
+	PrintLabels(request model.LabelRequest) *model.LabelSheetResponse
+}
+
+type ScanServiceInterface interface {
+	Resolve(code string) *model.ScanResponse
+	ScanToDocument(documentID uint, codes []string) *model.ScanBatchResponse
+}
+
+type MaintenanceServiceInterface interface {
+	CreateMaintenance(record *model.MaintenanceRecord) *model.MaintenanceResponse
+	CompleteMaintenance(record *model.MaintenanceRecord) *model.MaintenanceResponse
+	UpdateMaintenance(record *model.MaintenanceRecord) *model.MaintenanceResponse
+	GetMaintenance(id uint) *model.MaintenanceResponse
+	GetAllMaintenance() *model.MaintenanceListResponse
+	GetMaintenanceByEquipment(equipmentID uint) *model.MaintenanceListResponse
+	GetOverdueMaintenance() *model.MaintenanceDueResponse
+}
+
+type DepreciationServiceInterface interface {
+	RunDepreciation(period string) *model.DepreciationRunResponse
+	CancelDepreciation(id uint) *model.DepreciationRunResponse
+	GetDepreciationRun(id uint) *model.DepreciationRunResponse
+	GetDepreciationRuns() *model.DepreciationRunListResponse
+	ExportSchedule(filter model.ReportFilter) *model.ReportResponse
+}
+
+type ExchangeRateServiceInterface interface {
+	SaveRate(rate *model.ExchangeRate) *model.ExchangeRateResponse
+	DeleteRate(id uint) *model.ExchangeRateResponse
+	GetRates(currency string) *model.ExchangeRateListResponse
+	GetRate(currency string, date string) *model.ExchangeRateResponse
+	ImportCBR(content string) *model.ExchangeRateListResponse
+}
+
 type Service struct {
 	AuthServiceInterface
-	UserService      UserServiceInterface
-	EquipmentService EquipmentServiceInterface
-	SupplierService  SupplierServiceInterface
-	LocationService  LocationServiceInterface
-	MovementService  MovementServiceInterface
-	DocumentService  DocumentServiceInterface
-	CategoryService  CategoryServiceInterface
+	UserService         UserServiceInterface
+	EquipmentService    EquipmentServiceInterface
+	SupplierService     SupplierServiceInterface
+	LocationService     LocationServiceInterface
+	MovementService     MovementServiceInterface
+	DocumentService     DocumentServiceInterface
+	CategoryService     CategoryServiceInterface
+	EmployeeService     EmployeeServiceInterface
+	AuditService        AuditServiceInterface
+	SearchService       SearchServiceInterface
+	ImportService       ImportServiceInterface
+	ReportService       ReportServiceInterface
+	InventoryService    InventoryServiceInterface
+	LabelService        LabelServiceInterface
+	ScanService         ScanServiceInterface
+	MaintenanceService  MaintenanceServiceInterface
+	DepreciationService DepreciationServiceInterface
+	ExchangeRateService ExchangeRateServiceInterface
+	Session             *Session
 }
 
-func NewService(repos *repository.Repository) *Service {
-	docService := NewDocumentService(repos.Document)
+func NewService(repos *repository.Repository, cfg *config.Config) *Service {
+	session := NewSession(repos.User, cfg.Auth)
+	docService := NewDocumentService(repos.Document, session, cfg)
 	return &Service{
-		AuthServiceInterface: NewAuthService(repos.AuthRepositoryInterface),
-		UserService:          NewUserService(repos.User),
-		EquipmentService:     NewEquipmentService(repos.Equipment),
-		SupplierService:      NewSupplierService(repos.Supplier),
-		LocationService:      NewLocationService(repos.Location),
-		MovementService:      NewMovementService(repos.Movement, docService),
+		AuthServiceInterface: NewAuthService(repos.AuthRepositoryInterface, session, cfg),
+		UserService:          NewUserService(repos.User, session),
+		EquipmentService:     NewEquipmentService(repos.Equipment, session),
+		SupplierService:      NewSupplierService(repos.Supplier, session),
+		LocationService:      NewLocationService(repos.Location, session),
+		MovementService:      NewMovementService(repos.Movement, session),
 		DocumentService:      docService,
-		CategoryService:      NewCategoryService(repos.Category),
+		CategoryService:      NewCategoryService(repos.Category, session),
+		EmployeeService:      NewEmployeeService(repos.Employee, session),
+		AuditService:         NewAuditService(repos.Audit, session),
+		SearchService:        NewSearchService(repos.Search, session),
+		ImportService:        NewImportService(repos.Import, repos.Category, repos.Supplier, repos.Location, session),
+		ReportService:        NewReportService(repos.Report, repos.Category, repos.Supplier, repos.Location, session, cfg),
+		InventoryService:     NewInventoryService(repos.Inventory, repos.Document, docService, session, cfg),
+		LabelService:         NewLabelService(repos.Equipment, session),
+		ScanService:          NewScanService(repos.Scan, repos.Document, repos.Inventory, session),
+		MaintenanceService:   NewMaintenanceService(repos.Maintenance, session),
+		DepreciationService:  NewDepreciationService(repos.Depreciation, repos.Category, docService, session, cfg),
+		ExchangeRateService:  NewExchangeRateService(repos.Rates, session),
+		Session:              session,
 	}
 }
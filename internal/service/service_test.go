@@ -0,0 +1,52 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+	"tohaboy/internal/storage"
+
+	"gorm.io/gorm"
+)
+
+// serviceFixture настройки по умолчанию и база приложения во временном каталоге
+type serviceFixture struct {
+	cfg     *config.Config
+	db      *gorm.DB
+	session *Session
+}
+
+func newServiceFixture(t *testing.T) *serviceFixture {
+	t.Helper()
+	cfg, err := config.Load(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	s := storage.NewStorage(cfg.Database.Path)
+	if err := s.MigrateUp(); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	db := s.GetDB()
+	return &serviceFixture{
+		cfg:     cfg,
+		db:      db,
+		session: NewSession(repository.NewUserRepository(db), cfg.Auth),
+	}
+}
+
+// login открывает сессию пользователя с ролью role, создавая его при первом входе
+func (f *serviceFixture) login(t *testing.T, role string) *model.User {
+	t.Helper()
+	user := model.User{Username: role, Role: role}
+	if err := f.db.Where(model.User{Username: role}).FirstOrCreate(&user).Error; err != nil {
+		t.Fatalf("создание пользователя: %v", err)
+	}
+	token, err := f.session.issueToken(user.ID)
+	if err != nil {
+		t.Fatalf("issueToken: %v", err)
+	}
+	f.session.Start(token)
+	return &user
+}
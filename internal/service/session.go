@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnauthorized = errors.New(model.MessageUnauthorized)
+	ErrForbidden    = errors.New(model.MessageForbidden)
+)
+
+// Session хранит токен пользователя, вошедшего в приложение.
+// Приложение однооконное, поэтому сессия одна на процесс: её открывает
+// AuthService.Login/Authenticate, а все остальные сервисы проверяют её
+// перед выполнением метода.
+type Session struct {
+	mu     sync.RWMutex
+	token  string
+	users  repository.UserRepositoryInterface
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewSession(users repository.UserRepositoryInterface, auth config.AuthConfig) *Session {
+	return &Session{
+		users:  users,
+		secret: []byte(auth.SecretKey),
+		ttl:    auth.TokenTTL,
+	}
+}
+
+// Start открывает сессию с уже проверенным токеном
+func (s *Session) Start(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// End закрывает текущую сессию
+func (s *Session) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
+
+// CurrentUser проверяет токен сессии и возвращает актуальную запись пользователя
+func (s *Session) CurrentUser() (*model.User, error) {
+	s.mu.RLock()
+	token := s.token
+	s.mu.RUnlock()
+
+	if token == "" {
+		return nil, ErrUnauthorized
+	}
+
+	userID, err := s.parseToken(token)
+	if err != nil {
+		log.Printf("[service] invalid session token: %v", err)
+		return nil, ErrUnauthorized
+	}
+
+	response := s.users.GetByID(int(userID))
+	if response.Model == nil {
+		return nil, ErrUnauthorized
+	}
+
+	user := response.Model
+	user.Password = ""
+	return user, nil
+}
+
+// UserID возвращает ID пользователя сессии без обращения к БД (0 - нет сессии).
+// Используется журналом аудита для указания автора изменений.
+func (s *Session) UserID() uint {
+	s.mu.RLock()
+	token := s.token
+	s.mu.RUnlock()
+
+	if token == "" {
+		return 0
+	}
+	userID, err := s.parseToken(token)
+	if err != nil {
+		return 0
+	}
+	return userID
+}
+
+// Authorize проверяет, что текущий пользователь может вызвать метод сервиса.
+// Метод задается в виде "EquipmentService.DeleteEquipment" и ищется в матрице прав.
+func (s *Session) Authorize(method string) (*model.User, error) {
+	user, err := s.CurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasPermission(user.Role, method) {
+		log.Printf("[service] access denied: user %q (%s) -> %s", user.Username, user.Role, method)
+		return nil, ErrForbidden
+	}
+
+	return user, nil
+}
+
+// issueToken подписывает токен пользователя на срок, заданный в настройках
+func (s *Session) issueToken(userID uint) (string, error) {
+	claims := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    strconv.FormatUint(uint64(userID), 10),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.ttl)),
+	})
+	return claims.SignedString(s.secret)
+}
+
+// parseToken проверяет подпись и срок действия токена и возвращает ID пользователя
+func (s *Session) parseToken(tokenString string) (uint, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return 0, err
+	}
+
+	userID, err := strconv.ParseUint(claims.Issuer, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token issuer: %v", err)
+	}
+	return uint(userID), nil
+}
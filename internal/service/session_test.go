@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+	"tohaboy/internal/config"
+	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
+)
+
+func TestSessionCurrentUser(t *testing.T) {
+	f := newServiceFixture(t)
+	users := repository.NewUserRepository(f.db)
+	user := f.login(t, model.RoleManager)
+
+	current, err := f.session.CurrentUser()
+	if err != nil {
+		t.Fatalf("CurrentUser: %v", err)
+	}
+	if current.ID != user.ID || current.Password != "" {
+		t.Errorf("пользователь сессии %+v", current)
+	}
+	if got := f.session.UserID(); got != user.ID {
+		t.Errorf("UserID = %d, ожидалось %d", got, user.ID)
+	}
+
+	tests := []struct {
+		name  string
+		token func() string
+	}{
+		{"истекший токен", func() string {
+			expired := NewSession(users, config.AuthConfig{SecretKey: f.cfg.Auth.SecretKey, TokenTTL: -time.Minute})
+			token, _ := expired.issueToken(user.ID)
+			return token
+		}},
+		{"чужой ключ подписи", func() string {
+			foreign := NewSession(users, config.AuthConfig{SecretKey: f.cfg.Auth.SecretKey + "x", TokenTTL: time.Hour})
+			token, _ := foreign.issueToken(user.ID)
+			return token
+		}},
+		{"неизвестный пользователь", func() string {
+			token, _ := f.session.issueToken(999)
+			return token
+		}},
+		{"не токен", func() string { return "token" }},
+	}
+	for _, tt := range tests {
+		f.session.Start(tt.token())
+		if _, err := f.session.CurrentUser(); err != ErrUnauthorized {
+			t.Errorf("%s: %v, ожидалось %v", tt.name, err, ErrUnauthorized)
+		}
+	}
+
+	// Удаленный пользователь теряет доступ и с действующим токеном
+	f.login(t, model.RoleManager)
+	if err := f.db.Delete(&model.User{}, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.session.Authorize("EquipmentService.GetEquipment"); err != ErrUnauthorized {
+		t.Errorf("удаленный пользователь: %v, ожидалось %v", err, ErrUnauthorized)
+	}
+
+	f.session.End()
+	if got := f.session.UserID(); got != 0 {
+		t.Errorf("UserID после выхода = %d", got)
+	}
+}
@@ -6,14 +6,19 @@ import (
 )
 
 type SupplierService struct {
-	repo repository.SupplierRepositoryInterface
+	repo    repository.SupplierRepositoryInterface
+	session *Session
 }
 
-func NewSupplierService(repo repository.SupplierRepositoryInterface) *SupplierService {
-	return &SupplierService{repo: repo}
+func NewSupplierService(repo repository.SupplierRepositoryInterface, session *Session) *SupplierService {
+	return &SupplierService{repo: repo, session: session}
 }
 
 func (s *SupplierService) CreateSupplier(supplier *model.Supplier) *model.SupplierResponse {
+	if _, err := s.session.Authorize("SupplierService.CreateSupplier"); err != nil {
+		return &model.SupplierResponse{Message: err.Error()}
+	}
+
 	response := s.repo.CreateSupplier(supplier)
 	return &model.SupplierResponse{
 		Model:   response.Model,
@@ -22,6 +27,10 @@ func (s *SupplierService) CreateSupplier(supplier *model.Supplier) *model.Suppli
 }
 
 func (s *SupplierService) GetSupplier(id int) *model.SupplierResponse {
+	if _, err := s.session.Authorize("SupplierService.GetSupplier"); err != nil {
+		return &model.SupplierResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetSupplier(id)
 	return &model.SupplierResponse{
 		Model:   response.Model,
@@ -30,6 +39,10 @@ func (s *SupplierService) GetSupplier(id int) *model.SupplierResponse {
 }
 
 func (s *SupplierService) GetAllSuppliers() *model.SupplierListResponse {
+	if _, err := s.session.Authorize("SupplierService.GetAllSuppliers"); err != nil {
+		return &model.SupplierListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetAllSuppliers()
 	return &model.SupplierListResponse{
 		Model:   response.Model,
@@ -37,7 +50,24 @@ func (s *SupplierService) GetAllSuppliers() *model.SupplierListResponse {
 	}
 }
 
+// ListSuppliers возвращает страницу списка с учетом фильтров и сортировки
+func (s *SupplierService) ListSuppliers(query model.ListQuery) *model.SupplierPageResponse {
+	if _, err := s.session.Authorize("SupplierService.ListSuppliers"); err != nil {
+		return &model.SupplierPageResponse{Message: err.Error()}
+	}
+
+	response := s.repo.ListSuppliers(query)
+	return &model.SupplierPageResponse{
+		Model:   response.Model,
+		Message: response.Message,
+	}
+}
+
 func (s *SupplierService) UpdateSupplier(supplier *model.Supplier) *model.SupplierResponse {
+	if _, err := s.session.Authorize("SupplierService.UpdateSupplier"); err != nil {
+		return &model.SupplierResponse{Message: err.Error()}
+	}
+
 	response := s.repo.UpdateSupplier(supplier)
 	return &model.SupplierResponse{
 		Model:   response.Model,
@@ -46,6 +76,10 @@ func (s *SupplierService) UpdateSupplier(supplier *model.Supplier) *model.Suppli
 }
 
 func (s *SupplierService) DeleteSupplier(id int) *model.SupplierResponse {
+	if _, err := s.session.Authorize("SupplierService.DeleteSupplier"); err != nil {
+		return &model.SupplierResponse{Message: err.Error()}
+	}
+
 	response := s.repo.DeleteSupplier(id)
 	return &model.SupplierResponse{
 		Model:   response.Model,
@@ -54,6 +88,10 @@ func (s *SupplierService) DeleteSupplier(id int) *model.SupplierResponse {
 }
 
 func (s *SupplierService) GetSupplierByEquipment(equipmentID int) *model.SupplierListResponse {
+	if _, err := s.session.Authorize("SupplierService.GetSupplierByEquipment"); err != nil {
+		return &model.SupplierListResponse{Message: err.Error()}
+	}
+
 	response := s.repo.GetSupplierByEquipment(equipmentID)
 	return &model.SupplierListResponse{
 		Model:   response.Model,
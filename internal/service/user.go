@@ -6,35 +6,63 @@ import (
 )
 
 type UserService struct {
-	repo repository.UserRepositoryInterface
+	repo    repository.UserRepositoryInterface
+	session *Session
 }
 
-func NewUserService(repo repository.UserRepositoryInterface) *UserService {
-	return &UserService{repo: repo}
+func NewUserService(repo repository.UserRepositoryInterface, session *Session) *UserService {
+	return &UserService{repo: repo, session: session}
 }
 
 func (s *UserService) GetUser(username string) model.Response[*model.User] {
+	if _, err := s.session.Authorize("UserService.GetUser"); err != nil {
+		return model.Response[*model.User]{Message: err.Error()}
+	}
+
 	return s.repo.GetUser(username)
 }
 
 func (s *UserService) GetCurrentUser() model.Response[*model.User] {
-	// В данной реализации возвращаем админа, так как у нас пока нет сессий
-	return s.repo.GetUser("admin")
+	user, err := s.session.Authorize("UserService.GetCurrentUser")
+	if err != nil {
+		return model.Response[*model.User]{Message: err.Error()}
+	}
+
+	return model.Response[*model.User]{
+		Message: "Пользователь успешно получен",
+		Model:   user,
+	}
 }
 
 func (s *UserService) GetByID(id int) model.Response[*model.User] {
+	if _, err := s.session.Authorize("UserService.GetByID"); err != nil {
+		return model.Response[*model.User]{Message: err.Error()}
+	}
+
 	return s.repo.GetByID(id)
 }
 
 func (s *UserService) GetByName(name string) model.Response[*model.User] {
+	if _, err := s.session.Authorize("UserService.GetByName"); err != nil {
+		return model.Response[*model.User]{Message: err.Error()}
+	}
+
 	return s.repo.GetUser(name)
 }
 
 func (s *UserService) Update(user *model.User) model.Response[*model.User] {
+	if _, err := s.session.Authorize("UserService.Update"); err != nil {
+		return model.Response[*model.User]{Message: err.Error()}
+	}
+
 	return s.repo.Update(user)
 }
 
 func (s *UserService) Delete(id int) model.Response[*model.User] {
+	if _, err := s.session.Authorize("UserService.Delete"); err != nil {
+		return model.Response[*model.User]{Message: err.Error()}
+	}
+
 	return s.repo.Delete(id)
 }
 
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"tohaboy/internal/model"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Форматы значений в столбцах отчета
+const (
+	cellText  = ""
+	cellInt   = "int"
+	cellMoney = "money"
+)
+
+// reportColumn столбец отчета. Столбцы с одинаковым Group идут подряд
+// и объединяются общим заголовком над своими.
+type reportColumn struct {
+	Title  string
+	Group  string
+	Width  float64
+	Format string
+}
+
+// xlsxReport табличный отчет на одном листе: заголовок, шапка таблицы,
+// строки, группы с промежуточными итогами. Первая ошибка excelize
+// запоминается и возвращается из bytes.
+type xlsxReport struct {
+	f       *excelize.File
+	sheet   string
+	columns []reportColumn
+	row     int
+	styles  map[string]int
+	err     error
+}
+
+// newXLSXReport создает книгу с листом sheet, печатает заголовок, строки
+// подзаголовка (пустые пропускаются) и шапку таблицы
+func newXLSXReport(sheet, title string, subtitle []string, columns []reportColumn) *xlsxReport {
+	w := &xlsxReport{
+		f:       excelize.NewFile(),
+		sheet:   sheet,
+		columns: columns,
+		row:     1,
+		styles:  make(map[string]int),
+	}
+	w.check(w.f.SetSheetName("Sheet1", sheet))
+	w.createStyles()
+
+	for i, column := range columns {
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		w.check(w.f.SetColWidth(sheet, name, name, column.Width))
+	}
+
+	w.merged(title, "title")
+	w.check(w.f.SetRowHeight(sheet, 1, 22))
+	for _, line := range subtitle {
+		if line != "" {
+			w.merged(line, "subtitle")
+		}
+	}
+	w.row++
+	w.header()
+
+	orientation := "landscape"
+	fitWidth, fitHeight := 1, 0
+	w.check(w.f.SetPageLayout(sheet, &excelize.PageLayoutOptions{
+		Orientation: &orientation,
+		FitToWidth:  &fitWidth,
+		FitToHeight: &fitHeight,
+	}))
+	fitToPage := true
+	w.check(w.f.SetSheetProps(sheet, &excelize.SheetPropsOptions{FitToPage: &fitToPage}))
+	return w
+}
+
+func (w *xlsxReport) check(err error) {
+	if w.err == nil && err != nil {
+		w.err = err
+	}
+}
+
+func (w *xlsxReport) createStyles() {
+	border := []excelize.Border{
+		{Type: "left", Color: "000000", Style: 1},
+		{Type: "right", Color: "000000", Style: 1},
+		{Type: "top", Color: "000000", Style: 1},
+		{Type: "bottom", Color: "000000", Style: 1},
+	}
+	fills := map[string]excelize.Fill{
+		"header": {Type: "pattern", Pattern: 1, Color: []string{"D9D9D9"}},
+		"group":  {Type: "pattern", Pattern: 1, Color: []string{"F2F2F2"}},
+	}
+	numFmts := map[string]int{cellText: 0, cellInt: 3, cellMoney: 4}
+
+	styles := map[string]*excelize.Style{
+		"title":    {Font: &excelize.Font{Bold: true, Size: 14}},
+		"subtitle": {Font: &excelize.Font{Size: 10}},
+		"header": {
+			Font:      &excelize.Font{Bold: true},
+			Border:    border,
+			Fill:      fills["header"],
+			Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+		},
+	}
+	// Строки таблицы, строки групп и итогов для каждого формата значений
+	for format, numFmt := range numFmts {
+		styles["row"+format] = &excelize.Style{
+			Border:    border,
+			NumFmt:    numFmt,
+			Alignment: &excelize.Alignment{Vertical: "top", WrapText: format == cellText},
+		}
+		styles["group"+format] = &excelize.Style{
+			Font:   &excelize.Font{Bold: true},
+			Border: border,
+			Fill:   fills["group"],
+			NumFmt: numFmt,
+		}
+		styles["total"+format] = &excelize.Style{
+			Font:   &excelize.Font{Bold: true},
+			Border: border,
+			NumFmt: numFmt,
+		}
+	}
+
+	for name, style := range styles {
+		id, err := w.f.NewStyle(style)
+		w.check(err)
+		w.styles[name] = id
+	}
+}
+
+func (w *xlsxReport) cell(col, row int) string {
+	ref, err := excelize.CoordinatesToCellName(col, row)
+	w.check(err)
+	return ref
+}
+
+// merged печатает текст в строке, объединенной на всю ширину таблицы
+func (w *xlsxReport) merged(text, style string) {
+	first, last := w.cell(1, w.row), w.cell(len(w.columns), w.row)
+	w.check(w.f.SetCellValue(w.sheet, first, text))
+	w.check(w.f.MergeCell(w.sheet, first, last))
+	w.check(w.f.SetCellStyle(w.sheet, first, last, w.styles[style]))
+	w.row++
+}
+
+// header печатает шапку таблицы в одну или две строки, закрепляет ее
+// и повторяет на каждой печатной странице
+func (w *xlsxReport) header() {
+	grouped := false
+	for _, column := range w.columns {
+		if column.Group != "" {
+			grouped = true
+		}
+	}
+
+	top, bottom := w.row, w.row
+	if grouped {
+		bottom++
+	}
+	for i := 0; i < len(w.columns); i++ {
+		column := w.columns[i]
+		if column.Group == "" {
+			w.check(w.f.SetCellValue(w.sheet, w.cell(i+1, top), column.Title))
+			w.check(w.f.MergeCell(w.sheet, w.cell(i+1, top), w.cell(i+1, bottom)))
+			continue
+		}
+		last := i
+		for last+1 < len(w.columns) && w.columns[last+1].Group == column.Group {
+			last++
+		}
+		w.check(w.f.SetCellValue(w.sheet, w.cell(i+1, top), column.Group))
+		w.check(w.f.MergeCell(w.sheet, w.cell(i+1, top), w.cell(last+1, top)))
+		for j := i; j <= last; j++ {
+			w.check(w.f.SetCellValue(w.sheet, w.cell(j+1, bottom), w.columns[j].Title))
+		}
+		i = last
+	}
+	w.check(w.f.SetCellStyle(w.sheet, w.cell(1, top), w.cell(len(w.columns), bottom), w.styles["header"]))
+
+	w.check(w.f.SetPanes(w.sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      bottom,
+		TopLeftCell: w.cell(1, bottom+1),
+		ActivePane:  "bottomLeft",
+	}))
+	w.check(w.f.SetDefinedName(&excelize.DefinedName{
+		Name:     "_xlnm.Print_Titles",
+		RefersTo: fmt.Sprintf("'%s'!$%d:$%d", w.sheet, top, bottom),
+		Scope:    w.sheet,
+	}))
+	w.row = bottom + 1
+}
+
+// add печатает строку таблицы
+func (w *xlsxReport) add(values ...interface{}) {
+	w.values("row", values)
+}
+
+// group печатает заголовок группы строк
+func (w *xlsxReport) group(label string) {
+	w.merged(label, "group"+cellText)
+}
+
+// total печатает строку итогов: подпись в первых столбцах до первого
+// непустого значения, суммы - в своих столбцах
+func (w *xlsxReport) total(label string, values ...interface{}) {
+	span := 1
+	for span < len(values) && values[span] == nil {
+		span++
+	}
+	values[0] = label
+	w.values("total", values)
+	if span > 1 {
+		w.check(w.f.MergeCell(w.sheet, w.cell(1, w.row-1), w.cell(span, w.row-1)))
+	}
+}
+
+func (w *xlsxReport) values(style string, values []interface{}) {
+	for i, column := range w.columns {
+		ref := w.cell(i+1, w.row)
+		if i < len(values) && values[i] != nil {
+			value := values[i]
+			if money, ok := value.(model.Money); ok {
+				value = money.Float64()
+			}
+			w.check(w.f.SetCellValue(w.sheet, ref, value))
+		}
+		w.check(w.f.SetCellStyle(w.sheet, ref, ref, w.styles[style+column.Format]))
+	}
+	w.row++
+}
+
+// bytes возвращает содержимое файла XLSX
+func (w *xlsxReport) bytes() ([]byte, error) {
+	defer func() {
+		_ = w.f.Close()
+	}()
+	if w.err != nil {
+		return nil, fmt.Errorf("ошибка при формировании отчета: %v", w.err)
+	}
+
+	var buf bytes.Buffer
+	if err := w.f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("ошибка при сохранении файла: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,336 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"reflect"
+	"strings"
+	"tohaboy/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	auditTable     = "audit_entries"
+	auditBeforeKey = "audit:before"
+)
+
+// unaudited технические таблицы, изменения которых не пишутся в журнал
+var unaudited = map[string]bool{
+	"number_sequences": true,
+}
+
+// redacted значение чувствительного столбца в журнале аудита
+const redacted = "***"
+
+// ErrAuditImmutable возвращается при попытке изменить или удалить запись журнала аудита
+var ErrAuditImmutable = errors.New("записи журнала аудита нельзя изменять или удалять")
+
+// Actor возвращает ID пользователя, от имени которого выполняется изменение (0 - система)
+type Actor func() uint
+
+type auditor struct {
+	actor Actor
+}
+
+// EnableAudit регистрирует callbacks GORM, которые пишут в журнал аудита каждое
+// создание, изменение и удаление записей. Запись журнала создается в той же
+// транзакции, что и само изменение.
+func (s *Storage) EnableAudit(actor Actor) error {
+	a := &auditor{actor: actor}
+	callbacks := s.db.Callback()
+
+	if err := callbacks.Create().After("gorm:create").Register("audit:create", a.afterCreate); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("audit:before_update", a.beforeChange); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("audit:update", a.afterUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("audit:before_delete", a.beforeChange); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Register("audit:delete", a.afterDelete)
+}
+
+func (a *auditor) beforeChange(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || unaudited[db.Statement.Table] {
+		return
+	}
+	if db.Statement.Table == auditTable {
+		_ = db.AddError(ErrAuditImmutable)
+		return
+	}
+
+	rows, err := a.snapshot(db, primaryKeys(db))
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+	db.Statement.Settings.Store(auditBeforeKey, rows)
+}
+
+func (a *auditor) afterCreate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Table == auditTable || unaudited[db.Statement.Table] {
+		return
+	}
+
+	ids := primaryKeys(db)
+	if len(ids) == 0 {
+		return
+	}
+
+	rows, err := a.snapshot(db, ids)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	entries := make([]model.AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, a.entry(db, "create", row, nil, row))
+	}
+	a.write(db, entries)
+}
+
+func (a *auditor) afterUpdate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	before := loadBefore(db)
+	if len(before) == 0 {
+		return
+	}
+
+	ids := make([]interface{}, 0, len(before))
+	for _, row := range before {
+		ids = append(ids, row[pkColumn(db)])
+	}
+
+	after, err := a.snapshot(db, ids)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	afterByID := make(map[interface{}]map[string]interface{}, len(after))
+	for _, row := range after {
+		afterByID[row[pkColumn(db)]] = row
+	}
+
+	entries := make([]model.AuditEntry, 0, len(before))
+	for _, old := range before {
+		current, ok := afterByID[old[pkColumn(db)]]
+		if !ok {
+			continue
+		}
+		if len(diff(old, current)) == 0 {
+			continue
+		}
+		entries = append(entries, a.entry(db, "update", old, old, current))
+	}
+	a.write(db, entries)
+}
+
+func (a *auditor) afterDelete(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	before := loadBefore(db)
+	entries := make([]model.AuditEntry, 0, len(before))
+	for _, old := range before {
+		entries = append(entries, a.entry(db, "delete", old, old, nil))
+	}
+	a.write(db, entries)
+}
+
+// snapshot читает текущее состояние затрагиваемых строк в той же транзакции.
+// Строки выбираются по первичным ключам, а если их нет - по условию WHERE запроса.
+func (a *auditor) snapshot(db *gorm.DB, ids []interface{}) ([]map[string]interface{}, error) {
+	query := db.Session(&gorm.Session{NewDB: true}).Unscoped().Table(db.Statement.Table)
+
+	if len(ids) > 0 {
+		query = query.Where(clause.IN{Column: clause.Column{Name: pkColumn(db)}, Values: ids})
+	} else if where, ok := db.Statement.Clauses["WHERE"]; ok {
+		query = query.Clauses(resolvePrimaryColumn(db, where.Expression))
+	} else {
+		return nil, nil
+	}
+
+	var rows []map[string]interface{}
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (a *auditor) entry(db *gorm.DB, action string, row, before, after map[string]interface{}) model.AuditEntry {
+	entry := model.AuditEntry{
+		Entity:   db.Statement.Table,
+		EntityID: toUint(row[pkColumn(db)]),
+		Action:   action,
+		UserID:   a.actor(),
+	}
+	if before != nil {
+		entry.Before = marshal(redact(before))
+	}
+	if after != nil {
+		entry.After = marshal(redact(after))
+	}
+	if before != nil && after != nil {
+		changes := diff(before, after)
+		for key := range changes {
+			if sensitive(key) {
+				changes[key] = [2]interface{}{redacted, redacted}
+			}
+		}
+		entry.Changes = marshal(changes)
+	}
+	return entry
+}
+
+func (a *auditor) write(db *gorm.DB, entries []model.AuditEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	if err := db.Session(&gorm.Session{NewDB: true}).Create(&entries).Error; err != nil {
+		log.Printf("[storage] audit write error: %v", err)
+		_ = db.AddError(err)
+	}
+}
+
+func loadBefore(db *gorm.DB) []map[string]interface{} {
+	value, ok := db.Statement.Settings.LoadAndDelete(auditBeforeKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := value.([]map[string]interface{})
+	return rows
+}
+
+func pkColumn(db *gorm.DB) string {
+	if field := db.Statement.Schema.PrioritizedPrimaryField; field != nil {
+		return field.DBName
+	}
+	return "id"
+}
+
+// resolvePrimaryColumn подставляет имя первичного ключа в условия, которые GORM
+// строит по встроенным аргументам (db.Delete(&model.Movement{}, id)). Запрос
+// снимка выполняется без модели и clause.PrimaryColumn сам не разрешит.
+func resolvePrimaryColumn(db *gorm.DB, expression clause.Expression) clause.Expression {
+	where, ok := expression.(clause.Where)
+	if !ok {
+		return expression
+	}
+
+	column := clause.Column{Name: pkColumn(db)}
+	isPrimary := func(value interface{}) bool {
+		c, ok := value.(clause.Column)
+		return ok && c.Name == clause.PrimaryKey
+	}
+
+	exprs := make([]clause.Expression, len(where.Exprs))
+	for i, expr := range where.Exprs {
+		switch e := expr.(type) {
+		case clause.IN:
+			if isPrimary(e.Column) {
+				e.Column = column
+			}
+			expr = e
+		case clause.Eq:
+			if isPrimary(e.Column) {
+				e.Column = column
+			}
+			expr = e
+		}
+		exprs[i] = expr
+	}
+	return clause.Where{Exprs: exprs}
+}
+
+// sensitive сообщает, что значение столбца не должно попадать в журнал:
+// хэши паролей, токены и секреты
+func sensitive(column string) bool {
+	return column == "password" || strings.Contains(column, "token") || strings.Contains(column, "secret")
+}
+
+// redact возвращает копию строки, в которой значения чувствительных столбцов скрыты
+func redact(row map[string]interface{}) map[string]interface{} {
+	clean := make(map[string]interface{}, len(row))
+	for key, value := range row {
+		if sensitive(key) {
+			value = redacted
+		}
+		clean[key] = value
+	}
+	return clean
+}
+
+// primaryKeys возвращает непустые первичные ключи моделей, переданных в запрос
+func primaryKeys(db *gorm.DB) []interface{} {
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return nil
+	}
+
+	var ids []interface{}
+	value := reflect.Indirect(db.Statement.ReflectValue)
+	collect := func(v reflect.Value) {
+		if id, zero := field.ValueOf(db.Statement.Context, reflect.Indirect(v)); !zero {
+			ids = append(ids, id)
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			collect(value.Index(i))
+		}
+	case reflect.Struct:
+		collect(value)
+	}
+	return ids
+}
+
+// diff возвращает измененные поля в виде {"поле": [старое, новое]}
+func diff(before, after map[string]interface{}) map[string][2]interface{} {
+	changes := make(map[string][2]interface{})
+	for key, newValue := range after {
+		if key == "updated_at" {
+			continue
+		}
+		if oldValue := before[key]; !reflect.DeepEqual(oldValue, newValue) {
+			changes[key] = [2]interface{}{oldValue, newValue}
+		}
+	}
+	return changes
+}
+
+func marshal(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func toUint(value interface{}) uint {
+	switch v := value.(type) {
+	case int64:
+		return uint(v)
+	case int:
+		return uint(v)
+	case uint:
+		return v
+	case uint64:
+		return uint(v)
+	}
+	return 0
+}
@@ -0,0 +1,437 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// SchemaMigration запись о примененной миграции в таблице schema_migrations
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	Checksum  string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// migration пара SQL-файлов migrations/NNNN_name.up.sql и NNNN_name.down.sql
+type migration struct {
+	version  int
+	name     string
+	up       string
+	down     string
+	checksum string
+}
+
+// MigrateUp применяет все неприменённые миграции по порядку номеров.
+// Перед изменением существующей базы создается резервная копия, а контрольные
+// суммы уже примененных миграций сверяются с файлами приложения.
+func (s *Storage) MigrateUp() error {
+	if err := s.db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return err
+	}
+
+	migrations, applied, err := s.migrationState()
+	if err != nil {
+		return err
+	}
+	// Таблицы есть, а записей о миграциях нет - база создана до версионных миграций
+	if len(applied) == 0 && s.db.Migrator().HasTable("users") {
+		record, err := s.adoptLegacySchema(migrations[0])
+		if err != nil {
+			return err
+		}
+		applied[record.Version] = record
+	}
+
+	var pending []migration
+	for _, m := range migrations {
+		if _, ok := applied[m.version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if len(applied) > 0 {
+		backup, err := s.Backup()
+		if err != nil {
+			return fmt.Errorf("резервное копирование перед миграцией: %v", err)
+		}
+		if backup != "" {
+			log.Printf("[storage] backup created: %s", backup)
+		}
+	}
+
+	for _, m := range pending {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{
+				Version:   m.version,
+				Name:      m.name,
+				Checksum:  m.checksum,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("миграция %04d_%s: %v", m.version, m.name, err)
+		}
+		log.Printf("[storage] migration %04d_%s applied", m.version, m.name)
+	}
+
+	return nil
+}
+
+// MigrateDown откатывает примененные миграции с номером больше target
+func (s *Storage) MigrateDown(target int) error {
+	if err := s.db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return err
+	}
+
+	migrations, applied, err := s.migrationState()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Backup(); err != nil {
+		return fmt.Errorf("резервное копирование перед откатом: %v", err)
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.version <= target {
+			break
+		}
+		if _, ok := applied[m.version]; !ok {
+			continue
+		}
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, m.version).Error
+		})
+		if err != nil {
+			return fmt.Errorf("откат миграции %04d_%s: %v", m.version, m.name, err)
+		}
+		log.Printf("[storage] migration %04d_%s rolled back", m.version, m.name)
+	}
+
+	return nil
+}
+
+// SchemaVersion возвращает номер последней примененной миграции
+func (s *Storage) SchemaVersion() (int, error) {
+	var version int
+	err := s.db.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// Reset удаляет все таблицы и создает схему заново. Данные теряются,
+// поэтому перед сбросом создается резервная копия.
+func (s *Storage) Reset() error {
+	backup, err := s.Backup()
+	if err != nil {
+		return fmt.Errorf("резервное копирование перед сбросом: %v", err)
+	}
+	if backup != "" {
+		log.Printf("[storage] backup created: %s", backup)
+	}
+
+	if err := s.dropAllTables(); err != nil {
+		return err
+	}
+	return s.MigrateUp()
+}
+
+// Backup сохраняет согласованную копию файла базы рядом с ним и возвращает её путь.
+// Для базы в памяти копия не создается.
+func (s *Storage) Backup() (string, error) {
+	if s.path == "" || strings.Contains(s.path, ":memory:") {
+		return "", nil
+	}
+
+	var tables int64
+	if err := s.db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'").Scan(&tables).Error; err != nil {
+		return "", err
+	}
+	if tables == 0 {
+		return "", nil
+	}
+
+	version := 0
+	if s.db.Migrator().HasTable(&SchemaMigration{}) {
+		var err error
+		if version, err = s.SchemaVersion(); err != nil {
+			return "", err
+		}
+	}
+
+	stamp := time.Now().Format("20060102-150405")
+	backup := fmt.Sprintf("%s.%s-v%d.bak", s.path, stamp, version)
+	for i := 1; fileExists(backup); i++ {
+		backup = fmt.Sprintf("%s.%s-v%d-%d.bak", s.path, stamp, version, i)
+	}
+	if err := s.db.Exec("VACUUM INTO ?", backup).Error; err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// adoptLegacySchema переводит базу, созданную до появления версионных миграций
+// (таблицы создавал AutoMigrate), на схему первой миграции first без потери данных:
+// старые таблицы переименовываются в legacy_*, схема создается миграцией, а строки
+// копируются по совпадающим колонкам. Таблицы, которых нет в схеме, остаются как есть.
+// Если данные не укладываются в схему, перенос откатывается целиком и база не меняется.
+func (s *Storage) adoptLegacySchema(first migration) (SchemaMigration, error) {
+	record := SchemaMigration{
+		Version:   first.version,
+		Name:      first.name,
+		Checksum:  first.checksum,
+		AppliedAt: time.Now(),
+	}
+
+	backup, err := s.Backup()
+	if err != nil {
+		return record, fmt.Errorf("резервное копирование старой базы: %v", err)
+	}
+	log.Printf("[storage] legacy schema without migrations found, converting to %04d_%s (backup: %s)",
+		first.version, first.name, backup)
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var tables []string
+		if err := tx.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name <> ?",
+			record.TableName()).Scan(&tables).Error; err != nil {
+			return err
+		}
+
+		// Индексы и триггеры старых таблиц пересоздаст миграция под теми же именами
+		var objects []struct{ Type, Name string }
+		if err := tx.Raw("SELECT type, name FROM sqlite_master WHERE type IN ('index', 'trigger') AND sql IS NOT NULL").
+			Scan(&objects).Error; err != nil {
+			return err
+		}
+		for _, object := range objects {
+			if err := tx.Exec(fmt.Sprintf("DROP %s IF EXISTS `%s`", strings.ToUpper(object.Type), object.Name)).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, table := range tables {
+			if err := tx.Exec(fmt.Sprintf("ALTER TABLE `%s` RENAME TO `legacy_%s`", table, table)).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec(first.up).Error; err != nil {
+			return err
+		}
+
+		for _, table := range tables {
+			if !tx.Migrator().HasTable(table) {
+				if err := tx.Exec(fmt.Sprintf("ALTER TABLE `legacy_%s` RENAME TO `%s`", table, table)).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := copyLegacyTable(tx, table); err != nil {
+				return fmt.Errorf("таблица %s: %v", table, err)
+			}
+		}
+
+		return tx.Create(&record).Error
+	})
+	if err != nil {
+		return record, fmt.Errorf("перенос базы без версий миграций в схему %04d_%s: %v; база не изменена, "+
+			"резервная копия: %s. Исправьте данные или пересоздайте базу настройкой database.reset", first.version, first.name, err, backup)
+	}
+	return record, nil
+}
+
+// copyLegacyTable копирует строки legacy_<table> в новую таблицу table по общим колонкам
+// и удаляет старую таблицу
+func copyLegacyTable(tx *gorm.DB, table string) error {
+	columns := func(name string) (map[string]bool, error) {
+		var info []struct{ Name string }
+		if err := tx.Raw(fmt.Sprintf("PRAGMA table_info(`%s`)", name)).Scan(&info).Error; err != nil {
+			return nil, err
+		}
+		set := make(map[string]bool, len(info))
+		for _, column := range info {
+			set[column.Name] = true
+		}
+		return set, nil
+	}
+
+	target, err := columns(table)
+	if err != nil {
+		return err
+	}
+	source, err := columns("legacy_" + table)
+	if err != nil {
+		return err
+	}
+
+	var common []string
+	for column := range source {
+		if target[column] {
+			common = append(common, "`"+column+"`")
+		}
+	}
+	sort.Strings(common)
+
+	if len(common) > 0 {
+		list := strings.Join(common, ", ")
+		if err := tx.Exec(fmt.Sprintf("INSERT INTO `%s` (%s) SELECT %s FROM `legacy_%s`", table, list, list, table)).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Exec(fmt.Sprintf("DROP TABLE `legacy_%s`", table)).Error
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// dropAllTables удаляет все таблицы базы. Полнотекстовый индекс без модуля FTS5
+// удалить нельзя, поэтому он и его теневые таблицы остаются: сборка с FTS5
+// не найдет триггеров индекса и перестроит его.
+func (s *Storage) dropAllTables() error {
+	query := "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'"
+	if !s.FTS5Available() {
+		query += " AND name NOT LIKE 'search\\_index%' ESCAPE '\\'"
+	}
+
+	var tables []string
+	if err := s.db.Raw(query).Scan(&tables).Error; err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if err := s.db.Migrator().DropTable(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrationState загружает миграции приложения и сверяет с ними примененные
+func (s *Storage) migrationState() ([]migration, map[int]SchemaMigration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var records []SchemaMigration
+	if err := s.db.Order("version").Find(&records).Error; err != nil {
+		return nil, nil, err
+	}
+
+	known := make(map[int]migration, len(migrations))
+	for _, m := range migrations {
+		known[m.version] = m
+	}
+
+	applied := make(map[int]SchemaMigration, len(records))
+	for _, record := range records {
+		m, ok := known[record.Version]
+		if !ok {
+			return nil, nil, fmt.Errorf("база содержит миграцию %04d_%s, неизвестную этой версии приложения", record.Version, record.Name)
+		}
+		if m.checksum != record.Checksum {
+			return nil, nil, fmt.Errorf("контрольная сумма миграции %04d_%s не совпадает с примененной", m.version, m.name)
+		}
+		applied[record.Version] = record
+	}
+
+	return migrations, applied, nil
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, entry := range entries {
+		name := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		number, title, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("неверное имя файла миграции: %s", name)
+		}
+		version, err := strconv.Atoi(number)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("неверный номер миграции: %s", name)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: title}
+			byVersion[version] = m
+		} else if m.name != title {
+			return nil, fmt.Errorf("у миграции %04d разные имена: %s и %s", version, m.name, title)
+		}
+
+		if direction == "up" {
+			m.up = string(content)
+			sum := sha256.Sum256(content)
+			m.checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.down = string(content)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("у миграции %04d_%s нет файла up или down", m.version, m.name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+	return NewStorage(filepath.Join(t.TempDir(), "invent.db"))
+}
+
+// schemaSQL описание схемы базы: объекты sqlite_master с их SQL
+func schemaSQL(t *testing.T, s *Storage) map[string]string {
+	t.Helper()
+	var objects []struct{ Type, Name, SQL string }
+	if err := s.db.Raw("SELECT type, name, COALESCE(sql, '') AS sql FROM sqlite_master WHERE name NOT LIKE 'sqlite_%'").
+		Scan(&objects).Error; err != nil {
+		t.Fatalf("sqlite_master: %v", err)
+	}
+	schema := make(map[string]string, len(objects))
+	for _, object := range objects {
+		schema[object.Type+" "+object.Name] = object.SQL
+	}
+	return schema
+}
+
+func latestVersion(t *testing.T) int {
+	t.Helper()
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	return migrations[len(migrations)-1].version
+}
+
+func TestMigrateUpDown(t *testing.T) {
+	s := newTestStorage(t)
+	latest := latestVersion(t)
+
+	if err := s.MigrateUp(); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if version, err := s.SchemaVersion(); err != nil || version != latest {
+		t.Fatalf("версия схемы %d (%v), ожидалась %d", version, err, latest)
+	}
+	var records int64
+	s.db.Model(&SchemaMigration{}).Count(&records)
+	if int(records) != latest {
+		t.Errorf("записей о миграциях %d, ожидалось %d", records, latest)
+	}
+	// Повторный запуск ничего не меняет
+	if err := s.MigrateUp(); err != nil {
+		t.Fatalf("повторный MigrateUp: %v", err)
+	}
+	full := schemaSQL(t, s)
+
+	// Каждая миграция откатывается и применяется заново без изменения схемы
+	for version := latest; version >= 1; version-- {
+		if err := s.MigrateDown(version - 1); err != nil {
+			t.Fatalf("MigrateDown(%d): %v", version-1, err)
+		}
+		if got, _ := s.SchemaVersion(); got != version-1 {
+			t.Fatalf("после отката до %d версия схемы %d", version-1, got)
+		}
+		if err := s.MigrateUp(); err != nil {
+			t.Fatalf("MigrateUp после отката до %d: %v", version-1, err)
+		}
+		for name, sql := range schemaSQL(t, s) {
+			if full[name] != sql {
+				t.Errorf("после отката до %d и повторного применения %s отличается:\n%s\nбыло:\n%s", version-1, name, sql, full[name])
+			}
+		}
+	}
+
+	// Полный откат оставляет только таблицу версий
+	if err := s.MigrateDown(0); err != nil {
+		t.Fatalf("MigrateDown(0): %v", err)
+	}
+	for name := range schemaSQL(t, s) {
+		if name != "table schema_migrations" {
+			t.Errorf("после полного отката осталось %s", name)
+		}
+	}
+}
+
+func TestMigrateChecksum(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper string
+		err    string
+	}{
+		{
+			name:   "изменена примененная миграция",
+			tamper: "UPDATE schema_migrations SET checksum = 'changed' WHERE version = 1",
+			err:    "контрольная сумма миграции 0001_initial_schema не совпадает с примененной",
+		},
+		{
+			name:   "миграция из новой версии приложения",
+			tamper: "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (9999, 'future', 'x', CURRENT_TIMESTAMP)",
+			err:    "база содержит миграцию 9999_future, неизвестную этой версии приложения",
+		},
+	}
+	for _, tt := range tests {
+		s := newTestStorage(t)
+		if err := s.MigrateUp(); err != nil {
+			t.Fatalf("MigrateUp: %v", err)
+		}
+		if err := s.db.Exec(tt.tamper).Error; err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if err := s.MigrateUp(); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: MigrateUp вернул %v, ожидалась ошибка %q", tt.name, err, tt.err)
+		}
+		before := schemaSQL(t, s)
+		if err := s.MigrateDown(0); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: MigrateDown вернул %v, ожидалась ошибка %q", tt.name, err, tt.err)
+		}
+		if after := schemaSQL(t, s); len(after) != len(before) {
+			t.Errorf("%s: отказ в откате изменил схему", tt.name)
+		}
+	}
+}
+
+// legacySchema таблицы базы, созданной AutoMigrate до появления версионных миграций:
+// колонки частично совпадают со схемой 0001, notes в схеме нет
+const legacySchema = `
+CREATE TABLE users (id integer PRIMARY KEY AUTOINCREMENT, username text, password text, role text, created_at datetime, updated_at datetime, deleted_at datetime, theme text);
+CREATE INDEX idx_users_deleted_at ON users(deleted_at);
+CREATE TABLE locations (id integer PRIMARY KEY AUTOINCREMENT, name text, description text, floor integer);
+CREATE TABLE notes (id integer PRIMARY KEY, text text);
+INSERT INTO locations (id, name, description, floor) VALUES (7, 'Склад', 'Основной', 1), (9, 'Офис', '', 2);
+INSERT INTO notes (id, text) VALUES (1, 'заметка');
+`
+
+func TestAdoptLegacySchema(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.db.Exec(legacySchema).Error; err != nil {
+		t.Fatalf("старая схема: %v", err)
+	}
+	if err := s.db.Exec("INSERT INTO users (id, username, password, role, theme) VALUES (3, 'admin', 'hash', 'admin', 'dark')").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.MigrateUp(); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if version, _ := s.SchemaVersion(); version != latestVersion(t) {
+		t.Errorf("версия схемы %d, ожидалась %d", version, latestVersion(t))
+	}
+
+	// Строки перенесены по общим колонкам с прежними ID
+	var user struct {
+		ID             uint
+		Username, Role string
+	}
+	if err := s.db.Raw("SELECT id, username, role FROM users").Scan(&user).Error; err != nil || user.ID != 3 || user.Username != "admin" {
+		t.Errorf("пользователь после переноса %+v (%v)", user, err)
+	}
+	var locations []struct {
+		ID          uint
+		Name        string
+		Description string
+	}
+	s.db.Raw("SELECT id, name, description FROM locations ORDER BY id").Scan(&locations)
+	if len(locations) != 2 || locations[0].ID != 7 || locations[0].Name != "Склад" || locations[1].ID != 9 {
+		t.Errorf("местоположения после переноса %+v", locations)
+	}
+
+	schema := schemaSQL(t, s)
+	if strings.Contains(schema["table users"], "theme") || strings.Contains(schema["table locations"], "floor") {
+		t.Errorf("колонки старой схемы остались в таблицах: %s; %s", schema["table users"], schema["table locations"])
+	}
+	for name := range schema {
+		if strings.Contains(name, "legacy_") {
+			t.Errorf("осталась таблица %s", name)
+		}
+	}
+	// Таблица, которой нет в схеме, остается как есть
+	var notes int64
+	if err := s.db.Raw("SELECT COUNT(*) FROM notes").Scan(&notes).Error; err != nil || notes != 1 {
+		t.Errorf("таблица notes: %d строк (%v)", notes, err)
+	}
+}
+
+func TestAdoptLegacySchemaRejected(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.db.Exec(legacySchema).Error; err != nil {
+		t.Fatalf("старая схема: %v", err)
+	}
+	// В схеме 0001 логин уникален - повторяющиеся логины не переносятся
+	if err := s.db.Exec("INSERT INTO users (id, username, role) VALUES (1, 'admin', 'admin'), (2, 'admin', 'manager')").Error; err != nil {
+		t.Fatal(err)
+	}
+	before := schemaSQL(t, s)
+
+	err := s.MigrateUp()
+	if err == nil || !strings.Contains(err.Error(), "база не изменена") {
+		t.Fatalf("MigrateUp вернул %v, ожидался отказ переноса", err)
+	}
+
+	after := schemaSQL(t, s)
+	delete(after, "table schema_migrations")
+	if len(after) != len(before) {
+		t.Errorf("объектов схемы %d, было %d", len(after), len(before))
+	}
+	for name, sql := range before {
+		if after[name] != sql {
+			t.Errorf("%s изменен после отказа:\n%s", name, after[name])
+		}
+	}
+	var users, records int64
+	s.db.Raw("SELECT COUNT(*) FROM users").Scan(&users)
+	s.db.Model(&SchemaMigration{}).Count(&records)
+	if users != 2 || records != 0 {
+		t.Errorf("после отказа пользователей %d, записей о миграциях %d", users, records)
+	}
+}
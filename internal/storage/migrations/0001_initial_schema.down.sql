@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS `audit_entries`;
+DROP TABLE IF EXISTS `document_items`;
+DROP TABLE IF EXISTS `documents`;
+DROP TABLE IF EXISTS `movements`;
+DROP TABLE IF EXISTS `stock_balances`;
+DROP TABLE IF EXISTS `equipment`;
+DROP TABLE IF EXISTS `suppliers`;
+DROP TABLE IF EXISTS `locations`;
+DROP TABLE IF EXISTS `categories`;
+DROP TABLE IF EXISTS `users`;
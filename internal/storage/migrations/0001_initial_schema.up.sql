@@ -0,0 +1,138 @@
+-- Исходная схема, соответствующая моделям на момент перехода с AutoMigrate
+
+CREATE TABLE `users` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `username` text,
+    `password` text,
+    `role` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    CONSTRAINT `uni_users_username` UNIQUE (`username`)
+);
+CREATE INDEX `idx_users_deleted_at` ON `users`(`deleted_at`);
+
+CREATE TABLE `categories` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text,
+    `description` text
+);
+
+CREATE TABLE `locations` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text,
+    `description` text,
+    `address` text
+);
+
+CREATE TABLE `suppliers` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text,
+    `description` text,
+    `address` text,
+    `phone` text
+);
+
+CREATE TABLE `equipment` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text,
+    `description` text,
+    `serial_number` text,
+    `status` text,
+    `quantity` integer,
+    `price` real,
+    `category_id` integer,
+    `location_id` integer,
+    `supplier_id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_equipment_category` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`),
+    CONSTRAINT `fk_locations_equipment` FOREIGN KEY (`location_id`) REFERENCES `locations`(`id`),
+    CONSTRAINT `fk_suppliers_equipment` FOREIGN KEY (`supplier_id`) REFERENCES `suppliers`(`id`),
+    CONSTRAINT `uni_equipment_serial_number` UNIQUE (`serial_number`)
+);
+
+CREATE TABLE `stock_balances` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `equipment_id` integer NOT NULL,
+    `location_id` integer NOT NULL,
+    `quantity` integer NOT NULL,
+    `updated_at` datetime,
+    CONSTRAINT `fk_stock_balances_location` FOREIGN KEY (`location_id`) REFERENCES `locations`(`id`),
+    CONSTRAINT `fk_equipment_balances` FOREIGN KEY (`equipment_id`) REFERENCES `equipment`(`id`)
+);
+CREATE INDEX `idx_stock_balances_location_id` ON `stock_balances`(`location_id`);
+CREATE UNIQUE INDEX `idx_balance_equipment_location` ON `stock_balances`(`equipment_id`, `location_id`);
+
+CREATE TABLE `movements` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `equipment_id` integer,
+    `from_location_id` integer,
+    `to_location_id` integer,
+    `quantity` integer,
+    `reason` text,
+    `created_by_id` integer,
+    `document_id` integer,
+    `date` date,
+    CONSTRAINT `fk_movements_created_by` FOREIGN KEY (`created_by_id`) REFERENCES `users`(`id`),
+    CONSTRAINT `fk_locations_from_movements` FOREIGN KEY (`from_location_id`) REFERENCES `locations`(`id`),
+    CONSTRAINT `fk_locations_to_movements` FOREIGN KEY (`to_location_id`) REFERENCES `locations`(`id`),
+    CONSTRAINT `fk_equipment_movements` FOREIGN KEY (`equipment_id`) REFERENCES `equipment`(`id`)
+);
+
+CREATE TABLE `documents` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `type` text,
+    `number` text,
+    `status` text,
+    `date` date,
+    `created_by_id` integer,
+    `approved_by_id` integer DEFAULT null,
+    `location_id` integer,
+    `from_location_id` integer,
+    `comment` text,
+    `cancel_reason` text,
+    `canceled_by_id` integer DEFAULT null,
+    `canceled_at` datetime,
+    `reversal_of_id` integer DEFAULT null,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_documents_canceled_by` FOREIGN KEY (`canceled_by_id`) REFERENCES `users`(`id`),
+    CONSTRAINT `fk_documents_reversal_of` FOREIGN KEY (`reversal_of_id`) REFERENCES `documents`(`id`),
+    CONSTRAINT `fk_documents_created_by` FOREIGN KEY (`created_by_id`) REFERENCES `users`(`id`),
+    CONSTRAINT `fk_documents_approved_by` FOREIGN KEY (`approved_by_id`) REFERENCES `users`(`id`),
+    CONSTRAINT `fk_documents_location` FOREIGN KEY (`location_id`) REFERENCES `locations`(`id`),
+    CONSTRAINT `fk_documents_from_location` FOREIGN KEY (`from_location_id`) REFERENCES `locations`(`id`),
+    CONSTRAINT `uni_documents_number` UNIQUE (`number`)
+);
+CREATE INDEX `idx_documents_reversal_of_id` ON `documents`(`reversal_of_id`);
+
+CREATE TABLE `document_items` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `document_id` integer NOT NULL,
+    `equipment_id` integer NOT NULL,
+    `quantity` integer NOT NULL,
+    `actual_quantity` integer,
+    `price` real NOT NULL,
+    `total_price` real NOT NULL,
+    `comment` text,
+    CONSTRAINT `fk_equipment_documents` FOREIGN KEY (`equipment_id`) REFERENCES `equipment`(`id`),
+    CONSTRAINT `fk_documents_items` FOREIGN KEY (`document_id`) REFERENCES `documents`(`id`)
+);
+CREATE INDEX `idx_doc_equipment` ON `document_items`(`document_id`, `equipment_id`);
+
+CREATE TABLE `audit_entries` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `entity` text NOT NULL,
+    `entity_id` integer,
+    `action` text NOT NULL,
+    `user_id` integer,
+    `before` text,
+    `after` text,
+    `changes` text,
+    `created_at` datetime,
+    CONSTRAINT `fk_audit_entries_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_audit_entries_created_at` ON `audit_entries`(`created_at`);
+CREATE INDEX `idx_audit_entries_user_id` ON `audit_entries`(`user_id`);
+CREATE INDEX `idx_audit_entity` ON `audit_entries`(`entity`, `entity_id`);
@@ -0,0 +1,9 @@
+ALTER TABLE `documents` DROP COLUMN `responsible_id`;
+
+ALTER TABLE `movements` DROP COLUMN `to_responsible_id`;
+ALTER TABLE `movements` DROP COLUMN `from_responsible_id`;
+
+DROP INDEX IF EXISTS `idx_equipment_responsible_id`;
+ALTER TABLE `equipment` DROP COLUMN `responsible_id`;
+
+DROP TABLE IF EXISTS `employees`;
@@ -0,0 +1,23 @@
+-- Сотрудники и материальная ответственность за оборудование
+
+CREATE TABLE `employees` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text NOT NULL,
+    `position` text,
+    `department` text,
+    `personnel_number` text,
+    `contact` text,
+    `user_id` integer DEFAULT null,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_employees_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_employees_personnel_number` ON `employees`(`personnel_number`);
+
+ALTER TABLE `equipment` ADD COLUMN `responsible_id` integer DEFAULT null REFERENCES `employees`(`id`);
+CREATE INDEX `idx_equipment_responsible_id` ON `equipment`(`responsible_id`);
+
+ALTER TABLE `movements` ADD COLUMN `from_responsible_id` integer DEFAULT null REFERENCES `employees`(`id`);
+ALTER TABLE `movements` ADD COLUMN `to_responsible_id` integer DEFAULT null REFERENCES `employees`(`id`);
+
+ALTER TABLE `documents` ADD COLUMN `responsible_id` integer DEFAULT null REFERENCES `employees`(`id`);
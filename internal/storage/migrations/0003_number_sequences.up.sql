@@ -0,0 +1,11 @@
+-- Счетчики нумерации документов
+
+CREATE TABLE `number_sequences` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `document_type` text NOT NULL,
+    `period` text NOT NULL,
+    `location_id` integer NOT NULL,
+    `value` integer NOT NULL,
+    `updated_at` datetime
+);
+CREATE UNIQUE INDEX `idx_number_sequences_key` ON `number_sequences`(`document_type`, `period`, `location_id`);
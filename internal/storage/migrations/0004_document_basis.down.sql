@@ -0,0 +1,3 @@
+ALTER TABLE `documents` DROP COLUMN `basis_date`;
+ALTER TABLE `documents` DROP COLUMN `basis_number`;
+ALTER TABLE `documents` DROP COLUMN `basis`;
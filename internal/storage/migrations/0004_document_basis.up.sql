@@ -0,0 +1,5 @@
+-- Основание документа для печатных форм (приказ, постановление, распоряжение)
+
+ALTER TABLE `documents` ADD COLUMN `basis` text;
+ALTER TABLE `documents` ADD COLUMN `basis_number` text;
+ALTER TABLE `documents` ADD COLUMN `basis_date` date;
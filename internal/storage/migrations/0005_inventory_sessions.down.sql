@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS `idx_documents_inventory_id`;
+ALTER TABLE `documents` DROP COLUMN `inventory_id`;
+
+ALTER TABLE `document_items` DROP COLUMN `counted`;
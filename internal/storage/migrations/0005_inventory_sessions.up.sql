@@ -0,0 +1,7 @@
+-- Инвентаризация по местоположению: отметки о пересчете позиций
+-- и корректирующие документы, созданные при проведении инвентаризации
+
+ALTER TABLE `document_items` ADD COLUMN `counted` numeric DEFAULT false;
+
+ALTER TABLE `documents` ADD COLUMN `inventory_id` integer DEFAULT null;
+CREATE INDEX `idx_documents_inventory_id` ON `documents`(`inventory_id`);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS `idx_equipment_inventory_number`;
+ALTER TABLE `equipment` DROP COLUMN `inventory_number`;
@@ -0,0 +1,5 @@
+-- Инвентарные номера оборудования. Номера существующему оборудованию
+-- выдаются при запуске приложения по правилу нумерации из настроек.
+
+ALTER TABLE `equipment` ADD COLUMN `inventory_number` text DEFAULT null;
+CREATE UNIQUE INDEX `idx_equipment_inventory_number` ON `equipment`(`inventory_number`);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS `idx_equipment_status_changes_equipment_id`;
+DROP TABLE IF EXISTS `equipment_status_changes`;
@@ -0,0 +1,14 @@
+-- История статусов оборудования: выдача, возврат, ремонт, списание и его сторно
+
+CREATE TABLE `equipment_status_changes` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `equipment_id` integer NOT NULL,
+    `from_status` text,
+    `to_status` text NOT NULL,
+    `reason` text,
+    `user_id` integer DEFAULT null,
+    `document_id` integer DEFAULT null,
+    `created_at` datetime,
+    CONSTRAINT `fk_equipment_status_changes_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_equipment_status_changes_equipment_id` ON `equipment_status_changes`(`equipment_id`);
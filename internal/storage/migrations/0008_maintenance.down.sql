@@ -0,0 +1,5 @@
+ALTER TABLE `categories` DROP COLUMN `maintenance_interval_days`;
+
+DROP INDEX IF EXISTS `idx_maintenance_records_supplier_id`;
+DROP INDEX IF EXISTS `idx_maintenance_records_equipment_id`;
+DROP TABLE IF EXISTS `maintenance_records`;
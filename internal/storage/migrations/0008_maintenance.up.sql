@@ -0,0 +1,23 @@
+-- Ремонт и обслуживание оборудования, периодичность планового обслуживания по категориям
+
+CREATE TABLE `maintenance_records` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `equipment_id` integer NOT NULL,
+    `type` text NOT NULL,
+    `supplier_id` integer DEFAULT null,
+    `sent_at` datetime NOT NULL,
+    `returned_at` datetime,
+    `cost` real,
+    `description` text,
+    `outcome` text,
+    `created_by_id` integer DEFAULT null,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_maintenance_records_equipment` FOREIGN KEY (`equipment_id`) REFERENCES `equipment`(`id`),
+    CONSTRAINT `fk_maintenance_records_supplier` FOREIGN KEY (`supplier_id`) REFERENCES `suppliers`(`id`),
+    CONSTRAINT `fk_maintenance_records_created_by` FOREIGN KEY (`created_by_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_maintenance_records_equipment_id` ON `maintenance_records`(`equipment_id`);
+CREATE INDEX `idx_maintenance_records_supplier_id` ON `maintenance_records`(`supplier_id`);
+
+ALTER TABLE `categories` ADD COLUMN `maintenance_interval_days` integer DEFAULT 0;
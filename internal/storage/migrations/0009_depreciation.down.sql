@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS `idx_depreciation_postings_equipment_id`;
+DROP INDEX IF EXISTS `idx_depreciation_postings_run_id`;
+DROP TABLE IF EXISTS `depreciation_postings`;
+
+DROP INDEX IF EXISTS `idx_depreciation_runs_period`;
+DROP TABLE IF EXISTS `depreciation_runs`;
+
+ALTER TABLE `equipment` DROP COLUMN `accumulated_depreciation`;
+ALTER TABLE `equipment` DROP COLUMN `residual_value`;
+ALTER TABLE `equipment` DROP COLUMN `useful_life_months`;
+ALTER TABLE `equipment` DROP COLUMN `depreciation_method`;
+ALTER TABLE `equipment` DROP COLUMN `commissioned_at`;
+
+ALTER TABLE `categories` DROP COLUMN `depreciation_factor`;
+ALTER TABLE `categories` DROP COLUMN `useful_life_months`;
+ALTER TABLE `categories` DROP COLUMN `depreciation_method`;
+ALTER TABLE `categories` DROP COLUMN `okof`;
@@ -0,0 +1,38 @@
+-- Амортизация основных средств: условия по категориям (группам ОКОФ) и оборудованию,
+-- ежемесячные начисления с проводками по оборудованию
+
+ALTER TABLE `categories` ADD COLUMN `okof` text;
+ALTER TABLE `categories` ADD COLUMN `depreciation_method` text;
+ALTER TABLE `categories` ADD COLUMN `useful_life_months` integer DEFAULT 0;
+ALTER TABLE `categories` ADD COLUMN `depreciation_factor` real DEFAULT 0;
+
+ALTER TABLE `equipment` ADD COLUMN `commissioned_at` date;
+ALTER TABLE `equipment` ADD COLUMN `depreciation_method` text;
+ALTER TABLE `equipment` ADD COLUMN `useful_life_months` integer DEFAULT 0;
+ALTER TABLE `equipment` ADD COLUMN `residual_value` real DEFAULT 0;
+ALTER TABLE `equipment` ADD COLUMN `accumulated_depreciation` real DEFAULT 0;
+
+CREATE TABLE `depreciation_runs` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `period` date NOT NULL,
+    `total` real,
+    `created_by_id` integer DEFAULT null,
+    `created_at` datetime,
+    CONSTRAINT `fk_depreciation_runs_created_by` FOREIGN KEY (`created_by_id`) REFERENCES `users`(`id`)
+);
+CREATE UNIQUE INDEX `idx_depreciation_runs_period` ON `depreciation_runs`(`period`);
+
+CREATE TABLE `depreciation_postings` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `run_id` integer NOT NULL,
+    `equipment_id` integer NOT NULL,
+    `method` text,
+    `quantity` integer,
+    `book_value` real,
+    `unit_amount` real,
+    `amount` real,
+    CONSTRAINT `fk_depreciation_runs_postings` FOREIGN KEY (`run_id`) REFERENCES `depreciation_runs`(`id`),
+    CONSTRAINT `fk_depreciation_postings_equipment` FOREIGN KEY (`equipment_id`) REFERENCES `equipment`(`id`)
+);
+CREATE INDEX `idx_depreciation_postings_run_id` ON `depreciation_postings`(`run_id`);
+CREATE INDEX `idx_depreciation_postings_equipment_id` ON `depreciation_postings`(`equipment_id`);
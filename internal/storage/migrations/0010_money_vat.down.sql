@@ -0,0 +1,62 @@
+-- Суммы снова в рублях в колонках real, позиции документов без НДС.
+-- Округление сумм до копейки не откатывается.
+
+CREATE TABLE `document_items_rubles` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `document_id` integer NOT NULL,
+    `equipment_id` integer NOT NULL,
+    `quantity` integer NOT NULL,
+    `actual_quantity` integer,
+    `price` real NOT NULL,
+    `total_price` real NOT NULL,
+    `comment` text,
+    `counted` numeric DEFAULT false,
+    CONSTRAINT `fk_equipment_documents` FOREIGN KEY (`equipment_id`) REFERENCES `equipment`(`id`),
+    CONSTRAINT `fk_documents_items` FOREIGN KEY (`document_id`) REFERENCES `documents`(`id`)
+);
+INSERT INTO `document_items_rubles` (`id`, `document_id`, `equipment_id`, `quantity`, `actual_quantity`, `price`, `total_price`, `comment`, `counted`)
+SELECT `id`, `document_id`, `equipment_id`, `quantity`, `actual_quantity`, ROUND(`price` / 100.0, 2), ROUND(`total_price` / 100.0, 2), `comment`, `counted`
+FROM `document_items`;
+DROP TABLE `document_items`;
+ALTER TABLE `document_items_rubles` RENAME TO `document_items`;
+CREATE INDEX `idx_doc_equipment` ON `document_items`(`document_id`, `equipment_id`);
+
+ALTER TABLE `depreciation_postings` ADD COLUMN `amount_rubles` real;
+UPDATE `depreciation_postings` SET `amount_rubles` = ROUND(`amount` / 100.0, 2);
+ALTER TABLE `depreciation_postings` DROP COLUMN `amount`;
+ALTER TABLE `depreciation_postings` RENAME COLUMN `amount_rubles` TO `amount`;
+
+ALTER TABLE `depreciation_postings` ADD COLUMN `unit_amount_rubles` real;
+UPDATE `depreciation_postings` SET `unit_amount_rubles` = ROUND(`unit_amount` / 100.0, 2);
+ALTER TABLE `depreciation_postings` DROP COLUMN `unit_amount`;
+ALTER TABLE `depreciation_postings` RENAME COLUMN `unit_amount_rubles` TO `unit_amount`;
+
+ALTER TABLE `depreciation_postings` ADD COLUMN `book_value_rubles` real;
+UPDATE `depreciation_postings` SET `book_value_rubles` = ROUND(`book_value` / 100.0, 2);
+ALTER TABLE `depreciation_postings` DROP COLUMN `book_value`;
+ALTER TABLE `depreciation_postings` RENAME COLUMN `book_value_rubles` TO `book_value`;
+
+ALTER TABLE `depreciation_runs` ADD COLUMN `total_rubles` real;
+UPDATE `depreciation_runs` SET `total_rubles` = ROUND(`total` / 100.0, 2);
+ALTER TABLE `depreciation_runs` DROP COLUMN `total`;
+ALTER TABLE `depreciation_runs` RENAME COLUMN `total_rubles` TO `total`;
+
+ALTER TABLE `maintenance_records` ADD COLUMN `cost_rubles` real;
+UPDATE `maintenance_records` SET `cost_rubles` = ROUND(`cost` / 100.0, 2);
+ALTER TABLE `maintenance_records` DROP COLUMN `cost`;
+ALTER TABLE `maintenance_records` RENAME COLUMN `cost_rubles` TO `cost`;
+
+ALTER TABLE `equipment` ADD COLUMN `accumulated_depreciation_rubles` real DEFAULT 0;
+UPDATE `equipment` SET `accumulated_depreciation_rubles` = ROUND(`accumulated_depreciation` / 100.0, 2);
+ALTER TABLE `equipment` DROP COLUMN `accumulated_depreciation`;
+ALTER TABLE `equipment` RENAME COLUMN `accumulated_depreciation_rubles` TO `accumulated_depreciation`;
+
+ALTER TABLE `equipment` ADD COLUMN `residual_value_rubles` real DEFAULT 0;
+UPDATE `equipment` SET `residual_value_rubles` = ROUND(`residual_value` / 100.0, 2);
+ALTER TABLE `equipment` DROP COLUMN `residual_value`;
+ALTER TABLE `equipment` RENAME COLUMN `residual_value_rubles` TO `residual_value`;
+
+ALTER TABLE `equipment` ADD COLUMN `price_rubles` real;
+UPDATE `equipment` SET `price_rubles` = ROUND(`price` / 100.0, 2);
+ALTER TABLE `equipment` DROP COLUMN `price`;
+ALTER TABLE `equipment` RENAME COLUMN `price_rubles` TO `price`;
@@ -0,0 +1,66 @@
+-- Суммы хранятся целым числом копеек вместо рублей в колонках real, стоимость
+-- позиций пересчитывается по округленной цене. Ставка и сумма НДС позиций документов.
+-- SQLite не меняет тип колонки, поэтому каждая сумма переносится в новую
+-- колонку integer, а позиции документов (суммы в них NOT NULL) - в новую таблицу.
+
+ALTER TABLE `equipment` ADD COLUMN `price_kopecks` integer;
+UPDATE `equipment` SET `price_kopecks` = CAST(ROUND(`price` * 100) AS INTEGER);
+ALTER TABLE `equipment` DROP COLUMN `price`;
+ALTER TABLE `equipment` RENAME COLUMN `price_kopecks` TO `price`;
+
+ALTER TABLE `equipment` ADD COLUMN `residual_value_kopecks` integer DEFAULT 0;
+UPDATE `equipment` SET `residual_value_kopecks` = CAST(ROUND(`residual_value` * 100) AS INTEGER);
+ALTER TABLE `equipment` DROP COLUMN `residual_value`;
+ALTER TABLE `equipment` RENAME COLUMN `residual_value_kopecks` TO `residual_value`;
+
+ALTER TABLE `equipment` ADD COLUMN `accumulated_depreciation_kopecks` integer DEFAULT 0;
+UPDATE `equipment` SET `accumulated_depreciation_kopecks` = CAST(ROUND(`accumulated_depreciation` * 100) AS INTEGER);
+ALTER TABLE `equipment` DROP COLUMN `accumulated_depreciation`;
+ALTER TABLE `equipment` RENAME COLUMN `accumulated_depreciation_kopecks` TO `accumulated_depreciation`;
+
+ALTER TABLE `maintenance_records` ADD COLUMN `cost_kopecks` integer;
+UPDATE `maintenance_records` SET `cost_kopecks` = CAST(ROUND(`cost` * 100) AS INTEGER);
+ALTER TABLE `maintenance_records` DROP COLUMN `cost`;
+ALTER TABLE `maintenance_records` RENAME COLUMN `cost_kopecks` TO `cost`;
+
+ALTER TABLE `depreciation_runs` ADD COLUMN `total_kopecks` integer;
+UPDATE `depreciation_runs` SET `total_kopecks` = CAST(ROUND(`total` * 100) AS INTEGER);
+ALTER TABLE `depreciation_runs` DROP COLUMN `total`;
+ALTER TABLE `depreciation_runs` RENAME COLUMN `total_kopecks` TO `total`;
+
+ALTER TABLE `depreciation_postings` ADD COLUMN `book_value_kopecks` integer;
+UPDATE `depreciation_postings` SET `book_value_kopecks` = CAST(ROUND(`book_value` * 100) AS INTEGER);
+ALTER TABLE `depreciation_postings` DROP COLUMN `book_value`;
+ALTER TABLE `depreciation_postings` RENAME COLUMN `book_value_kopecks` TO `book_value`;
+
+ALTER TABLE `depreciation_postings` ADD COLUMN `unit_amount_kopecks` integer;
+UPDATE `depreciation_postings` SET `unit_amount_kopecks` = CAST(ROUND(`unit_amount` * 100) AS INTEGER);
+ALTER TABLE `depreciation_postings` DROP COLUMN `unit_amount`;
+ALTER TABLE `depreciation_postings` RENAME COLUMN `unit_amount_kopecks` TO `unit_amount`;
+
+ALTER TABLE `depreciation_postings` ADD COLUMN `amount_kopecks` integer;
+UPDATE `depreciation_postings` SET `amount_kopecks` = CAST(ROUND(`amount` * 100) AS INTEGER);
+ALTER TABLE `depreciation_postings` DROP COLUMN `amount`;
+ALTER TABLE `depreciation_postings` RENAME COLUMN `amount_kopecks` TO `amount`;
+
+CREATE TABLE `document_items_kopecks` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `document_id` integer NOT NULL,
+    `equipment_id` integer NOT NULL,
+    `quantity` integer NOT NULL,
+    `actual_quantity` integer,
+    `price` integer NOT NULL,
+    `total_price` integer NOT NULL,
+    `comment` text,
+    `counted` numeric DEFAULT false,
+    `vat_rate` text,
+    `vat_amount` integer DEFAULT 0,
+    CONSTRAINT `fk_equipment_documents` FOREIGN KEY (`equipment_id`) REFERENCES `equipment`(`id`),
+    CONSTRAINT `fk_documents_items` FOREIGN KEY (`document_id`) REFERENCES `documents`(`id`)
+);
+INSERT INTO `document_items_kopecks` (`id`, `document_id`, `equipment_id`, `quantity`, `actual_quantity`, `price`, `total_price`, `comment`, `counted`)
+SELECT `id`, `document_id`, `equipment_id`, `quantity`, `actual_quantity`, CAST(ROUND(`price` * 100) AS INTEGER), CAST(ROUND(`price` * 100) AS INTEGER) * `quantity`, `comment`, `counted`
+FROM `document_items`;
+DROP TABLE `document_items`;
+ALTER TABLE `document_items_kopecks` RENAME TO `document_items`;
+CREATE INDEX `idx_doc_equipment` ON `document_items`(`document_id`, `equipment_id`);
@@ -0,0 +1,12 @@
+ALTER TABLE `document_items` DROP COLUMN `exchange_nominal`;
+ALTER TABLE `document_items` DROP COLUMN `exchange_rate`;
+ALTER TABLE `document_items` DROP COLUMN `currency_price`;
+ALTER TABLE `document_items` DROP COLUMN `currency`;
+
+ALTER TABLE `equipment` DROP COLUMN `exchange_nominal`;
+ALTER TABLE `equipment` DROP COLUMN `exchange_rate`;
+ALTER TABLE `equipment` DROP COLUMN `currency_price`;
+ALTER TABLE `equipment` DROP COLUMN `currency`;
+
+DROP INDEX IF EXISTS `idx_exchange_rates_key`;
+DROP TABLE IF EXISTS `exchange_rates`;
@@ -0,0 +1,25 @@
+-- Курсы валют и цены в валюте поставщика (в копейках) у оборудования и позиций документов.
+-- Курс хранится целым числом десятитысячных долей рубля за номинал валюты,
+-- у оборудования и позиций - вместе с номиналом примененного курса.
+
+CREATE TABLE `exchange_rates` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `currency` text NOT NULL,
+    `date` date NOT NULL,
+    `nominal` integer NOT NULL DEFAULT 1,
+    `rate` integer NOT NULL,
+    `source` text,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+CREATE UNIQUE INDEX `idx_exchange_rates_key` ON `exchange_rates`(`currency`, `date`);
+
+ALTER TABLE `equipment` ADD COLUMN `currency` text;
+ALTER TABLE `equipment` ADD COLUMN `currency_price` integer DEFAULT 0;
+ALTER TABLE `equipment` ADD COLUMN `exchange_rate` integer DEFAULT 0;
+ALTER TABLE `equipment` ADD COLUMN `exchange_nominal` integer DEFAULT 0;
+
+ALTER TABLE `document_items` ADD COLUMN `currency` text;
+ALTER TABLE `document_items` ADD COLUMN `currency_price` integer DEFAULT 0;
+ALTER TABLE `document_items` ADD COLUMN `exchange_rate` integer DEFAULT 0;
+ALTER TABLE `document_items` ADD COLUMN `exchange_nominal` integer DEFAULT 0;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS `idx_locations_path`;
+DROP INDEX IF EXISTS `idx_locations_parent_id`;
+
+ALTER TABLE `locations` DROP COLUMN `path`;
+ALTER TABLE `locations` DROP COLUMN `parent_id`;
+ALTER TABLE `locations` DROP COLUMN `kind`;
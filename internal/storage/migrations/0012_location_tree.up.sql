@@ -0,0 +1,10 @@
+-- Иерархия местоположений: здание - этаж - помещение - стеллаж
+
+ALTER TABLE `locations` ADD COLUMN `kind` text;
+ALTER TABLE `locations` ADD COLUMN `parent_id` integer DEFAULT null;
+ALTER TABLE `locations` ADD COLUMN `path` text;
+CREATE INDEX `idx_locations_parent_id` ON `locations`(`parent_id`);
+CREATE INDEX `idx_locations_path` ON `locations`(`path`);
+
+-- Существующие места становятся местами верхнего уровня
+UPDATE `locations` SET `path` = '/' || `id` || '/';
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS `idx_categories_path`;
+DROP INDEX IF EXISTS `idx_categories_parent_id`;
+
+ALTER TABLE `categories` DROP COLUMN `serial_prefix`;
+ALTER TABLE `categories` DROP COLUMN `unit`;
+ALTER TABLE `categories` DROP COLUMN `depreciation_group`;
+ALTER TABLE `categories` DROP COLUMN `path`;
+ALTER TABLE `categories` DROP COLUMN `parent_id`;
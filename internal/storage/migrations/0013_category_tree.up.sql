@@ -0,0 +1,12 @@
+-- Иерархия категорий и наследуемые значения по умолчанию
+
+ALTER TABLE `categories` ADD COLUMN `parent_id` integer DEFAULT null;
+ALTER TABLE `categories` ADD COLUMN `path` text;
+ALTER TABLE `categories` ADD COLUMN `depreciation_group` integer DEFAULT 0;
+ALTER TABLE `categories` ADD COLUMN `unit` text;
+ALTER TABLE `categories` ADD COLUMN `serial_prefix` text;
+CREATE INDEX `idx_categories_parent_id` ON `categories`(`parent_id`);
+CREATE INDEX `idx_categories_path` ON `categories`(`path`);
+
+-- Существующие категории становятся категориями верхнего уровня
+UPDATE `categories` SET `path` = '/' || `id` || '/';
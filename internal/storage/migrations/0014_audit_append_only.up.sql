@@ -0,0 +1,20 @@
+-- Журнал аудита только пополняется: изменение и удаление записей запрещено
+-- на уровне базы, а не только в callbacks GORM. Перед этим из уже записанных
+-- снимков пользователей убираются хэши паролей.
+
+UPDATE `audit_entries` SET `before` = json_set(`before`, '$.password', '***')
+WHERE `entity` = 'users' AND json_valid(`before`) AND json_type(`before`, '$.password') IS NOT NULL;
+UPDATE `audit_entries` SET `after` = json_set(`after`, '$.password', '***')
+WHERE `entity` = 'users' AND json_valid(`after`) AND json_type(`after`, '$.password') IS NOT NULL;
+UPDATE `audit_entries` SET `changes` = json_set(`changes`, '$.password', json('["***","***"]'))
+WHERE `entity` = 'users' AND json_valid(`changes`) AND json_type(`changes`, '$.password') IS NOT NULL;
+
+CREATE TRIGGER `audit_entries_no_update` BEFORE UPDATE ON `audit_entries`
+BEGIN
+    SELECT RAISE(ABORT, 'записи журнала аудита нельзя изменять или удалять');
+END;
+
+CREATE TRIGGER `audit_entries_no_delete` BEFORE DELETE ON `audit_entries`
+BEGIN
+    SELECT RAISE(ABORT, 'записи журнала аудита нельзя изменять или удалять');
+END;
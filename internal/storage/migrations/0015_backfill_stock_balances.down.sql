@@ -0,0 +1 @@
+-- Перенесенные остатки не отличить от созданных проводками, откат их не удаляет
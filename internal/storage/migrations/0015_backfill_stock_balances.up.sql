@@ -0,0 +1,9 @@
+-- Остатки оборудования, заведенного до появления таблицы stock_balances:
+-- переносятся из колонок equipment.location_id и equipment.quantity.
+-- Оборудование, у которого уже есть остатки, не затрагивается.
+
+INSERT INTO `stock_balances` (`equipment_id`, `location_id`, `quantity`, `updated_at`)
+SELECT e.`id`, e.`location_id`, e.`quantity`, CURRENT_TIMESTAMP
+FROM `equipment` e
+WHERE e.`quantity` > 0 AND e.`location_id` <> 0
+AND NOT EXISTS (SELECT 1 FROM `stock_balances` b WHERE b.`equipment_id` = e.`id`);
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Полнотекстовый индекс search_index хранит все сущности в одной таблице FTS5,
+// чтобы результаты разных типов ранжировались вместе. rowid записи равен
+// id*4 + номер сущности, поэтому триггеры обновляют индекс без поиска по нему.
+var searchSources = []SearchSource{
+	{Table: "equipment", Entity: "equipment", Title: "name", Body: []string{"serial_number", "description"}, offset: 0},
+	{Table: "documents", Entity: "document", Title: "number", Body: []string{"comment"}, offset: 1},
+	{Table: "suppliers", Entity: "supplier", Title: "name", Body: []string{"description", "address", "phone"}, offset: 2},
+	{Table: "locations", Entity: "location", Title: "name", Body: []string{"description", "address"}, offset: 3},
+}
+
+// SearchSource таблица, попадающая в полнотекстовый индекс
+type SearchSource struct {
+	Table  string   // таблица БД
+	Entity string   // тип сущности в результатах поиска
+	Title  string   // колонка заголовка
+	Body   []string // текстовые колонки, склеиваемые в тело
+	offset int
+}
+
+// SearchSources возвращает таблицы полнотекстового индекса
+func SearchSources() []SearchSource {
+	return append([]SearchSource(nil), searchSources...)
+}
+
+// BodyExpr склеивает текстовые колонки через пробел, prefix - алиас строки ("new.")
+func (src SearchSource) BodyExpr(prefix string) string {
+	parts := make([]string, len(src.Body))
+	for i, column := range src.Body {
+		parts[i] = fmt.Sprintf("coalesce(%s%s, '')", prefix, column)
+	}
+	return strings.Join(parts, " || ' ' || ")
+}
+
+// FTS5Available сообщает, собран ли драйвер SQLite с FTS5 (тег сборки sqlite_fts5)
+func (s *Storage) FTS5Available() bool {
+	var used int
+	if err := s.db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&used).Error; err != nil {
+		return false
+	}
+	return used == 1
+}
+
+// EnableSearch создает полнотекстовый индекс и триггеры, которые поддерживают его
+// в актуальном состоянии. Индекс зависит от сборки драйвера, поэтому создается
+// при запуске, а не миграцией; без FTS5 поиск работает через LIKE, индекс не
+// создается, а триггеры, оставшиеся от сборки с FTS5, удаляются.
+func (s *Storage) EnableSearch() error {
+	if !s.FTS5Available() {
+		log.Println("[storage] SQLite built without FTS5, search falls back to LIKE (build with -tags sqlite_fts5)")
+		return s.dropSearchTriggers()
+	}
+
+	// Пока триггеров не было, индекс не обновлялся - его нужно перестроить
+	complete, err := s.searchTriggersComplete()
+	if err != nil {
+		return err
+	}
+	rebuild := !complete || !s.db.Migrator().HasTable("search_index")
+	if err := s.db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+		entity UNINDEXED,
+		entity_id UNINDEXED,
+		title,
+		body,
+		tokenize = 'unicode61 remove_diacritics 2',
+		prefix = '2 3'
+	)`).Error; err != nil {
+		return err
+	}
+
+	for _, src := range searchSources {
+		values := func(row string) string {
+			return fmt.Sprintf("%s.id * 4 + %d, '%s', %s.id, %s.%s, %s",
+				row, src.offset, src.Entity, row, row, src.Title, src.BodyExpr(row+"."))
+		}
+		triggers := []string{
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_%[1]s_ai AFTER INSERT ON %[1]s BEGIN
+				INSERT INTO search_index(rowid, entity, entity_id, title, body) VALUES (%[2]s);
+			END`, src.Table, values("new")),
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_%[1]s_au AFTER UPDATE ON %[1]s BEGIN
+				DELETE FROM search_index WHERE rowid = old.id * 4 + %[2]d;
+				INSERT INTO search_index(rowid, entity, entity_id, title, body) VALUES (%[3]s);
+			END`, src.Table, src.offset, values("new")),
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_%[1]s_ad AFTER DELETE ON %[1]s BEGIN
+				DELETE FROM search_index WHERE rowid = old.id * 4 + %[2]d;
+			END`, src.Table, src.offset),
+		}
+		for _, trigger := range triggers {
+			if err := s.db.Exec(trigger).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if rebuild {
+		return s.RebuildSearchIndex()
+	}
+	return nil
+}
+
+// dropSearchTriggers удаляет триггеры полнотекстового индекса. Без модуля FTS5
+// они ломают любую запись в таблицы-источники. Сама таблица search_index остается:
+// пока к ней не обращаются, отсутствие модуля не мешает работе с базой, а сборка
+// с FTS5 снова создаст триггеры и перестроит индекс.
+func (s *Storage) dropSearchTriggers() error {
+	for _, src := range searchSources {
+		for _, suffix := range []string{"ai", "au", "ad"} {
+			if err := s.db.Exec(fmt.Sprintf("DROP TRIGGER IF EXISTS search_%s_%s", src.Table, suffix)).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// searchTriggersComplete проверяет, что на месте все триггеры индекса
+func (s *Storage) searchTriggersComplete() (bool, error) {
+	var count int64
+	if err := s.db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name LIKE 'search\\_%' ESCAPE '\\'").
+		Scan(&count).Error; err != nil {
+		return false, err
+	}
+	return count == int64(3*len(searchSources)), nil
+}
+
+// RebuildSearchIndex заново заполняет полнотекстовый индекс из таблиц
+func (s *Storage) RebuildSearchIndex() error {
+	if !s.FTS5Available() {
+		return nil
+	}
+
+	if err := s.db.Exec("DELETE FROM search_index").Error; err != nil {
+		return err
+	}
+	for _, src := range searchSources {
+		if err := s.db.Exec(fmt.Sprintf(
+			`INSERT INTO search_index(rowid, entity, entity_id, title, body)
+			SELECT id * 4 + %d, '%s', id, %s, %s FROM %s`,
+			src.offset, src.Entity, src.Title, src.BodyExpr(""), src.Table)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -6,37 +6,24 @@ import (
 )
 
 type Storage struct {
-	db *gorm.DB
+	db   *gorm.DB
+	path string
 }
 
+// sqliteParams параметры подключения к SQLite. Транзакции начинаются с BEGIN IMMEDIATE
+// и сразу берут блокировку записи, а занятая другой транзакцией база ожидается до
+// пяти секунд вместо немедленной ошибки SQLITE_BUSY: так параллельные записи,
+// например выдача номеров документов, выполняются по очереди.
+const sqliteParams = "_busy_timeout=5000&_txlock=immediate"
+
 func NewStorage(storageName string) *Storage {
-	db, err := gorm.Open(sqlite.Open(storageName), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(storageName+"?"+sqliteParams), &gorm.Config{})
 	if err != nil {
 		panic(err)
 	}
-	return &Storage{db: db}
+	return &Storage{db: db, path: storageName}
 }
 
 func (s *Storage) GetDB() *gorm.DB {
 	return s.db
 }
-
-func (s *Storage) Migrate(models []interface{}) error {
-	migrator := s.db.Migrator()
-	for _, model := range models {
-		if err := migrator.AutoMigrate(model); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (s *Storage) DropTables(models []interface{}) error {
-	migrator := s.db.Migrator()
-	for _, model := range models {
-		if err := migrator.DropTable(model); err != nil {
-			return err
-		}
-	}
-	return nil
-}
@@ -0,0 +1,599 @@
+package xls
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"unicode/utf16"
+)
+
+// Идентификаторы записей BIFF8
+const (
+	recFormula        = 0x0006
+	recEOF            = 0x000A
+	recHPageBreaks    = 0x001B
+	recVPageBreaks    = 0x001A
+	recName           = 0x0018
+	recLeftMargin     = 0x0026
+	recRightMargin    = 0x0027
+	recTopMargin      = 0x0028
+	recBottomMargin   = 0x0029
+	recFont           = 0x0031
+	recContinue       = 0x003C
+	recDefColWidth    = 0x0055
+	recColInfo        = 0x007D
+	recWSBool         = 0x0081
+	recBoundSheet     = 0x0085
+	recSetup          = 0x00A1
+	recMulRK          = 0x00BD
+	recMulBlank       = 0x00BE
+	recXF             = 0x00E0
+	recMergeCells     = 0x00E5
+	recSST            = 0x00FC
+	recLabelSST       = 0x00FD
+	recBlank          = 0x0201
+	recNumber         = 0x0203
+	recLabel          = 0x0204
+	recRow            = 0x0208
+	recRK             = 0x027E
+	recFormat         = 0x041E
+	recBOF            = 0x0809
+	bofWorksheet      = 0x0010
+	builtinPrintArea  = 0x06
+	ptgArea3d         = 0x3B
+	maxRecordDataSize = 8224
+)
+
+type record struct {
+	id     uint16
+	offset int
+	data   []byte
+}
+
+func parse(stream []byte) (*Workbook, error) {
+	records, err := splitRecords(stream)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || records[0].id != recBOF {
+		return nil, ErrNotBIFF
+	}
+
+	wb := &Workbook{Formats: make(map[uint16]string)}
+	var sst []string
+	sheetsByOffset := make(map[int]*Sheet)
+	type printArea struct {
+		sheet int
+		area  Range
+	}
+	var printAreas []printArea
+
+	// Глобальная часть книги до первой записи EOF
+	i := 1
+	for ; i < len(records); i++ {
+		r := records[i]
+		if r.id == recEOF {
+			break
+		}
+
+		switch r.id {
+		case recFont:
+			font, err := parseFont(r.data)
+			if err != nil {
+				return nil, err
+			}
+			wb.Fonts = append(wb.Fonts, font)
+			// Индекс шрифта 4 в BIFF не используется, поэтому список сдвигается
+			if len(wb.Fonts) == 4 {
+				wb.Fonts = append(wb.Fonts, font)
+			}
+		case recFormat:
+			if len(r.data) < 2 {
+				return nil, errCorrupt("FORMAT")
+			}
+			id := binary.LittleEndian.Uint16(r.data)
+			value, _, err := unicodeString(r.data, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			wb.Formats[id] = value
+		case recXF:
+			xf, err := parseXF(r.data)
+			if err != nil {
+				return nil, err
+			}
+			wb.XFs = append(wb.XFs, xf)
+		case recBoundSheet:
+			if len(r.data) < 8 {
+				return nil, errCorrupt("BOUNDSHEET")
+			}
+			name, _, err := unicodeString(r.data, 6, 1)
+			if err != nil {
+				return nil, err
+			}
+			sheet := &Sheet{Name: name, Setup: PageSetup{Left: -1, Right: -1, Top: -1, Bottom: -1}}
+			wb.Sheets = append(wb.Sheets, sheet)
+			sheetsByOffset[int(binary.LittleEndian.Uint32(r.data))] = sheet
+		case recSST:
+			chunks := [][]byte{r.data}
+			for j := i + 1; j < len(records) && records[j].id == recContinue; j++ {
+				chunks = append(chunks, records[j].data)
+			}
+			if sst, err = parseSST(chunks); err != nil {
+				return nil, err
+			}
+		case recName:
+			if sheetIndex, area, ok := parsePrintArea(r.data); ok {
+				printAreas = append(printAreas, printArea{sheet: sheetIndex, area: area})
+			}
+		}
+	}
+
+	// Листы книги
+	var sheet *Sheet
+	for ; i < len(records); i++ {
+		r := records[i]
+		switch r.id {
+		case recBOF:
+			sheet = nil
+			if len(r.data) >= 4 && binary.LittleEndian.Uint16(r.data[2:]) == bofWorksheet {
+				sheet = sheetsByOffset[r.offset]
+			}
+			continue
+		case recEOF:
+			sheet = nil
+			continue
+		}
+		if sheet == nil {
+			continue
+		}
+		if err := parseSheetRecord(sheet, r, sst); err != nil {
+			return nil, fmt.Errorf("лист %q: %v", sheet.Name, err)
+		}
+	}
+
+	for _, pa := range printAreas {
+		if pa.sheet >= 0 && pa.sheet < len(wb.Sheets) {
+			wb.Sheets[pa.sheet].PrintArea = pa.area
+		}
+	}
+	return wb, nil
+}
+
+func splitRecords(stream []byte) ([]record, error) {
+	var records []record
+	for pos := 0; pos+4 <= len(stream); {
+		id := binary.LittleEndian.Uint16(stream[pos:])
+		size := int(binary.LittleEndian.Uint16(stream[pos+2:]))
+		if size > maxRecordDataSize || pos+4+size > len(stream) {
+			return nil, errCorrupt(fmt.Sprintf("запись 0x%04X", id))
+		}
+		records = append(records, record{id: id, offset: pos, data: stream[pos+4 : pos+4+size]})
+		pos += 4 + size
+	}
+	return records, nil
+}
+
+func parseSheetRecord(sheet *Sheet, r record, sst []string) error {
+	d := r.data
+	need := func(n int, name string) error {
+		if len(d) < n {
+			return errCorrupt(name)
+		}
+		return nil
+	}
+	u16 := func(off int) int { return int(binary.LittleEndian.Uint16(d[off:])) }
+
+	switch r.id {
+	case recLabelSST:
+		if err := need(10, "LABELSST"); err != nil {
+			return err
+		}
+		index := int(binary.LittleEndian.Uint32(d[6:]))
+		if index >= len(sst) {
+			return errCorrupt("LABELSST")
+		}
+		sheet.Cells = append(sheet.Cells, Cell{Row: u16(0), Col: u16(2), XF: u16(4), Value: sst[index]})
+	case recLabel:
+		if err := need(9, "LABEL"); err != nil {
+			return err
+		}
+		value, _, err := unicodeString(d, 6, 2)
+		if err != nil {
+			return err
+		}
+		sheet.Cells = append(sheet.Cells, Cell{Row: u16(0), Col: u16(2), XF: u16(4), Value: value})
+	case recNumber:
+		if err := need(14, "NUMBER"); err != nil {
+			return err
+		}
+		value := math.Float64frombits(binary.LittleEndian.Uint64(d[6:]))
+		sheet.Cells = append(sheet.Cells, Cell{Row: u16(0), Col: u16(2), XF: u16(4), Value: value})
+	case recRK:
+		if err := need(10, "RK"); err != nil {
+			return err
+		}
+		value := rk(binary.LittleEndian.Uint32(d[6:]))
+		sheet.Cells = append(sheet.Cells, Cell{Row: u16(0), Col: u16(2), XF: u16(4), Value: value})
+	case recMulRK:
+		if err := need(6, "MULRK"); err != nil {
+			return err
+		}
+		row, col := u16(0), u16(2)
+		for off := 4; off+6 <= len(d)-2; off += 6 {
+			value := rk(binary.LittleEndian.Uint32(d[off+2:]))
+			sheet.Cells = append(sheet.Cells, Cell{Row: row, Col: col, XF: u16(off), Value: value})
+			col++
+		}
+	case recBlank:
+		if err := need(6, "BLANK"); err != nil {
+			return err
+		}
+		sheet.Cells = append(sheet.Cells, Cell{Row: u16(0), Col: u16(2), XF: u16(4)})
+	case recMulBlank:
+		if err := need(6, "MULBLANK"); err != nil {
+			return err
+		}
+		row, col := u16(0), u16(2)
+		for off := 4; off+2 <= len(d)-2; off += 2 {
+			sheet.Cells = append(sheet.Cells, Cell{Row: row, Col: col, XF: u16(off)})
+			col++
+		}
+	case recFormula:
+		// Значения формул в шаблонах не используются, сохраняется только оформление ячейки
+		if err := need(6, "FORMULA"); err != nil {
+			return err
+		}
+		sheet.Cells = append(sheet.Cells, Cell{Row: u16(0), Col: u16(2), XF: u16(4)})
+	case recMergeCells:
+		if err := need(2, "MERGEDCELLS"); err != nil {
+			return err
+		}
+		count := u16(0)
+		if err := need(2+count*8, "MERGEDCELLS"); err != nil {
+			return err
+		}
+		for k := 0; k < count; k++ {
+			off := 2 + k*8
+			sheet.Merges = append(sheet.Merges, Range{
+				FirstRow: u16(off), LastRow: u16(off + 2),
+				FirstCol: u16(off + 4), LastCol: u16(off + 6),
+			})
+		}
+	case recColInfo:
+		if err := need(10, "COLINFO"); err != nil {
+			return err
+		}
+		sheet.Cols = append(sheet.Cols, ColInfo{
+			First: u16(0), Last: u16(2), Width: u16(4), XF: u16(6),
+			Hidden: u16(8)&0x0001 != 0,
+		})
+	case recRow:
+		if err := need(16, "ROW"); err != nil {
+			return err
+		}
+		sheet.Rows = append(sheet.Rows, RowInfo{
+			Row:    u16(0),
+			Height: u16(6) & 0x7FFF,
+			Hidden: u16(12)&0x0020 != 0,
+		})
+	case recDefColWidth:
+		if err := need(2, "DEFCOLWIDTH"); err != nil {
+			return err
+		}
+		sheet.DefaultColWidth = u16(0)
+	case recSetup:
+		if err := need(12, "SETUP"); err != nil {
+			return err
+		}
+		// Если установлен fNoPls, размер бумаги, масштаб и ориентация не заданы
+		if flags := u16(10); flags&0x0004 == 0 {
+			sheet.Setup.PaperSize = u16(0)
+			sheet.Setup.Scale = u16(2)
+			sheet.Setup.Landscape = flags&0x0002 == 0
+		}
+		sheet.Setup.FitWidth = u16(6)
+		sheet.Setup.FitHeight = u16(8)
+	case recWSBool:
+		if err := need(2, "WSBOOL"); err != nil {
+			return err
+		}
+		sheet.Setup.FitToPage = u16(0)&0x0100 != 0
+	case recLeftMargin, recRightMargin, recTopMargin, recBottomMargin:
+		if err := need(8, "MARGIN"); err != nil {
+			return err
+		}
+		value := math.Float64frombits(binary.LittleEndian.Uint64(d))
+		switch r.id {
+		case recLeftMargin:
+			sheet.Setup.Left = value
+		case recRightMargin:
+			sheet.Setup.Right = value
+		case recTopMargin:
+			sheet.Setup.Top = value
+		case recBottomMargin:
+			sheet.Setup.Bottom = value
+		}
+	case recHPageBreaks, recVPageBreaks:
+		if err := need(2, "PAGEBREAKS"); err != nil {
+			return err
+		}
+		count := u16(0)
+		if err := need(2+count*6, "PAGEBREAKS"); err != nil {
+			return err
+		}
+		for k := 0; k < count; k++ {
+			if r.id == recHPageBreaks {
+				sheet.RowBreaks = append(sheet.RowBreaks, u16(2+k*6))
+			} else {
+				sheet.ColBreaks = append(sheet.ColBreaks, u16(2+k*6))
+			}
+		}
+	}
+	return nil
+}
+
+func parseFont(d []byte) (Font, error) {
+	if len(d) < 16 {
+		return Font{}, errCorrupt("FONT")
+	}
+	name, _, err := unicodeString(d, 14, 1)
+	if err != nil {
+		return Font{}, err
+	}
+	flags := binary.LittleEndian.Uint16(d[2:])
+	return Font{
+		Name:      name,
+		Height:    int(binary.LittleEndian.Uint16(d)),
+		Italic:    flags&0x0002 != 0,
+		Strike:    flags&0x0008 != 0,
+		Color:     int(binary.LittleEndian.Uint16(d[4:])),
+		Bold:      binary.LittleEndian.Uint16(d[6:]) >= 700,
+		Script:    int(binary.LittleEndian.Uint16(d[8:])),
+		Underline: int(d[10]),
+	}, nil
+}
+
+func parseXF(d []byte) (XF, error) {
+	if len(d) < 20 {
+		return XF{}, errCorrupt("XF")
+	}
+	borders := binary.LittleEndian.Uint32(d[10:])
+	borders2 := binary.LittleEndian.Uint32(d[14:])
+	colors := binary.LittleEndian.Uint16(d[18:])
+	return XF{
+		Font:     int(binary.LittleEndian.Uint16(d)),
+		Format:   binary.LittleEndian.Uint16(d[2:]),
+		HAlign:   int(d[6] & 0x07),
+		Wrap:     d[6]&0x08 != 0,
+		VAlign:   int(d[6]>>4) & 0x07,
+		Rotation: int(d[7]),
+		Indent:   int(d[8] & 0x0F),
+		Shrink:   d[8]&0x10 != 0,
+		Border: [4]Border{
+			{Style: int(borders & 0x0F), Color: int(borders>>16) & 0x7F},
+			{Style: int(borders>>4) & 0x0F, Color: int(borders>>23) & 0x7F},
+			{Style: int(borders>>8) & 0x0F, Color: int(borders2) & 0x7F},
+			{Style: int(borders>>12) & 0x0F, Color: int(borders2>>7) & 0x7F},
+		},
+		Pattern:   int(borders2>>26) & 0x3F,
+		ForeColor: int(colors) & 0x7F,
+		BackColor: int(colors>>7) & 0x7F,
+	}, nil
+}
+
+// parsePrintArea разбирает встроенное имя Print_Area вида 'Лист'!$A$1:$S$32
+func parsePrintArea(d []byte) (int, Range, bool) {
+	if len(d) < 15 {
+		return 0, Range{}, false
+	}
+	flags := binary.LittleEndian.Uint16(d)
+	nameLen := int(d[3])
+	formulaLen := int(binary.LittleEndian.Uint16(d[4:]))
+	sheetIndex := int(binary.LittleEndian.Uint16(d[8:]))
+	if flags&0x0020 == 0 || nameLen != 1 || sheetIndex == 0 {
+		return 0, Range{}, false
+	}
+
+	nameStart := 14
+	charSize := 1
+	if d[nameStart]&0x01 != 0 {
+		charSize = 2
+	}
+	if d[nameStart+1] != builtinPrintArea {
+		return 0, Range{}, false
+	}
+
+	formula := d[nameStart+1+charSize:]
+	if formulaLen != 11 || len(formula) < 11 || formula[0] != ptgArea3d {
+		return 0, Range{}, false
+	}
+	u16 := func(off int) int { return int(binary.LittleEndian.Uint16(formula[off:])) }
+	return sheetIndex - 1, Range{
+		FirstRow: u16(3), LastRow: u16(5),
+		FirstCol: u16(7) & 0x3FFF, LastCol: u16(9) & 0x3FFF,
+	}, true
+}
+
+// parseSST читает таблицу строк, разбитую на записи SST и CONTINUE.
+// Символы строки могут продолжаться в следующей записи, которая начинается
+// с собственного байта флагов, определяющего кодировку продолжения.
+func parseSST(chunks [][]byte) ([]string, error) {
+	r := &chunkReader{chunks: chunks}
+	if _, err := r.uint32(); err != nil {
+		return nil, err
+	}
+	count, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	strings := make([]string, 0, count)
+	for k := uint32(0); k < count; k++ {
+		length, err := r.uint16()
+		if err != nil {
+			return nil, err
+		}
+		flags, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+
+		var runs, extSize uint32
+		if flags&0x08 != 0 {
+			n, err := r.uint16()
+			if err != nil {
+				return nil, err
+			}
+			runs = uint32(n)
+		}
+		if flags&0x04 != 0 {
+			if extSize, err = r.uint32(); err != nil {
+				return nil, err
+			}
+		}
+
+		value, err := r.chars(int(length), flags&0x01 != 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.skip(int(runs*4 + extSize)); err != nil {
+			return nil, err
+		}
+		strings = append(strings, value)
+	}
+	return strings, nil
+}
+
+type chunkReader struct {
+	chunks [][]byte
+	chunk  int
+	pos    int
+}
+
+func (r *chunkReader) next() bool {
+	for r.chunk < len(r.chunks) && r.pos >= len(r.chunks[r.chunk]) {
+		r.chunk++
+		r.pos = 0
+	}
+	return r.chunk < len(r.chunks)
+}
+
+func (r *chunkReader) byte() (byte, error) {
+	if !r.next() {
+		return 0, errCorrupt("SST")
+	}
+	b := r.chunks[r.chunk][r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *chunkReader) uint16() (uint16, error) {
+	lo, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	hi, err := r.byte()
+	return uint16(lo) | uint16(hi)<<8, err
+}
+
+func (r *chunkReader) uint32() (uint32, error) {
+	lo, err := r.uint16()
+	if err != nil {
+		return 0, err
+	}
+	hi, err := r.uint16()
+	return uint32(lo) | uint32(hi)<<16, err
+}
+
+func (r *chunkReader) skip(n int) error {
+	for ; n > 0; n-- {
+		if _, err := r.byte(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *chunkReader) chars(n int, wide bool) (string, error) {
+	units := make([]uint16, 0, n)
+	for len(units) < n {
+		if r.pos >= len(r.chunks[r.chunk]) {
+			// Продолжение строки в следующей записи CONTINUE
+			if !r.next() {
+				return "", errCorrupt("SST")
+			}
+			flags, err := r.byte()
+			if err != nil {
+				return "", err
+			}
+			wide = flags&0x01 != 0
+		}
+		if wide {
+			unit, err := r.uint16()
+			if err != nil {
+				return "", err
+			}
+			units = append(units, unit)
+		} else {
+			b, err := r.byte()
+			if err != nil {
+				return "", err
+			}
+			units = append(units, uint16(b))
+		}
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// unicodeString читает строку XLUnicodeString (lenSize = 2) или
+// ShortXLUnicodeString (lenSize = 1), начинающуюся с off.
+// Возвращает строку и смещение за ее концом.
+func unicodeString(d []byte, off, lenSize int) (string, int, error) {
+	if off+lenSize+1 > len(d) {
+		return "", 0, errCorrupt("строка")
+	}
+	length := int(d[off])
+	if lenSize == 2 {
+		length = int(binary.LittleEndian.Uint16(d[off:]))
+	}
+	off += lenSize
+	wide := d[off]&0x01 != 0
+	off++
+
+	units := make([]uint16, length)
+	for k := range units {
+		if wide {
+			if off+2 > len(d) {
+				return "", 0, errCorrupt("строка")
+			}
+			units[k] = binary.LittleEndian.Uint16(d[off:])
+			off += 2
+		} else {
+			if off+1 > len(d) {
+				return "", 0, errCorrupt("строка")
+			}
+			units[k] = uint16(d[off])
+			off++
+		}
+	}
+	return string(utf16.Decode(units)), off, nil
+}
+
+// rk раскодирует число в компактном формате RK
+func rk(v uint32) float64 {
+	var value float64
+	if v&0x02 != 0 {
+		value = float64(int32(v) >> 2)
+	} else {
+		value = math.Float64frombits(uint64(v&0xFFFFFFFC) << 32)
+	}
+	if v&0x01 != 0 {
+		value /= 100
+	}
+	return value
+}
+
+func errCorrupt(what string) error {
+	return fmt.Errorf("поврежденная запись %s", what)
+}
@@ -0,0 +1,238 @@
+package xls
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"unicode/utf16"
+)
+
+// biffRecord запись BIFF: идентификатор, размер и данные
+func biffRecord(id uint16, data []byte) []byte {
+	out := binary.LittleEndian.AppendUint16(nil, id)
+	out = binary.LittleEndian.AppendUint16(out, uint16(len(data)))
+	return append(out, data...)
+}
+
+// sstHeader начало записи SST: общее число ссылок и число уникальных строк
+func sstHeader(count uint32) []byte {
+	out := binary.LittleEndian.AppendUint32(nil, count)
+	return binary.LittleEndian.AppendUint32(out, count)
+}
+
+// compressed символы строки в однобайтовой кодировке BIFF (только Latin-1)
+func compressed(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		out = append(out, byte(r))
+	}
+	return out
+}
+
+// wide символы строки в UTF-16LE
+func wide(s string) []byte {
+	var out []byte
+	for _, unit := range utf16.Encode([]rune(s)) {
+		out = binary.LittleEndian.AppendUint16(out, unit)
+	}
+	return out
+}
+
+// sstString заголовок строки таблицы SST: длина в символах и флаги
+func sstString(length int, flags byte) []byte {
+	return append(binary.LittleEndian.AppendUint16(nil, uint16(length)), flags)
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+	return out
+}
+
+func TestSplitRecords(t *testing.T) {
+	stream := concat(
+		biffRecord(recBOF, []byte{0x00, 0x06, 0x05, 0x00}),
+		biffRecord(recEOF, nil),
+		biffRecord(recLabelSST, []byte{1, 2, 3}),
+	)
+	records, err := splitRecords(stream)
+	if err != nil {
+		t.Fatalf("splitRecords: %v", err)
+	}
+	want := []struct {
+		id     uint16
+		offset int
+		size   int
+	}{
+		{recBOF, 0, 4},
+		{recEOF, 8, 0},
+		{recLabelSST, 12, 3},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("записей %d, ожидалось %d", len(records), len(want))
+	}
+	for i, w := range want {
+		r := records[i]
+		if r.id != w.id || r.offset != w.offset || len(r.data) != w.size {
+			t.Errorf("запись %d: 0x%04X со смещения %d, %d байт; ожидалась 0x%04X со смещения %d, %d байт",
+				i, r.id, r.offset, len(r.data), w.id, w.offset, w.size)
+		}
+	}
+}
+
+func TestSplitRecordsCorrupt(t *testing.T) {
+	oversize := binary.LittleEndian.AppendUint16(binary.LittleEndian.AppendUint16(nil, recSST), maxRecordDataSize+1)
+	tests := map[string][]byte{
+		"данные короче размера":   biffRecord(recBOF, []byte{1, 2, 3, 4})[:6],
+		"запись больше 8224 байт": append(oversize, make([]byte, maxRecordDataSize+1)...),
+	}
+	for name, stream := range tests {
+		if _, err := splitRecords(stream); err == nil {
+			t.Errorf("%s: ожидалась ошибка", name)
+		}
+	}
+}
+
+func TestParseSST(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks [][]byte
+		want   []string
+	}{
+		{
+			name: "однобайтовые строки",
+			chunks: [][]byte{concat(sstHeader(2),
+				sstString(4, 0x00), compressed("Form"),
+				sstString(0, 0x00),
+			)},
+			want: []string{"Form", ""},
+		},
+		{
+			name: "кириллица в UTF-16",
+			chunks: [][]byte{concat(sstHeader(1),
+				sstString(7, 0x01), wide("ИНВ-19 "),
+			)},
+			want: []string{"ИНВ-19 "},
+		},
+		{
+			// Участки форматирования: флаг 0x08 и их число, по 4 байта на участок после
+			// символов; расширенные данные: флаг 0x04 и их размер, байты после участков
+			name: "форматирование и расширенные данные пропускаются",
+			chunks: [][]byte{concat(sstHeader(2),
+				sstString(2, 0x0D), []byte{2, 0}, []byte{3, 0, 0, 0}, wide("Ит"),
+				[]byte{0, 0, 1, 0, 1, 0, 2, 0}, []byte{9, 9, 9},
+				sstString(2, 0x00), compressed("ok"),
+			)},
+			want: []string{"Ит", "ok"},
+		},
+		{
+			name: "строка продолжается в CONTINUE в другой кодировке",
+			chunks: [][]byte{
+				concat(sstHeader(2), sstString(6, 0x00), compressed("abc")),
+				// Продолжение начинается с байта флагов: далее символы в UTF-16
+				concat([]byte{0x01}, wide("где")),
+				concat(sstString(2, 0x01), wide("№1")),
+			},
+			want: []string{"abcгде", "№1"},
+		},
+		{
+			name: "заголовок строки в следующей записи",
+			chunks: [][]byte{
+				concat(sstHeader(2), sstString(1, 0x00), compressed("a")),
+				concat(sstString(3, 0x01), wide("Дата")[:6]),
+			},
+			want: []string{"a", "Дат"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := parseSST(tt.chunks)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: строки %q, ожидались %q", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: строка %d = %q, ожидалась %q", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseSSTCorrupt(t *testing.T) {
+	tests := map[string][][]byte{
+		"нет заголовка":           {{1, 0, 0}},
+		"строк меньше заявленных": {concat(sstHeader(2), sstString(1, 0x00), compressed("a"))},
+		"символы обрываются":      {concat(sstHeader(1), sstString(5, 0x01), wide("аб"))},
+		"нет данных форматирования": {concat(sstHeader(1),
+			sstString(1, 0x08), []byte{4, 0}, compressed("a"))},
+	}
+	for name, chunks := range tests {
+		if _, err := parseSST(chunks); err == nil {
+			t.Errorf("%s: ожидалась ошибка", name)
+		}
+	}
+}
+
+func TestRK(t *testing.T) {
+	float := func(value float64) uint32 {
+		return uint32(math.Float64bits(value) >> 32)
+	}
+	tests := []struct {
+		name  string
+		value uint32
+		want  float64
+	}{
+		{"целое", 5<<2 | 0x02, 5},
+		{"отрицательное целое", 0xFFFFFFF4 | 0x02, -3}, // -3 << 2
+		{"целое, деленное на 100", 1234<<2 | 0x03, 12.34},
+		{"дробное", float(1.5), 1.5},
+		{"дробное, деленное на 100", float(1.5) | 0x01, 0.015},
+		{"ноль", 0, 0},
+	}
+	for _, tt := range tests {
+		if got := rk(tt.value); got != tt.want {
+			t.Errorf("%s: rk(0x%08X) = %v, ожидалось %v", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestUnicodeString(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		off     int
+		lenSize int
+		want    string
+		end     int
+	}{
+		{"короткая однобайтовая", concat([]byte{3, 0x00}, compressed("abc")), 0, 1, "abc", 5},
+		{"короткая UTF-16", concat([]byte{2, 0x01}, wide("Лист")[:4]), 0, 1, "Ли", 6},
+		{"длинная со смещением", concat([]byte{0xFF, 0xFF}, sstString(4, 0x01), wide("стр2")), 2, 2, "стр2", 13},
+		{"пустая", []byte{0, 0, 0x00}, 0, 2, "", 3},
+	}
+	for _, tt := range tests {
+		got, end, err := unicodeString(tt.data, tt.off, tt.lenSize)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want || end != tt.end {
+			t.Errorf("%s: %q до %d, ожидалось %q до %d", tt.name, got, end, tt.want, tt.end)
+		}
+	}
+
+	for name, data := range map[string][]byte{
+		"нет флагов":         {5},
+		"символы обрываются": concat([]byte{4, 0x01}, wide("аб")),
+	} {
+		if _, _, err := unicodeString(data, 0, 1); err == nil {
+			t.Errorf("%s: ожидалась ошибка", name)
+		}
+	}
+}
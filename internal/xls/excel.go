@@ -0,0 +1,332 @@
+package xls
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// defaultXF формат ячеек по умолчанию в BIFF8
+const defaultXF = 15
+
+// Цвета стандартной палитры BIFF8 для индексов 8-63
+var palette = [...]string{
+	"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF",
+	"800000", "008000", "000080", "808000", "800080", "008080", "C0C0C0", "808080",
+	"9999FF", "993366", "FFFFCC", "CCFFFF", "660066", "FF8080", "0066CC", "CCCCFF",
+	"000080", "FF00FF", "FFFF00", "00FFFF", "800080", "800000", "008080", "0000FF",
+	"00CCFF", "CCFFFF", "CCFFCC", "FFFF99", "99CCFF", "FF99CC", "CC99FF", "FFCC99",
+	"3366FF", "33CCCC", "99CC00", "FFCC00", "FF9900", "FF6600", "666699", "969696",
+	"003366", "339966", "003300", "333300", "993300", "993366", "333399", "333333",
+}
+
+var (
+	hAligns = []string{"", "left", "center", "right", "fill", "justify", "centerContinuous", "distributed"}
+	vAligns = []string{"top", "center", "", "justify", "distributed"}
+	sides   = []string{"left", "right", "top", "bottom"}
+)
+
+// Excelize переносит книгу в новый файл excelize, сохраняя оформление.
+// Полученный файл заполняется как обычная книга xlsx.
+func (wb *Workbook) Excelize() (*excelize.File, error) {
+	if len(wb.Sheets) == 0 {
+		return nil, fmt.Errorf("в книге нет листов")
+	}
+
+	f := excelize.NewFile()
+	styles := make(map[int]int)
+
+	for i, sheet := range wb.Sheets {
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", sheet.Name); err != nil {
+				f.Close()
+				return nil, err
+			}
+		} else if _, err := f.NewSheet(sheet.Name); err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		if err := wb.copySheet(f, sheet, styles); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("лист %q: %v", sheet.Name, err)
+		}
+	}
+	return f, nil
+}
+
+func (wb *Workbook) copySheet(f *excelize.File, sheet *Sheet, styles map[int]int) error {
+	name := sheet.Name
+
+	if sheet.DefaultColWidth > 0 {
+		width := uint8(sheet.DefaultColWidth)
+		if err := f.SetSheetProps(name, &excelize.SheetPropsOptions{BaseColWidth: &width}); err != nil {
+			return err
+		}
+	}
+
+	for _, col := range sheet.Cols {
+		first, err := excelize.ColumnNumberToName(col.First + 1)
+		if err != nil {
+			return err
+		}
+		last, err := excelize.ColumnNumberToName(col.Last + 1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetColWidth(name, first, last, float64(col.Width)/256); err != nil {
+			return err
+		}
+		if col.XF != defaultXF {
+			style, err := wb.style(f, col.XF, styles)
+			if err != nil {
+				return err
+			}
+			if err := f.SetColStyle(name, first+":"+last, style); err != nil {
+				return err
+			}
+		}
+		if col.Hidden {
+			if err := f.SetColVisible(name, first+":"+last, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, row := range sheet.Rows {
+		if err := f.SetRowHeight(name, row.Row+1, float64(row.Height)/20); err != nil {
+			return err
+		}
+		if row.Hidden {
+			if err := f.SetRowVisible(name, row.Row+1, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, cell := range sheet.Cells {
+		ref, err := excelize.CoordinatesToCellName(cell.Col+1, cell.Row+1)
+		if err != nil {
+			return err
+		}
+		if cell.Value != nil {
+			if err := f.SetCellValue(name, ref, cell.Value); err != nil {
+				return err
+			}
+		}
+		style, err := wb.style(f, cell.XF, styles)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(name, ref, ref, style); err != nil {
+			return err
+		}
+	}
+
+	for _, merge := range sheet.Merges {
+		first, last, err := rangeCells(merge)
+		if err != nil {
+			return err
+		}
+		if err := f.MergeCell(name, first, last); err != nil {
+			return err
+		}
+	}
+
+	if err := wb.copyPageSetup(f, sheet); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (wb *Workbook) copyPageSetup(f *excelize.File, sheet *Sheet) error {
+	name := sheet.Name
+	setup := sheet.Setup
+
+	layout := &excelize.PageLayoutOptions{}
+	if setup.PaperSize > 0 {
+		layout.Size = &setup.PaperSize
+	}
+	orientation := "portrait"
+	if setup.Landscape {
+		orientation = "landscape"
+	}
+	layout.Orientation = &orientation
+	if setup.FitToPage {
+		layout.FitToWidth = &setup.FitWidth
+		layout.FitToHeight = &setup.FitHeight
+		if err := f.SetSheetProps(name, &excelize.SheetPropsOptions{FitToPage: &setup.FitToPage}); err != nil {
+			return err
+		}
+	} else if setup.Scale >= 10 && setup.Scale <= 400 {
+		scale := uint(setup.Scale)
+		layout.AdjustTo = &scale
+	}
+	if err := f.SetPageLayout(name, layout); err != nil {
+		return err
+	}
+
+	margins := &excelize.PageLayoutMarginsOptions{}
+	for _, m := range []struct {
+		value  float64
+		target **float64
+	}{
+		{setup.Left, &margins.Left},
+		{setup.Right, &margins.Right},
+		{setup.Top, &margins.Top},
+		{setup.Bottom, &margins.Bottom},
+	} {
+		if m.value >= 0 {
+			value := m.value
+			*m.target = &value
+		}
+	}
+	if err := f.SetPageMargins(name, margins); err != nil {
+		return err
+	}
+
+	for _, row := range sheet.RowBreaks {
+		if err := f.InsertPageBreak(name, fmt.Sprintf("A%d", row+1)); err != nil {
+			return err
+		}
+	}
+	for _, col := range sheet.ColBreaks {
+		ref, err := excelize.CoordinatesToCellName(col+1, 1)
+		if err != nil {
+			return err
+		}
+		if err := f.InsertPageBreak(name, ref); err != nil {
+			return err
+		}
+	}
+
+	if !sheet.PrintArea.Empty() {
+		return SetPrintArea(f, name, sheet.PrintArea)
+	}
+	return nil
+}
+
+// SetPrintArea задает или заменяет область печати листа
+func SetPrintArea(f *excelize.File, sheet string, area Range) error {
+	first, err := excelize.CoordinatesToCellName(area.FirstCol+1, area.FirstRow+1, true)
+	if err != nil {
+		return err
+	}
+	last, err := excelize.CoordinatesToCellName(area.LastCol+1, area.LastRow+1, true)
+	if err != nil {
+		return err
+	}
+	name := &excelize.DefinedName{
+		Name:     "_xlnm.Print_Area",
+		RefersTo: fmt.Sprintf("'%s'!%s:%s", sheet, first, last),
+		Scope:    sheet,
+	}
+	// Ранее заданная область заменяется
+	_ = f.DeleteDefinedName(name)
+	return f.SetDefinedName(name)
+}
+
+// style создает стиль excelize для формата ячейки BIFF, повторно используя созданные
+func (wb *Workbook) style(f *excelize.File, index int, styles map[int]int) (int, error) {
+	if id, ok := styles[index]; ok {
+		return id, nil
+	}
+	if index < 0 || index >= len(wb.XFs) {
+		return 0, nil
+	}
+	xf := wb.XFs[index]
+
+	style := &excelize.Style{
+		Alignment: &excelize.Alignment{
+			Horizontal:   lookup(hAligns, xf.HAlign),
+			Vertical:     lookup(vAligns, xf.VAlign),
+			WrapText:     xf.Wrap,
+			TextRotation: xf.Rotation,
+			Indent:       xf.Indent,
+			ShrinkToFit:  xf.Shrink,
+		},
+	}
+
+	if xf.Font >= 0 && xf.Font < len(wb.Fonts) {
+		font := wb.Fonts[xf.Font]
+		style.Font = &excelize.Font{
+			Family: font.Name,
+			Size:   float64(font.Height) / 20,
+			Bold:   font.Bold,
+			Italic: font.Italic,
+			Strike: font.Strike,
+			Color:  color(font.Color),
+		}
+		switch font.Underline {
+		case 0x01, 0x21:
+			style.Font.Underline = "single"
+		case 0x02, 0x22:
+			style.Font.Underline = "double"
+		}
+		switch font.Script {
+		case 1:
+			style.Font.VertAlign = "superscript"
+		case 2:
+			style.Font.VertAlign = "subscript"
+		}
+	}
+
+	for i, border := range xf.Border {
+		if border.Style == 0 {
+			continue
+		}
+		borderColor := color(border.Color)
+		if borderColor == "" {
+			borderColor = "000000"
+		}
+		style.Border = append(style.Border, excelize.Border{Type: sides[i], Style: border.Style, Color: borderColor})
+	}
+
+	if xf.Pattern > 0 {
+		fill := excelize.Fill{Type: "pattern", Pattern: xf.Pattern}
+		if fore := color(xf.ForeColor); fore != "" {
+			fill.Color = []string{fore}
+		}
+		style.Fill = fill
+	}
+
+	if format, ok := wb.Formats[xf.Format]; ok {
+		style.CustomNumFmt = &format
+	} else {
+		style.NumFmt = int(xf.Format)
+	}
+
+	id, err := f.NewStyle(style)
+	if err != nil {
+		return 0, err
+	}
+	styles[index] = id
+	return id, nil
+}
+
+// color возвращает цвет палитры в виде RRGGBB (пустая строка - автоматический цвет)
+func color(index int) string {
+	if index >= 8 && index-8 < len(palette) {
+		return palette[index-8]
+	}
+	if index < 8 {
+		return palette[index%8]
+	}
+	return ""
+}
+
+func lookup(values []string, index int) string {
+	if index >= 0 && index < len(values) {
+		return values[index]
+	}
+	return ""
+}
+
+func rangeCells(r Range) (string, string, error) {
+	first, err := excelize.CoordinatesToCellName(r.FirstCol+1, r.FirstRow+1)
+	if err != nil {
+		return "", "", err
+	}
+	last, err := excelize.CoordinatesToCellName(r.LastCol+1, r.LastRow+1)
+	return first, last, err
+}
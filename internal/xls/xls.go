@@ -0,0 +1,151 @@
+// Package xls читает книги Excel 97-2003 (BIFF8) в объеме, нужном для
+// шаблонов печатных форм: значения ячеек, оформление, объединения,
+// размеры строк и столбцов, параметры страницы и области печати.
+// Формулы, рисунки и примечания не поддерживаются.
+package xls
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// ErrNotBIFF возвращается, если файл не является книгой Excel 97-2003
+var ErrNotBIFF = errors.New("файл не является книгой Excel 97-2003 (BIFF8)")
+
+// Workbook книга с листами и общими для них стилями
+type Workbook struct {
+	Sheets  []*Sheet
+	Fonts   []Font
+	XFs     []XF
+	Formats map[uint16]string
+}
+
+// Sheet лист книги
+type Sheet struct {
+	Name            string
+	Cells           []Cell
+	Merges          []Range
+	Cols            []ColInfo
+	Rows            []RowInfo
+	DefaultColWidth int
+	Setup           PageSetup
+	// PrintArea область печати (нулевой размер - не задана)
+	PrintArea Range
+	// RowBreaks первые строки новых страниц, ColBreaks - первые столбцы
+	RowBreaks []int
+	ColBreaks []int
+}
+
+// Cell ячейка: строка, число или пустая ячейка с оформлением.
+// Строки и столбцы нумеруются с нуля.
+type Cell struct {
+	Row, Col int
+	XF       int
+	Value    interface{}
+}
+
+// Range диапазон ячеек, включая границы
+type Range struct {
+	FirstRow, LastRow int
+	FirstCol, LastCol int
+}
+
+// Empty сообщает, что диапазон не задан
+func (r Range) Empty() bool {
+	return r == Range{}
+}
+
+// ColInfo ширина и оформление группы столбцов. Ширина в 1/256 ширины символа.
+type ColInfo struct {
+	First, Last int
+	Width       int
+	XF          int
+	Hidden      bool
+}
+
+// RowInfo высота строки в twips (1/20 пункта)
+type RowInfo struct {
+	Row    int
+	Height int
+	Hidden bool
+}
+
+// PageSetup параметры печати листа
+type PageSetup struct {
+	PaperSize int
+	Scale     int
+	FitWidth  int
+	FitHeight int
+	FitToPage bool
+	Landscape bool
+	// Поля страницы в дюймах (отрицательное значение - не задано)
+	Left, Right, Top, Bottom float64
+}
+
+// Font шрифт. Размер в twips, цвет - индекс палитры.
+type Font struct {
+	Name      string
+	Height    int
+	Bold      bool
+	Italic    bool
+	Strike    bool
+	Underline int
+	Script    int
+	Color     int
+}
+
+// XF формат ячейки: шрифт, числовой формат, выравнивание, границы и заливка
+type XF struct {
+	Font      int
+	Format    uint16
+	HAlign    int
+	VAlign    int
+	Wrap      bool
+	Rotation  int
+	Indent    int
+	Shrink    bool
+	Border    [4]Border // левая, правая, верхняя, нижняя
+	Pattern   int
+	ForeColor int
+	BackColor int
+}
+
+// Border линия границы: стиль (0 - нет) и индекс цвета палитры
+type Border struct {
+	Style int
+	Color int
+}
+
+// Open читает книгу из содержимого файла .xls
+func Open(data []byte) (*Workbook, error) {
+	doc, err := mscfb.New(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrNotBIFF
+	}
+
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.Name != "Workbook" && entry.Name != "Book" {
+			continue
+		}
+		stream, err := io.ReadAll(entry)
+		if err != nil {
+			return nil, fmt.Errorf("чтение потока книги: %v", err)
+		}
+		return parse(stream)
+	}
+	return nil, ErrNotBIFF
+}
+
+// Sheet возвращает лист по имени
+func (wb *Workbook) Sheet(name string) *Sheet {
+	for _, sheet := range wb.Sheets {
+		if sheet.Name == name {
+			return sheet
+		}
+	}
+	return nil
+}
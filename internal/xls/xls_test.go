@@ -0,0 +1,121 @@
+package xls
+
+import (
+	"errors"
+	"testing"
+	"tohaboy/internal/files"
+)
+
+func TestOpenLaw(t *testing.T) {
+	wb, err := Open(files.Law)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if len(wb.Sheets) != 2 {
+		t.Fatalf("листов %d, ожидалось 2", len(wb.Sheets))
+	}
+	if len(wb.Fonts) == 0 || len(wb.XFs) == 0 {
+		t.Errorf("шрифтов %d, форматов ячеек %d: глобальная часть книги не прочитана", len(wb.Fonts), len(wb.XFs))
+	}
+
+	tests := []struct {
+		sheet     string
+		merges    int
+		printArea Range
+		colBreaks []int
+		cells     map[[2]int]interface{}
+	}{
+		{
+			sheet:     "стр1",
+			merges:    37,
+			printArea: Range{FirstRow: 0, LastRow: 32, FirstCol: 0, LastCol: 18},
+			cells: map[[2]int]interface{}{
+				{0, 13}: "Унифицированная форма № ИНВ-19",
+				{4, 16}: "0317017",
+				{10, 1}: "Основание для проведения инвентаризации:",
+				{10, 9}: "приказ, постановление, распоряжение",
+				{8, 11}: "Вид деятельности",
+				{6, 3}:  "(организация)",
+				{3, 16}: "Код",
+				{5, 14}: "по ОКПО",
+				{2, 13}: "России от 18.08.98 № 88",
+				{1, 13}: "Утверждена постановлением Госкомстата",
+				{4, 11}: "Форма по ОКУД",
+				{8, 3}:  "(структурное подразделение)",
+			},
+		},
+		{
+			sheet:     "стр2",
+			merges:    147,
+			printArea: Range{FirstRow: 0, LastRow: 29, FirstCol: 0, LastCol: 37},
+			colBreaks: []int{19},
+			cells: map[[2]int]interface{}{
+				{0, 14}: "2-я страница формы № ИНВ-19",
+				{0, 34}: "3-я страница формы № ИНВ-19",
+				// Переводы строк внутри ячейки сохраняются
+				{2, 0}:  "Но-\nмер\nпо по-\nрядку",
+				{2, 26}: "Приходуются окончательные\nизлишки",
+				{3, 1}:  "наименование,\nхарактеристика\n(вид, сорт, группа)",
+				// Номера граф записаны числами RK
+				{5, 0}:  1.0,
+				{5, 7}:  7.0,
+				{5, 37}: 32.0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		sheet := wb.Sheet(tt.sheet)
+		if sheet == nil {
+			t.Errorf("нет листа %q", tt.sheet)
+			continue
+		}
+		if len(sheet.Merges) != tt.merges {
+			t.Errorf("%s: объединений %d, ожидалось %d", tt.sheet, len(sheet.Merges), tt.merges)
+		}
+		if sheet.PrintArea != tt.printArea {
+			t.Errorf("%s: область печати %+v, ожидалась %+v", tt.sheet, sheet.PrintArea, tt.printArea)
+		}
+		if !equalInts(sheet.ColBreaks, tt.colBreaks) {
+			t.Errorf("%s: разрывы по столбцам %v, ожидались %v", tt.sheet, sheet.ColBreaks, tt.colBreaks)
+		}
+		if !sheet.Setup.Landscape || sheet.Setup.PaperSize != 9 {
+			t.Errorf("%s: параметры страницы %+v, ожидалась альбомная A4", tt.sheet, sheet.Setup)
+		}
+
+		values := make(map[[2]int]interface{})
+		for _, cell := range sheet.Cells {
+			if cell.Value != nil {
+				values[[2]int{cell.Row, cell.Col}] = cell.Value
+			}
+		}
+		for at, want := range tt.cells {
+			if got := values[at]; got != want {
+				t.Errorf("%s: ячейка %v = %#v, ожидалось %#v", tt.sheet, at, got, want)
+			}
+		}
+	}
+}
+
+func TestOpenNotBIFF(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"пустой файл": nil,
+		"текст":       []byte("Номер;Наименование\n1;Ноутбук\n"),
+		"zip":         []byte("PK\x03\x04\x14\x00\x00\x00"),
+	} {
+		if _, err := Open(data); !errors.Is(err, ErrNotBIFF) {
+			t.Errorf("%s: ошибка %v, ожидалась ErrNotBIFF", name, err)
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -3,8 +3,10 @@ package main
 import (
 	"embed"
 	"fmt"
-	"math/rand"
-	"time"
+	"log"
+	"os"
+	"path/filepath"
+	"tohaboy/internal/config"
 	"tohaboy/internal/data"
 	"tohaboy/internal/model"
 	"tohaboy/internal/repository"
@@ -23,52 +25,66 @@ import (
 var assets embed.FS
 
 func main() {
-	var err error
-
 	// Create an instance of the app structure
 	app := NewApp()
 
+	// Загружаем настройки (при первом запуске создается файл с ключом подписи токенов)
+	cfg, err := config.Load("")
+	if err != nil {
+		panic(err)
+	}
+	log.Printf("config loaded from %s", cfg.File)
+
 	// Create DB connection
-	db := storage.NewStorage("invent.db")
-
-	models := []interface{}{
-		&model.User{},
-		&model.Equipment{},
-		&model.Supplier{},
-		&model.Location{},
-		&model.Movement{},
-		&model.Document{},
-		&model.DocumentItem{},
-		&model.Category{},
+	if err = os.MkdirAll(filepath.Dir(cfg.Database.Path), 0o700); err != nil {
+		panic(err)
 	}
+	db := storage.NewStorage(cfg.Database.Path)
 
-	// Only drop tables if explicitly needed for development
-	if true { // Change to true only when needed during development
-		if err = db.DropTables(models); err != nil {
+	// Сброс базы удаляет все данные, поэтому выполняется только по явному запросу в настройках
+	if cfg.Database.Reset {
+		if err = db.Reset(); err != nil {
 			panic(err)
 		}
 	}
 
-	// Migrate database
-	if err = db.Migrate(models); err != nil {
+	// Применяем новые миграции схемы
+	if err = db.MigrateUp(); err != nil {
 		panic(err)
 	}
 
 	// Generate test data only if tables are empty
-	if err = db.GetDB().First(&model.User{}).Error; err != nil {
-		if err = generateTestData(db.GetDB()); err != nil {
+	if cfg.Database.Seed == config.SeedDemo && db.GetDB().First(&model.User{}).Error != nil {
+		if err = generateTestData(db.GetDB(), cfg.Auth.AdminPassword); err != nil {
 			panic(fmt.Sprintf("Error generating test data: %v", err))
 		}
 	}
 
+	// Инвентарные номера оборудованию, созданному без них (демонстрационные данные, старые базы)
+	if count, err := repository.AssignInventoryNumbers(db.GetDB(), cfg.Numbering); err != nil {
+		panic(fmt.Sprintf("Error assigning inventory numbers: %v", err))
+	} else if count > 0 {
+		log.Printf("inventory numbers assigned to %d equipment items", count)
+	}
+
+	// Полнотекстовый индекс для SearchService
+	if err = db.EnableSearch(); err != nil {
+		panic(err)
+	}
+
 	// Create services
-	svc := service.NewService(repository.NewRepository(db.GetDB()))
+	svc := service.NewService(repository.NewRepository(db.GetDB(), cfg.Numbering), cfg)
+
+	// Все дальнейшие изменения данных попадают в журнал аудита
+	if err = db.EnableAudit(svc.Session.UserID); err != nil {
+		panic(err)
+	}
 
 	// Create application with options
 	err = wails.Run(&options.App{
 		Title:  "Инвентаризация и управление оборудованием",
-		Width:  1024,
-		Height: 768,
+		Width:  cfg.Window.Width,
+		Height: cfg.Window.Height,
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
@@ -83,6 +99,17 @@ func main() {
 			svc.MovementService,
 			svc.DocumentService,
 			svc.CategoryService,
+			svc.EmployeeService,
+			svc.AuditService,
+			svc.SearchService,
+			svc.ImportService,
+			svc.ReportService,
+			svc.InventoryService,
+			svc.LabelService,
+			svc.ScanService,
+			svc.MaintenanceService,
+			svc.DepreciationService,
+			svc.ExchangeRateService,
 		},
 	})
 
@@ -91,9 +118,9 @@ func main() {
 	}
 }
 
-func generateTestData(db *gorm.DB) error {
+func generateTestData(db *gorm.DB, adminPassword string) error {
 	// Create admin user
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("admin"), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(adminPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
@@ -114,18 +141,3 @@ func generateTestData(db *gorm.DB) error {
 
 	return nil
 }
-
-func generateSerialNumber() string {
-	return fmt.Sprintf("SN-%s-%d",
-		time.Now().Format("20060102"),
-		rand.Intn(1000),
-	)
-}
-
-func generateDocNumber(docType string, count int) string {
-	return fmt.Sprintf("%s-%s-%03d",
-		docType,
-		time.Now().Format("20060102"),
-		count,
-	)
-}
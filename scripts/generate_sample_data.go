@@ -5,7 +5,9 @@ import (
 	"log"
 	"math/rand"
 	"time"
+	"tohaboy/internal/config"
 	"tohaboy/internal/model"
+	"tohaboy/internal/repository"
 	"tohaboy/internal/storage"
 )
 
@@ -52,35 +54,26 @@ func generateAddress() string {
 	return fmt.Sprintf("%s, д. %d, %s", streets[rand.Intn(len(streets))], building, cities[rand.Intn(len(cities))])
 }
 
-func generateSerialNumber() string {
-	return fmt.Sprintf("%s-%d-%d",
-		string(rune('A'+rand.Intn(26))),
-		rand.Intn(10000),
-		time.Now().Year(),
-	)
-}
-
 func generateSampleData() {
 	rand.Seed(time.Now().UnixNano())
 
+	// Нумерация инвентарных номеров берется из настроек приложения
+	cfg, err := config.Load("")
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
 	// Initialize storage and migrate tables
 	db := storage.NewStorage("invent.db")
-	models := []interface{}{
-		&model.User{},
-		&model.Equipment{},
-		&model.Supplier{},
-		&model.Location{},
-		&model.Movement{},
-		&model.Document{},
-		&model.DocumentItem{},
-		&model.Category{},
-	}
 
-	if err := db.Migrate(models); err != nil {
+	if err := db.MigrateUp(); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
 	// Clear existing data
+	if err := db.GetDB().Exec("DELETE FROM stock_balances").Error; err != nil {
+		log.Printf("Error clearing stock balances: %v", err)
+	}
 	if err := db.GetDB().Exec("DELETE FROM equipment").Error; err != nil {
 		log.Printf("Error clearing equipment: %v", err)
 	}
@@ -88,6 +81,18 @@ func generateSampleData() {
 		log.Printf("Error clearing suppliers: %v", err)
 	}
 
+	// Серийные номера выдаются счетчиком по префиксу категории, инвентарные -
+	// по правилу нумерации оборудования, поэтому оборудование создается через репозиторий
+	category := model.Category{Name: "Промышленное оборудование", CategoryDefaults: model.CategoryDefaults{SerialPrefix: "PRM-"}}
+	if err := db.GetDB().Where("name = ?", category.Name).FirstOrCreate(&category).Error; err != nil {
+		log.Fatal("Failed to create category:", err)
+	}
+	location := model.Location{Name: "Производственный цех"}
+	if err := db.GetDB().Where("name = ?", location.Name).FirstOrCreate(&location).Error; err != nil {
+		log.Fatal("Failed to create location:", err)
+	}
+	equipmentRepo := repository.NewEquipmentRepository(db.GetDB(), cfg.Numbering)
+
 	// Генерируем 30 поставщиков
 	for i := 0; i < 30; i++ {
 		supplier := &model.Supplier{
@@ -108,17 +113,18 @@ func generateSampleData() {
 			equipType := equipmentTypes[rand.Intn(len(equipmentTypes))]
 			brand := equipmentBrands[rand.Intn(len(equipmentBrands))]
 			equipment := &model.Equipment{
-				Name:         fmt.Sprintf("%s %s", brand, equipType),
-				SerialNumber: generateSerialNumber(),
-				Description:  fmt.Sprintf("Промышленное оборудование %s производства %s", equipType, brand),
-				SupplierID:   supplier.ID,
-				Status:       "В эксплуатации",
-				Quantity:     rand.Intn(5) + 1,
-				Price:        float64(rand.Intn(1000000) + 100000),
+				Name:        fmt.Sprintf("%s %s", brand, equipType),
+				Description: fmt.Sprintf("Промышленное оборудование %s производства %s", equipType, brand),
+				CategoryID:  category.ID,
+				LocationID:  location.ID,
+				SupplierID:  supplier.ID,
+				Status:      model.EquipmentInUse,
+				Quantity:    rand.Intn(5) + 1,
+				Price:       model.Money((rand.Intn(1000000) + 100000) * 100),
 			}
 
-			if err := db.GetDB().Create(equipment).Error; err != nil {
-				log.Printf("Error creating equipment: %v", err)
+			if response := equipmentRepo.CreateEquipment(equipment); response.Model == nil {
+				log.Printf("Error creating equipment: %v", response.Message)
 				continue
 			}
 		}